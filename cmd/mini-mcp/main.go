@@ -9,10 +9,17 @@ import (
 	"syscall"
 	"time"
 
+	"mini-mcp/internal/domain/alerting"
+	"mini-mcp/internal/domain/metrics"
+	"mini-mcp/internal/domain/procfs"
 	"mini-mcp/internal/health"
+	"mini-mcp/internal/registry"
 	"mini-mcp/internal/server"
+	"mini-mcp/internal/shared/config"
 	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/prometheus"
 	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/shared/tracing"
 
 	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -20,11 +27,38 @@ import (
 func main() {
 	version := flag.String("version", "dev", "Version of the mini-mcp server")
 	logLevel := flag.String("log-level", "INFO", "Log level: DEBUG, INFO, WARNING, ERROR, FATAL")
+	logConfig := flag.String("log-config", "", "YAML file of log sinks (stderr, rotated files, syslog) with a level each; empty logs to stderr")
+	metricsInterval := flag.Duration("metrics-interval", metrics.DefaultInterval, "Interval of the background metrics sampler; 0 disables it and metrics_query")
+	metricsRetention := flag.Duration("metrics-retention", metrics.DefaultRetention, "How long sampled metrics are kept")
+	alertRules := flag.String("alert-rules", "", "YAML file of alert rules and webhook receivers; empty disables alerting")
+	metricsFile := flag.String("metrics-file", "", "File the metrics history is persisted to across restarts; empty keeps it in memory only")
+	metricsListen := flag.String("metrics-listen", "", "Address to serve Prometheus metrics on at /metrics, e.g. :9464; empty disables the exporter")
+	healthChecks := flag.String("health-checks", "", "YAML file of additional health checks (http, tcp, dns, process, file, disk, command)")
+	traceEndpoint := flag.String("trace-otlp-endpoint", "", "OTLP/HTTP collector to export traces to, e.g. http://localhost:4318; empty disables it")
+	traceFile := flag.String("trace-file", "", "File to append traces to as OTLP JSON lines; empty disables it")
 	flag.Parse()
 
 	// Initialize global logger
-	lvl := logging.LogLevel(*logLevel)
-	logging.InitGlobalLogger(lvl)
+	lvl, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -log-level: %v\n", err)
+		os.Exit(1)
+	}
+	if *logConfig != "" {
+		cfg, err := logging.LoadConfig(*logConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load logging config: %v\n", err)
+			os.Exit(1)
+		}
+		outputs, err := cfg.Open(lvl)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open log sinks: %v\n", err)
+			os.Exit(1)
+		}
+		logging.InitGlobalLoggerWithOutputs(outputs)
+	} else {
+		logging.InitGlobalLogger(lvl)
+	}
 	logger := logging.GetGlobalLogger()
 
 	// Logger initialization handled by structured logging
@@ -35,19 +69,119 @@ func main() {
 		"log_level": string(lvl),
 	})
 
+	// Load the security policy from the environment and CONFIG_FILE
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Error("Failed to load configuration", err, nil)
+		fmt.Fprintf(os.Stderr, "failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Security executor initialization handled by structured logging
-	// Initialize security executor with defaults
-	sec := security.NewSecureCommandExecutor(nil)
+	sec := security.NewSecureCommandExecutor(cfg.ToSecurityConfig())
 	// Security executor initialization handled by structured logging
-	logger.Info("Security executor initialized", nil)
+	logger.Info("Security executor initialized", map[string]any{
+		"environment":        cfg.Environment,
+		"controllable_units": cfg.Security.ControllableUnits,
+		"system_commands":    cfg.Security.SystemCommands,
+	})
 
-	// Create health checker
+	// Trace tool calls when a trace exporter is configured
+	var tracer *tracing.Tracer
+	if *traceEndpoint != "" || *traceFile != "" {
+		var exporters []tracing.Exporter
+		if *traceEndpoint != "" {
+			exporter, err := tracing.NewHTTPExporter(*traceEndpoint, nil)
+			if err != nil {
+				logger.Error("Failed to create OTLP trace exporter", err, map[string]any{"endpoint": *traceEndpoint})
+				fmt.Fprintf(os.Stderr, "failed to create OTLP trace exporter: %v\n", err)
+				os.Exit(1)
+			}
+			exporters = append(exporters, exporter)
+		}
+		if *traceFile != "" {
+			exporter, err := tracing.NewFileExporter(*traceFile)
+			if err != nil {
+				logger.Error("Failed to create trace file exporter", err, map[string]any{"file": *traceFile})
+				fmt.Fprintf(os.Stderr, "failed to create trace file exporter: %v\n", err)
+				os.Exit(1)
+			}
+			exporters = append(exporters, exporter)
+		}
+		tracer = tracing.NewTracer(tracing.Options{
+			ServiceName:    "mini-mcp",
+			ServiceVersion: *version,
+			Exporters:      exporters,
+		})
+		tracing.SetGlobalTracer(tracer)
+		logger.Info("Tracing enabled", map[string]any{
+			"otlp_endpoint": *traceEndpoint,
+			"file":          *traceFile,
+		})
+	}
+
+	// Create health checker with the checks of the checks file, if any
 	healthChecker := health.CreateDefaultHealthChecker(*version)
+	if *healthChecks != "" {
+		checksConfig, err := health.LoadChecksConfig(*healthChecks)
+		if err != nil {
+			logger.Error("Failed to load health checks", err, map[string]any{"file": *healthChecks})
+			fmt.Fprintf(os.Stderr, "failed to load health checks: %v\n", err)
+			os.Exit(1)
+		}
+		healthChecker, err = health.CreateConfiguredHealthChecker(*version, checksConfig, health.CheckDeps{
+			Collector:      procfs.NewCollector(procfs.DefaultRoot),
+			Run:            registry.NewCommandExecutor(sec, logger).ExecuteSystemCommand,
+			CommandAllowed: sec.IsSystemCommandAllowed,
+		})
+		if err != nil {
+			logger.Error("Failed to create health checks", err, map[string]any{"file": *healthChecks})
+			fmt.Fprintf(os.Stderr, "failed to create health checks: %v\n", err)
+			os.Exit(1)
+		}
+		logger.Info("Health checks loaded", map[string]any{"file": *healthChecks, "checks": len(checksConfig.Checks)})
+	}
+
+	// Create the metrics history store; the sampler starts with the server
+	var metricsStore *metrics.Store
+	if *metricsInterval > 0 {
+		metricsStore = metrics.NewStore(metrics.StoreOptions{
+			Interval:  *metricsInterval,
+			Retention: *metricsRetention,
+			Path:      *metricsFile,
+		})
+		if err := metricsStore.Load(time.Now()); err != nil {
+			logger.Warning("Failed to load metrics history, starting empty", map[string]any{"error": err.Error()})
+		}
+	}
+
+	// Create the alert rules engine; it starts evaluating with the server
+	var alertEngine *alerting.Engine
+	if *alertRules != "" {
+		alertConfig, err := alerting.LoadConfig(*alertRules)
+		if err != nil {
+			logger.Error("Failed to load alert rules", err, map[string]any{"file": *alertRules})
+			fmt.Fprintf(os.Stderr, "failed to load alert rules: %v\n", err)
+			os.Exit(1)
+		}
+		var metricSource alerting.MetricSource
+		if metricsStore != nil {
+			metricSource = metricsStore
+		}
+		alertEngine, err = alerting.NewEngine(alertConfig, metricSource, healthChecker, logger)
+		if err != nil {
+			logger.Error("Failed to create alert rules engine", err, map[string]any{"file": *alertRules})
+			fmt.Fprintf(os.Stderr, "failed to create alert rules engine: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	deps := server.Deps{
-		Logger:        logger,
-		Security:      sec,
-		HealthChecker: healthChecker,
+		Logger:         logger,
+		Security:       sec,
+		HealthChecker:  healthChecker,
+		MetricsHistory: metricsStore,
+		Alerts:         alertEngine,
 	}
 
 	// Server build handled by structured logging
@@ -65,11 +199,56 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Toggle DEBUG logging on SIGUSR1 without a restart
+	if controller, ok := logger.(logging.LevelController); ok {
+		go toggleDebugOnSignal(ctx, controller, logger)
+	}
+
+	// Run health checks on their own schedules so reports are served from cached results
+	healthChecker.Start(ctx)
+
+	// Start the metrics sampler; it persists the history when ctx is cancelled
+	samplerDone := make(chan struct{})
+	if metricsStore != nil {
+		sampler := metrics.NewSampler(procfs.NewCollector(procfs.DefaultRoot), metricsStore, logger)
+		go func() {
+			defer close(samplerDone)
+			sampler.Run(ctx)
+		}()
+		logger.Info("Metrics sampler started", map[string]any{
+			"interval":  metricsInterval.String(),
+			"retention": metricsRetention.String(),
+		})
+	} else {
+		close(samplerDone)
+	}
+
+	// Serve Prometheus metrics on their own port; stdio carries the MCP protocol
+	if *metricsListen != "" {
+		exporter := prometheus.NewExporter(logger.GetMetrics(), sec, *version)
+		go func() {
+			if err := prometheus.ListenAndServe(ctx, *metricsListen, exporter); err != nil {
+				logger.Error("Metrics exporter failed", err, map[string]any{"address": *metricsListen})
+			}
+		}()
+		logger.Info("Metrics exporter started", map[string]any{
+			"address": *metricsListen,
+			"path":    prometheus.MetricsPath,
+		})
+	}
+
+	if alertEngine != nil {
+		go alertEngine.Run(ctx)
+		logger.Info("Alert rules engine started", map[string]any{
+			"rules": len(alertEngine.Rules()),
+		})
+	}
+
 	// Start server in a goroutine
 	serverErr := make(chan error, 1)
 	go func() {
 		logger.Info("Starting MCP server on stdio transport", nil)
-		if err := s.Run(ctx, &mcp.StdioTransport{}); err != nil {
+		if err := s.Run(ctx, server.WithCorrelationIDs(&mcp.StdioTransport{})); err != nil {
 			serverErr <- err
 		}
 	}()
@@ -93,7 +272,7 @@ func main() {
 		case <-shutdownTimer.C:
 			logger.Warning("Shutdown timeout reached, forcing exit", nil)
 			os.Exit(1)
-		default:
+		case <-samplerDone:
 		}
 
 	case err := <-serverErr:
@@ -103,13 +282,13 @@ func main() {
 	}
 
 	// Perform cleanup
-	performCleanup(logger, sec)
+	performCleanup(logger, sec, tracer)
 
 	logger.Info("MCP server shutdown gracefully", nil)
 }
 
 // performCleanup handles resource cleanup during shutdown
-func performCleanup(logger logging.Logger, security *security.SecureCommandExecutor) {
+func performCleanup(logger logging.Logger, security *security.SecureCommandExecutor, tracer *tracing.Tracer) {
 	logger.Info("Performing cleanup before shutdown", nil)
 
 	// Cleanup security executor (kill any running processes)
@@ -118,6 +297,16 @@ func performCleanup(logger logging.Logger, security *security.SecureCommandExecu
 		security.Cleanup()
 	}
 
+	// Export the spans still queued
+	if tracer != nil {
+		logger.Debug("Flushing traces", nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := tracer.Shutdown(ctx); err != nil {
+			logger.Warning("Failed to flush traces", map[string]any{"error": err.Error()})
+		}
+		cancel()
+	}
+
 	// Log final metrics
 	if metrics := logger.GetMetrics(); metrics != nil {
 		logger.Info("Final metrics summary", metrics.GetMetricsSummary())
@@ -127,4 +316,11 @@ func performCleanup(logger logging.Logger, security *security.SecureCommandExecu
 	time.Sleep(100 * time.Millisecond) // Brief pause to ensure logs are written
 
 	logger.Info("Cleanup completed", nil)
+
+	// Close log files and syslog connections
+	if closer, ok := logger.(interface{ Close() error }); ok {
+		if err := closer.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to close log sinks: %v\n", err)
+		}
+	}
 }
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mini-mcp/internal/shared/logging"
+)
+
+// toggleDebugOnSignal switches DEBUG logging on and off on every SIGUSR1
+// until ctx is cancelled
+func toggleDebugOnSignal(ctx context.Context, controller logging.LevelController, logger logging.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			debug := controller.ToggleDebug()
+			// Logged at WARNING so the change shows at any configured level
+			logger.Warning("Log level toggled by SIGUSR1", map[string]any{"debug": debug})
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+
+	"mini-mcp/internal/shared/logging"
+)
+
+// toggleDebugOnSignal does nothing; Windows has no SIGUSR1
+func toggleDebugOnSignal(ctx context.Context, controller logging.LevelController, logger logging.Logger) {
+}
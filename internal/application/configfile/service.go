@@ -0,0 +1,51 @@
+package configfile
+
+import (
+	"context"
+
+	"mini-mcp/internal/domain/configfile"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/types/resources"
+)
+
+// Service defines the interface for configuration file application services
+type Service interface {
+	Get(ctx context.Context, path, key, format string) (*resources.ConfigValue, error)
+	Set(ctx context.Context, path, key string, value any, opts configfile.EditOptions) (*resources.ConfigChange, error)
+	Delete(ctx context.Context, path, key string, opts configfile.EditOptions) (*resources.ConfigChange, error)
+}
+
+// ServiceImpl implements the configuration file application service
+type ServiceImpl struct {
+	configDomainService configfile.Service
+}
+
+// NewService creates a new configuration file application service
+func NewService(configDomainService configfile.Service) Service {
+	return &ServiceImpl{
+		configDomainService: configDomainService,
+	}
+}
+
+// NewServiceWithDeps creates a new configuration file application service with dependencies
+func NewServiceWithDeps(securityValidator security.PathValidator, logger logging.Logger) Service {
+	return &ServiceImpl{
+		configDomainService: configfile.NewService(securityValidator, logger),
+	}
+}
+
+// Get reads a configuration value through the domain service
+func (s *ServiceImpl) Get(ctx context.Context, path, key, format string) (*resources.ConfigValue, error) {
+	return s.configDomainService.Get(ctx, path, key, format)
+}
+
+// Set writes a configuration value through the domain service
+func (s *ServiceImpl) Set(ctx context.Context, path, key string, value any, opts configfile.EditOptions) (*resources.ConfigChange, error) {
+	return s.configDomainService.Set(ctx, path, key, value, opts)
+}
+
+// Delete removes a configuration value through the domain service
+func (s *ServiceImpl) Delete(ctx context.Context, path, key string, opts configfile.EditOptions) (*resources.ConfigChange, error) {
+	return s.configDomainService.Delete(ctx, path, key, opts)
+}
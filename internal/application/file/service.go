@@ -2,18 +2,30 @@ package file
 
 import (
 	"context"
+	"os"
 
 	"mini-mcp/internal/domain/file"
 	"mini-mcp/internal/shared/logging"
 	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/types/resources"
 )
 
 // Service defines the interface for file application services
 type Service interface {
 	ReadFile(ctx context.Context, path string) (string, error)
 	WriteFile(ctx context.Context, path, content string) error
-	ListDirectory(ctx context.Context, path string) (string, error)
+	ListDirectory(ctx context.Context, path string, opts file.ListOptions) (*resources.DirectoryListing, error)
 	DeleteFile(ctx context.Context, path string) error
+	CreateDirectory(ctx context.Context, path string, mode os.FileMode) error
+	MovePath(ctx context.Context, src, dst string, overwrite bool) error
+	CopyPath(ctx context.Context, src, dst string, opts file.CopyOptions) (*resources.CopyResult, error)
+	ChangeMode(ctx context.Context, path string, mode os.FileMode, recursive bool) (int, error)
+	ChangeOwner(ctx context.Context, path, owner, group string, recursive bool) (int, error)
+	StatPath(ctx context.Context, path string) (*resources.FileStat, error)
+	Checksum(ctx context.Context, path, algorithm string) (*resources.FileChecksum, error)
+	CreateArchive(ctx context.Context, src, archive string, opts file.ArchiveOptions) (*resources.ArchiveResult, error)
+	ExtractArchive(ctx context.Context, archive, dest string, opts file.ExtractOptions) (*resources.ArchiveResult, error)
+	TailFile(ctx context.Context, path string, opts file.TailOptions) (*resources.FileTail, error)
 }
 
 // ServiceImpl implements the file service
@@ -50,11 +62,61 @@ func (s *ServiceImpl) WriteFile(ctx context.Context, path, content string) error
 }
 
 // ListDirectory lists directory contents through the domain service
-func (s *ServiceImpl) ListDirectory(ctx context.Context, path string) (string, error) {
-	return s.fileDomainService.ListDirectory(ctx, path)
+func (s *ServiceImpl) ListDirectory(ctx context.Context, path string, opts file.ListOptions) (*resources.DirectoryListing, error) {
+	return s.fileDomainService.ListDirectory(ctx, path, opts)
 }
 
 // DeleteFile deletes a file through the domain service
 func (s *ServiceImpl) DeleteFile(ctx context.Context, path string) error {
 	return s.fileDomainService.DeleteFile(ctx, path)
 }
+
+// CreateDirectory creates a directory through the domain service
+func (s *ServiceImpl) CreateDirectory(ctx context.Context, path string, mode os.FileMode) error {
+	return s.fileDomainService.CreateDirectory(ctx, path, mode)
+}
+
+// MovePath moves a file or directory through the domain service
+func (s *ServiceImpl) MovePath(ctx context.Context, src, dst string, overwrite bool) error {
+	return s.fileDomainService.MovePath(ctx, src, dst, overwrite)
+}
+
+// CopyPath copies a file or directory through the domain service
+func (s *ServiceImpl) CopyPath(ctx context.Context, src, dst string, opts file.CopyOptions) (*resources.CopyResult, error) {
+	return s.fileDomainService.CopyPath(ctx, src, dst, opts)
+}
+
+// ChangeMode changes permissions through the domain service
+func (s *ServiceImpl) ChangeMode(ctx context.Context, path string, mode os.FileMode, recursive bool) (int, error) {
+	return s.fileDomainService.ChangeMode(ctx, path, mode, recursive)
+}
+
+// ChangeOwner changes ownership through the domain service
+func (s *ServiceImpl) ChangeOwner(ctx context.Context, path, owner, group string, recursive bool) (int, error) {
+	return s.fileDomainService.ChangeOwner(ctx, path, owner, group, recursive)
+}
+
+// StatPath returns file metadata through the domain service
+func (s *ServiceImpl) StatPath(ctx context.Context, path string) (*resources.FileStat, error) {
+	return s.fileDomainService.StatPath(ctx, path)
+}
+
+// Checksum computes a file digest through the domain service
+func (s *ServiceImpl) Checksum(ctx context.Context, path, algorithm string) (*resources.FileChecksum, error) {
+	return s.fileDomainService.Checksum(ctx, path, algorithm)
+}
+
+// CreateArchive packs a path into an archive through the domain service
+func (s *ServiceImpl) CreateArchive(ctx context.Context, src, archive string, opts file.ArchiveOptions) (*resources.ArchiveResult, error) {
+	return s.fileDomainService.CreateArchive(ctx, src, archive, opts)
+}
+
+// ExtractArchive unpacks an archive through the domain service
+func (s *ServiceImpl) ExtractArchive(ctx context.Context, archive, dest string, opts file.ExtractOptions) (*resources.ArchiveResult, error) {
+	return s.fileDomainService.ExtractArchive(ctx, archive, dest, opts)
+}
+
+// TailFile reads the end of a file through the domain service
+func (s *ServiceImpl) TailFile(ctx context.Context, path string, opts file.TailOptions) (*resources.FileTail, error) {
+	return s.fileDomainService.TailFile(ctx, path, opts)
+}
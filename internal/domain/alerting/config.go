@@ -0,0 +1,284 @@
+// Package alerting evaluates threshold rules over the recorded metrics
+// history and health check results, tracks each alert through the pending,
+// firing and resolved states and delivers state changes to webhooks.
+package alerting
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityError    = "error"
+	SeverityCritical = "critical"
+)
+
+// Webhook payload formats
+const (
+	// FormatJSON posts {"status": ..., "alerts": [...]} with the alerts as the alerts tool reports them
+	FormatJSON = "json"
+	// FormatSlack posts a Slack incoming-webhook message with one attachment per alert
+	FormatSlack = "slack"
+	// FormatAlertmanager posts the alert list of the Alertmanager v2 API (POST /api/v2/alerts)
+	FormatAlertmanager = "alertmanager"
+)
+
+// Rule aggregations over the window, in addition to the metrics query aggregations
+const AggregationP95 = "p95"
+
+// Config defaults
+const (
+	DefaultEvaluationInterval = 30 * time.Second
+	DefaultRepeatInterval     = 4 * time.Hour
+	DefaultResolvedRetention  = 15 * time.Minute
+	DefaultWindow             = time.Minute
+	defaultWebhookTimeout     = 10 * time.Second
+)
+
+var (
+	// Severities lists the alert severities from least to most severe
+	Severities = []string{SeverityInfo, SeverityWarning, SeverityError, SeverityCritical}
+	// Formats lists the webhook payload formats
+	Formats = []string{FormatJSON, FormatSlack, FormatAlertmanager}
+	// Operators lists the threshold comparison operators
+	Operators = []string{">", ">=", "<", "<=", "==", "!="}
+	// Aggregations lists how a metric rule reduces its window to one value
+	Aggregations = []string{"last", "avg", "min", "max", AggregationP95}
+	// healthStatuses orders health statuses from best to worst
+	healthStatuses = []string{"healthy", "degraded", "unhealthy"}
+)
+
+// Config is the alerting configuration file
+type Config struct {
+	// EvaluationInterval is how often rules are evaluated
+	EvaluationInterval time.Duration `yaml:"evaluation_interval"`
+	// RepeatInterval is how often still-firing alerts are sent again; 0 uses
+	// DefaultRepeatInterval. Alertmanager receivers get firing alerts on every
+	// evaluation instead, so Alertmanager does not resolve them.
+	RepeatInterval time.Duration `yaml:"repeat_interval"`
+	// ResolvedRetention is how long resolved alerts stay listed
+	ResolvedRetention time.Duration `yaml:"resolved_retention"`
+	Rules             []Rule        `yaml:"rules"`
+	Receivers         []Receiver    `yaml:"receivers"`
+}
+
+// Rule is a threshold on a metric series or a health check. Metric rules
+// produce one alert per matching series, health rules one per matching check.
+type Rule struct {
+	Name string `yaml:"name"`
+
+	// Metric is a recorded metric name or glob, e.g. disk_used_percent
+	Metric string `yaml:"metric"`
+	// Match filters the metric series by label; values may be globs
+	Match map[string]string `yaml:"match"`
+	// Aggregation reduces the window to one value: last (default), avg, min, max or p95
+	Aggregation string `yaml:"aggregation"`
+	// Window is the time range the aggregation covers
+	Window time.Duration `yaml:"window"`
+	// Op compares the value with Threshold: >, >=, <, <=, == or !=
+	Op        string  `yaml:"op"`
+	Threshold float64 `yaml:"threshold"`
+
+	// HealthCheck is a health check or dependency name or glob
+	HealthCheck string `yaml:"health_check"`
+	// Status is the health status at or beyond which the rule fires: degraded or unhealthy (default)
+	Status string `yaml:"status"`
+
+	// For is how long the condition must hold before the alert fires
+	For time.Duration `yaml:"for"`
+	// Severity is info, warning (default), error or critical
+	Severity string `yaml:"severity"`
+	// Labels are added to the labels of every alert of the rule
+	Labels map[string]string `yaml:"labels"`
+	// Summary is a text/template for the alert message with .Value, .Threshold and .Labels
+	Summary string `yaml:"summary"`
+
+	summary *template.Template
+}
+
+// Receiver is a webhook that alert state changes are posted to
+type Receiver struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Format is json (default), slack or alertmanager
+	Format  string            `yaml:"format"`
+	Headers map[string]string `yaml:"headers"`
+	// Severities limits the receiver to these severities; empty receives all
+	Severities []string      `yaml:"severities"`
+	Timeout    time.Duration `yaml:"timeout"`
+	// SendResolved also posts alerts when they resolve
+	SendResolved bool `yaml:"send_resolved"`
+}
+
+// LoadConfig reads and validates an alerting configuration file
+func LoadConfig(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses and validates an alerting configuration, applying defaults
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse alert rules: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validate checks the configuration and fills in defaults
+func (c *Config) validate() error {
+	if c.EvaluationInterval == 0 {
+		c.EvaluationInterval = DefaultEvaluationInterval
+	}
+	if c.EvaluationInterval < time.Second {
+		return fmt.Errorf("evaluation_interval must be at least 1s")
+	}
+	if c.RepeatInterval == 0 {
+		c.RepeatInterval = DefaultRepeatInterval
+	}
+	if c.ResolvedRetention == 0 {
+		c.ResolvedRetention = DefaultResolvedRetention
+	}
+
+	names := make(map[string]bool)
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %d (%s): %w", i+1, rule.Name, err)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule %d: duplicate name %q", i+1, rule.Name)
+		}
+		names[rule.Name] = true
+	}
+
+	for i := range c.Receivers {
+		if err := c.Receivers[i].validate(); err != nil {
+			return fmt.Errorf("receiver %d (%s): %w", i+1, c.Receivers[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a rule and fills in defaults
+func (r *Rule) validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if (r.Metric == "") == (r.HealthCheck == "") {
+		return fmt.Errorf("exactly one of metric and health_check is required")
+	}
+	if r.Severity == "" {
+		r.Severity = SeverityWarning
+	}
+	if !slices.Contains(Severities, r.Severity) {
+		return fmt.Errorf("severity must be one of: %s", strings.Join(Severities, ", "))
+	}
+	if r.For < 0 {
+		return fmt.Errorf("for must not be negative")
+	}
+
+	if r.Metric != "" {
+		if _, err := path.Match(r.Metric, ""); err != nil {
+			return fmt.Errorf("invalid metric pattern %q", r.Metric)
+		}
+		if r.Aggregation == "" {
+			r.Aggregation = "last"
+		}
+		if !slices.Contains(Aggregations, r.Aggregation) {
+			return fmt.Errorf("aggregation must be one of: %s", strings.Join(Aggregations, ", "))
+		}
+		if r.Window == 0 {
+			r.Window = DefaultWindow
+		}
+		if r.Window < time.Second {
+			return fmt.Errorf("window must be at least 1s")
+		}
+		if !slices.Contains(Operators, r.Op) {
+			return fmt.Errorf("op must be one of: %s", strings.Join(Operators, " "))
+		}
+	} else {
+		if _, err := path.Match(r.HealthCheck, ""); err != nil {
+			return fmt.Errorf("invalid health_check pattern %q", r.HealthCheck)
+		}
+		if r.Status == "" {
+			r.Status = "unhealthy"
+		}
+		if r.Status != "degraded" && r.Status != "unhealthy" {
+			return fmt.Errorf("status must be degraded or unhealthy")
+		}
+	}
+
+	if r.Summary != "" {
+		tmpl, err := template.New(r.Name).Option("missingkey=zero").Parse(r.Summary)
+		if err != nil {
+			return fmt.Errorf("invalid summary template: %w", err)
+		}
+		r.summary = tmpl
+	}
+	return nil
+}
+
+// validate checks a receiver and fills in defaults
+func (r *Receiver) validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	if r.Format == "" {
+		r.Format = FormatJSON
+	}
+	if !slices.Contains(Formats, r.Format) {
+		return fmt.Errorf("format must be one of: %s", strings.Join(Formats, ", "))
+	}
+	for _, severity := range r.Severities {
+		if !slices.Contains(Severities, severity) {
+			return fmt.Errorf("severities must be among: %s", strings.Join(Severities, ", "))
+		}
+	}
+	if r.Timeout == 0 {
+		r.Timeout = defaultWebhookTimeout
+	}
+	return nil
+}
+
+// compare applies the rule operator
+func (r *Rule) compare(value float64) bool {
+	switch r.Op {
+	case ">":
+		return value > r.Threshold
+	case ">=":
+		return value >= r.Threshold
+	case "<":
+		return value < r.Threshold
+	case "<=":
+		return value <= r.Threshold
+	case "==":
+		return value == r.Threshold
+	case "!=":
+		return value != r.Threshold
+	}
+	return false
+}
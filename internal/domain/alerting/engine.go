@@ -0,0 +1,484 @@
+package alerting
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-mcp/internal/domain/metrics"
+	"mini-mcp/internal/health"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// Alert states
+const (
+	StatePending  = "pending"
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// Alert sources
+const (
+	SourceMetrics = "metrics"
+	SourceHealth  = "health"
+)
+
+// MetricSource answers queries over the recorded metrics history
+type MetricSource interface {
+	Query(q metrics.Query, now time.Time) (*resources.MetricQueryResult, error)
+}
+
+// HealthSource reports the current health check results
+type HealthSource interface {
+	CheckHealth(ctx context.Context) *health.HealthInfo
+}
+
+// Filter selects alerts for Alerts
+type Filter struct {
+	// States keeps alerts in these states; empty keeps pending and firing alerts
+	States []string
+	// MinSeverity keeps alerts at this severity or above
+	MinSeverity string
+}
+
+// instance is one series or check a rule evaluated, with whether its condition holds
+type instance struct {
+	resourceID string
+	labels     map[string]string
+	value      float64
+	active     bool
+	detail     string
+}
+
+// alertState is the tracked state of one alert
+type alertState struct {
+	alert        resources.Alert
+	lastNotified time.Time
+}
+
+// Engine evaluates rules on an interval and notifies receivers of state changes.
+// Alerts are deduplicated by fingerprint, the hash of the rule name and the
+// series or check labels, so each condition has at most one alert.
+type Engine struct {
+	cfg      *Config
+	metrics  MetricSource
+	health   HealthSource
+	notifier *notifier
+	logger   logging.Logger
+
+	mu     sync.RWMutex
+	alerts map[string]*alertState
+	rules  map[string]*resources.AlertRuleStatus
+}
+
+// NewEngine creates an engine for a validated configuration. metricSource may
+// be nil when no rule uses metrics, healthSource when no rule uses health checks.
+func NewEngine(cfg *Config, metricSource MetricSource, healthSource HealthSource, logger logging.Logger) (*Engine, error) {
+	e := &Engine{
+		cfg:      cfg,
+		metrics:  metricSource,
+		health:   healthSource,
+		notifier: newNotifier(logger),
+		logger:   logger,
+		alerts:   make(map[string]*alertState),
+		rules:    make(map[string]*resources.AlertRuleStatus),
+	}
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		status := &resources.AlertRuleStatus{Name: rule.Name, Severity: rule.Severity, Expression: rule.expression()}
+		if rule.For > 0 {
+			status.For = rule.For.String()
+		}
+		if rule.Metric != "" {
+			status.Source = SourceMetrics
+			if metricSource == nil {
+				return nil, fmt.Errorf("rule %s uses metrics but the metrics sampler is disabled", rule.Name)
+			}
+		} else {
+			status.Source = SourceHealth
+			if healthSource == nil {
+				return nil, fmt.Errorf("rule %s uses health checks but no health checker is configured", rule.Name)
+			}
+		}
+		e.rules[rule.Name] = status
+	}
+	return e, nil
+}
+
+// Run evaluates the rules every evaluation interval until ctx is cancelled
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.EvaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Evaluate(ctx, time.Now())
+		}
+	}
+}
+
+// Evaluate evaluates every rule once at now, advances alert states and
+// delivers the alerts that started firing, repeat or resolved
+func (e *Engine) Evaluate(ctx context.Context, now time.Time) {
+	var healthInfo *health.HealthInfo
+	var notify []resources.Alert
+
+	for i := range e.cfg.Rules {
+		rule := &e.cfg.Rules[i]
+		var instances []instance
+		var err error
+		if rule.Metric != "" {
+			instances, err = e.evaluateMetric(rule, now)
+		} else {
+			if healthInfo == nil {
+				healthInfo = e.health.CheckHealth(ctx)
+			}
+			instances = evaluateHealth(rule, healthInfo)
+		}
+
+		e.mu.Lock()
+		status := e.rules[rule.Name]
+		evaluatedAt := now
+		status.LastEvaluation = &evaluatedAt
+		status.LastError = ""
+		if err != nil {
+			// Keep the existing alerts of a rule that cannot be evaluated
+			status.LastError = err.Error()
+			e.mu.Unlock()
+			e.logger.Warning("Alert rule evaluation failed", map[string]any{"rule": rule.Name, "error": err.Error()})
+			continue
+		}
+		notify = append(notify, e.advance(rule, instances, now)...)
+		status.Active = e.countActive(rule.Name)
+		e.mu.Unlock()
+	}
+
+	e.mu.Lock()
+	e.expireResolved(now)
+	firing := e.firing()
+	e.mu.Unlock()
+
+	if len(notify) > 0 || len(firing) > 0 {
+		e.notifier.deliver(ctx, e.cfg.Receivers, notify, firing)
+	}
+}
+
+// evaluateMetric reduces the window of every matching series to one value
+func (e *Engine) evaluateMetric(rule *Rule, now time.Time) ([]instance, error) {
+	result, err := e.metrics.Query(metrics.Query{
+		Metric:    rule.Metric,
+		Labels:    rule.Match,
+		Since:     now.Add(-rule.Window),
+		Until:     now,
+		MaxPoints: 1,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	if result.Truncated {
+		e.logger.Warning("Alert rule matches too many series; some are not evaluated", map[string]any{"rule": rule.Name})
+	}
+
+	instances := make([]instance, 0, len(result.Series))
+	for _, series := range result.Series {
+		var value float64
+		switch rule.Aggregation {
+		case "avg":
+			value = series.Avg
+		case "min":
+			value = series.Min
+		case "max":
+			value = series.Max
+		case AggregationP95:
+			value = series.P95
+		default:
+			value = series.Values["last"]
+		}
+		labels := map[string]string{"metric": series.Name}
+		for k, v := range series.Labels {
+			labels[k] = v
+		}
+		instances = append(instances, instance{
+			resourceID: seriesID(series.Name, series.Labels),
+			labels:     labels,
+			value:      value,
+			active:     rule.compare(value),
+		})
+	}
+	return instances, nil
+}
+
+// evaluateHealth compares every matching check and dependency with the rule status
+func evaluateHealth(rule *Rule, info *health.HealthInfo) []instance {
+	threshold := slices.Index(healthStatuses, rule.Status)
+	var instances []instance
+	add := func(name string, status health.HealthStatus, message string) {
+		if ok, _ := path.Match(rule.HealthCheck, name); !ok {
+			return
+		}
+		rank := slices.Index(healthStatuses, string(status))
+		instances = append(instances, instance{
+			resourceID: name,
+			labels:     map[string]string{"check": name},
+			value:      float64(rank),
+			active:     rank >= threshold,
+			detail:     fmt.Sprintf("health check %s is %s: %s", name, status, message),
+		})
+	}
+	for name, result := range info.Checks {
+		add(name, result.Status, result.Message)
+	}
+	for _, dep := range info.Dependencies {
+		add(dep.Name, dep.Status, dep.Message)
+	}
+	return instances
+}
+
+// advance moves the alerts of a rule through their states and returns those
+// to notify about; the caller holds the lock
+func (e *Engine) advance(rule *Rule, instances []instance, now time.Time) []resources.Alert {
+	var notify []resources.Alert
+	active := make(map[string]bool)
+
+	for _, inst := range instances {
+		if !inst.active {
+			continue
+		}
+		labels := alertLabels(rule, inst)
+		fp := fingerprint(labels)
+		active[fp] = true
+
+		state, exists := e.alerts[fp]
+		if !exists || state.alert.State == StateResolved {
+			state = &alertState{alert: resources.Alert{
+				Name:        rule.Name,
+				Severity:    rule.Severity,
+				Source:      sourceOf(rule),
+				ResourceID:  inst.resourceID,
+				Fingerprint: fp,
+				Labels:      labels,
+				Threshold:   rule.threshold(),
+				Timestamp:   now,
+				State:       StatePending,
+			}}
+			e.alerts[fp] = state
+		}
+		state.alert.Value = inst.value
+		state.alert.Message = rule.message(inst)
+
+		switch state.alert.State {
+		case StatePending:
+			if now.Sub(state.alert.Timestamp) >= rule.For {
+				firedAt := now
+				state.alert.State = StateFiring
+				state.alert.FiredAt = &firedAt
+				state.lastNotified = now
+				notify = append(notify, state.alert)
+			}
+		case StateFiring:
+			if e.cfg.RepeatInterval > 0 && now.Sub(state.lastNotified) >= e.cfg.RepeatInterval {
+				state.lastNotified = now
+				notify = append(notify, state.alert)
+			}
+		}
+	}
+
+	// Alerts whose condition no longer holds, or whose series or check is gone
+	for fp, state := range e.alerts {
+		if state.alert.Name != rule.Name || state.alert.State == StateResolved || active[fp] {
+			continue
+		}
+		if state.alert.State == StatePending {
+			delete(e.alerts, fp)
+			continue
+		}
+		resolvedAt := now
+		state.alert.State = StateResolved
+		state.alert.ResolvedAt = &resolvedAt
+		notify = append(notify, state.alert)
+	}
+	return notify
+}
+
+// countActive counts the pending and firing alerts of a rule; the caller holds the lock
+func (e *Engine) countActive(rule string) int {
+	n := 0
+	for _, state := range e.alerts {
+		if state.alert.Name == rule && state.alert.State != StateResolved {
+			n++
+		}
+	}
+	return n
+}
+
+// firing returns the firing alerts ordered by rule and fingerprint; the caller holds the lock
+func (e *Engine) firing() []resources.Alert {
+	var alerts []resources.Alert
+	for _, state := range e.alerts {
+		if state.alert.State == StateFiring {
+			alerts = append(alerts, state.alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Name != alerts[j].Name {
+			return alerts[i].Name < alerts[j].Name
+		}
+		return alerts[i].Fingerprint < alerts[j].Fingerprint
+	})
+	return alerts
+}
+
+// expireResolved forgets alerts resolved longer than the retention ago; the caller holds the lock
+func (e *Engine) expireResolved(now time.Time) {
+	for fp, state := range e.alerts {
+		if state.alert.State == StateResolved && now.Sub(*state.alert.ResolvedAt) > e.cfg.ResolvedRetention {
+			delete(e.alerts, fp)
+		}
+	}
+}
+
+// Alerts returns the tracked alerts matching the filter, most severe and then oldest first
+func (e *Engine) Alerts(filter Filter) []resources.Alert {
+	states := filter.States
+	if len(states) == 0 {
+		states = []string{StatePending, StateFiring}
+	}
+	minSeverity := slices.Index(Severities, filter.MinSeverity)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	alerts := make([]resources.Alert, 0, len(e.alerts))
+	for _, state := range e.alerts {
+		if !slices.Contains(states, state.alert.State) || slices.Index(Severities, state.alert.Severity) < minSeverity {
+			continue
+		}
+		alerts = append(alerts, state.alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		si, sj := slices.Index(Severities, alerts[i].Severity), slices.Index(Severities, alerts[j].Severity)
+		if si != sj {
+			return si > sj
+		}
+		if !alerts[i].Timestamp.Equal(alerts[j].Timestamp) {
+			return alerts[i].Timestamp.Before(alerts[j].Timestamp)
+		}
+		return alerts[i].Fingerprint < alerts[j].Fingerprint
+	})
+	return alerts
+}
+
+// Report returns the alerts matching the filter with the firing and pending
+// totals, and the rule statuses when includeRules is set
+func (e *Engine) Report(filter Filter, includeRules bool) *resources.AlertsReport {
+	report := &resources.AlertsReport{Alerts: e.Alerts(filter)}
+	for _, alert := range e.Alerts(Filter{}) {
+		switch alert.State {
+		case StateFiring:
+			report.Firing++
+		case StatePending:
+			report.Pending++
+		}
+	}
+	if includeRules {
+		report.Rules = e.Rules()
+	}
+	return report
+}
+
+// Rules reports every rule with its last evaluation, in configuration order
+func (e *Engine) Rules() []resources.AlertRuleStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	rules := make([]resources.AlertRuleStatus, 0, len(e.cfg.Rules))
+	for _, rule := range e.cfg.Rules {
+		rules = append(rules, *e.rules[rule.Name])
+	}
+	return rules
+}
+
+// alertLabels combines the rule name, severity and labels with the instance labels
+func alertLabels(rule *Rule, inst instance) map[string]string {
+	labels := map[string]string{"alertname": rule.Name, "severity": rule.Severity}
+	for k, v := range rule.Labels {
+		labels[k] = v
+	}
+	for k, v := range inst.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// fingerprint hashes a label set independently of map order
+func fingerprint(labels map[string]string) string {
+	h := sha256.New()
+	for _, k := range sortedLabelNames(labels) {
+		h.Write([]byte(k + "\x00" + labels[k] + "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// seriesID renders a series as name{label="value",...}
+func seriesID(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	keys := sortedLabelNames(labels)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return name + "{" + strings.Join(parts, ",") + "}"
+}
+
+// sourceOf returns the alert source of a rule
+func sourceOf(rule *Rule) string {
+	if rule.Metric != "" {
+		return SourceMetrics
+	}
+	return SourceHealth
+}
+
+// threshold returns the threshold alerts report; for health rules it is the rank
+// of the rule status (1 degraded, 2 unhealthy), comparable with the alert value
+func (r *Rule) threshold() float64 {
+	if r.Metric != "" {
+		return r.Threshold
+	}
+	return float64(slices.Index(healthStatuses, r.Status))
+}
+
+// expression describes the rule condition
+func (r *Rule) expression() string {
+	if r.Metric != "" {
+		return fmt.Sprintf("%s(%s[%s]) %s %g", r.Aggregation, seriesID(r.Metric, r.Match), r.Window, r.Op, r.Threshold)
+	}
+	return fmt.Sprintf("health(%s) >= %s", r.HealthCheck, r.Status)
+}
+
+// message renders the summary template, or a default description of the condition
+func (r *Rule) message(inst instance) string {
+	if r.summary != nil {
+		var b strings.Builder
+		data := map[string]any{"Value": inst.value, "Threshold": r.Threshold, "Labels": inst.labels}
+		if err := r.summary.Execute(&b, data); err == nil {
+			return b.String()
+		}
+	}
+	if inst.detail != "" {
+		return inst.detail
+	}
+	return fmt.Sprintf("%s %s over %s is %.4g (%s %g)", inst.resourceID, r.Aggregation, r.Window, inst.value, r.Op, r.Threshold)
+}
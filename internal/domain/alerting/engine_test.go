@@ -0,0 +1,319 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/domain/metrics"
+	"mini-mcp/internal/health"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func testLogger() logging.Logger {
+	return logging.NewLogger(io.Discard, logging.LogLevelError)
+}
+
+// receiver records the bodies posted to an httptest server
+type receiver struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	status []int
+}
+
+func newReceiver(t *testing.T, status ...int) (*receiver, string) {
+	t.Helper()
+	r := &receiver{status: status}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.bodies = append(r.bodies, body)
+		if len(r.status) > 0 {
+			w.WriteHeader(r.status[0])
+			r.status = r.status[1:]
+		}
+	}))
+	t.Cleanup(server.Close)
+	return r, server.URL
+}
+
+func (r *receiver) received() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]byte(nil), r.bodies...)
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+evaluation_interval: 10s
+rules:
+  - name: HighMemory
+    metric: memory_used_percent
+    aggregation: avg
+    window: 5m
+    op: ">"
+    threshold: 90
+    for: 2m
+    severity: critical
+    labels: {team: infra}
+  - name: CheckFailing
+    health_check: "*"
+receivers:
+  - name: slack
+    url: https://hooks.slack.com/services/T/B/X
+    format: slack
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, cfg.EvaluationInterval)
+	assert.Equal(t, DefaultRepeatInterval, cfg.RepeatInterval)
+	assert.Equal(t, 5*time.Minute, cfg.Rules[0].Window)
+	assert.Equal(t, 2*time.Minute, cfg.Rules[0].For)
+	assert.Equal(t, SeverityWarning, cfg.Rules[1].Severity)
+	assert.Equal(t, "unhealthy", cfg.Rules[1].Status)
+	assert.Equal(t, defaultWebhookTimeout, cfg.Receivers[0].Timeout)
+
+	invalid := map[string]string{
+		"unknown field":     "rules:\n  - name: a\n    metric: x\n    op: '>'\n    treshold: 1\n",
+		"metric and health": "rules:\n  - name: a\n    metric: x\n    health_check: y\n    op: '>'\n",
+		"missing op":        "rules:\n  - name: a\n    metric: x\n",
+		"bad severity":      "rules:\n  - name: a\n    health_check: y\n    severity: page\n",
+		"bad status":        "rules:\n  - name: a\n    health_check: y\n    status: healthy\n",
+		"duplicate":         "rules:\n  - name: a\n    health_check: y\n  - name: a\n    health_check: z\n",
+		"bad template":      "rules:\n  - name: a\n    health_check: y\n    summary: '{{ .Value'\n",
+		"bad receiver url":  "receivers:\n  - name: r\n    url: ftp://example.com\n",
+		"bad format":        "receivers:\n  - name: r\n    url: http://example.com\n    format: teams\n",
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_Example(t *testing.T) {
+	cfg, err := LoadConfig("../../../alert-rules.yaml.example")
+	require.NoError(t, err)
+	assert.Len(t, cfg.Rules, 3)
+	assert.Len(t, cfg.Receivers, 3)
+}
+
+func TestEngine_MetricRuleLifecycle(t *testing.T) {
+	rec, url := newReceiver(t)
+	cfg, err := ParseConfig([]byte(`
+rules:
+  - name: DiskFull
+    metric: disk_used_percent
+    window: 1m
+    op: ">="
+    threshold: 90
+    for: 2m
+    severity: error
+    labels: {team: storage}
+    summary: "{{ .Labels.mount }} is {{ .Value }}% full"
+receivers:
+  - name: ops
+    url: ` + url + `
+    send_resolved: true
+`))
+	require.NoError(t, err)
+
+	store := metrics.NewStore(metrics.StoreOptions{Interval: 30 * time.Second})
+	engine, err := NewEngine(cfg, store, nil, testLogger())
+	require.NoError(t, err)
+
+	record := func(at time.Duration, root, data float64) time.Time {
+		now := epoch.Add(at)
+		store.Append(now, []metrics.Sample{
+			{Name: "disk_used_percent", Labels: map[string]string{"mount": "/"}, Value: root},
+			{Name: "disk_used_percent", Labels: map[string]string{"mount": "/data"}, Value: data},
+		})
+		return now
+	}
+
+	ctx := context.Background()
+	engine.Evaluate(ctx, record(0, 95, 50))
+	alerts := engine.Alerts(Filter{})
+	require.Len(t, alerts, 1)
+	assert.Equal(t, StatePending, alerts[0].State)
+	assert.Equal(t, `disk_used_percent{mount="/"}`, alerts[0].ResourceID)
+	assert.Equal(t, "/ is 95% full", alerts[0].Message)
+	assert.Equal(t, "storage", alerts[0].Labels["team"])
+	fp := alerts[0].Fingerprint
+
+	// Still pending before the for duration has passed; nothing is sent
+	engine.Evaluate(ctx, record(time.Minute, 96, 50))
+	assert.Equal(t, StatePending, engine.Alerts(Filter{})[0].State)
+	assert.Empty(t, rec.received())
+
+	engine.Evaluate(ctx, record(2*time.Minute, 97, 50))
+	alerts = engine.Alerts(Filter{States: []string{StateFiring}})
+	require.Len(t, alerts, 1)
+	assert.Equal(t, fp, alerts[0].Fingerprint, "the same condition keeps its alert")
+	assert.Equal(t, 97.0, alerts[0].Value)
+	require.NotNil(t, alerts[0].FiredAt)
+	require.Len(t, rec.received(), 1)
+
+	// Repeated evaluations while firing do not notify again before repeat_interval
+	engine.Evaluate(ctx, record(3*time.Minute, 97, 50))
+	assert.Len(t, rec.received(), 1)
+	assert.Equal(t, 1, engine.Rules()[0].Active)
+
+	engine.Evaluate(ctx, record(4*time.Minute, 60, 50))
+	assert.Empty(t, engine.Alerts(Filter{}))
+	resolved := engine.Alerts(Filter{States: []string{StateResolved}})
+	require.Len(t, resolved, 1)
+	require.NotNil(t, resolved[0].ResolvedAt)
+	bodies := rec.received()
+	require.Len(t, bodies, 2)
+
+	var last struct {
+		Status string            `json:"status"`
+		Alerts []resources.Alert `json:"alerts"`
+	}
+	require.NoError(t, json.Unmarshal(bodies[1], &last))
+	assert.Equal(t, StateResolved, last.Status)
+	assert.Equal(t, fp, last.Alerts[0].Fingerprint)
+
+	// Resolved alerts are forgotten after the retention period
+	engine.Evaluate(ctx, record(4*time.Minute+DefaultResolvedRetention+time.Minute, 60, 50))
+	assert.Empty(t, engine.Alerts(Filter{States: []string{StateResolved}}))
+}
+
+func TestEngine_AlertmanagerResendsFiringAlerts(t *testing.T) {
+	hook, hookURL := newReceiver(t)
+	am, amURL := newReceiver(t)
+	cfg, err := ParseConfig([]byte(`
+rules:
+  - name: Busy
+    metric: cpu_percent
+    op: ">"
+    threshold: 80
+receivers:
+  - name: hook
+    url: ` + hookURL + `
+  - name: am
+    url: ` + amURL + `
+    format: alertmanager
+    send_resolved: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRepeatInterval, cfg.RepeatInterval)
+	store := metrics.NewStore(metrics.StoreOptions{Interval: 30 * time.Second})
+	engine, err := NewEngine(cfg, store, nil, testLogger())
+	require.NoError(t, err)
+
+	evaluate := func(at time.Duration, value float64) {
+		store.Append(epoch.Add(at), []metrics.Sample{{Name: "cpu_percent", Value: value}})
+		engine.Evaluate(context.Background(), epoch.Add(at))
+	}
+	decode := func(body []byte) []alertmanagerAlert {
+		var alerts []alertmanagerAlert
+		require.NoError(t, json.Unmarshal(body, &alerts))
+		return alerts
+	}
+
+	evaluate(0, 90)
+	evaluate(30*time.Second, 95)
+	evaluate(time.Minute, 95)
+
+	// The webhook hears of the alert once; Alertmanager on every evaluation
+	assert.Len(t, hook.received(), 1)
+	bodies := am.received()
+	require.Len(t, bodies, 3)
+	for _, body := range bodies {
+		alerts := decode(body)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, "Busy", alerts[0].Labels["alertname"])
+		assert.Equal(t, epoch, alerts[0].StartsAt)
+		assert.Nil(t, alerts[0].EndsAt)
+	}
+
+	// The resolution is sent once, with endsAt, and then nothing more
+	evaluate(90*time.Second, 10)
+	evaluate(2*time.Minute, 10)
+	bodies = am.received()
+	require.Len(t, bodies, 4)
+	resolved := decode(bodies[3])
+	require.Len(t, resolved, 1)
+	require.NotNil(t, resolved[0].EndsAt)
+	assert.Equal(t, epoch.Add(90*time.Second), *resolved[0].EndsAt)
+}
+
+func TestEngine_PendingAlertClearsSilently(t *testing.T) {
+	cfg, err := ParseConfig([]byte("rules:\n  - name: Busy\n    metric: cpu_percent\n    op: '>'\n    threshold: 80\n    for: 5m\n"))
+	require.NoError(t, err)
+	store := metrics.NewStore(metrics.StoreOptions{Interval: 30 * time.Second})
+	engine, err := NewEngine(cfg, store, nil, testLogger())
+	require.NoError(t, err)
+
+	store.Append(epoch, []metrics.Sample{{Name: "cpu_percent", Value: 90}})
+	engine.Evaluate(context.Background(), epoch)
+	require.Len(t, engine.Alerts(Filter{}), 1)
+
+	store.Append(epoch.Add(time.Minute), []metrics.Sample{{Name: "cpu_percent", Value: 10}})
+	engine.Evaluate(context.Background(), epoch.Add(time.Minute))
+	assert.Empty(t, engine.Alerts(Filter{States: []string{StatePending, StateFiring, StateResolved}}))
+}
+
+func TestEngine_HealthRule(t *testing.T) {
+	checker := health.NewHealthChecker("test")
+	status := health.HealthStatusHealthy
+	checker.AddCheck("database", func(ctx context.Context) health.CheckResult {
+		return health.CheckResult{Status: status, Message: "connection refused"}
+	})
+	checker.AddCheck("ping", health.PingCheck())
+
+	cfg, err := ParseConfig([]byte("rules:\n  - name: CheckDegraded\n    health_check: '*'\n    status: degraded\n    severity: critical\n"))
+	require.NoError(t, err)
+	engine, err := NewEngine(cfg, nil, checker, testLogger())
+	require.NoError(t, err)
+
+	engine.Evaluate(context.Background(), epoch)
+	assert.Empty(t, engine.Alerts(Filter{}))
+
+	status = health.HealthStatusUnhealthy
+	engine.Evaluate(context.Background(), epoch.Add(time.Minute))
+	alerts := engine.Alerts(Filter{MinSeverity: SeverityError})
+	require.Len(t, alerts, 1)
+	assert.Equal(t, StateFiring, alerts[0].State)
+	assert.Equal(t, "database", alerts[0].Labels["check"])
+	assert.Equal(t, SourceHealth, alerts[0].Source)
+	assert.Contains(t, alerts[0].Message, "connection refused")
+
+	_, err = NewEngine(cfg, nil, nil, testLogger())
+	assert.Error(t, err, "health rules need a health source")
+}
+
+func TestEngine_Report(t *testing.T) {
+	cfg, err := ParseConfig([]byte("rules:\n  - name: Busy\n    metric: cpu_percent\n    op: '>'\n    threshold: 80\n    for: 1m\n  - name: Swapping\n    metric: swap_used_percent\n    op: '>'\n    threshold: 50\n    severity: critical\n"))
+	require.NoError(t, err)
+	store := metrics.NewStore(metrics.StoreOptions{Interval: 30 * time.Second})
+	engine, err := NewEngine(cfg, store, nil, testLogger())
+	require.NoError(t, err)
+
+	store.Append(epoch, []metrics.Sample{{Name: "cpu_percent", Value: 90}, {Name: "swap_used_percent", Value: 70}})
+	engine.Evaluate(context.Background(), epoch)
+
+	report := engine.Report(Filter{States: []string{StateFiring}}, true)
+	assert.Equal(t, 1, report.Firing)
+	assert.Equal(t, 1, report.Pending)
+	require.Len(t, report.Alerts, 1)
+	assert.Equal(t, "Swapping", report.Alerts[0].Name)
+	require.Len(t, report.Rules, 2)
+	assert.Equal(t, "last(cpu_percent[1m0s]) > 80", report.Rules[0].Expression)
+	assert.Equal(t, 1, report.Rules[0].Active)
+	assert.NotNil(t, report.Rules[0].LastEvaluation)
+}
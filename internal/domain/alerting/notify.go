@@ -0,0 +1,274 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+const (
+	// deliveryAttempts is how often a webhook is tried before the batch is dropped
+	deliveryAttempts = 3
+	// retryBackoff is the wait before the second attempt; it doubles after each failure
+	retryBackoff = 500 * time.Millisecond
+)
+
+// notifier posts alert batches to webhook receivers
+type notifier struct {
+	client  *http.Client
+	logger  logging.Logger
+	backoff time.Duration
+}
+
+// newNotifier creates a notifier; per-receiver timeouts apply through the request context
+func newNotifier(logger logging.Logger) *notifier {
+	return &notifier{client: &http.Client{}, logger: logger, backoff: retryBackoff}
+}
+
+// deliver sends each receiver the alerts it accepts out of the alerts that
+// started firing, repeat or resolved. Alertmanager receivers also get every
+// firing alert again, as Alertmanager resolves alerts that stop being sent.
+// Failures are logged and do not stop delivery to the other receivers.
+func (n *notifier) deliver(ctx context.Context, receivers []Receiver, alerts, firing []resources.Alert) {
+	for i := range receivers {
+		receiver := &receivers[i]
+		candidates := alerts
+		if receiver.Format == FormatAlertmanager {
+			candidates = withFiring(alerts, firing)
+		}
+		var batch []resources.Alert
+		changed := 0
+		for j, alert := range candidates {
+			if receiver.accepts(alert) {
+				batch = append(batch, alert)
+				if j < len(alerts) {
+					changed++
+				}
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		if err := n.send(ctx, receiver, batch); err != nil {
+			n.logger.Error("Alert delivery failed", err, map[string]any{"receiver": receiver.Name, "alerts": len(batch)})
+			continue
+		}
+		// Re-sending firing alerts to Alertmanager on every evaluation is routine
+		if changed == 0 {
+			n.logger.Debug("Firing alerts re-sent", map[string]any{"receiver": receiver.Name, "alerts": len(batch)})
+			continue
+		}
+		n.logger.Info("Alerts delivered", map[string]any{"receiver": receiver.Name, "alerts": len(batch)})
+	}
+}
+
+// withFiring adds the firing alerts missing from alerts
+func withFiring(alerts, firing []resources.Alert) []resources.Alert {
+	sent := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		sent[alert.Fingerprint] = true
+	}
+	out := slices.Clone(alerts)
+	for _, alert := range firing {
+		if !sent[alert.Fingerprint] {
+			out = append(out, alert)
+		}
+	}
+	return out
+}
+
+// accepts reports whether the receiver takes an alert
+func (r *Receiver) accepts(alert resources.Alert) bool {
+	if alert.State == StateResolved && !r.SendResolved {
+		return false
+	}
+	return len(r.Severities) == 0 || slices.Contains(r.Severities, alert.Severity)
+}
+
+// send posts one batch, retrying transport errors and 5xx or 429 responses
+func (n *notifier) send(ctx context.Context, receiver *Receiver, alerts []resources.Alert) error {
+	body, err := payload(receiver.Format, alerts)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(ctx, receiver, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == deliveryAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (n *notifier) post(ctx context.Context, receiver *Receiver, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, receiver.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, receiver.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mini-mcp-alerting")
+	for k, v := range receiver.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+// payload encodes alerts in a receiver format
+func payload(format string, alerts []resources.Alert) ([]byte, error) {
+	switch format {
+	case FormatSlack:
+		return json.Marshal(slackPayload(alerts))
+	case FormatAlertmanager:
+		return json.Marshal(alertmanagerPayload(alerts))
+	default:
+		status := StateResolved
+		for _, alert := range alerts {
+			if alert.State == StateFiring {
+				status = StateFiring
+				break
+			}
+		}
+		return json.Marshal(map[string]any{"status": status, "alerts": alerts})
+	}
+}
+
+// slackMessage is a Slack incoming-webhook message
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// slackAttachment is a colored block describing one alert
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Title  string       `json:"title"`
+	Text   string       `json:"text"`
+	Fields []slackField `json:"fields,omitempty"`
+	Ts     int64        `json:"ts"`
+}
+
+// slackField is a label shown in an attachment
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// slackPayload builds a message with one attachment per alert
+func slackPayload(alerts []resources.Alert) slackMessage {
+	firing := 0
+	for _, alert := range alerts {
+		if alert.State == StateFiring {
+			firing++
+		}
+	}
+	msg := slackMessage{Text: fmt.Sprintf("%d firing, %d resolved", firing, len(alerts)-firing)}
+
+	for _, alert := range alerts {
+		color := "good"
+		ts := alert.Timestamp
+		if alert.State == StateFiring {
+			color = "warning"
+			if alert.Severity == SeverityError || alert.Severity == SeverityCritical {
+				color = "danger"
+			}
+			if alert.FiredAt != nil {
+				ts = *alert.FiredAt
+			}
+		} else if alert.ResolvedAt != nil {
+			ts = *alert.ResolvedAt
+		}
+
+		attachment := slackAttachment{
+			Color: color,
+			Title: fmt.Sprintf("[%s] %s (%s)", strings.ToUpper(alert.State), alert.Name, alert.Severity),
+			Text:  alert.Message,
+			Ts:    ts.Unix(),
+		}
+		for _, k := range sortedLabelNames(alert.Labels) {
+			if k == "alertname" || k == "severity" {
+				continue
+			}
+			attachment.Fields = append(attachment.Fields, slackField{Title: k, Value: alert.Labels[k], Short: true})
+		}
+		msg.Attachments = append(msg.Attachments, attachment)
+	}
+	return msg
+}
+
+// alertmanagerAlert is an alert as accepted by POST /api/v2/alerts
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// alertmanagerPayload builds the Alertmanager v2 alert list; resolved alerts carry endsAt
+func alertmanagerPayload(alerts []resources.Alert) []alertmanagerAlert {
+	out := make([]alertmanagerAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		startsAt := alert.Timestamp
+		if alert.FiredAt != nil {
+			startsAt = *alert.FiredAt
+		}
+		out = append(out, alertmanagerAlert{
+			Labels: alert.Labels,
+			Annotations: map[string]string{
+				"summary":  alert.Message,
+				"value":    fmt.Sprintf("%g", alert.Value),
+				"resource": alert.ResourceID,
+			},
+			StartsAt: startsAt,
+			EndsAt:   alert.ResolvedAt,
+		})
+	}
+	return out
+}
+
+// sortedLabelNames returns label names in order
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
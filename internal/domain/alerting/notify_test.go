@@ -0,0 +1,86 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAlerts() []resources.Alert {
+	firedAt := epoch.Add(time.Minute)
+	resolvedAt := epoch.Add(time.Hour)
+	return []resources.Alert{
+		{
+			Name: "DiskFull", Severity: SeverityCritical, State: StateFiring, Message: "/ is 97% full",
+			Labels: map[string]string{"alertname": "DiskFull", "severity": SeverityCritical, "mount": "/"},
+			Value:  97, Timestamp: epoch, FiredAt: &firedAt,
+		},
+		{
+			Name: "Busy", Severity: SeverityInfo, State: StateResolved, Message: "cpu is busy",
+			Labels: map[string]string{"alertname": "Busy", "severity": SeverityInfo},
+			Value:  10, Timestamp: epoch, FiredAt: &firedAt, ResolvedAt: &resolvedAt,
+		},
+	}
+}
+
+func TestNotifier_Formats(t *testing.T) {
+	slack, slackURL := newReceiver(t)
+	am, amURL := newReceiver(t)
+	receivers := []Receiver{
+		{Name: "slack", URL: slackURL, Format: FormatSlack, Timeout: time.Second, SendResolved: true},
+		{Name: "alertmanager", URL: amURL, Format: FormatAlertmanager, Timeout: time.Second, SendResolved: true},
+	}
+	newNotifier(testLogger()).deliver(context.Background(), receivers, testAlerts(), nil)
+
+	require.Len(t, slack.received(), 1)
+	var msg slackMessage
+	require.NoError(t, json.Unmarshal(slack.received()[0], &msg))
+	assert.Equal(t, "1 firing, 1 resolved", msg.Text)
+	require.Len(t, msg.Attachments, 2)
+	assert.Equal(t, "danger", msg.Attachments[0].Color)
+	assert.Equal(t, "[FIRING] DiskFull (critical)", msg.Attachments[0].Title)
+	assert.Equal(t, []slackField{{Title: "mount", Value: "/", Short: true}}, msg.Attachments[0].Fields)
+	assert.Equal(t, "good", msg.Attachments[1].Color)
+
+	require.Len(t, am.received(), 1)
+	var posted []map[string]any
+	require.NoError(t, json.Unmarshal(am.received()[0], &posted))
+	require.Len(t, posted, 2)
+	assert.Equal(t, "DiskFull", posted[0]["labels"].(map[string]any)["alertname"])
+	assert.Equal(t, "/ is 97% full", posted[0]["annotations"].(map[string]any)["summary"])
+	assert.NotContains(t, posted[0], "endsAt")
+	assert.Equal(t, epoch.Add(time.Hour).Format(time.RFC3339), posted[1]["endsAt"])
+}
+
+func TestNotifier_FiltersAndRetries(t *testing.T) {
+	flaky, flakyURL := newReceiver(t, http.StatusServiceUnavailable, http.StatusOK)
+	rejecting, rejectingURL := newReceiver(t, http.StatusBadRequest)
+	receivers := []Receiver{
+		// Resolved alerts are only sent with send_resolved
+		{Name: "flaky", URL: flakyURL, Format: FormatJSON, Timeout: time.Second},
+		// Client errors are not retried
+		{Name: "rejecting", URL: rejectingURL, Format: FormatJSON, Timeout: time.Second, Severities: []string{SeverityCritical}},
+	}
+	n := newNotifier(testLogger())
+	n.backoff = time.Millisecond
+	n.deliver(context.Background(), receivers, testAlerts(), nil)
+
+	require.Len(t, flaky.received(), 2)
+	var body struct {
+		Status string            `json:"status"`
+		Alerts []resources.Alert `json:"alerts"`
+	}
+	require.NoError(t, json.Unmarshal(flaky.received()[1], &body))
+	assert.Equal(t, StateFiring, body.Status)
+	require.Len(t, body.Alerts, 1)
+	assert.Equal(t, "DiskFull", body.Alerts[0].Name)
+
+	assert.Len(t, rejecting.received(), 1)
+}
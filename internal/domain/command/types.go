@@ -7,6 +7,9 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/tracing"
 )
 
 var (
@@ -57,7 +60,10 @@ func (r *RepositoryImpl) Execute(ctx context.Context, cmd *Command) (*Result, er
 	// Execute command
 	start := time.Now()
 	execCmd := exec.CommandContext(ctx, "sh", "-c", cmd.Command)
+	execCmd.Env = logging.CommandEnv(ctx, nil)
+	_, span := tracing.StartExec(ctx, "sh", []string{"-c", cmd.Command})
 	output, err := execCmd.Output()
+	tracing.EndExec(span, err)
 
 	duration := time.Since(start)
 
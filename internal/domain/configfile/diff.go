@@ -0,0 +1,161 @@
+package configfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around each change
+	diffContext = 3
+	// maxDiffCells bounds the LCS table; larger inputs get a summary instead of a diff
+	maxDiffCells = 25_000_000
+)
+
+// diffOp is a single line of an edit script
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// unifiedDiff renders a unified diff between two texts
+func unifiedDiff(name string, before, after []byte) string {
+	if string(before) == string(after) {
+		return ""
+	}
+
+	a, b := splitDiffLines(string(before)), splitDiffLines(string(after))
+	if len(a)*len(b) > maxDiffCells {
+		return fmt.Sprintf("--- a/%s\n+++ b/%s\n(diff omitted: %d lines before, %d lines after)\n", name, name, len(a), len(b))
+	}
+
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context of each other
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from := max(start-diffContext, 0)
+		to := min(end+diffContext, len(ops))
+
+		aStart, bStart := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				aStart++
+			}
+			if op.kind != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		if aLen == 0 {
+			aStart--
+		}
+		if bLen == 0 {
+			bStart--
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, op := range ops[from:to] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.text)
+			out.WriteByte('\n')
+		}
+
+		start = to
+	}
+
+	return out.String()
+}
+
+// splitDiffLines splits text into lines without terminators
+func splitDiffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a minimal line edit script using a longest common subsequence table
+func diffLines(a, b []string) []diffOp {
+	// Common prefix and suffix are trimmed first; config edits are usually small
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(ma) && j < len(mb) {
+		switch {
+		case ma[i] == mb[j]:
+			ops = append(ops, diffOp{' ', ma[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', ma[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', mb[j]})
+			j++
+		}
+	}
+	for ; i < len(ma); i++ {
+		ops = append(ops, diffOp{'-', ma[i]})
+	}
+	for ; j < len(mb); j++ {
+		ops = append(ops, diffOp{'+', mb[j]})
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
@@ -0,0 +1,252 @@
+package configfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// iniDocument edits INI files line by line. Paths are "key" for keys before
+// the first section and "section.key" otherwise; a section name on its own
+// addresses the whole section.
+type iniDocument struct {
+	lines []string
+}
+
+// iniLine is a classified INI line
+type iniLine struct {
+	section string
+	key     string
+	isKey   bool
+	header  bool
+	// valueCol is the byte offset of the value on a key line
+	valueCol int
+}
+
+// parseINI parses an INI document, rejecting lines that are neither
+// sections, assignments nor comments
+func parseINI(data []byte) (*iniDocument, error) {
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	var lines []string
+	if text != "" {
+		lines = strings.Split(text, "\n")
+	}
+
+	doc := &iniDocument{lines: lines}
+	for i, line := range lines {
+		if _, ok := classifyINILine(line, ""); !ok {
+			return nil, fmt.Errorf("line %d: expected [section], key = value or a comment", i+1)
+		}
+	}
+	return doc, nil
+}
+
+// classifyINILine parses one line within the given section
+func classifyINILine(line, section string) (iniLine, bool) {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#"):
+		return iniLine{section: section}, true
+	case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+		return iniLine{section: strings.TrimSpace(trimmed[1 : len(trimmed)-1]), header: true}, true
+	}
+
+	sep := strings.IndexAny(line, "=:")
+	if sep <= 0 || strings.TrimSpace(line[:sep]) == "" {
+		return iniLine{}, false
+	}
+	valueCol := sep + 1
+	for valueCol < len(line) && (line[valueCol] == ' ' || line[valueCol] == '\t') {
+		valueCol++
+	}
+	return iniLine{
+		section:  section,
+		key:      strings.TrimSpace(line[:sep]),
+		isKey:    true,
+		valueCol: valueCol,
+	}, true
+}
+
+// scan classifies every line, tracking the current section
+func (d *iniDocument) scan() []iniLine {
+	out := make([]iniLine, len(d.lines))
+	section := ""
+	for i, line := range d.lines {
+		parsed, _ := classifyINILine(line, section)
+		if parsed.header {
+			section = parsed.section
+		}
+		out[i] = parsed
+	}
+	return out
+}
+
+// splitINIPath maps a key path onto a section and key
+func splitINIPath(path keyPath) (string, string, error) {
+	parts := make([]string, len(path))
+	for i, seg := range path {
+		if seg.isIndex {
+			return "", "", fmt.Errorf("%s: list indexes are not supported in INI files", path)
+		}
+		parts[i] = seg.key
+	}
+	if len(parts) == 1 {
+		return "", parts[0], nil
+	}
+	return strings.Join(parts[:len(parts)-1], "."), parts[len(parts)-1], nil
+}
+
+// Get returns the value at path, or a whole section as a map
+func (d *iniDocument) Get(path keyPath) (any, error) {
+	section, key, err := splitINIPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := d.scan()
+	for i, l := range lines {
+		if l.isKey && l.section == section && l.key == key {
+			return strings.TrimSpace(d.lines[i][l.valueCol:]), nil
+		}
+	}
+
+	// A single-segment path may name a section
+	if section == "" {
+		values := make(map[string]any)
+		found := false
+		for i, l := range lines {
+			if l.header && l.section == key {
+				found = true
+			}
+			if l.isKey && l.section == key {
+				values[l.key] = strings.TrimSpace(d.lines[i][l.valueCol:])
+			}
+		}
+		if found {
+			return values, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+}
+
+// Set replaces a value in place or appends the key to its section
+func (d *iniDocument) Set(path keyPath, value any) error {
+	section, key, err := splitINIPath(path)
+	if err != nil {
+		return err
+	}
+	encoded, err := iniValue(value)
+	if err != nil {
+		return err
+	}
+
+	lines := d.scan()
+	for i, l := range lines {
+		if l.isKey && l.section == section && l.key == key {
+			d.lines[i] = d.lines[i][:l.valueCol] + encoded
+			return nil
+		}
+	}
+
+	separator := " = "
+	for i, l := range lines {
+		if l.isKey {
+			line := d.lines[i]
+			start := strings.Index(line, l.key) + len(l.key)
+			separator = line[start:l.valueCol]
+			break
+		}
+	}
+	entry := key + separator + encoded
+
+	// Insert after the last assignment of the section
+	at, headerAt := -1, -1
+	for i, l := range lines {
+		if l.header && l.section == section {
+			headerAt = i
+		}
+		if l.isKey && l.section == section {
+			at = i + 1
+		}
+	}
+	switch {
+	case at >= 0:
+	case section == "":
+		at = 0
+		for at < len(lines) && !lines[at].header && strings.TrimSpace(d.lines[at]) != "" {
+			at++
+		}
+	case headerAt >= 0:
+		at = headerAt + 1
+	default:
+		// New section at the end of the file
+		if len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) != "" {
+			d.lines = append(d.lines, "")
+		}
+		d.lines = append(d.lines, "["+section+"]", entry)
+		return nil
+	}
+
+	d.lines = append(d.lines[:at], append([]string{entry}, d.lines[at:]...)...)
+	return nil
+}
+
+// Delete removes a key, or a whole section when the path names one
+func (d *iniDocument) Delete(path keyPath) error {
+	section, key, err := splitINIPath(path)
+	if err != nil {
+		return err
+	}
+
+	lines := d.scan()
+	for i, l := range lines {
+		if l.isKey && l.section == section && l.key == key {
+			d.lines = append(d.lines[:i], d.lines[i+1:]...)
+			return nil
+		}
+	}
+
+	if section == "" {
+		for i, l := range lines {
+			if !l.header || l.section != key {
+				continue
+			}
+			end := i + 1
+			for end < len(lines) && !lines[end].header {
+				end++
+			}
+			d.lines = append(d.lines[:i], d.lines[end:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+}
+
+// Bytes returns the edited document
+func (d *iniDocument) Bytes() ([]byte, error) {
+	if len(d.lines) == 0 {
+		return nil, nil
+	}
+	return []byte(strings.Join(d.lines, "\n") + "\n"), nil
+}
+
+// iniValue renders a scalar as an INI value
+func iniValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, "\r\n") {
+			return "", fmt.Errorf("INI values cannot contain newlines")
+		}
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	default:
+		return "", fmt.Errorf("INI values must be strings, numbers or booleans, got %T", value)
+	}
+}
@@ -0,0 +1,395 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonDocument edits JSON by splicing text at the byte spans of values,
+// so untouched parts of the file keep their exact formatting and key order
+type jsonDocument struct {
+	data   []byte
+	root   *jsonNode
+	indent string
+	pretty bool
+}
+
+// jsonNode is a parsed value with its byte span in the source
+type jsonNode struct {
+	kind     byte // '{', '[' or 'v' for scalars
+	start    int
+	end      int
+	members  []jsonMember
+	elements []*jsonNode
+}
+
+// jsonMember is an object member with the span of its key
+type jsonMember struct {
+	key      string
+	keyStart int
+	keyEnd   int
+	value    *jsonNode
+}
+
+// parseJSON parses a JSON document, recording value spans
+func parseJSON(data []byte) (*jsonDocument, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("{}\n")
+	}
+	if !json.Valid(data) {
+		var v any
+		err := json.Unmarshal(data, &v)
+		return nil, err
+	}
+
+	p := &jsonParser{data: data}
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &jsonDocument{data: data, root: root}
+	doc.indent, doc.pretty = detectJSONIndent(data)
+	return doc, nil
+}
+
+// detectJSONIndent returns the indentation unit and whether the document spans multiple lines
+func detectJSONIndent(data []byte) (string, bool) {
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)], true
+		}
+	}
+	return "  ", bytes.Count(bytes.TrimSpace(data), []byte("\n")) > 0
+}
+
+// Get decodes the value at path
+func (d *jsonDocument) Get(path keyPath) (any, error) {
+	node, err := d.find(path)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(d.data[node.start:node.end], &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Set replaces or inserts the value at path
+func (d *jsonDocument) Set(path keyPath, value any) error {
+	node := d.root
+	for i, seg := range path {
+		var next *jsonNode
+		switch {
+		case node.kind == '{' && !seg.isIndex:
+			for _, m := range node.members {
+				if m.key == seg.key {
+					next = m.value
+				}
+			}
+		case node.kind == '[' && seg.isIndex && seg.index < len(node.elements):
+			next = node.elements[seg.index]
+		case node.kind == '{':
+			return fmt.Errorf("%s: expected a key, found an object", path[:i+1])
+		case node.kind == '[' && !seg.isIndex:
+			return fmt.Errorf("%s: expected an index, found an array", path[:i+1])
+		case node.kind == 'v':
+			return fmt.Errorf("%s: cannot descend into a scalar value", path[:i+1])
+		}
+
+		if next == nil {
+			nested, err := nestValue(path[i+1:], value)
+			if err != nil {
+				return err
+			}
+			if node.kind == '[' && seg.index != len(node.elements) {
+				return fmt.Errorf("%s: index out of range (array has %d elements)", path[:i+1], len(node.elements))
+			}
+			return d.insert(node, seg, nested)
+		}
+		node = next
+	}
+
+	encoded, err := d.encode(value, lineIndent(d.data, node.start))
+	if err != nil {
+		return err
+	}
+	return d.splice(node.start, node.end, encoded)
+}
+
+// Delete removes the member or element at path
+func (d *jsonDocument) Delete(path keyPath) error {
+	parent, err := d.find(path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	seg := path[len(path)-1]
+
+	// spans holds [start, end) of each child including its key
+	var spans [][2]int
+	idx := -1
+	switch {
+	case parent.kind == '{' && !seg.isIndex:
+		for i, m := range parent.members {
+			spans = append(spans, [2]int{m.keyStart, m.value.end})
+			if m.key == seg.key {
+				idx = i
+			}
+		}
+	case parent.kind == '[' && seg.isIndex:
+		for _, e := range parent.elements {
+			spans = append(spans, [2]int{e.start, e.end})
+		}
+		if seg.index < len(spans) {
+			idx = seg.index
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+	}
+
+	switch {
+	case len(spans) == 1:
+		// Removing the only child empties the container
+		return d.splice(parent.start+1, parent.end-1, nil)
+	case idx < len(spans)-1:
+		return d.splice(spans[idx][0], spans[idx+1][0], nil)
+	default:
+		return d.splice(spans[idx-1][1], spans[idx][1], nil)
+	}
+}
+
+// Bytes returns the edited document
+func (d *jsonDocument) Bytes() ([]byte, error) {
+	return d.data, nil
+}
+
+// find returns the node at path
+func (d *jsonDocument) find(path keyPath) (*jsonNode, error) {
+	node := d.root
+	for i, seg := range path {
+		var next *jsonNode
+		switch {
+		case node.kind == '{' && !seg.isIndex:
+			for _, m := range node.members {
+				if m.key == seg.key {
+					next = m.value
+				}
+			}
+		case node.kind == '[' && seg.isIndex && seg.index < len(node.elements):
+			next = node.elements[seg.index]
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, path[:i+1])
+		}
+		node = next
+	}
+	return node, nil
+}
+
+// insert adds a new member or element at the end of a container
+func (d *jsonDocument) insert(container *jsonNode, seg segment, value any) error {
+	containerIndent := lineIndent(d.data, container.start)
+	childIndent := containerIndent + d.indent
+	separator := ": "
+
+	var siblings int
+	if container.kind == '{' {
+		siblings = len(container.members)
+		if siblings > 0 {
+			last := container.members[siblings-1]
+			childIndent = lineIndent(d.data, last.keyStart)
+			separator = string(d.data[last.keyEnd:last.value.start])
+		} else if !d.pretty {
+			separator = ":"
+		}
+	} else {
+		siblings = len(container.elements)
+		if siblings > 0 {
+			childIndent = lineIndent(d.data, container.elements[siblings-1].start)
+		}
+	}
+
+	encoded, err := d.encode(value, childIndent)
+	if err != nil {
+		return err
+	}
+
+	var entry []byte
+	if container.kind == '{' {
+		key, _ := json.Marshal(seg.key)
+		entry = append(append(key, separator...), encoded...)
+	} else {
+		entry = encoded
+	}
+
+	if siblings == 0 {
+		if d.pretty {
+			text := "\n" + childIndent + string(entry) + "\n" + containerIndent
+			return d.splice(container.start+1, container.end-1, []byte(text))
+		}
+		return d.splice(container.start+1, container.end-1, entry)
+	}
+
+	var lastEnd int
+	if container.kind == '{' {
+		lastEnd = container.members[siblings-1].value.end
+	} else {
+		lastEnd = container.elements[siblings-1].end
+	}
+	if d.pretty {
+		return d.splice(lastEnd, lastEnd, []byte(",\n"+childIndent+string(entry)))
+	}
+	return d.splice(lastEnd, lastEnd, append([]byte(","), entry...))
+}
+
+// encode marshals a value for insertion at a line with the given indentation
+func (d *jsonDocument) encode(value any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if d.pretty {
+		enc.SetIndent(indent, d.indent)
+	}
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// splice replaces data[start:end] and reparses the document
+func (d *jsonDocument) splice(start, end int, text []byte) error {
+	out := make([]byte, 0, len(d.data)-(end-start)+len(text))
+	out = append(out, d.data[:start]...)
+	out = append(out, text...)
+	out = append(out, d.data[end:]...)
+	if !json.Valid(out) {
+		return fmt.Errorf("edit produced invalid JSON")
+	}
+
+	p := &jsonParser{data: out}
+	root, err := p.value()
+	if err != nil {
+		return err
+	}
+	d.data, d.root = out, root
+	return nil
+}
+
+// lineIndent returns the leading whitespace of the line containing pos
+func lineIndent(data []byte, pos int) string {
+	start := bytes.LastIndexByte(data[:pos], '\n') + 1
+	end := start
+	for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
+
+// jsonParser is a minimal span-recording parser for already validated JSON
+type jsonParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *jsonParser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonParser) value() (*jsonNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("unexpected end of JSON")
+	}
+
+	node := &jsonNode{start: p.pos}
+	switch p.data[p.pos] {
+	case '{':
+		node.kind = '{'
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.data[p.pos] == '}' {
+				p.pos++
+				break
+			}
+			if p.data[p.pos] == ',' {
+				p.pos++
+				p.skipSpace()
+			}
+			keyStart := p.pos
+			if err := p.skipString(); err != nil {
+				return nil, err
+			}
+			keyEnd := p.pos
+			var key string
+			if err := json.Unmarshal(p.data[keyStart:keyEnd], &key); err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			p.pos++ // ':'
+			val, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			node.members = append(node.members, jsonMember{key: key, keyStart: keyStart, keyEnd: keyEnd, value: val})
+		}
+	case '[':
+		node.kind = '['
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.data[p.pos] == ']' {
+				p.pos++
+				break
+			}
+			if p.data[p.pos] == ',' {
+				p.pos++
+			}
+			val, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			node.elements = append(node.elements, val)
+		}
+	case '"':
+		node.kind = 'v'
+		if err := p.skipString(); err != nil {
+			return nil, err
+		}
+	default:
+		node.kind = 'v'
+		for p.pos < len(p.data) && !strings.ContainsRune(" \t\r\n,]}", rune(p.data[p.pos])) {
+			p.pos++
+		}
+	}
+	node.end = p.pos
+	return node, nil
+}
+
+func (p *jsonParser) skipString() error {
+	if p.pos >= len(p.data) || p.data[p.pos] != '"' {
+		return fmt.Errorf("expected string at offset %d", p.pos)
+	}
+	for p.pos++; p.pos < len(p.data); p.pos++ {
+		switch p.data[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			return nil
+		}
+	}
+	return fmt.Errorf("unterminated string")
+}
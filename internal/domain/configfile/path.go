@@ -0,0 +1,131 @@
+package configfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segment is one step of a key path: a map key or a list index
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// keyPath addresses a value inside a document, e.g. services.web.image or [0].name
+type keyPath []segment
+
+// parsePath parses dotted keys, [N] indexes and ["quoted.keys"]
+func parsePath(s string) (keyPath, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("key path is required")
+	}
+
+	var (
+		path keyPath
+		key  strings.Builder
+	)
+	flush := func() {
+		if key.Len() > 0 {
+			path = append(path, segment{key: key.String()})
+			key.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '.':
+			if key.Len() == 0 && (i == 0 || s[i-1] != ']') {
+				return nil, fmt.Errorf("empty key in path %q", s)
+			}
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in path %q", s)
+			}
+			inner := s[i+1 : i+end]
+			if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+				path = append(path, segment{key: inner[1 : len(inner)-1]})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid index %q in path %q", inner, s)
+				}
+				path = append(path, segment{index: n, isIndex: true})
+			}
+			i += end
+		default:
+			key.WriteByte(c)
+		}
+	}
+	if strings.HasSuffix(s, ".") {
+		return nil, fmt.Errorf("empty key in path %q", s)
+	}
+	flush()
+
+	return path, nil
+}
+
+// String formats the path back into its textual form
+func (p keyPath) String() string {
+	var b strings.Builder
+	for i, seg := range p {
+		switch {
+		case seg.isIndex:
+			fmt.Fprintf(&b, "[%d]", seg.index)
+		case strings.ContainsAny(seg.key, ".[]"):
+			fmt.Fprintf(&b, "[%q]", seg.key)
+		default:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(seg.key)
+		}
+	}
+	return b.String()
+}
+
+// lookup navigates decoded maps and slices along the path
+func lookup(value any, path keyPath) (any, bool) {
+	for _, seg := range path {
+		switch v := value.(type) {
+		case map[string]any:
+			if seg.isIndex {
+				return nil, false
+			}
+			next, ok := v[seg.key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			if !seg.isIndex || seg.index >= len(v) {
+				return nil, false
+			}
+			value = v[seg.index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// nestValue wraps value in maps and single-element lists for the given path,
+// used when a set creates intermediate containers
+func nestValue(path keyPath, value any) (any, error) {
+	for i := len(path) - 1; i >= 0; i-- {
+		seg := path[i]
+		if seg.isIndex {
+			if seg.index != 0 {
+				return nil, fmt.Errorf("cannot create list element [%d]; new lists start at [0]", seg.index)
+			}
+			value = []any{value}
+			continue
+		}
+		value = map[string]any{seg.key: value}
+	}
+	return value, nil
+}
@@ -0,0 +1,319 @@
+package configfile
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"mini-mcp/internal/shared/errors"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/types/resources"
+)
+
+// Supported configuration formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+	FormatINI  = "ini"
+)
+
+// maxConfigSize bounds the size of configuration files that will be edited
+const maxConfigSize = 10 << 20
+
+// ErrKeyNotFound is returned when a key path does not exist in a document
+var ErrKeyNotFound = stderrors.New("key not found")
+
+// document is a parsed configuration file that can be edited in place
+type document interface {
+	Get(path keyPath) (any, error)
+	Set(path keyPath, value any) error
+	Delete(path keyPath) error
+	Bytes() ([]byte, error)
+}
+
+// EditOptions controls how Set and Delete modify a file
+type EditOptions struct {
+	// Format overrides detection from the file extension
+	Format string
+	// Create allows Set to create a missing file
+	Create bool
+	// DryRun computes the diff without writing
+	DryRun bool
+}
+
+// Service defines the interface for configuration file domain services
+type Service interface {
+	Get(ctx context.Context, path, key, format string) (*resources.ConfigValue, error)
+	Set(ctx context.Context, path, key string, value any, opts EditOptions) (*resources.ConfigChange, error)
+	Delete(ctx context.Context, path, key string, opts EditOptions) (*resources.ConfigChange, error)
+}
+
+// ServiceImpl implements the configuration file domain service
+type ServiceImpl struct {
+	securityValidator security.PathValidator
+	logger            logging.Logger
+}
+
+// NewService creates a new configuration file domain service
+func NewService(securityValidator security.PathValidator, logger logging.Logger) Service {
+	return &ServiceImpl{
+		securityValidator: securityValidator,
+		logger:            logger,
+	}
+}
+
+// DetectFormat returns the format implied by a file extension
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	case ".ini", ".cfg", ".conf":
+		return FormatINI, nil
+	default:
+		return "", fmt.Errorf("cannot detect config format of %s; specify one of yaml, json, toml or ini", path)
+	}
+}
+
+// parseDocument parses data in the given format
+func parseDocument(format string, data []byte) (document, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAML(data)
+	case FormatJSON:
+		return parseJSON(data)
+	case FormatTOML:
+		return parseTOML(data)
+	case FormatINI:
+		return parseINI(data)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+}
+
+// Get reads the value at key
+func (s *ServiceImpl) Get(ctx context.Context, path, key, format string) (*resources.ConfigValue, error) {
+	format, keys, err := s.prepare(ctx, "config_get", path, key, format)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := s.readFile("config_get", path, false)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseDocument(format, data)
+	if err != nil {
+		return nil, errors.NewInvalidInputError(fmt.Sprintf("failed to parse %s as %s: %v", path, format, err))
+	}
+
+	value, err := doc.Get(keys)
+	if err != nil {
+		return nil, errors.NewInvalidInputError(err.Error())
+	}
+
+	return &resources.ConfigValue{Path: path, Format: format, Key: key, Value: value}, nil
+}
+
+// Set writes value at key, creating intermediate keys as needed
+func (s *ServiceImpl) Set(ctx context.Context, path, key string, value any, opts EditOptions) (*resources.ConfigChange, error) {
+	return s.edit(ctx, "config_set", path, key, opts, func(doc document, keys keyPath) error {
+		return doc.Set(keys, value)
+	})
+}
+
+// Delete removes the value at key
+func (s *ServiceImpl) Delete(ctx context.Context, path, key string, opts EditOptions) (*resources.ConfigChange, error) {
+	opts.Create = false
+	return s.edit(ctx, "config_delete", path, key, opts, func(doc document, keys keyPath) error {
+		return doc.Delete(keys)
+	})
+}
+
+// edit applies a change, validates that the result still parses and writes it atomically
+func (s *ServiceImpl) edit(ctx context.Context, operation, path, key string, opts EditOptions, apply func(document, keyPath) error) (*resources.ConfigChange, error) {
+	format, keys, err := s.prepare(ctx, operation, path, key, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	// A symlinked config is edited at its target, so the link survives the rename
+	target, err := s.resolve(ctx, operation, path)
+	if err != nil {
+		return nil, err
+	}
+
+	before, info, err := s.readFile(operation, target, opts.Create)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseDocument(format, before)
+	if err != nil {
+		return nil, errors.NewInvalidInputError(fmt.Sprintf("failed to parse %s as %s: %v", path, format, err))
+	}
+
+	if err := apply(doc, keys); err != nil {
+		return nil, errors.NewInvalidInputError(err.Error())
+	}
+	after, err := doc.Bytes()
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to serialize "+path)
+	}
+	if _, err := parseDocument(format, after); err != nil {
+		return nil, errors.WrapError(err, errors.ErrorCodeInternalError, fmt.Sprintf("Edited %s no longer parses as %s", path, format))
+	}
+
+	change := &resources.ConfigChange{
+		Path:    path,
+		Format:  format,
+		Key:     key,
+		Changed: string(before) != string(after),
+		DryRun:  opts.DryRun,
+		Diff:    unifiedDiff(filepath.Base(path), before, after),
+	}
+	if !change.Changed || opts.DryRun {
+		return change, nil
+	}
+
+	if err := writeAtomic(target, after, info); err != nil {
+		logging.FromContext(ctx, s.logger).Error("Config file write failed", err, map[string]any{"path": path, "operation": operation})
+		if os.IsPermission(err) {
+			return nil, errors.NewPermissionDeniedError(path)
+		}
+		return nil, errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to write "+path)
+	}
+
+	logging.FromContext(ctx, s.logger).Info("Config file updated", map[string]any{"path": path, "operation": operation, "key": key})
+	return change, nil
+}
+
+// prepare validates the path and resolves the format and key path
+func (s *ServiceImpl) prepare(ctx context.Context, operation, path, key, format string) (string, keyPath, error) {
+	if err := s.securityValidator.ValidatePath(path); err != nil {
+		logging.FromContext(ctx, s.logger).Error("Path validation failed for "+operation+" operation", err, map[string]any{
+			"path":      path,
+			"operation": operation,
+		})
+		return "", nil, errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for "+operation+" operation")
+	}
+
+	if format == "" {
+		detected, err := DetectFormat(path)
+		if err != nil {
+			return "", nil, errors.NewInvalidInputError(err.Error())
+		}
+		format = detected
+	}
+	format = strings.ToLower(format)
+
+	keys, err := parsePath(key)
+	if err != nil {
+		return "", nil, errors.NewInvalidInputError(err.Error())
+	}
+	return format, keys, nil
+}
+
+// resolve returns the file that path points to once its symlinks are
+// followed, or path itself when it does not exist yet. The resolved file must
+// pass the path validator too.
+func (s *ServiceImpl) resolve(ctx context.Context, operation, path string) (string, error) {
+	target, err := filepath.EvalSymlinks(path)
+	if os.IsNotExist(err) {
+		dir, dirErr := filepath.EvalSymlinks(filepath.Dir(path))
+		if dirErr != nil {
+			return path, nil
+		}
+		target, err = filepath.Join(dir, filepath.Base(path)), nil
+	}
+	if err != nil {
+		return "", errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to resolve "+path)
+	}
+	if target == path {
+		return path, nil
+	}
+	if err := s.securityValidator.ValidatePath(target); err != nil {
+		logging.FromContext(ctx, s.logger).Error("Path validation failed for "+operation+" operation", err, map[string]any{
+			"path":      path,
+			"target":    target,
+			"operation": operation,
+		})
+		return "", errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for "+operation+" operation")
+	}
+	return target, nil
+}
+
+// readFile reads a configuration file; a missing file reads as empty when create is set
+func (s *ServiceImpl) readFile(operation, path string, create bool) ([]byte, os.FileInfo, error) {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err) && create:
+		return nil, nil, nil
+	case os.IsNotExist(err):
+		return nil, nil, errors.NewFileNotFoundError(path)
+	case os.IsPermission(err):
+		return nil, nil, errors.NewPermissionDeniedError(path)
+	case err != nil:
+		return nil, nil, errors.WrapError(err, errors.ErrorCodeInternalError, fmt.Sprintf("Failed to %s %s", operation, path))
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, nil, errors.NewInvalidInputError(fmt.Sprintf("not a regular file: %s", path))
+	}
+	if info.Size() > maxConfigSize {
+		return nil, nil, errors.NewResourceExhaustedError(fmt.Sprintf("config file larger than %d bytes", maxConfigSize))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsPermission(err) {
+			return nil, nil, errors.NewPermissionDeniedError(path)
+		}
+		return nil, nil, errors.WrapError(err, errors.ErrorCodeInternalError, fmt.Sprintf("Failed to %s %s", operation, path))
+	}
+	return data, info, nil
+}
+
+// writeAtomic replaces path through a temporary file in the same directory,
+// keeping the original mode and ownership
+func writeAtomic(path string, data []byte, original os.FileInfo) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() { _ = os.Remove(tmpName) }()
+
+	mode := os.FileMode(0o644)
+	if original != nil {
+		mode = original.Mode().Perm()
+		if st, ok := original.Sys().(*syscall.Stat_t); ok {
+			// Best effort: only privileged processes can give files away
+			_ = tmp.Chown(int(st.Uid), int(st.Gid))
+		}
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
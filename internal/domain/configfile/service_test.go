@@ -0,0 +1,321 @@
+package configfile
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errPathNotAllowed = stderrors.New("path not allowed")
+
+// prefixValidator allows only paths below a fixed root
+type prefixValidator struct {
+	root string
+}
+
+func (v prefixValidator) ValidatePath(path string) error {
+	if !v.IsPathAllowed(path) {
+		return errPathNotAllowed
+	}
+	return nil
+}
+
+func (v prefixValidator) IsPathAllowed(path string) bool {
+	return strings.HasPrefix(filepath.Clean(path), v.root)
+}
+
+func newTestService(t *testing.T) (*ServiceImpl, string) {
+	t.Helper()
+	root := t.TempDir()
+	return NewService(prefixValidator{root: root}, logging.NewLogger(io.Discard, logging.LogLevelError)).(*ServiceImpl), root
+}
+
+func writeFixture(t *testing.T, root, name, content string) string {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0640))
+	return path
+}
+
+func readFixture(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestParsePath(t *testing.T) {
+	path, err := parsePath(`services.web.image`)
+	require.NoError(t, err)
+	assert.Equal(t, keyPath{{key: "services"}, {key: "web"}, {key: "image"}}, path)
+
+	path, err = parsePath(`[0].name`)
+	require.NoError(t, err)
+	assert.Equal(t, keyPath{{index: 0, isIndex: true}, {key: "name"}}, path)
+
+	path, err = parsePath(`hosts["example.com"].port`)
+	require.NoError(t, err)
+	assert.Equal(t, keyPath{{key: "hosts"}, {key: "example.com"}, {key: "port"}}, path)
+	assert.Equal(t, `hosts["example.com"].port`, path.String())
+
+	for _, bad := range []string{"", "a..b", ".a", "a[", "a[-1]", "a[x]"} {
+		_, err := parsePath(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestYAML_PreservesComments(t *testing.T) {
+	svc, root := newTestService(t)
+	ctx := context.Background()
+	path := writeFixture(t, root, "compose.yaml", `# Compose file
+services:
+  web:
+    image: nginx:1.25 # pinned
+    ports:
+      - "80:80"
+  db:
+    image: postgres:16
+`)
+
+	value, err := svc.Get(ctx, path, "services.web.ports[0]", "")
+	require.NoError(t, err)
+	assert.Equal(t, "80:80", value.Value)
+	assert.Equal(t, FormatYAML, value.Format)
+
+	change, err := svc.Set(ctx, path, "services.web.image", "nginx:1.27", EditOptions{})
+	require.NoError(t, err)
+	assert.True(t, change.Changed)
+	assert.Contains(t, change.Diff, "-    image: nginx:1.25 # pinned")
+	assert.Contains(t, change.Diff, "+    image: nginx:1.27 # pinned")
+
+	content := readFixture(t, path)
+	assert.Contains(t, content, "# Compose file")
+	assert.Contains(t, content, "image: nginx:1.27 # pinned")
+	assert.Less(t, strings.Index(content, "web:"), strings.Index(content, "db:"))
+
+	_, err = svc.Set(ctx, path, "services.web.environment.DEBUG", true, EditOptions{})
+	require.NoError(t, err)
+	value, err = svc.Get(ctx, path, "services.web.environment.DEBUG", "")
+	require.NoError(t, err)
+	assert.Equal(t, true, value.Value)
+
+	_, err = svc.Delete(ctx, path, "services.db", EditOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, readFixture(t, path), "postgres")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
+
+func TestJSON_PreservesFormatting(t *testing.T) {
+	svc, root := newTestService(t)
+	ctx := context.Background()
+	original := `{
+    "name": "app",
+    "servers": [
+        {"host": "a", "port": 80},
+        {"host": "b", "port": 81}
+    ],
+    "debug": false
+}
+`
+	path := writeFixture(t, root, "app.json", original)
+
+	value, err := svc.Get(ctx, path, "servers[1].host", "")
+	require.NoError(t, err)
+	assert.Equal(t, "b", value.Value)
+
+	_, err = svc.Set(ctx, path, "servers[0].port", 8080, EditOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(original, `"port": 80}`, `"port": 8080}`, 1), readFixture(t, path))
+
+	_, err = svc.Set(ctx, path, "logging.level", "info", EditOptions{})
+	require.NoError(t, err)
+	content := readFixture(t, path)
+	assert.Contains(t, content, "    \"debug\": false,\n    \"logging\": {\n        \"level\": \"info\"\n    }\n}")
+
+	_, err = svc.Delete(ctx, path, "servers[0]", EditOptions{})
+	require.NoError(t, err)
+	value, err = svc.Get(ctx, path, "servers", "")
+	require.NoError(t, err)
+	assert.Len(t, value.Value, 1)
+
+	_, err = svc.Delete(ctx, path, "missing", EditOptions{})
+	assert.Error(t, err)
+}
+
+func TestTOML_PreservesComments(t *testing.T) {
+	svc, root := newTestService(t)
+	ctx := context.Background()
+	path := writeFixture(t, root, "config.toml", `# Service settings
+title = "demo"
+
+[server]
+host = "0.0.0.0" # listen on all interfaces
+port = 8080
+
+[database]
+url = "postgres://localhost"
+`)
+
+	value, err := svc.Get(ctx, path, "server.port", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8080), value.Value)
+
+	_, err = svc.Set(ctx, path, "server.host", "127.0.0.1", EditOptions{})
+	require.NoError(t, err)
+	_, err = svc.Set(ctx, path, "server.workers", 4, EditOptions{})
+	require.NoError(t, err)
+	_, err = svc.Set(ctx, path, "cache.ttl", "5m", EditOptions{})
+	require.NoError(t, err)
+
+	content := readFixture(t, path)
+	assert.Contains(t, content, "# Service settings")
+	assert.Contains(t, content, `host = "127.0.0.1" # listen on all interfaces`)
+	assert.Contains(t, content, "port = 8080\nworkers = 4\n")
+
+	value, err = svc.Get(ctx, path, "cache.ttl", "")
+	require.NoError(t, err)
+	assert.Equal(t, "5m", value.Value)
+
+	_, err = svc.Delete(ctx, path, "database", EditOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, readFixture(t, path), "postgres")
+}
+
+func TestINI_EditsInPlace(t *testing.T) {
+	svc, root := newTestService(t)
+	ctx := context.Background()
+	path := writeFixture(t, root, "app.ini", `; global settings
+name=demo
+
+[server]
+; bind address
+host=0.0.0.0
+port=8080
+`)
+
+	value, err := svc.Get(ctx, path, "server.port", "")
+	require.NoError(t, err)
+	assert.Equal(t, "8080", value.Value)
+
+	_, err = svc.Set(ctx, path, "server.port", 9090, EditOptions{})
+	require.NoError(t, err)
+	_, err = svc.Set(ctx, path, "server.timeout", "30s", EditOptions{})
+	require.NoError(t, err)
+	_, err = svc.Set(ctx, path, "log.level", "debug", EditOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `; global settings
+name=demo
+
+[server]
+; bind address
+host=0.0.0.0
+port=9090
+timeout=30s
+
+[log]
+level=debug
+`, readFixture(t, path))
+
+	_, err = svc.Delete(ctx, path, "server.host", EditOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, readFixture(t, path), "host=")
+}
+
+func TestEdit_DryRunAndCreate(t *testing.T) {
+	svc, root := newTestService(t)
+	ctx := context.Background()
+	path := writeFixture(t, root, "dry.yaml", "a: 1\n")
+
+	change, err := svc.Set(ctx, path, "a", 2, EditOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, change.DryRun)
+	assert.Contains(t, change.Diff, "+a: 2")
+	assert.Equal(t, "a: 1\n", readFixture(t, path))
+
+	newPath := filepath.Join(root, "new.json")
+	_, err = svc.Set(ctx, newPath, "a", 1, EditOptions{})
+	assert.Error(t, err)
+	_, err = svc.Set(ctx, newPath, "a.b", 1, EditOptions{Create: true})
+	require.NoError(t, err)
+	value, err := svc.Get(ctx, newPath, "a.b", "")
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), value.Value)
+}
+
+func TestEdit_FollowsSymlinks(t *testing.T) {
+	svc, root := newTestService(t)
+	ctx := context.Background()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "real"), 0755))
+	real := writeFixture(t, root, "real/app.yaml", "a: 1\n")
+	link := filepath.Join(root, "app.yaml")
+	require.NoError(t, os.Symlink(real, link))
+
+	_, err := svc.Set(ctx, link, "a", 2, EditOptions{})
+	require.NoError(t, err)
+	info, err := os.Lstat(link)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeSymlink, "the link survives the edit")
+	assert.Equal(t, "a: 2\n", readFixture(t, real))
+
+	// A link out of the allowed paths is refused
+	outside := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(outside, []byte("a: 1\n"), 0644))
+	escape := filepath.Join(root, "escape.yaml")
+	require.NoError(t, os.Symlink(outside, escape))
+	_, err = svc.Set(ctx, escape, "a", 2, EditOptions{})
+	assert.Error(t, err)
+	data, err := os.ReadFile(outside)
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1\n", string(data))
+}
+
+func TestEdit_Rejections(t *testing.T) {
+	svc, root := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.Get(ctx, "/elsewhere/app.yaml", "a", "")
+	assert.Error(t, err)
+
+	unknown := writeFixture(t, root, "settings", "a: 1\n")
+	_, err = svc.Get(ctx, unknown, "a", "")
+	assert.Error(t, err)
+	value, err := svc.Get(ctx, unknown, "a", FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value.Value)
+
+	broken := writeFixture(t, root, "broken.json", `{"a": `)
+	_, err = svc.Set(ctx, broken, "a", 1, EditOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, `{"a": `, readFixture(t, broken))
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := []byte("a\nb\nc\nd\ne\nf\ng\nh\n")
+	after := []byte("a\nb\nc\nd\nE\nf\ng\nh\n")
+	assert.Equal(t, `--- a/x
++++ b/x
+@@ -2,7 +2,7 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+`, unifiedDiff("x", before, after))
+	assert.Empty(t, unifiedDiff("x", before, before))
+}
@@ -0,0 +1,469 @@
+package configfile
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+var (
+	tomlTableHeader      = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+	tomlArrayTableHeader = regexp.MustCompile(`^\s*\[\[\s*([^\[\]]+?)\s*\]\]\s*(#.*)?$`)
+)
+
+// tomlDocument edits TOML line by line so comments and layout survive; values
+// are read and the edited result validated with a full TOML parser
+type tomlDocument struct {
+	lines []string
+}
+
+// tomlEntry is a key/value assignment spanning one or more lines
+type tomlEntry struct {
+	path      keyPath
+	first     int // line index of the key
+	last      int // line index where the value ends
+	valueCol  int // byte offset of the value on the first line
+	valueEnd  int // byte offset just past the value on the last line
+	arrayItem bool
+}
+
+// tomlTable is a [table] section
+type tomlTable struct {
+	path      keyPath
+	header    int // line index of the header, -1 for the root table
+	end       int // line index of the next header (exclusive)
+	arrayItem bool
+}
+
+// parseTOML parses a TOML document
+func parseTOML(data []byte) (*tomlDocument, error) {
+	var probe map[string]any
+	if err := toml.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	var lines []string
+	if text != "" {
+		lines = strings.Split(text, "\n")
+	}
+	return &tomlDocument{lines: lines}, nil
+}
+
+// Get decodes the value at path
+func (d *tomlDocument) Get(path keyPath) (any, error) {
+	var values map[string]any
+	if err := toml.Unmarshal([]byte(strings.Join(d.lines, "\n")), &values); err != nil {
+		return nil, err
+	}
+	value, ok := lookup(normalizeTOML(values), path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+	}
+	return value, nil
+}
+
+// Set replaces an existing assignment or adds one to the closest enclosing table
+func (d *tomlDocument) Set(path keyPath, value any) error {
+	for _, seg := range path {
+		if seg.isIndex {
+			return fmt.Errorf("%s: list indexes are not supported when editing TOML", path)
+		}
+	}
+
+	tables, entries := d.scan()
+
+	for _, e := range entries {
+		if !e.arrayItem && pathEqual(e.path, path) {
+			encoded, err := tomlValue(value)
+			if err != nil {
+				return err
+			}
+			first := d.lines[e.first]
+			rest := d.lines[e.last][e.valueEnd:]
+			replaced := first[:e.valueCol] + encoded + rest
+			d.lines = append(d.lines[:e.first], append([]string{replaced}, d.lines[e.last+1:]...)...)
+			return nil
+		}
+	}
+	for _, t := range tables {
+		if !t.arrayItem && t.header >= 0 && pathEqual(t.path, path) {
+			return fmt.Errorf("%s is a table; set its keys individually", path)
+		}
+	}
+
+	// Place the key in the deepest table that prefixes the path
+	var target *tomlTable
+	for i := range tables {
+		t := &tables[i]
+		if t.arrayItem || len(t.path) >= len(path) || !pathEqual(t.path, path[:len(t.path)]) {
+			continue
+		}
+		if target == nil || len(t.path) > len(target.path) {
+			target = t
+		}
+	}
+
+	encoded, err := tomlValue(value)
+	if err != nil {
+		return err
+	}
+	line := tomlKey(path[len(target.path):]) + " = " + encoded
+
+	// Insert after the table's last assignment, before trailing blank lines and comments
+	at := target.header + 1
+	for _, e := range entries {
+		if e.first >= target.header && e.last < target.end && e.last+1 > at {
+			at = e.last + 1
+		}
+	}
+	if target.header < 0 && len(entries) == 0 {
+		at = 0
+		for at < target.end && strings.HasPrefix(strings.TrimSpace(d.lines[at]), "#") {
+			at++
+		}
+	}
+
+	d.lines = append(d.lines[:at], append([]string{line}, d.lines[at:]...)...)
+	return nil
+}
+
+// Delete removes an assignment, or a whole [table] section
+func (d *tomlDocument) Delete(path keyPath) error {
+	tables, entries := d.scan()
+
+	for _, e := range entries {
+		if !e.arrayItem && pathEqual(e.path, path) {
+			d.lines = append(d.lines[:e.first], d.lines[e.last+1:]...)
+			return nil
+		}
+	}
+	for _, t := range tables {
+		if !t.arrayItem && t.header >= 0 && pathEqual(t.path, path) {
+			d.lines = append(d.lines[:t.header], d.lines[t.end:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+}
+
+// Bytes returns the edited document
+func (d *tomlDocument) Bytes() ([]byte, error) {
+	if len(d.lines) == 0 {
+		return nil, nil
+	}
+	return []byte(strings.Join(d.lines, "\n") + "\n"), nil
+}
+
+// scan locates every table header and key assignment
+func (d *tomlDocument) scan() ([]tomlTable, []tomlEntry) {
+	tables := []tomlTable{{header: -1, end: len(d.lines)}}
+	var entries []tomlEntry
+
+	current := &tables[0]
+	for i := 0; i < len(d.lines); i++ {
+		line := d.lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if m := tomlArrayTableHeader.FindStringSubmatch(line); m != nil {
+			tables[len(tables)-1].end = i
+			tables = append(tables, tomlTable{path: splitTOMLKey(m[1]), header: i, end: len(d.lines), arrayItem: true})
+			current = &tables[len(tables)-1]
+			continue
+		}
+		if m := tomlTableHeader.FindStringSubmatch(line); m != nil {
+			tables[len(tables)-1].end = i
+			tables = append(tables, tomlTable{path: splitTOMLKey(m[1]), header: i, end: len(d.lines)})
+			current = &tables[len(tables)-1]
+			continue
+		}
+
+		eq := tomlKeyEnd(line)
+		if eq < 0 {
+			continue
+		}
+		valueCol := eq + 1
+		for valueCol < len(line) && (line[valueCol] == ' ' || line[valueCol] == '\t') {
+			valueCol++
+		}
+		last, valueEnd := tomlValueEnd(d.lines, i, valueCol)
+
+		key := append(keyPath{}, current.path...)
+		key = append(key, splitTOMLKey(line[:eq])...)
+		entries = append(entries, tomlEntry{
+			path:      key,
+			first:     i,
+			last:      last,
+			valueCol:  valueCol,
+			valueEnd:  valueEnd,
+			arrayItem: current.arrayItem,
+		})
+		i = last
+	}
+
+	return tables, entries
+}
+
+// tomlKeyEnd returns the index of the '=' separating key and value, or -1
+func tomlKeyEnd(line string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '=':
+			return i
+		case c == '#':
+			return -1
+		}
+	}
+	return -1
+}
+
+// tomlValueEnd finds where a value starting at lines[first][col] ends, handling
+// multi-line strings, arrays and inline tables
+func tomlValueEnd(lines []string, first, col int) (int, int) {
+	depth := 0
+	var quote string
+	for i := first; i < len(lines); i++ {
+		line := lines[i]
+		j := 0
+		if i == first {
+			j = col
+		}
+		for j < len(line) {
+			switch {
+			case quote != "":
+				if quote == `"` || quote == `"""` {
+					if line[j] == '\\' {
+						j += 2
+						continue
+					}
+				}
+				if strings.HasPrefix(line[j:], quote) {
+					j += len(quote)
+					quote = ""
+					if depth == 0 {
+						return i, j
+					}
+					continue
+				}
+				j++
+			case strings.HasPrefix(line[j:], `"""`), strings.HasPrefix(line[j:], `'''`):
+				quote = line[j : j+3]
+				j += 3
+			case line[j] == '"' || line[j] == '\'':
+				quote = line[j : j+1]
+				j++
+			case line[j] == '[' || line[j] == '{':
+				depth++
+				j++
+			case line[j] == ']' || line[j] == '}':
+				depth--
+				j++
+				if depth == 0 {
+					return i, j
+				}
+			case line[j] == '#':
+				if depth == 0 {
+					return i, trimRightSpace(line[:j])
+				}
+				j = len(line)
+			case depth == 0 && (line[j] == ' ' || line[j] == '\t'):
+				// Bare values (numbers, booleans, dates) may contain single spaces only in datetimes
+				rest := strings.TrimSpace(line[j:])
+				if rest == "" || strings.HasPrefix(rest, "#") {
+					return i, j
+				}
+				j++
+			default:
+				j++
+			}
+		}
+		if quote == "" && depth == 0 {
+			return i, trimRightSpace(line)
+		}
+		if quote == `"` || quote == `'` {
+			// Single-line strings cannot span lines; treat as ending here
+			return i, len(line)
+		}
+	}
+	return len(lines) - 1, len(lines[len(lines)-1])
+}
+
+// trimRightSpace returns the length of s without trailing whitespace
+func trimRightSpace(s string) int {
+	return len(strings.TrimRight(s, " \t"))
+}
+
+// splitTOMLKey splits a dotted TOML key, removing quotes
+func splitTOMLKey(raw string) keyPath {
+	var (
+		path  keyPath
+		cur   strings.Builder
+		quote byte
+	)
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '.':
+			path = append(path, segment{key: strings.TrimSpace(cur.String())})
+			cur.Reset()
+		case c == ' ' || c == '\t':
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(path, segment{key: strings.TrimSpace(cur.String())})
+}
+
+// tomlKey renders a dotted key, quoting parts that are not bare keys
+func tomlKey(path keyPath) string {
+	parts := make([]string, len(path))
+	for i, seg := range path {
+		parts[i] = seg.key
+		for _, c := range seg.key {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+				parts[i] = tomlString(seg.key)
+				break
+			}
+		}
+		if seg.key == "" {
+			parts[i] = `""`
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// tomlValue renders a JSON-decoded value as a TOML literal
+func tomlValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("TOML has no null value; use config_delete to remove a key")
+	case string:
+		return tomlString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			s, err := tomlValue(v[k])
+			if err != nil {
+				return "", err
+			}
+			items[i] = tomlKey(keyPath{{key: k}}) + " = " + s
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	default:
+		return "", fmt.Errorf("unsupported TOML value type %T", value)
+	}
+}
+
+// tomlString renders a TOML basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// normalizeTOML converts decoded TOML values into the JSON-like shapes used by lookup
+func normalizeTOML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalizeTOML(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeTOML(item)
+		}
+		return v
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeTOML(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// pathEqual compares two key paths
+func pathEqual(a, b keyPath) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
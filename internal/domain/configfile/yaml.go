@@ -0,0 +1,215 @@
+package configfile
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlDocument edits YAML through the yaml.v3 node tree, which keeps
+// comments, key order and scalar styles intact across a round trip
+type yamlDocument struct {
+	root   yaml.Node
+	indent int
+}
+
+// parseYAML parses a YAML document
+func parseYAML(data []byte) (*yamlDocument, error) {
+	doc := &yamlDocument{indent: detectYAMLIndent(data)}
+	if err := yaml.Unmarshal(data, &doc.root); err != nil {
+		return nil, err
+	}
+	if doc.root.Kind == 0 {
+		// Empty file: start from an empty mapping
+		doc.root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	return doc, nil
+}
+
+// detectYAMLIndent returns the indentation width of the first nested line
+func detectYAMLIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || len(trimmed) == len(line) {
+			continue
+		}
+		if n := len(line) - len(trimmed); n >= 2 && n <= 8 {
+			return n
+		}
+		break
+	}
+	return 2
+}
+
+// Get decodes the value at path
+func (d *yamlDocument) Get(path keyPath) (any, error) {
+	node, err := d.find(path)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Set replaces or creates the value at path, keeping comments attached to a replaced value
+func (d *yamlDocument) Set(path keyPath, value any) error {
+	var replacement yaml.Node
+	if err := replacement.Encode(value); err != nil {
+		return err
+	}
+
+	parent := d.root.Content[0]
+	for i, seg := range path {
+		parent = resolveAlias(parent)
+		last := i == len(path)-1
+
+		switch parent.Kind {
+		case yaml.MappingNode:
+			if seg.isIndex {
+				return fmt.Errorf("%s: expected a key, found a mapping", path[:i+1])
+			}
+			child := mappingValue(parent, seg.key)
+			if child == nil {
+				nested, err := nestValue(path[i+1:], value)
+				if err != nil {
+					return err
+				}
+				var node yaml.Node
+				if err := node.Encode(nested); err != nil {
+					return err
+				}
+				parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: seg.key}, &node)
+				return nil
+			}
+			if last {
+				replaceNode(child, &replacement)
+				return nil
+			}
+			parent = child
+
+		case yaml.SequenceNode:
+			if !seg.isIndex {
+				return fmt.Errorf("%s: expected an index, found a list", path[:i+1])
+			}
+			if seg.index == len(parent.Content) {
+				nested, err := nestValue(path[i+1:], value)
+				if err != nil {
+					return err
+				}
+				var node yaml.Node
+				if err := node.Encode(nested); err != nil {
+					return err
+				}
+				parent.Content = append(parent.Content, &node)
+				return nil
+			}
+			if seg.index > len(parent.Content) {
+				return fmt.Errorf("%s: index out of range (list has %d elements)", path[:i+1], len(parent.Content))
+			}
+			if last {
+				replaceNode(parent.Content[seg.index], &replacement)
+				return nil
+			}
+			parent = parent.Content[seg.index]
+
+		default:
+			return fmt.Errorf("%s: cannot descend into a scalar value", path[:i+1])
+		}
+	}
+	return nil
+}
+
+// Delete removes the key or list element at path
+func (d *yamlDocument) Delete(path keyPath) error {
+	parent, err := d.find(path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	seg := path[len(path)-1]
+
+	switch parent.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(parent.Content); i += 2 {
+			if !seg.isIndex && parent.Content[i].Value == seg.key {
+				parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+				return nil
+			}
+		}
+	case yaml.SequenceNode:
+		if seg.isIndex && seg.index < len(parent.Content) {
+			parent.Content = append(parent.Content[:seg.index], parent.Content[seg.index+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+}
+
+// Bytes serializes the document with its original indentation
+func (d *yamlDocument) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(d.indent)
+	if err := enc.Encode(&d.root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// find returns the node at path
+func (d *yamlDocument) find(path keyPath) (*yaml.Node, error) {
+	node := d.root.Content[0]
+	for i, seg := range path {
+		node = resolveAlias(node)
+		var next *yaml.Node
+		switch {
+		case node.Kind == yaml.MappingNode && !seg.isIndex:
+			next = mappingValue(node, seg.key)
+		case node.Kind == yaml.SequenceNode && seg.isIndex && seg.index < len(node.Content):
+			next = node.Content[seg.index]
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, path[:i+1])
+		}
+		node = next
+	}
+	return resolveAlias(node), nil
+}
+
+// mappingValue returns the value node for key in a mapping, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// resolveAlias follows an alias node to its anchor
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// replaceNode overwrites target in place, carrying over its comments and anchor
+func replaceNode(target, replacement *yaml.Node) {
+	replacement.HeadComment = target.HeadComment
+	replacement.LineComment = target.LineComment
+	replacement.FootComment = target.FootComment
+	replacement.Anchor = target.Anchor
+	if target.Kind == yaml.ScalarNode && replacement.Kind == yaml.ScalarNode && replacement.Tag == "!!str" {
+		// Keep the original quoting style for strings
+		replacement.Style = target.Style
+	}
+	*target = *replacement
+}
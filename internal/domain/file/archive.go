@@ -0,0 +1,428 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"mini-mcp/internal/shared/errors"
+	"mini-mcp/internal/types/resources"
+)
+
+// Archive formats supported by CreateArchive and ExtractArchive
+const (
+	ArchiveFormatTar    = "tar"
+	ArchiveFormatTarGz  = "tar.gz"
+	ArchiveFormatTarZst = "tar.zst"
+	ArchiveFormatZip    = "zip"
+)
+
+const (
+	// DefaultMaxExtractBytes caps the total uncompressed size of an extraction
+	DefaultMaxExtractBytes int64 = 1 << 30
+	// DefaultMaxExtractEntries caps the number of entries in an extraction
+	DefaultMaxExtractEntries = 10000
+	// maxLinkTargetSize bounds symlink targets read from zip entry bodies
+	maxLinkTargetSize = 4096
+)
+
+// ArchiveOptions controls how CreateArchive builds an archive
+type ArchiveOptions struct {
+	// Format is the archive format; detected from the archive name when empty
+	Format string
+	// Include limits files to those matching at least one glob
+	Include []string
+	// Exclude skips files and directories matching any glob
+	Exclude []string
+	// Overwrite replaces an existing archive file
+	Overwrite bool
+}
+
+// ExtractOptions controls how ExtractArchive unpacks an archive
+type ExtractOptions struct {
+	// Format is the archive format; detected from the archive name when empty
+	Format string
+	// MaxBytes caps the total uncompressed size (DefaultMaxExtractBytes when 0)
+	MaxBytes int64
+	// MaxEntries caps the number of entries (DefaultMaxExtractEntries when 0)
+	MaxEntries int
+	// Overwrite replaces existing files in the destination
+	Overwrite bool
+}
+
+// DetectArchiveFormat infers the archive format from a file name
+func DetectArchiveFormat(name string) (string, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveFormatTarGz, nil
+	case strings.HasSuffix(lower, ".tar.zst"), strings.HasSuffix(lower, ".tzst"):
+		return ArchiveFormatTarZst, nil
+	case strings.HasSuffix(lower, ".tar"):
+		return ArchiveFormatTar, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveFormatZip, nil
+	default:
+		return "", fmt.Errorf("cannot detect archive format of %s", name)
+	}
+}
+
+// resolveArchiveFormat validates an explicit format or detects one from the name
+func resolveArchiveFormat(format, name string) (string, error) {
+	switch format {
+	case "":
+		detected, err := DetectArchiveFormat(name)
+		if err != nil {
+			return "", errors.NewInvalidFormatError("format", "tar, tar.gz, tar.zst or zip")
+		}
+		return detected, nil
+	case ArchiveFormatTar, ArchiveFormatTarGz, ArchiveFormatTarZst, ArchiveFormatZip:
+		return format, nil
+	default:
+		return "", errors.NewInvalidFormatError("format", "tar, tar.gz, tar.zst or zip")
+	}
+}
+
+// CreateArchive packs src into a new archive file
+func (s *ServiceImpl) CreateArchive(ctx context.Context, src, archive string, opts ArchiveOptions) (*resources.ArchiveResult, error) {
+	if err := s.validate(ctx, "archive_create", src, archive); err != nil {
+		return nil, err
+	}
+
+	format, err := resolveArchiveFormat(opts.Format, archive)
+	if err != nil {
+		return nil, err
+	}
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.NewInvalidInputError(fmt.Sprintf("invalid glob pattern: %q", pattern))
+		}
+	}
+
+	if _, err := os.Lstat(src); err != nil {
+		return nil, s.wrapFSError(ctx, "archive_create", src, err)
+	}
+	if _, err := os.Lstat(archive); err == nil && !opts.Overwrite {
+		return nil, errors.NewInvalidInputError(fmt.Sprintf("archive already exists: %s", archive))
+	}
+
+	// Write to a temporary file next to the archive so a failed run leaves nothing behind
+	tmp, err := os.CreateTemp(filepath.Dir(archive), "."+filepath.Base(archive)+".*")
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "archive_create", archive, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	result := &resources.ArchiveResult{Archive: archive, Format: format, Directory: src}
+	if err := writeArchive(ctx, tmp, src, tmp.Name(), format, opts, s.securityValidator.IsPathAllowed, result); err != nil {
+		_ = tmp.Close()
+		return nil, s.wrapFSError(ctx, "archive_create", src, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, s.wrapFSError(ctx, "archive_create", archive, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return nil, s.wrapFSError(ctx, "archive_create", archive, err)
+	}
+	if err := os.Rename(tmp.Name(), archive); err != nil {
+		return nil, s.wrapFSError(ctx, "archive_create", archive, err)
+	}
+
+	if info, err := os.Stat(archive); err == nil {
+		result.ArchiveSize = info.Size()
+	}
+
+	s.audit(ctx, "archive_create", map[string]any{
+		"source":  src,
+		"archive": archive,
+		"format":  format,
+		"entries": result.Entries,
+		"bytes":   result.Bytes,
+	})
+	return result, nil
+}
+
+// ExtractArchive unpacks an archive into dest, rejecting unsafe entries
+func (s *ServiceImpl) ExtractArchive(ctx context.Context, archive, dest string, opts ExtractOptions) (*resources.ArchiveResult, error) {
+	if err := s.validate(ctx, "archive_extract", archive, dest); err != nil {
+		return nil, err
+	}
+
+	format, err := resolveArchiveFormat(opts.Format, archive)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxExtractBytes
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMaxExtractEntries
+	}
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return nil, s.wrapFSError(ctx, "archive_extract", dest, err)
+	}
+
+	x := &extractor{
+		ctx:      ctx,
+		dest:     dest,
+		opts:     opts,
+		result:   &resources.ArchiveResult{Archive: archive, Format: format, Directory: dest},
+		validate: s.securityValidator.ValidatePath,
+		deny: func(name, reason string) error {
+			return s.policyDenied(ctx, "archive_extract", name, reason)
+		},
+	}
+
+	if format == ArchiveFormatZip {
+		err = x.extractZip(archive)
+	} else {
+		err = x.extractTar(archive, format)
+	}
+	if err != nil {
+		var resp *errors.ErrorResponse
+		if stderrors.As(err, &resp) {
+			return nil, resp
+		}
+		return nil, s.wrapFSError(ctx, "archive_extract", archive, err)
+	}
+
+	if info, err := os.Stat(archive); err == nil {
+		x.result.ArchiveSize = info.Size()
+	}
+
+	s.audit(ctx, "archive_extract", map[string]any{
+		"archive":     archive,
+		"destination": dest,
+		"format":      format,
+		"entries":     x.result.Entries,
+		"bytes":       x.result.Bytes,
+	})
+	return x.result, nil
+}
+
+// archiveFilter applies include/exclude globs to slash-separated relative paths
+type archiveFilter struct {
+	include []string
+	exclude []string
+}
+
+// matchAny reports whether rel or its base name matches any pattern
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether a path is excluded
+func (f archiveFilter) excluded(rel string) bool {
+	return matchAny(f.exclude, rel)
+}
+
+// included reports whether a non-directory path passes the include list
+func (f archiveFilter) included(rel string) bool {
+	return len(f.include) == 0 || matchAny(f.include, rel)
+}
+
+// archiveWriter is the format-specific half of archive creation
+type archiveWriter interface {
+	add(name string, info fs.FileInfo, link string, body io.Reader) error
+	Close() error
+}
+
+// writeArchive walks src and streams its entries into w, skipping those the
+// path policy does not allow
+func writeArchive(ctx context.Context, w io.Writer, src, skip, format string, opts ArchiveOptions, allowed func(path string) bool, result *resources.ArchiveResult) error {
+	var (
+		aw      archiveWriter
+		closers []io.Closer
+	)
+	switch format {
+	case ArchiveFormatZip:
+		aw = &zipArchiveWriter{zw: zip.NewWriter(w)}
+	case ArchiveFormatTarGz:
+		gz := gzip.NewWriter(w)
+		closers = append(closers, gz)
+		aw = &tarArchiveWriter{tw: tar.NewWriter(gz)}
+	case ArchiveFormatTarZst:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		closers = append(closers, zw)
+		aw = &tarArchiveWriter{tw: tar.NewWriter(zw)}
+	default:
+		aw = &tarArchiveWriter{tw: tar.NewWriter(w)}
+	}
+
+	filter := archiveFilter{include: opts.Include, exclude: opts.Exclude}
+	base := filepath.Base(filepath.Clean(src))
+	absSkip, _ := filepath.Abs(skip)
+
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if abs, _ := filepath.Abs(p); abs == absSkip {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		name := base
+		if rel != "." {
+			name = path.Join(base, rel)
+			if filter.excluded(rel) || !allowed(p) {
+				result.Skipped = append(result.Skipped, rel)
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.IsDir():
+			// Directories are only recorded explicitly when nothing is filtered in
+			if len(opts.Include) > 0 {
+				return nil
+			}
+			if err := aw.add(name+"/", info, "", nil); err != nil {
+				return err
+			}
+		case info.Mode()&fs.ModeSymlink != 0:
+			if rel != "." && !filter.included(rel) {
+				return nil
+			}
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			if err := aw.add(name, info, link, nil); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			if rel != "." && !filter.included(rel) {
+				return nil
+			}
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			err = aw.add(name, info, "", f)
+			_ = f.Close()
+			if err != nil {
+				return err
+			}
+			result.Bytes += info.Size()
+		default:
+			result.Skipped = append(result.Skipped, rel)
+			return nil
+		}
+
+		result.Entries++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := aw.Close(); err != nil {
+		return err
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tarArchiveWriter writes entries to a tar stream
+type tarArchiveWriter struct {
+	tw *tar.Writer
+}
+
+func (a *tarArchiveWriter) add(name string, info fs.FileInfo, link string, body io.Reader) error {
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	hdr.Format = tar.FormatPAX
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if body != nil {
+		// Only the stat size fits the header; a file that grows is cut there
+		var n int64
+		n, err = io.CopyN(a.tw, body, hdr.Size)
+		if err == io.EOF {
+			err = fmt.Errorf("%s shrank from %d to %d bytes while being archived", name, hdr.Size, n)
+		}
+	}
+	return err
+}
+
+func (a *tarArchiveWriter) Close() error {
+	return a.tw.Close()
+}
+
+// zipArchiveWriter writes entries to a zip file; symlinks store their target as the body
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func (a *zipArchiveWriter) add(name string, info fs.FileInfo, link string, body io.Reader) error {
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if info.Mode().IsRegular() {
+		hdr.Method = zip.Deflate
+	}
+	w, err := a.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	switch {
+	case link != "":
+		_, err = io.WriteString(w, link)
+	case body != nil:
+		_, err = io.Copy(w, body)
+	}
+	return err
+}
+
+func (a *zipArchiveWriter) Close() error {
+	return a.zw.Close()
+}
@@ -0,0 +1,376 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"mini-mcp/internal/shared/errors"
+	"mini-mcp/internal/types/resources"
+)
+
+// entryKind classifies archive entries independently of the archive format
+type entryKind int
+
+const (
+	entryFile entryKind = iota
+	entryDir
+	entrySymlink
+	entryHardlink
+	entrySpecial
+	entryUnsupported
+)
+
+// archiveEntry is a format-neutral view of a single archive member
+type archiveEntry struct {
+	name     string
+	kind     entryKind
+	mode     fs.FileMode
+	size     int64
+	modTime  time.Time
+	linkname string
+	body     io.Reader
+}
+
+// extractor writes archive entries below a destination directory.
+// All filesystem access goes through an os.Root, so symlinks that already
+// exist (or were extracted earlier) can never redirect writes outside dest.
+type extractor struct {
+	ctx      context.Context
+	dest     string
+	root     *os.Root
+	opts     ExtractOptions
+	result   *resources.ArchiveResult
+	written  int64
+	dirs     []archiveEntry
+	validate func(path string) error
+	deny     func(name, reason string) error
+}
+
+// extractTar unpacks a tar, tar.gz or tar.zst archive
+func (x *extractor) extractTar(archive, format string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	switch format {
+	case ArchiveFormatTarGz:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	case ArchiveFormatTarZst:
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	return x.run(func(yield func(archiveEntry) error) error {
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			entry := archiveEntry{
+				name:     hdr.Name,
+				mode:     hdr.FileInfo().Mode(),
+				size:     hdr.Size,
+				modTime:  hdr.ModTime,
+				linkname: hdr.Linkname,
+				body:     tr,
+			}
+			switch hdr.Typeflag {
+			case tar.TypeReg:
+				entry.kind = entryFile
+			case tar.TypeDir:
+				entry.kind = entryDir
+			case tar.TypeSymlink:
+				entry.kind = entrySymlink
+			case tar.TypeLink:
+				entry.kind = entryHardlink
+			case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+				entry.kind = entrySpecial
+			default:
+				entry.kind = entryUnsupported
+			}
+
+			if err := yield(entry); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// extractZip unpacks a zip archive
+func (x *extractor) extractZip(archive string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zr.Close() }()
+
+	return x.run(func(yield func(archiveEntry) error) error {
+		for _, zf := range zr.File {
+			mode := zf.Mode()
+			entry := archiveEntry{
+				name:    zf.Name,
+				mode:    mode,
+				size:    int64(zf.UncompressedSize64),
+				modTime: zf.Modified,
+			}
+			switch {
+			case mode.IsDir():
+				entry.kind = entryDir
+			case mode&fs.ModeSymlink != 0:
+				entry.kind = entrySymlink
+			case mode&(fs.ModeDevice|fs.ModeCharDevice|fs.ModeNamedPipe|fs.ModeSocket) != 0:
+				entry.kind = entrySpecial
+			case mode.IsRegular():
+				entry.kind = entryFile
+			default:
+				entry.kind = entryUnsupported
+			}
+
+			// Declared sizes are checked up front; the copy is still capped in case they lie
+			if entry.kind == entryFile && entry.size > x.opts.MaxBytes-x.written {
+				return x.sizeExceeded()
+			}
+
+			if entry.kind == entryFile || entry.kind == entrySymlink {
+				rc, err := zf.Open()
+				if err != nil {
+					return err
+				}
+				if entry.kind == entrySymlink {
+					link, err := io.ReadAll(io.LimitReader(rc, maxLinkTargetSize))
+					_ = rc.Close()
+					if err != nil {
+						return err
+					}
+					entry.linkname = string(link)
+				} else {
+					entry.body = rc
+					err = yield(entry)
+					_ = rc.Close()
+					if err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			if err := yield(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// run opens the destination root, feeds every entry through extract and
+// restores directory timestamps once all children have been written
+func (x *extractor) run(entries func(yield func(archiveEntry) error) error) error {
+	root, err := os.OpenRoot(x.dest)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = root.Close() }()
+	x.root = root
+
+	if err := entries(x.extract); err != nil {
+		return err
+	}
+
+	for i := len(x.dirs) - 1; i >= 0; i-- {
+		_ = x.root.Chtimes(x.dirs[i].name, x.dirs[i].modTime, x.dirs[i].modTime)
+	}
+	return nil
+}
+
+// extract validates and writes a single entry
+func (x *extractor) extract(entry archiveEntry) error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	if x.result.Entries >= x.opts.MaxEntries {
+		return errors.NewResourceExhaustedError(fmt.Sprintf("archive entries (limit %d)", x.opts.MaxEntries))
+	}
+
+	name, err := x.entryName(entry.name)
+	if err != nil {
+		return err
+	}
+	if name == "." {
+		return nil
+	}
+	entry.name = name
+
+	// setuid/setgid and world-writable bits are dropped, matching the chmod policy
+	perm := entry.mode.Perm() &^ 0o002
+
+	switch entry.kind {
+	case entryDir:
+		if err := x.root.MkdirAll(name, perm|0o700); err != nil {
+			return err
+		}
+		x.dirs = append(x.dirs, entry)
+
+	case entryFile:
+		if err := x.prepare(name); err != nil {
+			return err
+		}
+		if err := x.writeFile(entry, perm); err != nil {
+			return err
+		}
+
+	case entrySymlink:
+		if err := x.checkLink(entry); err != nil {
+			return err
+		}
+		if err := x.prepare(name); err != nil {
+			return err
+		}
+		if err := x.root.Symlink(entry.linkname, name); err != nil {
+			return err
+		}
+
+	case entryHardlink:
+		target, err := x.entryName(entry.linkname)
+		if err != nil {
+			return err
+		}
+		if err := x.prepare(name); err != nil {
+			return err
+		}
+		if err := x.root.Link(target, name); err != nil {
+			return err
+		}
+
+	case entrySpecial:
+		return x.deny(entry.name, "device files, FIFOs and sockets cannot be extracted")
+
+	default:
+		x.result.Skipped = append(x.result.Skipped, name)
+		return nil
+	}
+
+	x.result.Entries++
+	return nil
+}
+
+// entryName normalizes an archive member name, rejecting absolute paths and escapes
+func (x *extractor) entryName(raw string) (string, error) {
+	slashed := strings.ReplaceAll(raw, "\\", "/")
+	if slashed == "" {
+		return "", x.deny(raw, "empty entry name")
+	}
+	if path.IsAbs(slashed) || filepath.IsAbs(raw) || filepath.VolumeName(raw) != "" {
+		return "", x.deny(raw, "absolute paths are not allowed in archives")
+	}
+
+	name := path.Clean(slashed)
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", x.deny(raw, "entry escapes the destination directory")
+	}
+
+	target := filepath.Join(x.dest, filepath.FromSlash(name))
+	if err := x.validate(target); err != nil {
+		return "", errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for archive entry "+raw)
+	}
+
+	return name, nil
+}
+
+// checkLink rejects symlinks whose target would resolve outside the destination
+func (x *extractor) checkLink(entry archiveEntry) error {
+	link := strings.ReplaceAll(entry.linkname, "\\", "/")
+	if link == "" || path.IsAbs(link) || filepath.IsAbs(entry.linkname) {
+		return x.deny(entry.name, "symlink target must be a relative path")
+	}
+	resolved := path.Clean(path.Join(path.Dir(entry.name), link))
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return x.deny(entry.name, "symlink target escapes the destination directory")
+	}
+	return nil
+}
+
+// prepare creates parent directories and clears an existing file when overwriting
+func (x *extractor) prepare(name string) error {
+	if dir := path.Dir(name); dir != "." {
+		if err := x.root.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	info, err := x.root.Lstat(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !x.opts.Overwrite {
+		return errors.NewInvalidInputError(fmt.Sprintf("destination already exists: %s (set overwrite to replace)", name))
+	}
+	if info.IsDir() {
+		return errors.NewInvalidInputError(fmt.Sprintf("cannot replace directory with file: %s", name))
+	}
+	return x.root.Remove(name)
+}
+
+// writeFile copies an entry body, enforcing the total size budget
+func (x *extractor) writeFile(entry archiveEntry, perm fs.FileMode) error {
+	f, err := x.root.OpenFile(entry.name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	remaining := x.opts.MaxBytes - x.written
+	n, err := io.Copy(f, io.LimitReader(entry.body, remaining+1))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n > remaining {
+		return x.sizeExceeded()
+	}
+
+	x.written += n
+	x.result.Bytes += n
+
+	if err := x.root.Chmod(entry.name, perm); err != nil {
+		return err
+	}
+	return x.root.Chtimes(entry.name, entry.modTime, entry.modTime)
+}
+
+// sizeExceeded reports that the archive expands beyond the size budget
+func (x *extractor) sizeExceeded() error {
+	return errors.NewResourceExhaustedError(fmt.Sprintf("archive size (limit %d bytes)", x.opts.MaxBytes))
+}
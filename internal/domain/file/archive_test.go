@@ -0,0 +1,254 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"mini-mcp/internal/shared/errors"
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tarMember describes an entry for hand-built malicious archives
+type tarMember struct {
+	name     string
+	typeflag byte
+	linkname string
+	body     string
+}
+
+func writeTar(t *testing.T, path string, members []tarMember) {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, m := range members {
+		hdr := &tar.Header{Name: m.name, Typeflag: m.typeflag, Linkname: m.linkname, Mode: 0644, Size: int64(len(m.body))}
+		if m.typeflag != tar.TypeReg {
+			hdr.Size = 0
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write([]byte(m.body))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func extractedPaths(t *testing.T, root string) []string {
+	t.Helper()
+	var paths []string
+	require.NoError(t, filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		if p != root {
+			rel, _ := filepath.Rel(root, p)
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return nil
+	}))
+	sort.Strings(paths)
+	return paths
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	for _, name := range []string{"bundle.tar", "bundle.tar.gz", "bundle.tar.zst", "bundle.zip"} {
+		t.Run(name, func(t *testing.T) {
+			root := createListingFixture(t)
+			svc := newTestService(t, root)
+			ctx := context.Background()
+
+			archive := filepath.Join(root, name)
+			created, err := svc.CreateArchive(ctx, filepath.Join(root, "logs"), archive, ArchiveOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, 4, created.Entries)
+			assert.Equal(t, int64(150), created.Bytes)
+			assert.NotZero(t, created.ArchiveSize)
+
+			_, err = svc.CreateArchive(ctx, filepath.Join(root, "logs"), archive, ArchiveOptions{})
+			assert.Error(t, err, "existing archive requires overwrite")
+
+			out := filepath.Join(root, "out")
+			extracted, err := svc.ExtractArchive(ctx, archive, out, ExtractOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, 4, extracted.Entries)
+			assert.Equal(t, int64(150), extracted.Bytes)
+			assert.Equal(t, []string{"logs", "logs/app.log", "logs/archive", "logs/archive/old.log"}, extractedPaths(t, out))
+
+			_, err = svc.ExtractArchive(ctx, archive, out, ExtractOptions{})
+			assert.Error(t, err, "existing files require overwrite")
+			_, err = svc.ExtractArchive(ctx, archive, out, ExtractOptions{Overwrite: true})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestArchive_IncludeExclude(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+	ctx := context.Background()
+
+	archive := filepath.Join(root, "filtered.tar.gz")
+	result, err := svc.CreateArchive(ctx, root, archive, ArchiveOptions{Include: []string{"*.log"}, Exclude: []string{"archive"}})
+	require.NoError(t, err)
+	assert.Contains(t, result.Skipped, "logs/archive")
+
+	out := filepath.Join(root, "out")
+	_, err = svc.ExtractArchive(ctx, archive, out, ExtractOptions{})
+	require.NoError(t, err)
+
+	base := filepath.Base(root)
+	assert.Equal(t, []string{base, base + "/current.log", base + "/logs", base + "/logs/app.log"}, extractedPaths(t, out))
+
+	_, err = svc.CreateArchive(ctx, root, filepath.Join(root, "bad.tar"), ArchiveOptions{Include: []string{"["}})
+	assert.Error(t, err)
+}
+
+func TestArchive_SkipsDeniedPaths(t *testing.T) {
+	root := createListingFixture(t)
+	svc := NewService(prefixValidator{root: root, blocked: []string{filepath.Join(root, "logs", "archive")}}, logging.NewLogger(io.Discard, logging.LogLevelError))
+	ctx := context.Background()
+
+	archive := filepath.Join(root, "logs.tar")
+	result, err := svc.CreateArchive(ctx, filepath.Join(root, "logs"), archive, ArchiveOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"archive"}, result.Skipped)
+	assert.Equal(t, int64(100), result.Bytes)
+
+	out := filepath.Join(root, "out")
+	_, err = svc.ExtractArchive(ctx, archive, out, ExtractOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logs", "logs/app.log"}, extractedPaths(t, out))
+}
+
+func TestArchive_ExtractRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []tarMember
+	}{
+		{"parent escape", []tarMember{{name: "../evil.txt", typeflag: tar.TypeReg, body: "x"}}},
+		{"nested escape", []tarMember{{name: "a/../../evil.txt", typeflag: tar.TypeReg, body: "x"}}},
+		{"absolute path", []tarMember{{name: "/tmp/evil.txt", typeflag: tar.TypeReg, body: "x"}}},
+		{"absolute symlink", []tarMember{{name: "link", typeflag: tar.TypeSymlink, linkname: "/etc"}}},
+		{"escaping symlink", []tarMember{{name: "a/link", typeflag: tar.TypeSymlink, linkname: "../../etc"}}},
+		{"escaping hardlink", []tarMember{{name: "hard", typeflag: tar.TypeLink, linkname: "../outside"}}},
+		{"device file", []tarMember{{name: "dev", typeflag: tar.TypeChar}}},
+		{"fifo", []tarMember{{name: "pipe", typeflag: tar.TypeFifo}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			svc := newTestService(t, root)
+
+			archive := filepath.Join(root, "evil.tar")
+			writeTar(t, archive, tt.members)
+
+			_, err := svc.ExtractArchive(context.Background(), archive, filepath.Join(root, "out"), ExtractOptions{})
+			require.Error(t, err)
+			assert.NoFileExists(t, filepath.Join(root, "evil.txt"))
+		})
+	}
+}
+
+func TestArchive_ExtractDoesNotFollowExistingSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	svc := newTestService(t, root)
+
+	out := filepath.Join(root, "out")
+	require.NoError(t, os.MkdirAll(out, 0755))
+	require.NoError(t, os.Symlink(outside, filepath.Join(out, "escape")))
+
+	archive := filepath.Join(root, "evil.tar")
+	writeTar(t, archive, []tarMember{{name: "escape/pwned.txt", typeflag: tar.TypeReg, body: "x"}})
+
+	_, err := svc.ExtractArchive(context.Background(), archive, out, ExtractOptions{})
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(outside, "pwned.txt"))
+}
+
+func TestArchive_ExtractLimits(t *testing.T) {
+	root := t.TempDir()
+	svc := newTestService(t, root)
+	ctx := context.Background()
+
+	archive := filepath.Join(root, "big.tar")
+	writeTar(t, archive, []tarMember{
+		{name: "a.txt", typeflag: tar.TypeReg, body: "0123456789"},
+		{name: "b.txt", typeflag: tar.TypeReg, body: "0123456789"},
+	})
+
+	_, err := svc.ExtractArchive(ctx, archive, filepath.Join(root, "bytes"), ExtractOptions{MaxBytes: 15})
+	var resp *errors.ErrorResponse
+	require.ErrorAs(t, err, &resp)
+	assert.Equal(t, errors.ErrorCodeResourceExhausted, resp.Code)
+
+	_, err = svc.ExtractArchive(ctx, archive, filepath.Join(root, "entries"), ExtractOptions{MaxEntries: 1})
+	require.ErrorAs(t, err, &resp)
+	assert.Equal(t, errors.ErrorCodeResourceExhausted, resp.Code)
+
+	// Zip entries are rejected on their declared size before any data is written
+	zipPath := filepath.Join(root, "big.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("huge.txt")
+	require.NoError(t, err)
+	_, err = w.Write(bytes.Repeat([]byte("a"), 1024))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(zipPath, buf.Bytes(), 0644))
+
+	_, err = svc.ExtractArchive(ctx, zipPath, filepath.Join(root, "zip"), ExtractOptions{MaxBytes: 100})
+	require.ErrorAs(t, err, &resp)
+	assert.Equal(t, errors.ErrorCodeResourceExhausted, resp.Code)
+	assert.NoFileExists(t, filepath.Join(root, "zip", "huge.txt"))
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	cases := map[string]string{
+		"a.tar":     ArchiveFormatTar,
+		"a.tgz":     ArchiveFormatTarGz,
+		"a.TAR.GZ":  ArchiveFormatTarGz,
+		"a.tar.zst": ArchiveFormatTarZst,
+		"a.zip":     ArchiveFormatZip,
+	}
+	for name, want := range cases {
+		got, err := DetectArchiveFormat(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := DetectArchiveFormat("a.rar")
+	assert.Error(t, err)
+}
+
+func TestTarArchiveWriter_FileChangesSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("12345"), 0644))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	// A file that grew after stat is archived up to its stat size
+	var buf bytes.Buffer
+	aw := &tarArchiveWriter{tw: tar.NewWriter(&buf)}
+	require.NoError(t, aw.add("app.log", info, "", bytes.NewReader([]byte("1234567890"))))
+	require.NoError(t, aw.Close())
+	tr := tar.NewReader(&buf)
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), hdr.Size)
+
+	// A file that shrank fails
+	aw = &tarArchiveWriter{tw: tar.NewWriter(&bytes.Buffer{})}
+	assert.Error(t, aw.add("app.log", info, "", bytes.NewReader([]byte("123"))))
+}
@@ -0,0 +1,374 @@
+package file
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"mini-mcp/internal/types/resources"
+)
+
+const (
+	// DefaultListLimit is the page size used when no limit is requested
+	DefaultListLimit = 500
+	// MaxListLimit is the largest page size a caller may request
+	MaxListLimit = 5000
+	// maxWalkEntries caps how many entries a single listing may visit
+	maxWalkEntries = 100000
+)
+
+// Sort keys supported by ListOptions.SortBy
+const (
+	SortByName  = "name"
+	SortBySize  = "size"
+	SortByMTime = "mtime"
+	SortByType  = "type"
+)
+
+// ListOptions controls how a directory is listed
+type ListOptions struct {
+	// Recursive descends into subdirectories
+	Recursive bool
+	// MaxDepth limits recursion depth (1 lists direct children only, 0 is unlimited)
+	MaxDepth int
+	// IncludeHidden includes dot-files and dot-directories
+	IncludeHidden bool
+	// SortBy orders siblings by name, size, mtime or type
+	SortBy string
+	// Reverse reverses the sort order
+	Reverse bool
+	// Offset is the index of the first entry to return
+	Offset int
+	// Limit is the maximum number of entries to return
+	Limit int
+	// DirSizes computes aggregate sizes for directories
+	DirSizes bool
+}
+
+// normalize applies defaults and bounds to the options
+func (o *ListOptions) normalize() error {
+	switch o.SortBy {
+	case "":
+		o.SortBy = SortByName
+	case SortByName, SortBySize, SortByMTime, SortByType:
+	default:
+		return fmt.Errorf("unsupported sort key: %s", o.SortBy)
+	}
+	if o.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must not be negative")
+	}
+	if o.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	if o.Limit <= 0 {
+		o.Limit = DefaultListLimit
+	}
+	if o.Limit > MaxListLimit {
+		o.Limit = MaxListLimit
+	}
+	if !o.Recursive {
+		o.MaxDepth = 1
+	}
+	return nil
+}
+
+// directoryWalker collects entries below a root directory
+type directoryWalker struct {
+	root     string
+	opts     ListOptions
+	dirSizes map[string]int64
+	// sizesPartial is set when the size walk hit the entry cap
+	sizesPartial bool
+	entries      []resources.FileEntry
+	truncated    bool
+	names        *ownerCache
+	// allowed reports whether the path policy permits an entry; denied
+	// entries are left out and not descended into
+	allowed func(path string) bool
+}
+
+// listDirectory builds a structured listing of root according to opts,
+// leaving out entries the path policy does not allow
+func listDirectory(root string, opts ListOptions, allowed func(path string) bool) (*resources.DirectoryListing, error) {
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", root)
+	}
+
+	w := &directoryWalker{
+		root:    root,
+		opts:    opts,
+		names:   newOwnerCache(),
+		allowed: allowed,
+	}
+
+	if opts.DirSizes {
+		w.dirSizes, w.sizesPartial = aggregateSizes(root, maxWalkEntries, allowed)
+	}
+
+	if err := w.walk(root, "", 0); err != nil {
+		return nil, err
+	}
+
+	listing := &resources.DirectoryListing{
+		Path:         root,
+		TotalEntries: len(w.entries),
+		Offset:       opts.Offset,
+		Limit:        opts.Limit,
+		Truncated:    w.truncated,
+		SizesPartial: w.sizesPartial,
+		Entries:      make([]resources.FileEntry, 0),
+	}
+	if w.dirSizes != nil {
+		listing.TotalSize = w.dirSizes[""]
+	}
+
+	if opts.Offset < len(w.entries) {
+		end := opts.Offset + opts.Limit
+		if end > len(w.entries) {
+			end = len(w.entries)
+		}
+		listing.Entries = w.entries[opts.Offset:end]
+		if end < len(w.entries) {
+			listing.HasMore = true
+			listing.NextOffset = end
+		}
+	}
+
+	return listing, nil
+}
+
+// walk appends the children of dir in tree order, descending as allowed by the options
+func (w *directoryWalker) walk(dir, rel string, depth int) error {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		if depth == 0 {
+			return err
+		}
+		// Unreadable subdirectories are skipped rather than failing the listing
+		return nil
+	}
+
+	children := make([]resources.FileEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		if !w.opts.IncludeHidden && strings.HasPrefix(de.Name(), ".") {
+			continue
+		}
+		if !w.allowed(filepath.Join(dir, de.Name())) {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		children = append(children, w.newEntry(dir, rel, depth, info))
+	}
+
+	sortEntries(children, w.opts.SortBy, w.opts.Reverse)
+
+	for _, child := range children {
+		if len(w.entries) >= maxWalkEntries {
+			w.truncated = true
+			return nil
+		}
+		w.entries = append(w.entries, child)
+
+		if child.Type == "dir" && (w.opts.MaxDepth == 0 || depth+1 < w.opts.MaxDepth) {
+			if err := w.walk(filepath.Join(dir, child.Name), child.Path, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// newEntry converts file info into a listing entry
+func (w *directoryWalker) newEntry(dir, rel string, depth int, info fs.FileInfo) resources.FileEntry {
+	entry := resources.FileEntry{
+		Name:        info.Name(),
+		Path:        filepath.Join(rel, info.Name()),
+		Type:        fileType(info.Mode()),
+		Size:        info.Size(),
+		Mode:        info.Mode().String(),
+		Permissions: fmt.Sprintf("%04o", info.Mode().Perm()),
+		ModTime:     info.ModTime(),
+		Hidden:      strings.HasPrefix(info.Name(), "."),
+		Depth:       depth,
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		entry.UID = stat.Uid
+		entry.GID = stat.Gid
+		entry.Owner = w.names.userName(stat.Uid)
+		entry.Group = w.names.groupName(stat.Gid)
+	}
+
+	if entry.Type == "symlink" {
+		if target, err := os.Readlink(filepath.Join(dir, info.Name())); err == nil {
+			entry.LinkTarget = target
+		}
+	}
+
+	if entry.Type == "dir" && w.dirSizes != nil {
+		entry.TotalSize = w.dirSizes[entry.Path]
+	}
+
+	return entry
+}
+
+// fileType maps a file mode to a listing type marker
+func fileType(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "dir"
+	case mode&fs.ModeSymlink != 0:
+		return "symlink"
+	case mode&fs.ModeSocket != 0:
+		return "socket"
+	case mode&fs.ModeNamedPipe != 0:
+		return "pipe"
+	case mode&fs.ModeCharDevice != 0:
+		return "char_device"
+	case mode&fs.ModeDevice != 0:
+		return "device"
+	case mode.IsRegular():
+		return "file"
+	default:
+		return "other"
+	}
+}
+
+// sortEntries orders sibling entries by the given key
+func sortEntries(entries []resources.FileEntry, by string, reverse bool) {
+	less := func(a, b resources.FileEntry) bool {
+		switch by {
+		case SortBySize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case SortByMTime:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.Before(b.ModTime)
+			}
+		case SortByType:
+			if a.Type != b.Type {
+				// Directories first, then alphabetically by type marker
+				if a.Type == "dir" || b.Type == "dir" {
+					return a.Type == "dir"
+				}
+				return a.Type < b.Type
+			}
+		}
+		return a.Name < b.Name
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if reverse {
+			return less(entries[j], entries[i])
+		}
+		return less(entries[i], entries[j])
+	})
+}
+
+// aggregateSizes returns the total size of regular files below each directory,
+// keyed by path relative to root ("" is the root itself). Entries the path
+// policy does not allow are not counted. The walk stops after limit entries;
+// partial reports whether it did.
+func aggregateSizes(root string, limit int, allowed func(path string) bool) (sizes map[string]int64, partial bool) {
+	sizes = make(map[string]int64)
+	visited := 0
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if visited++; visited > limit {
+			partial = true
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return nil
+		}
+		if path != root && !allowed(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		for {
+			if rel == "." {
+				rel = ""
+			}
+			sizes[rel] += info.Size()
+			if rel == "" {
+				break
+			}
+			rel = filepath.Dir(rel)
+		}
+		return nil
+	})
+	return sizes, partial
+}
+
+// ownerCache memoizes UID/GID to name lookups for a single listing
+type ownerCache struct {
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+// newOwnerCache creates an empty owner cache
+func newOwnerCache() *ownerCache {
+	return &ownerCache{
+		users:  make(map[uint32]string),
+		groups: make(map[uint32]string),
+	}
+}
+
+// userName resolves a UID, falling back to its numeric form
+func (c *ownerCache) userName(uid uint32) string {
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+	id := strconv.FormatUint(uint64(uid), 10)
+	name := id
+	if u, err := user.LookupId(id); err == nil {
+		name = u.Username
+	}
+	c.users[uid] = name
+	return name
+}
+
+// groupName resolves a GID, falling back to its numeric form
+func (c *ownerCache) groupName(gid uint32) string {
+	if name, ok := c.groups[gid]; ok {
+		return name
+	}
+	id := strconv.FormatUint(uint64(gid), 10)
+	name := id
+	if g, err := user.LookupGroupId(id); err == nil {
+		name = g.Name
+	}
+	c.groups[gid] = name
+	return name
+}
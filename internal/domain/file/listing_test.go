@@ -0,0 +1,138 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowAll is a path policy that permits everything
+func allowAll(string) bool { return true }
+
+func createListingFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "logs", "archive"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.conf"), []byte("key=value\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".env"), []byte("SECRET=1\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "logs", "app.log"), make([]byte, 100), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "logs", "archive", "old.log"), make([]byte, 50), 0644))
+	require.NoError(t, os.Symlink("logs/app.log", filepath.Join(root, "current.log")))
+
+	return root
+}
+
+func entryPaths(t *testing.T, root string, opts ListOptions) []string {
+	t.Helper()
+	listing, err := listDirectory(root, opts, allowAll)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(listing.Entries))
+	for _, entry := range listing.Entries {
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
+
+func TestListDirectory_Flat(t *testing.T) {
+	root := createListingFixture(t)
+
+	listing, err := listDirectory(root, ListOptions{}, allowAll)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, listing.TotalEntries)
+	assert.Equal(t, []string{"app.conf", "current.log", "logs"}, entryPaths(t, root, ListOptions{}))
+
+	byName := make(map[string]string)
+	for _, entry := range listing.Entries {
+		byName[entry.Name] = entry.Type
+		if entry.Name == "current.log" {
+			assert.Equal(t, "logs/app.log", entry.LinkTarget)
+		}
+		if entry.Name == "app.conf" {
+			assert.Equal(t, "0644", entry.Permissions)
+			assert.Equal(t, int64(10), entry.Size)
+			assert.NotEmpty(t, entry.Owner)
+		}
+	}
+	assert.Equal(t, map[string]string{"app.conf": "file", "current.log": "symlink", "logs": "dir"}, byName)
+}
+
+func TestListDirectory_RecursiveAndHidden(t *testing.T) {
+	root := createListingFixture(t)
+
+	paths := entryPaths(t, root, ListOptions{Recursive: true, IncludeHidden: true})
+	assert.Equal(t, []string{
+		".env",
+		"app.conf",
+		"current.log",
+		"logs",
+		"logs/app.log",
+		"logs/archive",
+		"logs/archive/old.log",
+	}, paths)
+
+	paths = entryPaths(t, root, ListOptions{Recursive: true, MaxDepth: 2})
+	assert.Equal(t, []string{"app.conf", "current.log", "logs", "logs/app.log", "logs/archive"}, paths)
+}
+
+func TestListDirectory_SortAndPagination(t *testing.T) {
+	root := createListingFixture(t)
+
+	paths := entryPaths(t, root, ListOptions{SortBy: SortByType})
+	assert.Equal(t, "logs", paths[0])
+
+	listing, err := listDirectory(root, ListOptions{Recursive: true, Limit: 2, Offset: 1}, allowAll)
+	require.NoError(t, err)
+	assert.Equal(t, 6, listing.TotalEntries)
+	assert.Len(t, listing.Entries, 2)
+	assert.True(t, listing.HasMore)
+	assert.Equal(t, 3, listing.NextOffset)
+
+	_, err = listDirectory(root, ListOptions{SortBy: "owner"}, allowAll)
+	assert.Error(t, err)
+}
+
+func TestListDirectory_DirSizes(t *testing.T) {
+	root := createListingFixture(t)
+
+	listing, err := listDirectory(root, ListOptions{DirSizes: true}, allowAll)
+	require.NoError(t, err)
+
+	// app.conf (10) + .env (9) + logs/app.log (100) + logs/archive/old.log (50)
+	assert.Equal(t, int64(169), listing.TotalSize)
+	for _, entry := range listing.Entries {
+		if entry.Name == "logs" {
+			assert.Equal(t, int64(150), entry.TotalSize)
+		}
+	}
+	assert.False(t, listing.SizesPartial)
+
+	// The size walk stops at the entry cap and says so
+	sizes, partial := aggregateSizes(root, 3, allowAll)
+	assert.True(t, partial)
+	assert.Less(t, sizes[""], int64(169))
+}
+
+func TestListDirectory_SkipsDeniedPaths(t *testing.T) {
+	root := createListingFixture(t)
+	policy := prefixValidator{root: root, blocked: []string{filepath.Join(root, "logs", "archive")}}
+
+	listing, err := listDirectory(root, ListOptions{Recursive: true, DirSizes: true}, policy.IsPathAllowed)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(listing.Entries))
+	for _, entry := range listing.Entries {
+		paths = append(paths, entry.Path)
+		if entry.Name == "logs" {
+			assert.Equal(t, int64(100), entry.TotalSize)
+		}
+	}
+	assert.Equal(t, []string{"app.conf", "current.log", "logs", "logs/app.log"}, paths)
+	// app.conf (10) + .env (9) + logs/app.log (100)
+	assert.Equal(t, int64(119), listing.TotalSize)
+}
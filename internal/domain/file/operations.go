@@ -0,0 +1,605 @@
+package file
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"mini-mcp/internal/shared/errors"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// Checksum algorithms supported by Checksum
+const (
+	ChecksumSHA256 = "sha256"
+	ChecksumMD5    = "md5"
+)
+
+// forbiddenModeBits are permission bits chmod refuses to set
+const forbiddenModeBits = os.ModeSetuid | os.ModeSetgid | 0o002
+
+// CopyOptions controls how CopyPath copies files
+type CopyOptions struct {
+	// Recursive allows copying directories
+	Recursive bool
+	// Overwrite replaces existing destination files
+	Overwrite bool
+	// PreserveOwner keeps UID/GID (only effective when running with sufficient privileges)
+	PreserveOwner bool
+}
+
+// ParseMode parses an octal permission string such as "755" or "0644"
+func ParseMode(value string) (os.FileMode, error) {
+	bits, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32)
+	if err != nil || bits > 0o7777 {
+		return 0, fmt.Errorf("invalid octal mode: %q", value)
+	}
+
+	mode := os.FileMode(bits & 0o777)
+	if bits&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode, nil
+}
+
+// CreateDirectory creates a directory and any missing parents
+func (s *ServiceImpl) CreateDirectory(ctx context.Context, path string, mode os.FileMode) error {
+	if err := s.validate(ctx, "mkdir", path); err != nil {
+		return err
+	}
+
+	if mode == 0 {
+		mode = 0755
+	}
+	if mode&forbiddenModeBits != 0 {
+		return s.policyDenied(ctx, "mkdir", path, "directory mode must not be world-writable or set setuid/setgid bits")
+	}
+
+	if err := os.MkdirAll(path, mode); err != nil {
+		return s.wrapFSError(ctx, "mkdir", path, err)
+	}
+
+	s.audit(ctx, "mkdir", map[string]any{"path": path, "mode": fmt.Sprintf("%04o", mode.Perm())})
+	return nil
+}
+
+// MovePath moves or renames a file or directory
+func (s *ServiceImpl) MovePath(ctx context.Context, src, dst string, overwrite bool) error {
+	if err := s.validate(ctx, "move", src, dst); err != nil {
+		return err
+	}
+
+	target, err := resolveDestination(src, dst, overwrite)
+	if err != nil {
+		return s.wrapFSError(ctx, "move", dst, err)
+	}
+	// The target may lie below a symlinked directory
+	if err := s.validate(ctx, "move", realPath(target)); err != nil {
+		return err
+	}
+	// Moving a tree would carry the paths below it that the policy denies
+	if denied := firstDenied(src, s.securityValidator.IsPathAllowed); denied != "" {
+		return s.policyDenied(ctx, "move", denied, "path is not allowed by the file access policy")
+	}
+
+	if err := os.Rename(src, target); err != nil {
+		var linkErr *os.LinkError
+		if !stderrors.As(err, &linkErr) || !stderrors.Is(linkErr.Err, syscall.EXDEV) {
+			return s.wrapFSError(ctx, "move", src, err)
+		}
+
+		// Cross-device move: copy then remove the source
+		result, err := copyTree(src, target, CopyOptions{Recursive: true, Overwrite: overwrite, PreserveOwner: true}, s.securityValidator.IsPathAllowed)
+		if err != nil {
+			return s.wrapFSError(ctx, "move", src, err)
+		}
+		if len(result.Skipped) > 0 {
+			return s.policyDenied(ctx, "move", src, "source kept because these entries were not copied: "+strings.Join(result.Skipped, ", "))
+		}
+		if err := os.RemoveAll(src); err != nil {
+			return s.wrapFSError(ctx, "move", src, err)
+		}
+	}
+
+	s.audit(ctx, "move", map[string]any{"source": src, "destination": target})
+	return nil
+}
+
+// CopyPath copies a file or directory tree, preserving mode and timestamps
+func (s *ServiceImpl) CopyPath(ctx context.Context, src, dst string, opts CopyOptions) (*resources.CopyResult, error) {
+	if err := s.validate(ctx, "copy", src, dst); err != nil {
+		return nil, err
+	}
+
+	target, err := resolveDestination(src, dst, opts.Overwrite)
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "copy", dst, err)
+	}
+	// The target may lie below a symlinked directory
+	if err := s.validate(ctx, "copy", realPath(target)); err != nil {
+		return nil, err
+	}
+
+	result, err := copyTree(src, target, opts, s.securityValidator.IsPathAllowed)
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "copy", src, err)
+	}
+
+	s.audit(ctx, "copy", map[string]any{
+		"source":      src,
+		"destination": target,
+		"files":       result.Files,
+		"bytes":       result.Bytes,
+	})
+	return result, nil
+}
+
+// ChangeMode changes permission bits on a path
+func (s *ServiceImpl) ChangeMode(ctx context.Context, path string, mode os.FileMode, recursive bool) (int, error) {
+	if err := s.validate(ctx, "chmod", path); err != nil {
+		return 0, err
+	}
+	if mode&forbiddenModeBits != 0 {
+		return 0, s.policyDenied(ctx, "chmod", path, "mode must not be world-writable or set setuid/setgid bits")
+	}
+
+	changed := 0
+	err := applyToTree(path, recursive, s.securityValidator.IsPathAllowed, func(p string, d fs.DirEntry) error {
+		// chmod follows symlinks, so links inside the tree are left alone
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		if err := os.Chmod(p, mode); err != nil {
+			return err
+		}
+		changed++
+		return nil
+	})
+	if err != nil {
+		return changed, s.wrapFSError(ctx, "chmod", path, err)
+	}
+
+	s.audit(ctx, "chmod", map[string]any{
+		"path":      path,
+		"mode":      fmt.Sprintf("%04o", mode.Perm()),
+		"recursive": recursive,
+		"changed":   changed,
+	})
+	return changed, nil
+}
+
+// ChangeOwner changes the owning user and/or group of a path
+func (s *ServiceImpl) ChangeOwner(ctx context.Context, path, owner, group string, recursive bool) (int, error) {
+	if err := s.validate(ctx, "chown", path); err != nil {
+		return 0, err
+	}
+
+	uid, gid := -1, -1
+	if owner != "" {
+		id, err := lookupUID(owner)
+		if err != nil {
+			return 0, errors.NewInvalidInputError(err.Error())
+		}
+		uid = id
+	}
+	if group != "" {
+		id, err := lookupGID(group)
+		if err != nil {
+			return 0, errors.NewInvalidInputError(err.Error())
+		}
+		gid = id
+	}
+	if uid == 0 || gid == 0 {
+		return 0, s.policyDenied(ctx, "chown", path, "ownership cannot be transferred to root")
+	}
+
+	changed := 0
+	err := applyToTree(path, recursive, s.securityValidator.IsPathAllowed, func(p string, d fs.DirEntry) error {
+		if err := os.Lchown(p, uid, gid); err != nil {
+			return err
+		}
+		changed++
+		return nil
+	})
+	if err != nil {
+		return changed, s.wrapFSError(ctx, "chown", path, err)
+	}
+
+	s.audit(ctx, "chown", map[string]any{
+		"path":      path,
+		"owner":     owner,
+		"group":     group,
+		"recursive": recursive,
+		"changed":   changed,
+	})
+	return changed, nil
+}
+
+// StatPath returns detailed metadata for a path without following symlinks
+func (s *ServiceImpl) StatPath(ctx context.Context, path string) (*resources.FileStat, error) {
+	if err := s.validate(ctx, "stat", path); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "stat", path, err)
+	}
+
+	w := &directoryWalker{names: newOwnerCache()}
+	entry := w.newEntry(filepath.Dir(path), "", 0, info)
+	entry.Path = path
+
+	stat := &resources.FileStat{FileEntry: entry}
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		stat.Inode = uint64(sys.Ino)
+		stat.Links = uint64(sys.Nlink)
+		stat.Device = uint64(sys.Dev)
+		stat.Blocks = int64(sys.Blocks)
+		stat.BlockSize = int64(sys.Blksize)
+		stat.AccessTime = time.Unix(statAtime(sys))
+		stat.ChangeTime = time.Unix(statCtime(sys))
+	}
+
+	return stat, nil
+}
+
+// Checksum computes a digest of a regular file
+func (s *ServiceImpl) Checksum(ctx context.Context, path, algorithm string) (*resources.FileChecksum, error) {
+	if err := s.validate(ctx, "checksum", path); err != nil {
+		return nil, err
+	}
+
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "", ChecksumSHA256:
+		algorithm = ChecksumSHA256
+		h = sha256.New()
+	case ChecksumMD5:
+		algorithm = ChecksumMD5
+		h = md5.New()
+	default:
+		return nil, errors.NewInvalidFormatError("algorithm", "sha256 or md5")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "checksum", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "checksum", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, errors.NewInvalidInputError(fmt.Sprintf("not a regular file: %s", path))
+	}
+
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "checksum", path, err)
+	}
+
+	return &resources.FileChecksum{
+		Path:      path,
+		Algorithm: algorithm,
+		Checksum:  hex.EncodeToString(h.Sum(nil)),
+		Size:      size,
+	}, nil
+}
+
+// validate runs every path through the security validator
+func (s *ServiceImpl) validate(ctx context.Context, operation string, paths ...string) error {
+	for _, path := range paths {
+		if err := s.securityValidator.ValidatePath(path); err != nil {
+			s.log(ctx).Error("Path validation failed for "+operation+" operation", err, map[string]any{
+				"path":      path,
+				"operation": operation,
+			})
+			return errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for "+operation+" operation").
+				WithRequestID(logging.CorrelationID(ctx))
+		}
+	}
+	return nil
+}
+
+// policyDenied logs and returns a file access policy violation
+func (s *ServiceImpl) policyDenied(ctx context.Context, operation, path, reason string) error {
+	s.log(ctx).Warning("File operation denied by policy", map[string]any{
+		"path":      path,
+		"operation": operation,
+		"reason":    reason,
+	})
+	return errors.NewFileAccessError(path, operation, reason).WithRequestID(logging.CorrelationID(ctx))
+}
+
+// wrapFSError maps filesystem errors onto structured error responses
+func (s *ServiceImpl) wrapFSError(ctx context.Context, operation, path string, err error) error {
+	s.log(ctx).Error("File operation failed", err, map[string]any{
+		"path":      path,
+		"operation": operation,
+	})
+	var response *errors.ErrorResponse
+	switch {
+	case os.IsNotExist(err):
+		response = errors.NewFileNotFoundError(path)
+	case os.IsPermission(err):
+		response = errors.NewPermissionDeniedError(path)
+	default:
+		response = errors.WrapError(err, errors.ErrorCodeInternalError, fmt.Sprintf("Failed to %s %s", operation, path))
+	}
+	return response.WithRequestID(logging.CorrelationID(ctx))
+}
+
+// audit records a successful mutating or inspecting file operation
+func (s *ServiceImpl) audit(ctx context.Context, operation string, fields map[string]any) {
+	fields["operation"] = operation
+	s.log(ctx).Info("File operation completed", fields)
+}
+
+// log returns the logger for the tool call ctx belongs to
+func (s *ServiceImpl) log(ctx context.Context) logging.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
+// resolveDestination applies cp/mv semantics: an existing directory receives src by name
+func resolveDestination(src, dst string, overwrite bool) (string, error) {
+	if _, err := os.Lstat(src); err != nil {
+		return "", err
+	}
+
+	target := dst
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		target = filepath.Join(dst, filepath.Base(src))
+	}
+
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return "", err
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	if absTarget == absSrc || strings.HasPrefix(absTarget, absSrc+string(filepath.Separator)) {
+		return "", fmt.Errorf("cannot copy or move %s into itself", src)
+	}
+
+	if info, err := os.Lstat(target); err == nil {
+		if !overwrite {
+			return "", fmt.Errorf("destination already exists: %s", target)
+		}
+		// Overwriting through a link would write wherever it points
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("destination is a symlink: %s", target)
+		}
+	}
+
+	return target, nil
+}
+
+// realPath resolves the symlinks in the directory of path, leaving path as is
+// when the directory cannot be resolved
+func realPath(path string) string {
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return path
+	}
+	return filepath.Join(dir, filepath.Base(path))
+}
+
+// copyTree copies src to dst, recreating symlinks and preserving metadata.
+// Entries whose source or destination the path policy denies are skipped.
+func copyTree(src, dst string, opts CopyOptions, allowed func(path string) bool) (*resources.CopyResult, error) {
+	result := &resources.CopyResult{Source: src, Destination: dst}
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() && !opts.Recursive {
+		return nil, fmt.Errorf("%s is a directory (set recursive to copy it)", src)
+	}
+
+	// Directory timestamps are applied last, since writing children updates them
+	type dirTimes struct {
+		path string
+		info fs.FileInfo
+	}
+	var dirs []dirTimes
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if !allowed(path) || !allowed(target) {
+			result.Skipped = append(result.Skipped, rel)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.IsDir():
+			if err := os.MkdirAll(target, info.Mode().Perm()); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirTimes{path: target, info: info})
+			result.Directories++
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if opts.Overwrite {
+				_ = os.Remove(target)
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+			result.Symlinks++
+		case info.Mode().IsRegular():
+			n, err := copyFile(path, target, info, opts.Overwrite)
+			if err != nil {
+				return err
+			}
+			result.Files++
+			result.Bytes += n
+		default:
+			// Devices, sockets and pipes are not copied
+			result.Skipped = append(result.Skipped, rel)
+			return nil
+		}
+
+		if opts.PreserveOwner {
+			preserveOwner(target, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Chmod(dirs[i].path, dirs[i].info.Mode().Perm())
+		_ = os.Chtimes(dirs[i].path, dirs[i].info.ModTime(), dirs[i].info.ModTime())
+	}
+
+	return result, nil
+}
+
+// copyFile copies a regular file's contents, mode and modification time
+func copyFile(src, dst string, info fs.FileInfo, overwrite bool) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = in.Close() }()
+
+	// An existing symlink at dst is refused rather than followed
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC | syscall.O_NOFOLLOW
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+	out, err := os.OpenFile(dst, flags, info.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return n, err
+	}
+
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return n, err
+	}
+	return n, os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// preserveOwner copies UID/GID when permitted; failures are ignored for unprivileged runs
+func preserveOwner(path string, info fs.FileInfo) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		_ = os.Lchown(path, int(stat.Uid), int(stat.Gid))
+	}
+}
+
+// applyToTree calls fn for path and, when recursive, everything below it that
+// the path policy allows
+func applyToTree(path string, recursive bool, allowed func(path string) bool, fn func(string, fs.DirEntry) error) error {
+	if !recursive {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		return fn(path, fs.FileInfoToDirEntry(info))
+	}
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != path && !allowed(p) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(p, d)
+	})
+}
+
+// firstDenied returns the first path below root that the path policy does
+// not allow, or "" when it allows all of them
+func firstDenied(root string, allowed func(path string) bool) string {
+	var denied string
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if p != root && !allowed(p) {
+			denied = p
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return denied
+}
+
+// lookupUID resolves a user name or numeric UID
+func lookupUID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown user: %s", name)
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+// lookupGID resolves a group name or numeric GID
+func lookupGID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown group: %s", name)
+	}
+	return strconv.Atoi(g.Gid)
+}
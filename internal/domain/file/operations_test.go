@@ -0,0 +1,243 @@
+package file
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prefixValidator allows only paths below a fixed root, except those below
+// one of the blocked paths
+type prefixValidator struct {
+	root    string
+	blocked []string
+}
+
+func (v prefixValidator) ValidatePath(path string) error {
+	if !v.IsPathAllowed(path) {
+		return ErrPathNotAllowed
+	}
+	return nil
+}
+
+func (v prefixValidator) IsPathAllowed(path string) bool {
+	path = filepath.Clean(path)
+	for _, blocked := range v.blocked {
+		if path == blocked || strings.HasPrefix(path, blocked+string(filepath.Separator)) {
+			return false
+		}
+	}
+	return strings.HasPrefix(path, v.root)
+}
+
+func newTestService(t *testing.T, root string) *ServiceImpl {
+	t.Helper()
+	return NewService(prefixValidator{root: root}, logging.NewLogger(io.Discard, logging.LogLevelError)).(*ServiceImpl)
+}
+
+func TestCreateDirectory(t *testing.T) {
+	root := t.TempDir()
+	svc := newTestService(t, root)
+	ctx := context.Background()
+
+	target := filepath.Join(root, "a", "b", "c")
+	require.NoError(t, svc.CreateDirectory(ctx, target, 0750))
+
+	info, err := os.Stat(target)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	assert.Error(t, svc.CreateDirectory(ctx, filepath.Join(root, "open"), 0777))
+	assert.Error(t, svc.CreateDirectory(ctx, "/elsewhere/dir", 0755))
+}
+
+func TestCopyPath_PreservesMetadata(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+	ctx := context.Background()
+
+	src := filepath.Join(root, "logs")
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(src, "app.log"), old, old))
+	require.NoError(t, os.Chmod(filepath.Join(src, "app.log"), 0640))
+	require.NoError(t, os.Symlink("app.log", filepath.Join(src, "latest")))
+
+	_, err := svc.CopyPath(ctx, src, filepath.Join(root, "backup"), CopyOptions{})
+	assert.Error(t, err, "directories require recursive")
+
+	result, err := svc.CopyPath(ctx, src, filepath.Join(root, "backup"), CopyOptions{Recursive: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Files)
+	assert.Equal(t, 2, result.Directories)
+	assert.Equal(t, 1, result.Symlinks)
+	assert.Equal(t, int64(150), result.Bytes)
+
+	info, err := os.Stat(filepath.Join(root, "backup", "app.log"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	assert.True(t, info.ModTime().Equal(old))
+
+	link, err := os.Readlink(filepath.Join(root, "backup", "latest"))
+	require.NoError(t, err)
+	assert.Equal(t, "app.log", link)
+
+	// Copying into an existing directory places the source inside it
+	result, err = svc.CopyPath(ctx, filepath.Join(root, "app.conf"), filepath.Join(root, "backup"), CopyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "backup", "app.conf"), result.Destination)
+
+	_, err = svc.CopyPath(ctx, filepath.Join(root, "app.conf"), filepath.Join(root, "backup"), CopyOptions{})
+	assert.Error(t, err, "existing destination requires overwrite")
+
+	_, err = svc.CopyPath(ctx, src, filepath.Join(src, "archive", "nested"), CopyOptions{Recursive: true})
+	assert.Error(t, err, "copy into itself")
+}
+
+func TestMovePath(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+	ctx := context.Background()
+
+	require.NoError(t, svc.MovePath(ctx, filepath.Join(root, "app.conf"), filepath.Join(root, "app.conf.bak"), false))
+	assert.NoFileExists(t, filepath.Join(root, "app.conf"))
+	assert.FileExists(t, filepath.Join(root, "app.conf.bak"))
+
+	require.NoError(t, svc.MovePath(ctx, filepath.Join(root, "app.conf.bak"), filepath.Join(root, "logs"), false))
+	assert.FileExists(t, filepath.Join(root, "logs", "app.conf.bak"))
+
+	assert.Error(t, svc.MovePath(ctx, filepath.Join(root, "logs", "app.log"), "/elsewhere/app.log", false))
+}
+
+func TestCopyPath_RefusesSymlinkedDestinations(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+	ctx := context.Background()
+
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("keep"), 0644))
+	require.NoError(t, os.Symlink(secret, filepath.Join(root, "link")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "out")))
+
+	src := filepath.Join(root, "app.conf")
+	_, err := svc.CopyPath(ctx, src, filepath.Join(root, "link"), CopyOptions{Overwrite: true})
+	assert.Error(t, err)
+	_, err = svc.CopyPath(ctx, src, filepath.Join(root, "out"), CopyOptions{Overwrite: true})
+	assert.Error(t, err)
+	assert.Error(t, svc.MovePath(ctx, src, filepath.Join(root, "out"), true))
+
+	data, err := os.ReadFile(secret)
+	require.NoError(t, err)
+	assert.Equal(t, "keep", string(data))
+	assert.NoFileExists(t, filepath.Join(outside, "app.conf"))
+}
+
+func TestChangeMode_Policy(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+	ctx := context.Background()
+
+	changed, err := svc.ChangeMode(ctx, filepath.Join(root, "logs"), 0750, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, changed)
+
+	info, err := os.Stat(filepath.Join(root, "logs", "archive", "old.log"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+
+	_, err = svc.ChangeMode(ctx, filepath.Join(root, "app.conf"), 0666, false)
+	assert.Error(t, err)
+	_, err = svc.ChangeMode(ctx, filepath.Join(root, "app.conf"), 0755|os.ModeSetuid, false)
+	assert.Error(t, err)
+}
+
+func TestTreeOperations_SkipDeniedPaths(t *testing.T) {
+	root := createListingFixture(t)
+	blocked := filepath.Join(root, "logs", "archive")
+	svc := NewService(prefixValidator{root: root, blocked: []string{blocked}}, logging.NewLogger(io.Discard, logging.LogLevelError)).(*ServiceImpl)
+	ctx := context.Background()
+	src := filepath.Join(root, "logs")
+
+	result, err := svc.CopyPath(ctx, src, filepath.Join(root, "backup"), CopyOptions{Recursive: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Files)
+	assert.Equal(t, []string{"archive"}, result.Skipped)
+	assert.NoDirExists(t, filepath.Join(root, "backup", "archive"))
+
+	changed, err := svc.ChangeMode(ctx, src, 0750, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, changed)
+	info, err := os.Stat(filepath.Join(blocked, "old.log"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	// Moving the tree would carry the denied directory along
+	assert.Error(t, svc.MovePath(ctx, src, filepath.Join(root, "moved"), false))
+	assert.DirExists(t, blocked)
+}
+
+func TestChangeOwner_DeniesRoot(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+
+	_, err := svc.ChangeOwner(context.Background(), filepath.Join(root, "app.conf"), "0", "", false)
+	assert.Error(t, err)
+}
+
+func TestStatPath(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+
+	stat, err := svc.StatPath(context.Background(), filepath.Join(root, "current.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "symlink", stat.Type)
+	assert.Equal(t, "logs/app.log", stat.LinkTarget)
+	assert.NotZero(t, stat.Inode)
+	assert.NotZero(t, stat.Links)
+}
+
+func TestChecksum(t *testing.T) {
+	root := createListingFixture(t)
+	svc := newTestService(t, root)
+	ctx := context.Background()
+	path := filepath.Join(root, "app.conf")
+
+	sum, err := svc.Checksum(ctx, path, "")
+	require.NoError(t, err)
+	assert.Equal(t, ChecksumSHA256, sum.Algorithm)
+	assert.Equal(t, "d5c5f09b69f25bf5059606bc891a4bdaac96e4ba058fc001cab9a8a4b9ee7c39", sum.Checksum)
+	assert.Equal(t, int64(10), sum.Size)
+
+	sum, err = svc.Checksum(ctx, path, "md5")
+	require.NoError(t, err)
+	assert.Len(t, sum.Checksum, 32)
+
+	_, err = svc.Checksum(ctx, path, "sha1")
+	assert.Error(t, err)
+	_, err = svc.Checksum(ctx, filepath.Join(root, "logs"), "")
+	assert.Error(t, err)
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("0755")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), mode)
+
+	mode, err = ParseMode("4755")
+	require.NoError(t, err)
+	assert.NotZero(t, mode&os.ModeSetuid)
+
+	_, err = ParseMode("abc")
+	assert.Error(t, err)
+	_, err = ParseMode("17777")
+	assert.Error(t, err)
+}
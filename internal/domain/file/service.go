@@ -9,6 +9,7 @@ import (
 	"mini-mcp/internal/shared/errors"
 	"mini-mcp/internal/shared/logging"
 	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/types/resources"
 )
 
 var (
@@ -20,8 +21,18 @@ var (
 type Service interface {
 	ReadFile(ctx context.Context, path string) (string, error)
 	WriteFile(ctx context.Context, path, content string) error
-	ListDirectory(ctx context.Context, path string) (string, error)
+	ListDirectory(ctx context.Context, path string, opts ListOptions) (*resources.DirectoryListing, error)
 	DeleteFile(ctx context.Context, path string) error
+	CreateDirectory(ctx context.Context, path string, mode os.FileMode) error
+	MovePath(ctx context.Context, src, dst string, overwrite bool) error
+	CopyPath(ctx context.Context, src, dst string, opts CopyOptions) (*resources.CopyResult, error)
+	ChangeMode(ctx context.Context, path string, mode os.FileMode, recursive bool) (int, error)
+	ChangeOwner(ctx context.Context, path, owner, group string, recursive bool) (int, error)
+	StatPath(ctx context.Context, path string) (*resources.FileStat, error)
+	Checksum(ctx context.Context, path, algorithm string) (*resources.FileChecksum, error)
+	CreateArchive(ctx context.Context, src, archive string, opts ArchiveOptions) (*resources.ArchiveResult, error)
+	ExtractArchive(ctx context.Context, archive, dest string, opts ExtractOptions) (*resources.ArchiveResult, error)
+	TailFile(ctx context.Context, path string, opts TailOptions) (*resources.FileTail, error)
 }
 
 // ServiceImpl implements the file domain service
@@ -42,7 +53,7 @@ func NewService(securityValidator security.PathValidator, logger logging.Logger)
 func (s *ServiceImpl) ReadFile(ctx context.Context, path string) (string, error) {
 	// Validate path using security validator
 	if err := s.securityValidator.ValidatePath(path); err != nil {
-		s.logger.Error("Path validation failed for read operation", err, map[string]any{
+		s.log(ctx).Error("Path validation failed for read operation", err, map[string]any{
 			"path": path,
 		})
 		return "", errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for read operation")
@@ -51,25 +62,25 @@ func (s *ServiceImpl) ReadFile(ctx context.Context, path string) (string, error)
 	content, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			s.logger.Error("File not found", err, map[string]any{
+			s.log(ctx).Error("File not found", err, map[string]any{
 				"path": path,
 			})
 			return "", errors.NewFileNotFoundError(path)
 		}
 		if os.IsPermission(err) {
-			s.logger.Error("Permission denied", err, map[string]any{
+			s.log(ctx).Error("Permission denied", err, map[string]any{
 				"path": path,
 			})
 			return "", errors.NewPermissionDeniedError(path)
 		}
 
-		s.logger.Error("Failed to read file", err, map[string]any{
+		s.log(ctx).Error("Failed to read file", err, map[string]any{
 			"path": path,
 		})
 		return "", errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to read file")
 	}
 
-	s.logger.Debug("File read successfully", map[string]any{
+	s.log(ctx).Debug("File read successfully", map[string]any{
 		"path":         path,
 		"content_size": len(content),
 	})
@@ -81,7 +92,7 @@ func (s *ServiceImpl) ReadFile(ctx context.Context, path string) (string, error)
 func (s *ServiceImpl) WriteFile(ctx context.Context, path, content string) error {
 	// Validate path using security validator
 	if err := s.securityValidator.ValidatePath(path); err != nil {
-		s.logger.Error("Path validation failed for write operation", err, map[string]any{
+		s.log(ctx).Error("Path validation failed for write operation", err, map[string]any{
 			"path": path,
 		})
 		return errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for write operation")
@@ -90,7 +101,7 @@ func (s *ServiceImpl) WriteFile(ctx context.Context, path, content string) error
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		s.logger.Error("Failed to create directory", err, map[string]any{
+		s.log(ctx).Error("Failed to create directory", err, map[string]any{
 			"directory": dir,
 		})
 		return errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to create directory")
@@ -99,19 +110,19 @@ func (s *ServiceImpl) WriteFile(ctx context.Context, path, content string) error
 	err := os.WriteFile(path, []byte(content), 0644)
 	if err != nil {
 		if os.IsPermission(err) {
-			s.logger.Error("Permission denied", err, map[string]any{
+			s.log(ctx).Error("Permission denied", err, map[string]any{
 				"path": path,
 			})
 			return errors.NewPermissionDeniedError(path)
 		}
 
-		s.logger.Error("Failed to write file", err, map[string]any{
+		s.log(ctx).Error("Failed to write file", err, map[string]any{
 			"path": path,
 		})
 		return errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to write file")
 	}
 
-	s.logger.Debug("File written successfully", map[string]any{
+	s.log(ctx).Debug("File written successfully", map[string]any{
 		"path":         path,
 		"content_size": len(content),
 	})
@@ -119,63 +130,52 @@ func (s *ServiceImpl) WriteFile(ctx context.Context, path, content string) error
 	return nil
 }
 
-// ListDirectory lists directory contents
-func (s *ServiceImpl) ListDirectory(ctx context.Context, path string) (string, error) {
+// ListDirectory lists directory contents with metadata
+func (s *ServiceImpl) ListDirectory(ctx context.Context, path string, opts ListOptions) (*resources.DirectoryListing, error) {
 	// Validate path using security validator
 	if err := s.securityValidator.ValidatePath(path); err != nil {
-		s.logger.Error("Path validation failed for list operation", err, map[string]any{
+		s.log(ctx).Error("Path validation failed for list operation", err, map[string]any{
 			"path": path,
 		})
-		return "", errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for list operation")
+		return nil, errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for list operation")
 	}
 
-	entries, err := os.ReadDir(path)
+	listing, err := listDirectory(path, opts, s.securityValidator.IsPathAllowed)
 	if err != nil {
 		if os.IsNotExist(err) {
-			s.logger.Error("Directory not found", err, map[string]any{
+			s.log(ctx).Error("Directory not found", err, map[string]any{
 				"path": path,
 			})
-			return "", errors.NewFileNotFoundError(path)
+			return nil, errors.NewFileNotFoundError(path)
 		}
 		if os.IsPermission(err) {
-			s.logger.Error("Permission denied", err, map[string]any{
+			s.log(ctx).Error("Permission denied", err, map[string]any{
 				"path": path,
 			})
-			return "", errors.NewPermissionDeniedError(path)
+			return nil, errors.NewPermissionDeniedError(path)
 		}
 
-		s.logger.Error("Failed to read directory", err, map[string]any{
+		s.log(ctx).Error("Failed to read directory", err, map[string]any{
 			"path": path,
 		})
-		return "", errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to read directory")
-	}
-
-	var result string
-	for _, entry := range entries {
-		info, err := entry.Info()
-		if err != nil {
-			s.logger.Warning("Failed to get file info", map[string]any{
-				"entry": entry.Name(),
-			})
-			continue
-		}
-		result += info.Name() + "\n"
+		return nil, errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to read directory")
 	}
 
-	s.logger.Debug("Directory listed successfully", map[string]any{
-		"path":        path,
-		"entry_count": len(entries),
-		"result_size": len(result),
+	s.log(ctx).Debug("Directory listed successfully", map[string]any{
+		"path":          path,
+		"total_entries": listing.TotalEntries,
+		"returned":      len(listing.Entries),
+		"recursive":     opts.Recursive,
 	})
 
-	return result, nil
+	return listing, nil
 }
 
 // DeleteFile deletes a file or directory
 func (s *ServiceImpl) DeleteFile(ctx context.Context, path string) error {
 	// Validate path using security validator
 	if err := s.securityValidator.ValidatePath(path); err != nil {
-		s.logger.Error("Path validation failed for delete operation", err, map[string]any{
+		s.log(ctx).Error("Path validation failed for delete operation", err, map[string]any{
 			"path": path,
 		})
 		return errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for delete operation")
@@ -184,25 +184,25 @@ func (s *ServiceImpl) DeleteFile(ctx context.Context, path string) error {
 	err := os.RemoveAll(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			s.logger.Error("File/directory not found", err, map[string]any{
+			s.log(ctx).Error("File/directory not found", err, map[string]any{
 				"path": path,
 			})
 			return errors.NewFileNotFoundError(path)
 		}
 		if os.IsPermission(err) {
-			s.logger.Error("Permission denied", err, map[string]any{
+			s.log(ctx).Error("Permission denied", err, map[string]any{
 				"path": path,
 			})
 			return errors.NewPermissionDeniedError(path)
 		}
 
-		s.logger.Error("Failed to delete file/directory", err, map[string]any{
+		s.log(ctx).Error("Failed to delete file/directory", err, map[string]any{
 			"path": path,
 		})
 		return errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to delete file/directory")
 	}
 
-	s.logger.Debug("File/directory deleted successfully", map[string]any{
+	s.log(ctx).Debug("File/directory deleted successfully", map[string]any{
 		"path": path,
 	})
 
@@ -0,0 +1,13 @@
+package file
+
+import "syscall"
+
+// statAtime returns the access time of a stat result as seconds and nanoseconds
+func statAtime(st *syscall.Stat_t) (int64, int64) {
+	return int64(st.Atim.Sec), int64(st.Atim.Nsec)
+}
+
+// statCtime returns the status change time of a stat result as seconds and nanoseconds
+func statCtime(st *syscall.Stat_t) (int64, int64) {
+	return int64(st.Ctim.Sec), int64(st.Ctim.Nsec)
+}
@@ -0,0 +1,209 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"mini-mcp/internal/shared/errors"
+	"mini-mcp/internal/types/resources"
+)
+
+const (
+	// DefaultTailLines is the number of lines returned when no limit is requested
+	DefaultTailLines = 100
+	// MaxTailLines is the largest number of lines a single read may return
+	MaxTailLines = 5000
+	// maxTailBytes caps how much of a file a single read may scan
+	maxTailBytes = 1 << 20
+)
+
+// TailOptions controls how TailFile reads a file
+type TailOptions struct {
+	// Lines is the number of trailing lines to return without a cursor,
+	// and the maximum number of new lines to return with one
+	Lines int
+	// Cursor is a position returned by a previous read; only lines appended since are returned
+	Cursor string
+}
+
+// tailCursor is the decoded form of FileTail.Cursor
+type tailCursor struct {
+	inode  uint64
+	offset int64
+}
+
+// String encodes the cursor as "<inode>:<offset>"
+func (c tailCursor) String() string {
+	return strconv.FormatUint(c.inode, 10) + ":" + strconv.FormatInt(c.offset, 10)
+}
+
+// parseTailCursor decodes a cursor produced by tailCursor.String
+func parseTailCursor(value string) (tailCursor, error) {
+	inodeStr, offsetStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return tailCursor{}, fmt.Errorf("invalid cursor: %q", value)
+	}
+	inode, err := strconv.ParseUint(inodeStr, 10, 64)
+	if err != nil {
+		return tailCursor{}, fmt.Errorf("invalid cursor: %q", value)
+	}
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil || offset < 0 {
+		return tailCursor{}, fmt.Errorf("invalid cursor: %q", value)
+	}
+	return tailCursor{inode: inode, offset: offset}, nil
+}
+
+// fileInode returns the inode number of a file, or 0 when unavailable
+func fileInode(info fs.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
+
+// TailFile returns the last lines of a file, or the lines appended since a cursor.
+// Only complete (newline-terminated) lines are returned; the cursor stops before
+// a trailing partial line so it is delivered once it is finished.
+func (s *ServiceImpl) TailFile(ctx context.Context, path string, opts TailOptions) (*resources.FileTail, error) {
+	if err := s.validate(ctx, "tail", path); err != nil {
+		return nil, err
+	}
+
+	if opts.Lines <= 0 {
+		opts.Lines = DefaultTailLines
+	}
+	if opts.Lines > MaxTailLines {
+		opts.Lines = MaxTailLines
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "tail", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "tail", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, errors.NewInvalidInputError(fmt.Sprintf("not a regular file: %s", path))
+	}
+
+	result := &resources.FileTail{
+		Path:  path,
+		Size:  info.Size(),
+		Inode: fileInode(info),
+		Lines: make([]string, 0),
+	}
+
+	if opts.Cursor == "" {
+		err = tailLastLines(f, result, opts.Lines)
+	} else {
+		cursor, perr := parseTailCursor(opts.Cursor)
+		if perr != nil {
+			return nil, errors.NewInvalidInputError(perr.Error())
+		}
+
+		start := cursor.offset
+		switch {
+		case cursor.inode != 0 && result.Inode != 0 && cursor.inode != result.Inode:
+			// The path now refers to a different file: read the new one from the start
+			result.Rotated = true
+			start = 0
+		case cursor.offset > result.Size:
+			result.Truncated = true
+			start = 0
+		}
+		err = tailFrom(f, result, start, opts.Lines)
+	}
+	if err != nil {
+		return nil, s.wrapFSError(ctx, "tail", path, err)
+	}
+
+	return result, nil
+}
+
+// tailLastLines fills result with up to n complete lines from the end of f
+func tailLastLines(f *os.File, result *resources.FileTail, n int) error {
+	start := result.Size - maxTailBytes
+	if start < 0 {
+		start = 0
+	}
+
+	buf := make([]byte, result.Size-start)
+	if _, err := f.ReadAt(buf, start); err != nil && err != io.EOF {
+		return err
+	}
+
+	end := bytes.LastIndexByte(buf, '\n') + 1
+	lines := splitLines(buf[:end])
+	// When the scan window starts mid-file its first line is incomplete
+	if start > 0 && len(lines) > 0 {
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	result.Lines = append(result.Lines, lines...)
+	result.Cursor = tailCursor{inode: result.Inode, offset: start + int64(end)}.String()
+	return nil
+}
+
+// tailFrom fills result with up to n complete lines starting at offset
+func tailFrom(f *os.File, result *resources.FileTail, offset int64, n int) error {
+	size := result.Size - offset
+	if size > maxTailBytes {
+		size = maxTailBytes
+		result.HasMore = true
+	}
+
+	buf := make([]byte, size)
+	if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return err
+	}
+
+	end := bytes.LastIndexByte(buf, '\n') + 1
+	if end == 0 && len(buf) == maxTailBytes {
+		// A single line longer than the scan window is split rather than stalling the cursor
+		end = len(buf)
+		buf = append(buf, '\n')
+	}
+
+	consumed := 0
+	for consumed < end && len(result.Lines) < n {
+		next := bytes.IndexByte(buf[consumed:], '\n')
+		result.Lines = append(result.Lines, strings.TrimSuffix(string(buf[consumed:consumed+next]), "\r"))
+		consumed += next + 1
+	}
+	if consumed > end {
+		consumed = end
+	}
+	if consumed < end {
+		result.HasMore = true
+	}
+
+	result.Cursor = tailCursor{inode: result.Inode, offset: offset + int64(consumed)}.String()
+	return nil
+}
+
+// splitLines splits newline-terminated data into lines without their terminators
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
@@ -0,0 +1,87 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestTailFile_LastLinesAndCursor(t *testing.T) {
+	root := t.TempDir()
+	svc := newTestService(t, root)
+	ctx := context.Background()
+	path := filepath.Join(root, "app.log")
+
+	appendFile(t, path, "one\ntwo\nthree\nfour\n")
+
+	tail, err := svc.TailFile(ctx, path, TailOptions{Lines: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three", "four"}, tail.Lines)
+	assert.NotEmpty(t, tail.Cursor)
+
+	// Nothing new yet
+	next, err := svc.TailFile(ctx, path, TailOptions{Cursor: tail.Cursor})
+	require.NoError(t, err)
+	assert.Empty(t, next.Lines)
+	assert.Equal(t, tail.Cursor, next.Cursor)
+
+	// A partial line is held back until it is completed
+	appendFile(t, path, "five\nsi")
+	next, err = svc.TailFile(ctx, path, TailOptions{Cursor: next.Cursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"five"}, next.Lines)
+
+	appendFile(t, path, "x\nseven\n")
+	next, err = svc.TailFile(ctx, path, TailOptions{Cursor: next.Cursor, Lines: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"six"}, next.Lines)
+	assert.True(t, next.HasMore)
+
+	next, err = svc.TailFile(ctx, path, TailOptions{Cursor: next.Cursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"seven"}, next.Lines)
+	assert.False(t, next.HasMore)
+
+	_, err = svc.TailFile(ctx, path, TailOptions{Cursor: "garbage"})
+	assert.Error(t, err)
+}
+
+func TestTailFile_RotationAndTruncation(t *testing.T) {
+	root := t.TempDir()
+	svc := newTestService(t, root)
+	ctx := context.Background()
+	path := filepath.Join(root, "app.log")
+
+	appendFile(t, path, "old-1\nold-2\n")
+	tail, err := svc.TailFile(ctx, path, TailOptions{})
+	require.NoError(t, err)
+
+	// Truncation in place keeps the inode but shrinks the file
+	require.NoError(t, os.Truncate(path, 0))
+	appendFile(t, path, "new\n")
+	next, err := svc.TailFile(ctx, path, TailOptions{Cursor: tail.Cursor})
+	require.NoError(t, err)
+	assert.True(t, next.Truncated)
+	assert.Equal(t, []string{"new"}, next.Lines)
+
+	// Rotation moves the file away and creates a new one with a different inode
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendFile(t, path, "rotated-1\nrotated-2\nrotated-3\n")
+	next, err = svc.TailFile(ctx, path, TailOptions{Cursor: next.Cursor})
+	require.NoError(t, err)
+	assert.True(t, next.Rotated)
+	assert.Equal(t, []string{"rotated-1", "rotated-2", "rotated-3"}, next.Lines)
+}
@@ -0,0 +1,178 @@
+package file
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Change events reported by Watcher
+const (
+	WatchEventGrown     = "grown"
+	WatchEventTruncated = "truncated"
+	WatchEventRotated   = "rotated"
+	WatchEventRemoved   = "removed"
+)
+
+// DefaultWatchInterval is how often watched files are polled
+const DefaultWatchInterval = time.Second
+
+// Watcher polls watched files and reports growth, truncation and rotation.
+// Rotation is detected by a change of inode, truncation by a shrinking size.
+// Polling only runs while at least one file is watched.
+type Watcher struct {
+	interval time.Duration
+	onChange func(key, path, event string)
+
+	mu      sync.Mutex
+	watches map[string]*watchState
+	stop    chan struct{}
+}
+
+// watchState is the last observed state of a watched file
+type watchState struct {
+	path   string
+	refs   int
+	inode  uint64
+	size   int64
+	exists bool
+}
+
+// NewWatcher creates a watcher that calls onChange for every detected change
+func NewWatcher(interval time.Duration, onChange func(key, path, event string)) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	return &Watcher{
+		interval: interval,
+		onChange: onChange,
+		watches:  make(map[string]*watchState),
+	}
+}
+
+// Watch starts watching path under key; watching the same key again adds a reference
+func (w *Watcher) Watch(key, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if state, ok := w.watches[key]; ok {
+		state.refs++
+		return nil
+	}
+	w.watches[key] = &watchState{
+		path:   path,
+		refs:   1,
+		inode:  fileInode(info),
+		size:   info.Size(),
+		exists: true,
+	}
+
+	if w.stop == nil {
+		w.stop = make(chan struct{})
+		go w.loop(w.stop)
+	}
+	return nil
+}
+
+// Unwatch drops a reference to key, stopping the watch when none remain
+func (w *Watcher) Unwatch(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.watches[key]
+	if !ok {
+		return
+	}
+	state.refs--
+	if state.refs > 0 {
+		return
+	}
+	delete(w.watches, key)
+
+	if len(w.watches) == 0 && w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+// Close stops all watches
+func (w *Watcher) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.watches = make(map[string]*watchState)
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+// loop polls until stop is closed
+func (w *Watcher) loop(stop chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll compares every watched file with its last observed state
+func (w *Watcher) poll() {
+	type change struct {
+		key, path, event string
+	}
+	var changes []change
+
+	w.mu.Lock()
+	for key, state := range w.watches {
+		event := state.refresh()
+		if event != "" {
+			changes = append(changes, change{key: key, path: state.path, event: event})
+		}
+	}
+	w.mu.Unlock()
+
+	// Callbacks run outside the lock so they may call Watch or Unwatch
+	for _, c := range changes {
+		w.onChange(c.key, c.path, c.event)
+	}
+}
+
+// refresh stats the file, updates the state and returns the change event, if any
+func (s *watchState) refresh() string {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if s.exists {
+			s.exists = false
+			return WatchEventRemoved
+		}
+		return ""
+	}
+
+	inode, size := fileInode(info), info.Size()
+	wasMissing := !s.exists
+	prevInode, prevSize := s.inode, s.size
+	s.exists, s.inode, s.size = true, inode, size
+
+	switch {
+	case wasMissing || inode != prevInode:
+		return WatchEventRotated
+	case size < prevSize:
+		return WatchEventTruncated
+	case size > prevSize:
+		return WatchEventGrown
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,64 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_DetectsChanges(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "app.log")
+	appendFile(t, path, "start\n")
+
+	var events []string
+	w := NewWatcher(time.Hour, func(key, p, event string) {
+		assert.Equal(t, "file://"+path, key)
+		events = append(events, event)
+	})
+	defer w.Close()
+
+	require.NoError(t, w.Watch("file://"+path, path))
+	require.Error(t, w.Watch("file:///missing", filepath.Join(root, "missing")))
+
+	w.poll()
+	assert.Empty(t, events)
+
+	appendFile(t, path, "more\n")
+	w.poll()
+	require.NoError(t, os.Truncate(path, 0))
+	w.poll()
+	require.NoError(t, os.Rename(path, path+".1"))
+	w.poll()
+	appendFile(t, path, "fresh\n")
+	w.poll()
+
+	assert.Equal(t, []string{WatchEventGrown, WatchEventTruncated, WatchEventRemoved, WatchEventRotated}, events)
+}
+
+func TestWatcher_ReferenceCounting(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "app.log")
+	appendFile(t, path, "start\n")
+
+	w := NewWatcher(time.Hour, func(string, string, string) {})
+	defer w.Close()
+
+	require.NoError(t, w.Watch("a", path))
+	require.NoError(t, w.Watch("a", path))
+
+	w.Unwatch("a")
+	w.mu.Lock()
+	assert.Len(t, w.watches, 1)
+	w.mu.Unlock()
+
+	w.Unwatch("a")
+	w.mu.Lock()
+	assert.Empty(t, w.watches)
+	assert.Nil(t, w.stop)
+	w.mu.Unlock()
+}
@@ -0,0 +1,137 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// cephStatus is the subset of `ceph status` used. Releases before Nautilus
+// nest the OSD counts in a second osdmap object.
+type cephStatus struct {
+	Health struct {
+		Status string `json:"status"`
+	} `json:"health"`
+	QuorumNames []string `json:"quorum_names"`
+	MonMap      struct {
+		NumMons int        `json:"num_mons"`
+		Mons    []struct{} `json:"mons"`
+	} `json:"monmap"`
+	OSDMap struct {
+		cephOSDCounts
+		OSDMap *cephOSDCounts `json:"osdmap"`
+	} `json:"osdmap"`
+	PGMap struct {
+		NumPGs     int    `json:"num_pgs"`
+		BytesTotal uint64 `json:"bytes_total"`
+		BytesUsed  uint64 `json:"bytes_used"`
+		BytesAvail uint64 `json:"bytes_avail"`
+	} `json:"pgmap"`
+}
+
+type cephOSDCounts struct {
+	NumOSDs   int  `json:"num_osds"`
+	NumUpOSDs int  `json:"num_up_osds"`
+	NumInOSDs int  `json:"num_in_osds"`
+	Full      bool `json:"full"`
+	NearFull  bool `json:"nearfull"`
+}
+
+// collectCeph reports health and the monitor, OSD and PG maps; standard adds
+// pool usage and detailed the health check messages
+func (s *ServiceImpl) collectCeph(ctx context.Context, opts Options) (any, error) {
+	var status cephStatus
+	if err := s.ceph(ctx, &status, "status"); err != nil {
+		return nil, err
+	}
+
+	osd := status.OSDMap.cephOSDCounts
+	if status.OSDMap.OSDMap != nil {
+		osd = *status.OSDMap.OSDMap
+	}
+	mons := status.MonMap.NumMons
+	if mons == 0 {
+		mons = len(status.MonMap.Mons)
+	}
+	info := &resources.CephInfo{
+		Health: status.Health.Status,
+		MonMap: &resources.CephMonMap{Monitors: mons, Quorum: status.QuorumNames},
+		OSDMap: &resources.CephOSDMap{
+			NumOSDs:   osd.NumOSDs,
+			NumUpOSDs: osd.NumUpOSDs,
+			NumInOSDs: osd.NumInOSDs,
+			Full:      osd.Full,
+			NearFull:  osd.NearFull,
+		},
+		PGMap: &resources.CephPGMap{
+			NumPGs:         status.PGMap.NumPGs,
+			BytesTotal:     status.PGMap.BytesTotal,
+			BytesUsed:      status.PGMap.BytesUsed,
+			BytesAvailable: status.PGMap.BytesAvail,
+		},
+	}
+	var errs []error
+
+	if opts.Level >= LevelStandard {
+		var df struct {
+			Pools []struct {
+				Name  string `json:"name"`
+				ID    int    `json:"id"`
+				Stats struct {
+					BytesUsed   uint64  `json:"bytes_used"`
+					PercentUsed float64 `json:"percent_used"`
+				} `json:"stats"`
+			} `json:"pools"`
+		}
+		if err := s.ceph(ctx, &df, "df"); err != nil {
+			errs = append(errs, err)
+		}
+		for _, p := range df.Pools {
+			// percent_used is a fraction of the pool's available capacity
+			info.Pools = append(info.Pools, resources.CephPool{
+				Name:        p.Name,
+				ID:          p.ID,
+				UsedBytes:   p.Stats.BytesUsed,
+				UsedPercent: p.Stats.PercentUsed * 100,
+			})
+		}
+	}
+
+	if opts.Level >= LevelDetailed && info.Health != "HEALTH_OK" {
+		var detail struct {
+			Checks map[string]struct {
+				Severity string `json:"severity"`
+				Summary  struct {
+					Message string `json:"message"`
+				} `json:"summary"`
+			} `json:"checks"`
+		}
+		if err := s.ceph(ctx, &detail, "health", "detail"); err != nil {
+			errs = append(errs, err)
+		}
+		var messages []string
+		for name, check := range detail.Checks {
+			messages = append(messages, fmt.Sprintf("%s: %s", name, check.Summary.Message))
+		}
+		sort.Strings(messages)
+		info.HealthDetail = strings.Join(messages, "; ")
+	}
+	return info, joinErrors(errs)
+}
+
+// ceph runs a ceph command with JSON output and decodes it
+func (s *ServiceImpl) ceph(ctx context.Context, out any, args ...string) error {
+	name := "ceph " + strings.Join(args, " ")
+	output, err := s.run(ctx, "ceph", append(args, "--format", "json")...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := json.Unmarshal([]byte(output), out); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
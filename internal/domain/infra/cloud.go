@@ -0,0 +1,217 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+const (
+	// defaultDMIRoot holds the firmware identification strings used to spot cloud VMs
+	defaultDMIRoot = "/sys/class/dmi/id"
+	// defaultMetadataURL is the link-local instance metadata service shared by the major clouds
+	defaultMetadataURL = "http://169.254.169.254"
+	// azureAssetTag is the chassis asset tag of every Azure VM
+	azureAssetTag = "7783-7084-3265-9085-8269-3286-77"
+	// maxMetadataSize caps metadata responses
+	maxMetadataSize = 1 << 20
+)
+
+// collectCloud detects the cloud provider from DMI data; standard and above
+// also query the instance metadata service for the instance details. A host
+// outside any known cloud yields no section and no error.
+func (s *ServiceImpl) collectCloud(ctx context.Context, opts Options) (any, error) {
+	provider := strings.ToLower(opts.CloudProvider)
+	if provider == "" {
+		provider = s.detectCloud()
+	}
+	if provider == "" {
+		return nil, nil
+	}
+
+	info := &resources.CloudInfo{Provider: provider}
+	if opts.Level < LevelStandard {
+		return info, nil
+	}
+
+	var err error
+	switch provider {
+	case "aws":
+		err = s.awsMetadata(ctx, info)
+	case "gcp":
+		err = s.gcpMetadata(ctx, info)
+	case "azure":
+		err = s.azureMetadata(ctx, info)
+	default:
+		err = fmt.Errorf("no metadata support for provider %q", provider)
+	}
+	if err != nil {
+		return info, fmt.Errorf("%s metadata: %w", provider, err)
+	}
+	return info, nil
+}
+
+// detectCloud identifies the provider from the DMI vendor, product and asset tag strings
+func (s *ServiceImpl) detectCloud() string {
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(s.dmiRoot, name))
+		return strings.TrimSpace(string(data))
+	}
+	vendor := strings.ToLower(read("sys_vendor") + " " + read("bios_vendor"))
+	product := strings.ToLower(read("product_name") + " " + read("product_version"))
+
+	switch {
+	case strings.Contains(vendor, "amazon") || strings.Contains(product, "amazon ec2"):
+		return "aws"
+	case strings.Contains(vendor, "google") || strings.Contains(product, "google compute engine"):
+		return "gcp"
+	case read("chassis_asset_tag") == azureAssetTag:
+		return "azure"
+	case strings.Contains(vendor, "digitalocean"):
+		return "digitalocean"
+	case strings.Contains(vendor, "hetzner"):
+		return "hetzner"
+	case strings.Contains(product, "openstack"):
+		return "openstack"
+	}
+	return ""
+}
+
+// awsMetadata reads the EC2 identity document using an IMDSv2 session token
+func (s *ServiceImpl) awsMetadata(ctx context.Context, info *resources.CloudInfo) error {
+	token, err := s.metadata(ctx, http.MethodPut, "/latest/api/token", map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"X-aws-ec2-metadata-token": string(token)}
+
+	body, err := s.metadata(ctx, http.MethodGet, "/latest/dynamic/instance-identity/document", headers)
+	if err != nil {
+		return err
+	}
+	var doc struct {
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+		PrivateIP        string `json:"privateIp"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return err
+	}
+	info.InstanceID, info.InstanceType, info.Region = doc.InstanceID, doc.InstanceType, doc.Region
+	info.AvailabilityZone, info.PrivateIP = doc.AvailabilityZone, doc.PrivateIP
+
+	// Instances without a public address answer 404 here
+	if ip, err := s.metadata(ctx, http.MethodGet, "/latest/meta-data/public-ipv4", headers); err == nil {
+		info.PublicIP = string(ip)
+	}
+	return nil
+}
+
+// gcpMetadata reads the Compute Engine instance document
+func (s *ServiceImpl) gcpMetadata(ctx context.Context, info *resources.CloudInfo) error {
+	body, err := s.metadata(ctx, http.MethodGet, "/computeMetadata/v1/instance/?recursive=true", map[string]string{"Metadata-Flavor": "Google"})
+	if err != nil {
+		return err
+	}
+	var doc struct {
+		ID                json.Number `json:"id"`
+		MachineType       string      `json:"machineType"`
+		Zone              string      `json:"zone"`
+		NetworkInterfaces []struct {
+			IP            string `json:"ip"`
+			AccessConfigs []struct {
+				ExternalIP string `json:"externalIp"`
+			} `json:"accessConfigs"`
+		} `json:"networkInterfaces"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return err
+	}
+	// Machine type and zone are resource paths such as projects/123/zones/us-central1-a
+	info.InstanceID = doc.ID.String()
+	info.InstanceType = path.Base(doc.MachineType)
+	info.AvailabilityZone = path.Base(doc.Zone)
+	if i := strings.LastIndex(info.AvailabilityZone, "-"); i > 0 {
+		info.Region = info.AvailabilityZone[:i]
+	}
+	if len(doc.NetworkInterfaces) > 0 {
+		info.PrivateIP = doc.NetworkInterfaces[0].IP
+		for _, ac := range doc.NetworkInterfaces[0].AccessConfigs {
+			if ac.ExternalIP != "" {
+				info.PublicIP = ac.ExternalIP
+			}
+		}
+	}
+	return nil
+}
+
+// azureMetadata reads the Azure instance metadata document
+func (s *ServiceImpl) azureMetadata(ctx context.Context, info *resources.CloudInfo) error {
+	body, err := s.metadata(ctx, http.MethodGet, "/metadata/instance?api-version=2021-02-01", map[string]string{"Metadata": "true"})
+	if err != nil {
+		return err
+	}
+	var doc struct {
+		Compute struct {
+			VMID     string `json:"vmId"`
+			VMSize   string `json:"vmSize"`
+			Location string `json:"location"`
+			Zone     string `json:"zone"`
+		} `json:"compute"`
+		Network struct {
+			Interface []struct {
+				IPv4 struct {
+					IPAddress []struct {
+						PrivateIPAddress string `json:"privateIpAddress"`
+						PublicIPAddress  string `json:"publicIpAddress"`
+					} `json:"ipAddress"`
+				} `json:"ipv4"`
+			} `json:"interface"`
+		} `json:"network"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return err
+	}
+	info.InstanceID, info.InstanceType = doc.Compute.VMID, doc.Compute.VMSize
+	info.Region, info.AvailabilityZone = doc.Compute.Location, doc.Compute.Zone
+	if ifaces := doc.Network.Interface; len(ifaces) > 0 && len(ifaces[0].IPv4.IPAddress) > 0 {
+		info.PrivateIP = ifaces[0].IPv4.IPAddress[0].PrivateIPAddress
+		info.PublicIP = ifaces[0].IPv4.IPAddress[0].PublicIPAddress
+	}
+	return nil
+}
+
+// metadata calls the instance metadata service
+func (s *ServiceImpl) metadata(ctx context.Context, method, target string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.metadataURL+target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, target, resp.Status)
+	}
+	return body, nil
+}
@@ -0,0 +1,271 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// jsonFormat makes docker print one JSON object per line
+const jsonFormat = "{{json .}}"
+
+// dockerInfo is the subset of `docker info` used
+type dockerInfo struct {
+	ServerVersion     string
+	Containers        int
+	ContainersRunning int
+	ContainersPaused  int
+	ContainersStopped int
+	Images            int
+	Driver            string
+	LoggingDriver     string
+	Plugins           struct{ Volume []string }
+	ClientInfo        struct{ Version string }
+	// ServerErrors is set when the CLI could not reach the daemon
+	ServerErrors []string
+}
+
+// dockerContainer is a line of `docker ps --format json`
+type dockerContainer struct {
+	ID, Names, Image, State, Status, CreatedAt, Ports, Networks string
+}
+
+// dockerImage is a line of `docker images --format json`
+type dockerImage struct {
+	ID, Repository, Tag, Size, CreatedAt string
+}
+
+// dockerVolume is a line of `docker volume ls --format json`
+type dockerVolume struct {
+	Name, Driver, Mountpoint string
+}
+
+// dockerNetwork is a line of `docker network ls --format json`
+type dockerNetwork struct {
+	ID, Name, Driver, Scope string
+}
+
+// dockerStats is a line of `docker stats --no-stream --format json`
+type dockerStats struct {
+	ID, CPUPerc, MemUsage, MemPerc, NetIO, BlockIO string
+}
+
+// dockerPortPattern matches a published port such as "0.0.0.0:8080->80/tcp" or an exposed "80/tcp"
+var dockerPortPattern = regexp.MustCompile(`^(?:.*:(\d+)->)?(\d+)(?:-\d+)?/(\w+)$`)
+
+// collectDocker reports daemon counts; standard adds containers, detailed
+// images, volumes and networks, comprehensive container resource usage
+func (s *ServiceImpl) collectDocker(ctx context.Context, opts Options) (any, error) {
+	out, err := s.run(ctx, "docker", "info", "--format", jsonFormat)
+	if err != nil {
+		return nil, fmt.Errorf("docker info: %w", err)
+	}
+	var raw dockerInfo
+	if err := json.Unmarshal([]byte(out), &raw); err != nil {
+		return nil, fmt.Errorf("docker info: %w", err)
+	}
+	if len(raw.ServerErrors) > 0 {
+		return nil, fmt.Errorf("docker daemon: %s", strings.Join(raw.ServerErrors, "; "))
+	}
+
+	info := &resources.DockerInfo{
+		Version:       raw.ClientInfo.Version,
+		ServerVersion: raw.ServerVersion,
+		Containers:    raw.Containers,
+		Running:       raw.ContainersRunning,
+		Paused:        raw.ContainersPaused,
+		Stopped:       raw.ContainersStopped,
+		Images:        raw.Images,
+		StorageDriver: raw.Driver,
+		LoggingDriver: raw.LoggingDriver,
+		VolumePlugins: raw.Plugins.Volume,
+	}
+	var errs []error
+
+	if opts.Level >= LevelStandard {
+		var containers []dockerContainer
+		if err := s.dockerLines(ctx, &containers, "ps", "--all", "--no-trunc", "--format", jsonFormat); err != nil {
+			errs = append(errs, err)
+		}
+		for _, c := range containers {
+			info.ContainerList = append(info.ContainerList, convertDockerContainer(c))
+		}
+	}
+
+	if opts.Level >= LevelDetailed {
+		var images []dockerImage
+		if err := s.dockerLines(ctx, &images, "images", "--no-trunc", "--format", jsonFormat); err != nil {
+			errs = append(errs, err)
+		}
+		for _, img := range images {
+			info.ImageList = append(info.ImageList, resources.DockerImage{
+				ID:         img.ID,
+				Repository: img.Repository,
+				Tag:        img.Tag,
+				Size:       parseSize(img.Size),
+				Created:    parseDockerTime(img.CreatedAt),
+			})
+		}
+
+		var volumes []dockerVolume
+		if err := s.dockerLines(ctx, &volumes, "volume", "ls", "--format", jsonFormat); err != nil {
+			errs = append(errs, err)
+		}
+		for _, v := range volumes {
+			info.VolumeList = append(info.VolumeList, resources.DockerVolume{Name: v.Name, Driver: v.Driver, Mountpoint: v.Mountpoint})
+		}
+
+		var networks []dockerNetwork
+		if err := s.dockerLines(ctx, &networks, "network", "ls", "--no-trunc", "--format", jsonFormat); err != nil {
+			errs = append(errs, err)
+		}
+		for _, n := range networks {
+			info.NetworkList = append(info.NetworkList, resources.DockerNetwork{ID: n.ID, Name: n.Name, Driver: n.Driver, Scope: n.Scope})
+		}
+	}
+
+	if opts.Level >= LevelComprehensive && info.Running > 0 {
+		var stats []dockerStats
+		if err := s.dockerLines(ctx, &stats, "stats", "--no-stream", "--no-trunc", "--format", jsonFormat); err != nil {
+			errs = append(errs, err)
+		}
+		byID := make(map[string]*resources.DockerContainerStats, len(stats))
+		for _, st := range stats {
+			byID[st.ID] = convertDockerStats(st)
+		}
+		for i := range info.ContainerList {
+			info.ContainerList[i].Stats = byID[info.ContainerList[i].ID]
+		}
+	}
+	return info, joinErrors(errs)
+}
+
+// dockerLines runs a docker listing and decodes its JSON lines into out
+func (s *ServiceImpl) dockerLines(ctx context.Context, out any, args ...string) error {
+	name := "docker " + strings.Join(args[:len(args)-2], " ")
+	output, err := s.run(ctx, "docker", args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := decodeJSONLines(output, out); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// decodeJSONLines decodes one JSON object per line into the slice out points to
+func decodeJSONLines(output string, out any) error {
+	var b strings.Builder
+	b.WriteByte('[')
+	first := true
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte(',')
+		}
+		b.WriteString(line)
+		first = false
+	}
+	b.WriteByte(']')
+	return json.Unmarshal([]byte(b.String()), out)
+}
+
+// convertDockerContainer maps a docker ps line to a container
+func convertDockerContainer(c dockerContainer) resources.DockerContainer {
+	container := resources.DockerContainer{
+		ID:          c.ID,
+		Name:        c.Names,
+		Image:       c.Image,
+		Status:      c.Status,
+		Created:     parseDockerTime(c.CreatedAt),
+		NetworkMode: c.Networks,
+	}
+	if c.State != "" {
+		container.Status = c.State + " (" + c.Status + ")"
+	}
+	for _, spec := range strings.Split(c.Ports, ",") {
+		m := dockerPortPattern.FindStringSubmatch(strings.TrimSpace(spec))
+		if m == nil {
+			continue
+		}
+		port := resources.DockerPort{Type: m[3]}
+		port.PublicPort, _ = strconv.Atoi(m[1])
+		port.PrivatePort, _ = strconv.Atoi(m[2])
+		// IPv4 and IPv6 bindings of the same mapping are listed separately
+		if n := len(container.Ports); n > 0 && container.Ports[n-1] == port {
+			continue
+		}
+		container.Ports = append(container.Ports, port)
+	}
+	return container
+}
+
+// convertDockerStats parses the human-readable figures of docker stats
+func convertDockerStats(st dockerStats) *resources.DockerContainerStats {
+	stats := &resources.DockerContainerStats{
+		CPUPercent:    parsePercent(st.CPUPerc),
+		MemoryPercent: parsePercent(st.MemPerc),
+	}
+	stats.MemoryUsage, stats.MemoryLimit = parseSizePair(st.MemUsage)
+	stats.NetworkRx, stats.NetworkTx = parseSizePair(st.NetIO)
+	stats.BlockRead, stats.BlockWrite = parseSizePair(st.BlockIO)
+	return stats
+}
+
+// parseDockerTime parses docker's "2024-01-15 10:00:00 +0000 UTC" timestamps
+func parseDockerTime(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05 -0700 MST", value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// parsePercent parses "12.34%"
+func parsePercent(value string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	return f
+}
+
+// parseSizePair parses "12.3MiB / 1.944GiB"
+func parseSizePair(value string) (uint64, uint64) {
+	a, b, _ := strings.Cut(value, "/")
+	return parseSize(a), parseSize(b)
+}
+
+// sizeUnits are the multipliers of the decimal and binary units docker prints
+var sizeUnits = map[string]float64{
+	"b": 1, "kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+}
+
+// parseSize parses sizes such as "1.2GB", "512MiB" or "0B"; unknown values are 0
+func parseSize(value string) uint64 {
+	value = strings.TrimSpace(value)
+	i := strings.IndexFunc(value, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(value)
+	}
+	n, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil {
+		return 0
+	}
+	unit := strings.ToLower(strings.TrimSpace(value[i:]))
+	if unit == "" {
+		unit = "b"
+	}
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0
+	}
+	return uint64(math.Round(n * multiplier))
+}
@@ -0,0 +1,137 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+
+	"mini-mcp/internal/domain/network"
+	"mini-mcp/internal/domain/systemd"
+	"mini-mcp/internal/types/resources"
+)
+
+// Process list sizes per level; comprehensive lists every process
+const (
+	standardProcessLimit = 10
+	detailedProcessLimit = 25
+)
+
+// serviceLogLines is how many journal entries accompany each unit at the comprehensive level
+const serviceLogLines = 10
+
+// collectSystem reports the host identity, memory, uptime and load; standard
+// adds memory and disk details, detailed adds CPU details and interface counters
+func (s *ServiceImpl) collectSystem(ctx context.Context, opts Options) (any, error) {
+	info := &resources.SystemInfo{OS: runtime.GOOS, Arch: runtime.GOARCH, CPUs: runtime.NumCPU()}
+	var errs []error
+
+	if hostname, err := os.Hostname(); err == nil {
+		info.Hostname = hostname
+	}
+	if kernel, err := s.collector.KernelRelease(); err == nil {
+		info.Kernel = kernel
+	}
+	mem, err := s.collector.Memory()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("memory: %w", err))
+	} else {
+		info.Memory = fmt.Sprintf("%d MB", mem.Total/1024/1024)
+	}
+	if uptime, err := s.collector.Uptime(); err != nil {
+		errs = append(errs, fmt.Errorf("uptime: %w", err))
+	} else {
+		info.Uptime = uptime.Uptime
+		info.BootTime = uptime.BootTime
+		info.LoadAverage = uptime.LoadAverage
+	}
+
+	if opts.Level >= LevelStandard {
+		info.MemoryDetails = mem
+		if disks, err := s.collector.Disks(); err != nil {
+			errs = append(errs, fmt.Errorf("disks: %w", err))
+		} else {
+			info.DiskInfo = disks
+		}
+	}
+	if opts.Level >= LevelDetailed {
+		if cpu, err := s.collector.CPU(); err != nil {
+			errs = append(errs, fmt.Errorf("cpu: %w", err))
+		} else {
+			info.CPUDetails = cpu
+		}
+		if ifaces, err := s.collector.Interfaces(); err != nil {
+			errs = append(errs, fmt.Errorf("interfaces: %w", err))
+		} else {
+			info.NetworkInterfaces = ifaces
+		}
+	}
+	return info, joinErrors(errs)
+}
+
+// collectProcesses counts processes; standard lists the top consumers by
+// lifetime CPU, detailed samples current CPU usage and comprehensive lists
+// every process with sampled I/O rates
+func (s *ServiceImpl) collectProcesses(ctx context.Context, opts Options) (any, error) {
+	var (
+		procs []resources.Process
+		err   error
+	)
+	if opts.Level >= LevelDetailed && opts.SampleInterval > 0 {
+		procs, err = s.collector.SampleProcesses(ctx, opts.SampleInterval, opts.Level >= LevelComprehensive)
+	} else {
+		procs, err = s.collector.Processes()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read processes: %w", err)
+	}
+
+	info := &resources.ProcessInfo{TotalProcesses: len(procs)}
+	sort.SliceStable(procs, func(i, j int) bool {
+		if procs[i].CPUPercent != procs[j].CPUPercent {
+			return procs[i].CPUPercent > procs[j].CPUPercent
+		}
+		return procs[i].MemoryUsage > procs[j].MemoryUsage
+	})
+	switch opts.Level {
+	case LevelBasic:
+	case LevelStandard:
+		info.Processes = procs[:min(standardProcessLimit, len(procs))]
+	case LevelDetailed:
+		info.Processes = procs[:min(detailedProcessLimit, len(procs))]
+	default:
+		info.Processes = procs
+	}
+	return info, nil
+}
+
+// collectNetwork reports interfaces; standard adds routes and DNS, detailed
+// adds connections and comprehensive adds firewall rules
+func (s *ServiceImpl) collectNetwork(ctx context.Context, opts Options) (any, error) {
+	info, err := s.network.Info(ctx, network.Options{
+		Interfaces:  true,
+		Routing:     opts.Level >= LevelStandard,
+		DNS:         opts.Level >= LevelStandard,
+		Connections: opts.Level >= LevelDetailed,
+		Firewall:    opts.Level >= LevelComprehensive,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// collectServices lists systemd units; detailed adds their status and
+// comprehensive recent journal entries (for small result sets)
+func (s *ServiceImpl) collectServices(ctx context.Context, opts Options) (any, error) {
+	info, err := s.systemd.List(ctx, systemd.ListOptions{
+		Status:   opts.Level >= LevelDetailed,
+		Logs:     opts.Level >= LevelComprehensive,
+		LogLines: serviceLogLines,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
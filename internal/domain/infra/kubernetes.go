@@ -0,0 +1,319 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/types/resources"
+)
+
+// maxEvents caps the events reported at the comprehensive level
+const maxEvents = 50
+
+// kubeMeta is the metadata shared by every Kubernetes object
+type kubeMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Labels            map[string]string `json:"labels"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+}
+
+// kubeList is the shape of `kubectl get -o json`
+type kubeList[T any] struct {
+	Items []T `json:"items"`
+}
+
+type kubeNode struct {
+	Metadata kubeMeta `json:"metadata"`
+	Status   struct {
+		Capacity    map[string]string `json:"capacity"`
+		Allocatable map[string]string `json:"allocatable"`
+		Conditions  []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+		NodeInfo struct {
+			KubeletVersion string `json:"kubeletVersion"`
+		} `json:"nodeInfo"`
+	} `json:"status"`
+	Spec struct {
+		Unschedulable bool `json:"unschedulable"`
+	} `json:"spec"`
+}
+
+type kubePod struct {
+	Metadata kubeMeta `json:"metadata"`
+	Spec     struct {
+		NodeName   string     `json:"nodeName"`
+		Containers []struct{} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase             string `json:"phase"`
+		Reason            string `json:"reason"`
+		PodIP             string `json:"podIP"`
+		ContainerStatuses []struct {
+			RestartCount int `json:"restartCount"`
+			State        struct {
+				Waiting *struct {
+					Reason string `json:"reason"`
+				} `json:"waiting"`
+			} `json:"state"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+type kubeService struct {
+	Metadata kubeMeta `json:"metadata"`
+	Spec     struct {
+		Type        string            `json:"type"`
+		ClusterIP   string            `json:"clusterIP"`
+		ExternalIPs []string          `json:"externalIPs"`
+		Selector    map[string]string `json:"selector"`
+		Ports       []struct {
+			Name       string          `json:"name"`
+			Port       int             `json:"port"`
+			TargetPort json.RawMessage `json:"targetPort"`
+			Protocol   string          `json:"protocol"`
+			NodePort   int             `json:"nodePort"`
+		} `json:"ports"`
+	} `json:"spec"`
+	Status struct {
+		LoadBalancer struct {
+			Ingress []struct {
+				IP       string `json:"ip"`
+				Hostname string `json:"hostname"`
+			} `json:"ingress"`
+		} `json:"loadBalancer"`
+	} `json:"status"`
+}
+
+type kubeDeployment struct {
+	Metadata kubeMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int `json:"replicas"`
+		Strategy struct {
+			Type string `json:"type"`
+		} `json:"strategy"`
+	} `json:"spec"`
+	Status struct {
+		ReadyReplicas int `json:"readyReplicas"`
+	} `json:"status"`
+}
+
+type kubeEvent struct {
+	Type           string `json:"type"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+	InvolvedObject struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"involvedObject"`
+	LastTimestamp time.Time `json:"lastTimestamp"`
+	EventTime     time.Time `json:"eventTime"`
+}
+
+// collectKubernetes reports the server version and nodes; standard adds pods
+// and deployments, detailed services and comprehensive recent events. Objects
+// come from Namespace when set, otherwise from every namespace.
+func (s *ServiceImpl) collectKubernetes(ctx context.Context, opts Options) (any, error) {
+	var version struct {
+		ServerVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"serverVersion"`
+	}
+	if err := s.kubectl(ctx, opts, &version, "version", "--output=json"); err != nil {
+		return nil, err
+	}
+
+	info := &resources.KubernetesInfo{Version: version.ServerVersion.GitVersion, CurrentNamespace: opts.Namespace}
+	if info.CurrentNamespace == "" {
+		info.CurrentNamespace = "all"
+	}
+	var errs []error
+
+	var nodes kubeList[kubeNode]
+	if err := s.kubectl(ctx, opts, &nodes, "get", "nodes", "--output=json"); err != nil {
+		errs = append(errs, err)
+	}
+	for _, n := range nodes.Items {
+		info.Nodes = append(info.Nodes, convertKubeNode(n))
+	}
+
+	scope := "--all-namespaces"
+	if opts.Namespace != "" {
+		scope = "--namespace=" + opts.Namespace
+	}
+	now := time.Now()
+
+	if opts.Level >= LevelStandard {
+		var pods kubeList[kubePod]
+		if err := s.kubectl(ctx, opts, &pods, "get", "pods", scope, "--output=json"); err != nil {
+			errs = append(errs, err)
+		}
+		for _, p := range pods.Items {
+			info.Pods = append(info.Pods, convertKubePod(p, now))
+		}
+
+		var deployments kubeList[kubeDeployment]
+		if err := s.kubectl(ctx, opts, &deployments, "get", "deployments", scope, "--output=json"); err != nil {
+			errs = append(errs, err)
+		}
+		for _, d := range deployments.Items {
+			replicas := 1
+			if d.Spec.Replicas != nil {
+				replicas = *d.Spec.Replicas
+			}
+			info.Deployments = append(info.Deployments, resources.KubernetesDeployment{
+				Name:          d.Metadata.Name,
+				Namespace:     d.Metadata.Namespace,
+				Replicas:      replicas,
+				ReadyReplicas: d.Status.ReadyReplicas,
+				Strategy:      d.Spec.Strategy.Type,
+				Age:           age(d.Metadata.CreationTimestamp, now),
+			})
+		}
+	}
+
+	if opts.Level >= LevelDetailed {
+		var services kubeList[kubeService]
+		if err := s.kubectl(ctx, opts, &services, "get", "services", scope, "--output=json"); err != nil {
+			errs = append(errs, err)
+		}
+		for _, svc := range services.Items {
+			info.Services = append(info.Services, convertKubeService(svc))
+		}
+	}
+
+	if opts.Level >= LevelComprehensive {
+		var events kubeList[kubeEvent]
+		if err := s.kubectl(ctx, opts, &events, "get", "events", scope, "--output=json"); err != nil {
+			errs = append(errs, err)
+		}
+		info.Events = recentKubeEvents(events.Items, maxEvents)
+	}
+	return info, joinErrors(errs)
+}
+
+// kubectl runs kubectl with the configured kubeconfig and decodes its JSON output
+func (s *ServiceImpl) kubectl(ctx context.Context, opts Options, out any, args ...string) error {
+	if opts.Kubeconfig != "" {
+		args = append([]string{"--kubeconfig=" + opts.Kubeconfig}, args...)
+	}
+	name := "kubectl " + strings.Join(args[:min(len(args), 3)], " ")
+	output, err := s.run(ctx, "kubectl", args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := json.Unmarshal([]byte(output), out); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// convertKubeNode derives the node status from its Ready condition and roles from its labels
+func convertKubeNode(n kubeNode) resources.KubernetesNode {
+	node := resources.KubernetesNode{
+		Name:           n.Metadata.Name,
+		Status:         "Unknown",
+		KubeletVersion: n.Status.NodeInfo.KubeletVersion,
+		Capacity:       n.Status.Capacity,
+		Allocatable:    n.Status.Allocatable,
+	}
+	for _, cond := range n.Status.Conditions {
+		if cond.Type == "Ready" {
+			node.Status = "NotReady"
+			if cond.Status == "True" {
+				node.Status = "Ready"
+			}
+		}
+	}
+	if n.Spec.Unschedulable {
+		node.Status += ",SchedulingDisabled"
+	}
+	for label := range n.Metadata.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			node.Roles = append(node.Roles, role)
+		}
+	}
+	sort.Strings(node.Roles)
+	return node
+}
+
+// convertKubePod reports a waiting container's reason (e.g. CrashLoopBackOff) as the pod status, as kubectl does
+func convertKubePod(p kubePod, now time.Time) resources.KubernetesPod {
+	pod := resources.KubernetesPod{
+		Name:       p.Metadata.Name,
+		Namespace:  p.Metadata.Namespace,
+		Status:     p.Status.Phase,
+		Node:       p.Spec.NodeName,
+		IP:         p.Status.PodIP,
+		Containers: len(p.Spec.Containers),
+		Age:        age(p.Metadata.CreationTimestamp, now),
+	}
+	if p.Status.Reason != "" {
+		pod.Status = p.Status.Reason
+	}
+	for _, cs := range p.Status.ContainerStatuses {
+		pod.RestartCount += cs.RestartCount
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			pod.Status = cs.State.Waiting.Reason
+		}
+	}
+	return pod
+}
+
+// convertKubeService maps a service; numeric target ports are kept and named ones reported as 0
+func convertKubeService(svc kubeService) resources.KubernetesService {
+	service := resources.KubernetesService{
+		Name:       svc.Metadata.Name,
+		Namespace:  svc.Metadata.Namespace,
+		Type:       svc.Spec.Type,
+		ClusterIP:  svc.Spec.ClusterIP,
+		ExternalIP: strings.Join(svc.Spec.ExternalIPs, ","),
+		Selector:   svc.Spec.Selector,
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if service.ExternalIP == "" {
+			service.ExternalIP = ingress.IP + ingress.Hostname
+		}
+	}
+	for _, p := range svc.Spec.Ports {
+		port := resources.KubernetesPort{Name: p.Name, Port: p.Port, Protocol: p.Protocol, NodePort: p.NodePort}
+		_ = json.Unmarshal(p.TargetPort, &port.TargetPort)
+		service.Ports = append(service.Ports, port)
+	}
+	return service
+}
+
+// recentKubeEvents returns the newest events first, at most limit of them
+func recentKubeEvents(items []kubeEvent, limit int) []resources.KubernetesEvent {
+	events := make([]resources.KubernetesEvent, 0, len(items))
+	for _, e := range items {
+		t := e.LastTimestamp
+		if t.IsZero() {
+			t = e.EventTime
+		}
+		events = append(events, resources.KubernetesEvent{
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Object:  strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name,
+			Message: e.Message,
+			Time:    t,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.After(events[j].Time) })
+	return events[:min(limit, len(events))]
+}
+
+// age renders how long ago an object was created
+func age(created, now time.Time) string {
+	if created.IsZero() {
+		return ""
+	}
+	return procfs.FormatUptime(now.Sub(created))
+}
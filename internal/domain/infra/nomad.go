@@ -0,0 +1,78 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// defaultNomadAddr is where the nomad CLI looks for an agent when NOMAD_ADDR is unset
+const defaultNomadAddr = "http://127.0.0.1:4646"
+
+// collectNomad reports client nodes; standard adds jobs
+func (s *ServiceImpl) collectNomad(ctx context.Context, opts Options) (any, error) {
+	var nodes []struct {
+		ID                    string
+		Name                  string
+		Datacenter            string
+		Status                string
+		SchedulingEligibility string
+		Drain                 bool
+	}
+	if err := s.nomad(ctx, &nodes, "node", "status"); err != nil {
+		return nil, err
+	}
+
+	info := &resources.NomadInfo{Address: os.Getenv("NOMAD_ADDR")}
+	if info.Address == "" {
+		info.Address = defaultNomadAddr
+	}
+	for _, n := range nodes {
+		info.Nodes = append(info.Nodes, resources.NomadNode{
+			ID:          n.ID,
+			Name:        n.Name,
+			Datacenter:  n.Datacenter,
+			Status:      n.Status,
+			Eligibility: n.SchedulingEligibility,
+			Drain:       n.Drain,
+		})
+	}
+
+	if opts.Level >= LevelStandard {
+		var jobs []struct {
+			ID        string
+			Name      string
+			Type      string
+			Status    string
+			Namespace string
+		}
+		if err := s.nomad(ctx, &jobs, "job", "status"); err != nil {
+			return info, err
+		}
+		for _, j := range jobs {
+			info.Jobs = append(info.Jobs, resources.NomadJob(j))
+		}
+	}
+	return info, nil
+}
+
+// nomad runs a nomad status command with JSON output and decodes it
+func (s *ServiceImpl) nomad(ctx context.Context, out any, args ...string) error {
+	name := "nomad " + strings.Join(args, " ")
+	output, err := s.run(ctx, "nomad", append(args, "-json")...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	// An empty cluster prints a message such as "No running jobs" instead of JSON
+	if strings.HasPrefix(strings.TrimSpace(output), "No ") {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(output), out); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
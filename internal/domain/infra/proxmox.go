@@ -0,0 +1,138 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mini-mcp/internal/proxmox"
+	"mini-mcp/internal/proxmox/types"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/types/resources"
+)
+
+// proxmoxReader reads the cluster inventory from pvesh or the Proxmox API
+type proxmoxReader interface {
+	GetVersion(ctx context.Context) (*types.Version, error)
+	GetClusterResources(ctx context.Context) ([]types.ClusterResource, error)
+}
+
+// collectProxmox reports the version and nodes; standard adds VMs and
+// containers, detailed storage. The local pvesh is used unless a Proxmox
+// API host is given.
+func (s *ServiceImpl) collectProxmox(ctx context.Context, opts Options) (any, error) {
+	var reader proxmoxReader = pvesh{run: s.run}
+	host := "localhost"
+	if opts.Proxmox != nil && opts.Proxmox.Proxmox.Host != "" {
+		client := proxmox.NewClient(opts.Proxmox)
+		if err := client.Authenticate(ctx); err != nil {
+			return nil, fmt.Errorf("proxmox login: %w", err)
+		}
+		reader = client
+		host = opts.Proxmox.Proxmox.Host
+	}
+
+	version, err := reader.GetVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := reader.GetClusterResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &resources.ProxmoxInfo{Version: version.Version, Host: host}
+	online := 0
+	for _, r := range items {
+		switch {
+		case r.Type == "node":
+			if r.Status == "online" {
+				online++
+			}
+			info.Nodes = append(info.Nodes, resources.ProxmoxNode{
+				Name:        r.Node,
+				Status:      r.Status,
+				CPUUsage:    r.CPU * 100,
+				MemoryUsage: usedPercent(r.Mem, r.MaxMem),
+				MemoryTotal: r.MaxMem,
+				Uptime:      r.Uptime,
+			})
+		case r.Type == "qemu" && opts.Level >= LevelStandard:
+			info.VMs = append(info.VMs, resources.ProxmoxVM(proxmoxGuest(r)))
+		case r.Type == "lxc" && opts.Level >= LevelStandard:
+			info.Containers = append(info.Containers, proxmoxGuest(r))
+		case r.Type == "storage" && opts.Level >= LevelDetailed:
+			info.Storage = append(info.Storage, resources.ProxmoxStorage{
+				Name:        r.Storage + "@" + r.Node,
+				Type:        r.PluginType,
+				Status:      r.Status,
+				Total:       r.MaxDisk,
+				Used:        r.Disk,
+				UsedPercent: usedPercent(r.Disk, r.MaxDisk),
+				Available:   r.MaxDisk - min(r.Disk, r.MaxDisk),
+			})
+		}
+	}
+	info.Status = fmt.Sprintf("%d/%d nodes online", online, len(info.Nodes))
+	return info, nil
+}
+
+// proxmoxGuest maps a VM or container resource
+func proxmoxGuest(r types.ClusterResource) resources.ProxmoxContainer {
+	return resources.ProxmoxContainer{
+		ID:          fmt.Sprint(r.VMID),
+		Name:        r.Name,
+		Status:      r.Status,
+		Node:        r.Node,
+		CPU:         int(r.MaxCPU),
+		CPUUsage:    r.CPU * 100,
+		Memory:      r.MaxMem,
+		MemoryUsage: usedPercent(r.Mem, r.MaxMem),
+		Disk:        r.MaxDisk,
+		DiskUsage:   usedPercent(r.Disk, r.MaxDisk),
+		Uptime:      r.Uptime,
+	}
+}
+
+// pvesh reads the cluster inventory through the local pvesh CLI
+type pvesh struct {
+	run registry.CommandRunner
+}
+
+// GetVersion returns the version of the local Proxmox node
+func (p pvesh) GetVersion(ctx context.Context) (*types.Version, error) {
+	var version types.Version
+	if err := p.get(ctx, "/version", &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetClusterResources returns the nodes, guests and storage of the cluster
+func (p pvesh) GetClusterResources(ctx context.Context) ([]types.ClusterResource, error) {
+	var resources []types.ClusterResource
+	if err := p.get(ctx, "/cluster/resources", &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// get reads an API path into out
+func (p pvesh) get(ctx context.Context, path string, out any) error {
+	output, err := p.run(ctx, "pvesh", "get", path, "--output-format", "json")
+	if err != nil {
+		return fmt.Errorf("pvesh get %s: %w", path, err)
+	}
+	if err := json.Unmarshal([]byte(output), out); err != nil {
+		return fmt.Errorf("pvesh get %s: %w", path, err)
+	}
+	return nil
+}
+
+// usedPercent returns used as a percentage of total
+func usedPercent(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
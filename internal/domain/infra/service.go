@@ -0,0 +1,323 @@
+// Package infra collects the infrastructure_info report: host, process,
+// network and service state plus Docker, Kubernetes, cloud, Proxmox, Ceph
+// and Nomad, each gathered by its own collector running concurrently under
+// its own timeout.
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-mcp/internal/domain/network"
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/domain/systemd"
+	"mini-mcp/internal/proxmox/types"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// Level is how much detail collectors gather
+type Level int
+
+// Collection levels, from cheapest to most thorough
+const (
+	LevelBasic Level = iota
+	LevelStandard
+	LevelDetailed
+	LevelComprehensive
+)
+
+// levelNames are the names of the collection levels, indexed by Level
+var levelNames = []string{"basic", "standard", "detailed", "comprehensive"}
+
+// ParseLevel parses a collection level name; empty means standard
+func ParseLevel(name string) (Level, error) {
+	if name == "" {
+		return LevelStandard, nil
+	}
+	for i, level := range levelNames {
+		if strings.EqualFold(name, level) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown collection level %q (use %s)", name, strings.Join(levelNames, ", "))
+}
+
+// String returns the level name
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// Sections of the report
+const (
+	SectionSystem     = "system"
+	SectionProcesses  = "processes"
+	SectionNetwork    = "network"
+	SectionServices   = "services"
+	SectionDocker     = "docker"
+	SectionKubernetes = "kubernetes"
+	SectionCloud      = "cloud"
+	SectionProxmox    = "proxmox"
+	SectionCeph       = "ceph"
+	SectionNomad      = "nomad"
+)
+
+// Sections lists every section in report order
+var Sections = []string{
+	SectionSystem, SectionProcesses, SectionNetwork, SectionServices, SectionDocker,
+	SectionKubernetes, SectionCloud, SectionProxmox, SectionCeph, SectionNomad,
+}
+
+// Collector outcomes
+const (
+	StatusOK = "ok"
+	// StatusPartial means the section is present but some of it could not be read
+	StatusPartial = "partial"
+	StatusError   = "error"
+	StatusTimeout = "timeout"
+)
+
+// defaultTimeouts bound each collector; the local readers are fast, while
+// daemons and cluster APIs may hang
+var defaultTimeouts = map[string]time.Duration{
+	SectionSystem:     5 * time.Second,
+	SectionProcesses:  5 * time.Second,
+	SectionNetwork:    10 * time.Second,
+	SectionServices:   15 * time.Second,
+	SectionDocker:     15 * time.Second,
+	SectionKubernetes: 20 * time.Second,
+	SectionCloud:      5 * time.Second,
+	SectionProxmox:    15 * time.Second,
+	SectionCeph:       15 * time.Second,
+	SectionNomad:      10 * time.Second,
+}
+
+// Options selects the sections and depth of the report
+type Options struct {
+	// Level is how much detail to gather
+	Level Level
+	// Sections are the sections to collect
+	Sections []string
+	// SampleInterval is the CPU sampling window for processes at the detailed level and above
+	SampleInterval time.Duration
+	// Timeout overrides the per-collector timeouts when positive
+	Timeout time.Duration
+	// Kubeconfig and Namespace are passed to kubectl
+	Kubeconfig string
+	Namespace  string
+	// Proxmox queries a remote Proxmox API instead of the local pvesh
+	Proxmox *types.AuthConfig
+	// CloudProvider skips detection and queries this provider's metadata service (aws, gcp, azure)
+	CloudProvider string
+}
+
+// Service defines the interface for infrastructure inventory domain services
+type Service interface {
+	Collect(ctx context.Context, opts Options) (*resources.InfrastructureInfo, error)
+}
+
+// collectFunc gathers one section; a non-nil value is kept even with an error
+type collectFunc func(ctx context.Context, opts Options) (any, error)
+
+// ServiceImpl implements the infrastructure inventory domain service
+type ServiceImpl struct {
+	collector *procfs.Collector
+	network   network.Service
+	systemd   systemd.Service
+	run       registry.CommandRunner
+	http      *http.Client
+	logger    logging.Logger
+
+	// dmiRoot and metadataURL locate cloud provider hints; replaced in tests
+	dmiRoot     string
+	metadataURL string
+	// collectors maps sections to their collectors; replaced in tests
+	collectors map[string]collectFunc
+}
+
+// NewService creates an infrastructure inventory service. run executes the
+// docker, kubectl, pvesh, ceph and nomad CLIs, which must be allowlisted.
+func NewService(collector *procfs.Collector, networkService network.Service, systemdService systemd.Service, run registry.CommandRunner, logger logging.Logger) Service {
+	s := &ServiceImpl{
+		collector:   collector,
+		network:     networkService,
+		systemd:     systemdService,
+		run:         run,
+		http:        &http.Client{},
+		logger:      logger,
+		dmiRoot:     defaultDMIRoot,
+		metadataURL: defaultMetadataURL,
+	}
+	s.collectors = map[string]collectFunc{
+		SectionSystem:     s.collectSystem,
+		SectionProcesses:  s.collectProcesses,
+		SectionNetwork:    s.collectNetwork,
+		SectionServices:   s.collectServices,
+		SectionDocker:     s.collectDocker,
+		SectionKubernetes: s.collectKubernetes,
+		SectionCloud:      s.collectCloud,
+		SectionProxmox:    s.collectProxmox,
+		SectionCeph:       s.collectCeph,
+		SectionNomad:      s.collectNomad,
+	}
+	return s
+}
+
+// collectorResult is what a collector goroutine hands back
+type collectorResult struct {
+	section string
+	value   any
+	status  resources.CollectorStatus
+}
+
+// Collect runs the selected collectors concurrently. A collector that fails
+// or outlives its timeout only degrades its own section, which is recorded in
+// Collectors; the call itself fails only for invalid options.
+func (s *ServiceImpl) Collect(ctx context.Context, opts Options) (*resources.InfrastructureInfo, error) {
+	sections := make([]string, 0, len(opts.Sections))
+	for _, section := range opts.Sections {
+		if _, ok := s.collectors[section]; !ok {
+			return nil, fmt.Errorf("unknown section %q", section)
+		}
+		if !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+	if len(sections) == 0 {
+		sections = []string{SectionSystem}
+	}
+
+	results := make(chan collectorResult, len(sections))
+	var wg sync.WaitGroup
+	for _, section := range sections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- s.runCollector(ctx, section, opts)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	info := resources.NewInfrastructureInfo(opts.Level.String())
+	for result := range results {
+		if result.value != nil {
+			assign(info, result.value)
+		}
+		info.Collectors = append(info.Collectors, result.status)
+	}
+	sort.Slice(info.Collectors, func(i, j int) bool {
+		return slices.Index(Sections, info.Collectors[i].Name) < slices.Index(Sections, info.Collectors[j].Name)
+	})
+	return info, nil
+}
+
+// runCollector runs one collector under its timeout. A collector that does
+// not return in time is abandoned; its late result is discarded.
+func (s *ServiceImpl) runCollector(ctx context.Context, section string, opts Options) collectorResult {
+	timeout := defaultTimeouts[section]
+	if section == SectionProcesses && opts.Level >= LevelDetailed {
+		timeout += opts.SampleInterval
+	}
+	if opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		value any
+		err   error
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("collector panicked: %v", r)}
+			}
+		}()
+		value, err := s.collectors[section](ctx, opts)
+		done <- outcome{value, err}
+	}()
+
+	result := collectorResult{section: section, status: resources.CollectorStatus{Name: section}}
+	select {
+	case out := <-done:
+		result.value = out.value
+		switch {
+		case out.err == nil:
+			result.status.Status = StatusOK
+		case ctx.Err() == context.DeadlineExceeded:
+			result.status.Status = StatusTimeout
+			result.status.Error = fmt.Sprintf("timed out after %s: %v", timeout, out.err)
+		case out.value != nil:
+			result.status.Status = StatusPartial
+			result.status.Error = out.err.Error()
+		default:
+			result.status.Status = StatusError
+			result.status.Error = out.err.Error()
+		}
+	case <-ctx.Done():
+		result.status.Status = StatusTimeout
+		result.status.Error = fmt.Sprintf("timed out after %s", timeout)
+	}
+	result.status.DurationMs = time.Since(start).Milliseconds()
+
+	if result.status.Status != StatusOK {
+		logging.FromContext(ctx, s.logger).Warning("Infrastructure collector degraded", map[string]any{
+			"section": section,
+			"status":  result.status.Status,
+			"error":   result.status.Error,
+		})
+	}
+	return result
+}
+
+// assign stores a collector's value in its field of the report
+func assign(info *resources.InfrastructureInfo, value any) {
+	switch v := value.(type) {
+	case *resources.SystemInfo:
+		info.SystemInfo = v
+	case *resources.ProcessInfo:
+		info.ProcessInfo = v
+	case *resources.NetworkInfo:
+		info.NetworkInfo = v
+	case *resources.ServiceInfo:
+		info.ServiceInfo = v
+	case *resources.DockerInfo:
+		info.DockerInfo = v
+	case *resources.KubernetesInfo:
+		info.KubernetesInfo = v
+	case *resources.CloudInfo:
+		info.CloudInfo = v
+	case *resources.ProxmoxInfo:
+		info.ProxmoxInfo = v
+	case *resources.CephInfo:
+		info.CephInfo = v
+	case *resources.NomadInfo:
+		info.NomadInfo = v
+	}
+}
+
+// joinErrors combines the errors of the parts of a section that failed
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
@@ -0,0 +1,349 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/proxmox/types"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommands answers commands by their full command line
+type fakeCommands struct {
+	outputs map[string]string
+	calls   []string
+}
+
+func (f *fakeCommands) run(ctx context.Context, command string, args ...string) (string, error) {
+	line := strings.Join(append([]string{command}, args...), " ")
+	f.calls = append(f.calls, line)
+	if out, ok := f.outputs[line]; ok {
+		return out, nil
+	}
+	return "", fmt.Errorf("command not allowed: %s", command)
+}
+
+func newTestService(commands *fakeCommands) *ServiceImpl {
+	return NewService(nil, nil, nil, commands.run, logging.NewLogger(io.Discard, logging.LogLevelError)).(*ServiceImpl)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, LevelStandard, level)
+
+	level, err = ParseLevel("Comprehensive")
+	require.NoError(t, err)
+	assert.Equal(t, LevelComprehensive, level)
+	assert.Equal(t, "comprehensive", level.String())
+
+	_, err = ParseLevel("everything")
+	assert.Error(t, err)
+}
+
+func TestCollect_DegradesSlowAndFailingSections(t *testing.T) {
+	s := newTestService(&fakeCommands{})
+	s.collectors = map[string]collectFunc{
+		SectionSystem: func(ctx context.Context, opts Options) (any, error) {
+			return &resources.SystemInfo{Hostname: "web1"}, nil
+		},
+		SectionDocker: func(ctx context.Context, opts Options) (any, error) {
+			// A hung daemon that ignores cancellation
+			time.Sleep(time.Second)
+			return &resources.DockerInfo{}, nil
+		},
+		SectionCeph: func(ctx context.Context, opts Options) (any, error) {
+			return nil, fmt.Errorf("ceph: command not allowed")
+		},
+		SectionNomad: func(ctx context.Context, opts Options) (any, error) {
+			return &resources.NomadInfo{Address: "x"}, fmt.Errorf("nomad job status: denied")
+		},
+	}
+
+	start := time.Now()
+	info, err := s.Collect(context.Background(), Options{
+		Level:    LevelDetailed,
+		Sections: []string{SectionNomad, SectionDocker, SectionCeph, SectionSystem},
+		Timeout:  50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	assert.Equal(t, "detailed", info.CollectionLevel)
+	require.NotNil(t, info.SystemInfo)
+	assert.Equal(t, "web1", info.SystemInfo.Hostname)
+	assert.Nil(t, info.DockerInfo)
+	assert.Nil(t, info.CephInfo)
+	require.NotNil(t, info.NomadInfo)
+
+	require.Len(t, info.Collectors, 4)
+	statuses := make(map[string]string)
+	for _, c := range info.Collectors {
+		statuses[c.Name] = c.Status
+	}
+	assert.Equal(t, map[string]string{
+		SectionSystem: StatusOK,
+		SectionDocker: StatusTimeout,
+		SectionCeph:   StatusError,
+		SectionNomad:  StatusPartial,
+	}, statuses)
+	// Collectors are reported in section order
+	assert.Equal(t, SectionSystem, info.Collectors[0].Name)
+	assert.Equal(t, SectionNomad, info.Collectors[3].Name)
+
+	_, err = s.Collect(context.Background(), Options{Sections: []string{"mainframe"}})
+	assert.Error(t, err)
+}
+
+func TestCollectDocker_Levels(t *testing.T) {
+	commands := &fakeCommands{outputs: map[string]string{
+		"docker info --format {{json .}}": `{"ServerVersion":"24.0.7","Containers":2,"ContainersRunning":1,"ContainersStopped":1,` +
+			`"Images":3,"Driver":"overlay2","LoggingDriver":"json-file","Plugins":{"Volume":["local"]},"ClientInfo":{"Version":"24.0.7"}}`,
+		"docker ps --all --no-trunc --format {{json .}}": `{"ID":"abc","Names":"web","Image":"nginx:1.25","State":"running","Status":"Up 2 hours",` +
+			`"CreatedAt":"2024-01-15 10:00:00 +0000 UTC","Ports":"0.0.0.0:8080->80/tcp, :::8080->80/tcp, 443/tcp","Networks":"bridge"}
+{"ID":"def","Names":"job","Image":"busybox","State":"exited","Status":"Exited (0) 1 hour ago","CreatedAt":"2024-01-15 09:00:00 +0000 UTC"}`,
+		"docker images --no-trunc --format {{json .}}":            `{"ID":"sha256:1","Repository":"nginx","Tag":"1.25","Size":"187MB","CreatedAt":"2024-01-10 10:00:00 +0000 UTC"}`,
+		"docker volume ls --format {{json .}}":                    `{"Name":"data","Driver":"local","Mountpoint":"/var/lib/docker/volumes/data/_data"}`,
+		"docker network ls --no-trunc --format {{json .}}":        `{"ID":"n1","Name":"bridge","Driver":"bridge","Scope":"local"}`,
+		"docker stats --no-stream --no-trunc --format {{json .}}": `{"ID":"abc","CPUPerc":"1.50%","MemUsage":"64MiB / 2GiB","MemPerc":"3.13%","NetIO":"1.2kB / 3kB","BlockIO":"0B / 4.1MB"}`,
+	}}
+	s := newTestService(commands)
+
+	value, err := s.collectDocker(context.Background(), Options{Level: LevelBasic})
+	require.NoError(t, err)
+	info := value.(*resources.DockerInfo)
+	assert.Equal(t, "24.0.7", info.ServerVersion)
+	assert.Equal(t, 1, info.Running)
+	assert.Equal(t, []string{"local"}, info.VolumePlugins)
+	assert.Empty(t, info.ContainerList)
+	assert.Len(t, commands.calls, 1)
+
+	value, err = s.collectDocker(context.Background(), Options{Level: LevelComprehensive})
+	require.NoError(t, err)
+	info = value.(*resources.DockerInfo)
+	require.Len(t, info.ContainerList, 2)
+	web := info.ContainerList[0]
+	assert.Equal(t, "running (Up 2 hours)", web.Status)
+	assert.Equal(t, []resources.DockerPort{{PrivatePort: 80, PublicPort: 8080, Type: "tcp"}, {PrivatePort: 443, Type: "tcp"}}, web.Ports)
+	require.NotNil(t, web.Stats)
+	assert.Equal(t, uint64(64<<20), web.Stats.MemoryUsage)
+	assert.Equal(t, uint64(2<<30), web.Stats.MemoryLimit)
+	assert.Equal(t, uint64(4100000), web.Stats.BlockWrite)
+	assert.InDelta(t, 1.5, web.Stats.CPUPercent, 0.001)
+	assert.Nil(t, info.ContainerList[1].Stats)
+	require.Len(t, info.ImageList, 1)
+	assert.Equal(t, uint64(187000000), info.ImageList[0].Size)
+	assert.Len(t, info.VolumeList, 1)
+	assert.Len(t, info.NetworkList, 1)
+}
+
+func TestCollectDocker_DaemonDown(t *testing.T) {
+	s := newTestService(&fakeCommands{outputs: map[string]string{
+		"docker info --format {{json .}}": `{"ServerErrors":["Cannot connect to the Docker daemon"]}`,
+	}})
+
+	value, err := s.collectDocker(context.Background(), Options{Level: LevelStandard})
+	assert.Nil(t, value)
+	assert.ErrorContains(t, err, "Cannot connect")
+}
+
+func TestCollectKubernetes(t *testing.T) {
+	commands := &fakeCommands{outputs: map[string]string{
+		"kubectl --kubeconfig=/etc/kube.conf version --output=json": `{"serverVersion":{"gitVersion":"v1.29.1"}}`,
+		"kubectl --kubeconfig=/etc/kube.conf get nodes --output=json": `{"items":[{"metadata":{"name":"cp1","labels":{"node-role.kubernetes.io/control-plane":""}},` +
+			`"spec":{"unschedulable":true},"status":{"conditions":[{"type":"Ready","status":"True"}],"nodeInfo":{"kubeletVersion":"v1.29.1"}}}]}`,
+		"kubectl --kubeconfig=/etc/kube.conf get pods --namespace=shop --output=json": `{"items":[{"metadata":{"name":"api-1","namespace":"shop"},` +
+			`"spec":{"nodeName":"cp1","containers":[{},{}]},"status":{"phase":"Running","podIP":"10.0.0.5",` +
+			`"containerStatuses":[{"restartCount":4,"state":{"waiting":{"reason":"CrashLoopBackOff"}}},{"restartCount":1,"state":{}}]}}]}`,
+		"kubectl --kubeconfig=/etc/kube.conf get deployments --namespace=shop --output=json": `{"items":[{"metadata":{"name":"api","namespace":"shop"},` +
+			`"spec":{"replicas":3,"strategy":{"type":"RollingUpdate"}},"status":{"readyReplicas":2}}]}`,
+	}}
+	s := newTestService(commands)
+
+	value, err := s.collectKubernetes(context.Background(), Options{Level: LevelStandard, Kubeconfig: "/etc/kube.conf", Namespace: "shop"})
+	require.NoError(t, err)
+	info := value.(*resources.KubernetesInfo)
+	assert.Equal(t, "v1.29.1", info.Version)
+	assert.Equal(t, "shop", info.CurrentNamespace)
+	require.Len(t, info.Nodes, 1)
+	assert.Equal(t, "Ready,SchedulingDisabled", info.Nodes[0].Status)
+	assert.Equal(t, []string{"control-plane"}, info.Nodes[0].Roles)
+	require.Len(t, info.Pods, 1)
+	assert.Equal(t, "CrashLoopBackOff", info.Pods[0].Status)
+	assert.Equal(t, 5, info.Pods[0].RestartCount)
+	assert.Equal(t, 2, info.Pods[0].Containers)
+	require.Len(t, info.Deployments, 1)
+	assert.Equal(t, 2, info.Deployments[0].ReadyReplicas)
+	assert.Empty(t, info.Services)
+
+	// Services are only listed from the detailed level; their failure degrades the section
+	value, err = s.collectKubernetes(context.Background(), Options{Level: LevelDetailed, Kubeconfig: "/etc/kube.conf", Namespace: "shop"})
+	assert.Error(t, err)
+	assert.NotNil(t, value)
+}
+
+func TestCollectCeph(t *testing.T) {
+	s := newTestService(&fakeCommands{outputs: map[string]string{
+		"ceph status --format json": `{"health":{"status":"HEALTH_WARN"},"quorum_names":["a","b","c"],"monmap":{"num_mons":3},` +
+			`"osdmap":{"num_osds":6,"num_up_osds":5,"num_in_osds":6},"pgmap":{"num_pgs":128,"bytes_total":1000,"bytes_used":400,"bytes_avail":600}}`,
+		"ceph df --format json":            `{"pools":[{"name":"rbd","id":1,"stats":{"bytes_used":400,"percent_used":0.25}}]}`,
+		"ceph health detail --format json": `{"checks":{"OSD_DOWN":{"severity":"HEALTH_WARN","summary":{"message":"1 osds down"}}}}`,
+	}})
+
+	value, err := s.collectCeph(context.Background(), Options{Level: LevelDetailed})
+	require.NoError(t, err)
+	info := value.(*resources.CephInfo)
+	assert.Equal(t, "HEALTH_WARN", info.Health)
+	assert.Equal(t, "OSD_DOWN: 1 osds down", info.HealthDetail)
+	assert.Equal(t, 3, info.MonMap.Monitors)
+	assert.Equal(t, 5, info.OSDMap.NumUpOSDs)
+	assert.Equal(t, uint64(600), info.PGMap.BytesAvailable)
+	require.Len(t, info.Pools, 1)
+	assert.InDelta(t, 25.0, info.Pools[0].UsedPercent, 0.001)
+}
+
+func TestCollectProxmox_Pvesh(t *testing.T) {
+	s := newTestService(&fakeCommands{outputs: map[string]string{
+		"pvesh get /version --output-format json": `{"version":"8.1.4"}`,
+		"pvesh get /cluster/resources --output-format json": `[
+			{"type":"node","node":"pve1","status":"online","cpu":0.25,"mem":4,"maxmem":16,"uptime":100},
+			{"type":"node","node":"pve2","status":"offline"},
+			{"type":"qemu","vmid":100,"name":"db","node":"pve1","status":"running","maxcpu":4,"mem":2,"maxmem":8},
+			{"type":"lxc","vmid":200,"name":"dns","node":"pve1","status":"stopped"},
+			{"type":"storage","storage":"local","node":"pve1","plugintype":"dir","status":"available","disk":30,"maxdisk":100}]`,
+	}})
+
+	value, err := s.collectProxmox(context.Background(), Options{Level: LevelBasic})
+	require.NoError(t, err)
+	info := value.(*resources.ProxmoxInfo)
+	assert.Equal(t, "8.1.4", info.Version)
+	assert.Equal(t, "1/2 nodes online", info.Status)
+	assert.InDelta(t, 25.0, info.Nodes[0].CPUUsage, 0.001)
+	assert.Empty(t, info.VMs)
+
+	value, err = s.collectProxmox(context.Background(), Options{Level: LevelDetailed})
+	require.NoError(t, err)
+	info = value.(*resources.ProxmoxInfo)
+	require.Len(t, info.VMs, 1)
+	assert.Equal(t, "100", info.VMs[0].ID)
+	assert.InDelta(t, 25.0, info.VMs[0].MemoryUsage, 0.001)
+	require.Len(t, info.Containers, 1)
+	require.Len(t, info.Storage, 1)
+	assert.Equal(t, uint64(70), info.Storage[0].Available)
+}
+
+func TestCollectProxmox_API(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api2/json/access/ticket":
+			require.NoError(t, r.ParseForm())
+			if r.PostForm.Get("username") != "root@pam" || r.PostForm.Get("password") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"ticket":"PVE:ticket","CSRFPreventionToken":"csrf","username":"root@pam"}}`))
+		case r.Header.Get("Cookie") != "PVEAuthCookie=PVE:ticket":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/api2/json/version":
+			_, _ = w.Write([]byte(`{"data":{"version":"8.2.2"}}`))
+		case r.URL.Path == "/api2/json/cluster/resources":
+			_, _ = w.Write([]byte(`{"data":[{"type":"node","node":"pve1","status":"online","cpu":0.5}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	commands := &fakeCommands{}
+	s := newTestService(commands)
+	auth := &types.AuthConfig{}
+	auth.Proxmox.Host = strings.TrimPrefix(server.URL, "https://")
+	auth.Proxmox.User = "root@pam"
+	auth.Proxmox.Password = "secret"
+
+	value, err := s.collectProxmox(context.Background(), Options{Level: LevelBasic, Proxmox: auth})
+	require.NoError(t, err)
+	info := value.(*resources.ProxmoxInfo)
+	assert.Equal(t, "8.2.2", info.Version)
+	assert.Equal(t, auth.Proxmox.Host, info.Host)
+	assert.Equal(t, "1/1 nodes online", info.Status)
+	assert.InDelta(t, 50.0, info.Nodes[0].CPUUsage, 0.001)
+	assert.Empty(t, commands.calls, "pvesh is not used")
+
+	// The test server's certificate is self-signed
+	auth.Proxmox.VerifySSL = true
+	_, err = s.collectProxmox(context.Background(), Options{Level: LevelBasic, Proxmox: auth})
+	assert.Error(t, err)
+}
+
+func TestCollectCloud_AWS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("token"))
+		case r.Header.Get("X-aws-ec2-metadata-token") != "token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/dynamic/instance-identity/document":
+			_, _ = w.Write([]byte(`{"instanceId":"i-123","instanceType":"t3.micro","region":"eu-west-1","availabilityZone":"eu-west-1a","privateIp":"10.0.0.4"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s := newTestService(&fakeCommands{})
+	s.dmiRoot = t.TempDir()
+	s.metadataURL = server.URL
+	require.NoError(t, os.WriteFile(filepath.Join(s.dmiRoot, "sys_vendor"), []byte("Amazon EC2\n"), 0644))
+
+	value, err := s.collectCloud(context.Background(), Options{Level: LevelBasic})
+	require.NoError(t, err)
+	assert.Equal(t, &resources.CloudInfo{Provider: "aws"}, value)
+
+	value, err = s.collectCloud(context.Background(), Options{Level: LevelStandard})
+	require.NoError(t, err)
+	info := value.(*resources.CloudInfo)
+	assert.Equal(t, "i-123", info.InstanceID)
+	assert.Equal(t, "eu-west-1a", info.AvailabilityZone)
+	assert.Empty(t, info.PublicIP)
+
+	// No DMI hints: not a cloud instance
+	s.dmiRoot = t.TempDir()
+	value, err = s.collectCloud(context.Background(), Options{Level: LevelStandard})
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestCollectNomad(t *testing.T) {
+	s := newTestService(&fakeCommands{outputs: map[string]string{
+		"nomad node status -json": `[{"ID":"n1","Name":"client1","Datacenter":"dc1","Status":"ready","SchedulingEligibility":"eligible"}]`,
+		"nomad job status -json":  "No running jobs\n",
+	}})
+
+	value, err := s.collectNomad(context.Background(), Options{Level: LevelStandard})
+	require.NoError(t, err)
+	info := value.(*resources.NomadInfo)
+	require.Len(t, info.Nodes, 1)
+	assert.Equal(t, "eligible", info.Nodes[0].Eligibility)
+	assert.Empty(t, info.Jobs)
+}
+
+func TestParseSize(t *testing.T) {
+	for input, want := range map[string]uint64{
+		"0B": 0, "512": 512, "1.5kB": 1500, "2MiB": 2 << 20, "1.2GB": 1200000000, " 3 GiB ": 3 << 30, "n/a": 0,
+	} {
+		assert.Equal(t, want, parseSize(input), input)
+	}
+}
@@ -0,0 +1,107 @@
+// Package journal decodes entries of `journalctl -o json` output.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// Levels maps syslog priorities to log level names, most severe first
+var Levels = []string{"emergency", "alert", "critical", "error", "warning", "notice", "info", "debug"}
+
+// priorityAliases are the priority names journalctl and syslog accept besides Levels
+var priorityAliases = map[string]int{
+	"emerg": 0, "panic": 0, "crit": 2, "err": 3, "warn": 4, "information": 6,
+}
+
+// ParsePriority parses a priority name ("err", "warning") or number ("3")
+func ParsePriority(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n < len(Levels) {
+		return n, nil
+	}
+	if n, ok := priorityAliases[value]; ok {
+		return n, nil
+	}
+	for n, level := range Levels {
+		if level == value {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q (use 0-7 or %s)", value, strings.Join(Levels, ", "))
+}
+
+// LevelPriority returns the priority of a level name, or -1 when unknown
+func LevelPriority(level string) int {
+	if n, err := ParsePriority(level); err == nil {
+		return n
+	}
+	return -1
+}
+
+// Entry is a decoded journal entry
+type Entry struct {
+	resources.LogEntry
+	// Cursor is the journal position of the entry, usable with --after-cursor
+	Cursor string
+}
+
+// ParseEntry decodes one line of `journalctl -o json` output
+func ParseEntry(line []byte) (Entry, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return Entry{}, fmt.Errorf("invalid journal entry: %w", err)
+	}
+
+	entry := Entry{
+		LogEntry: resources.LogEntry{
+			Message: field(fields, "MESSAGE"),
+			Source:  field(fields, "SYSLOG_IDENTIFIER"),
+			Unit:    field(fields, "_SYSTEMD_UNIT"),
+			Host:    field(fields, "_HOSTNAME"),
+			BootID:  field(fields, "_BOOT_ID"),
+		},
+		Cursor: field(fields, "__CURSOR"),
+	}
+	if entry.Source == "" {
+		entry.Source = field(fields, "_COMM")
+	}
+	if entry.Unit == "" {
+		entry.Unit = field(fields, "UNIT") // messages systemd logs about a unit
+	}
+	if p, err := strconv.Atoi(field(fields, "PRIORITY")); err == nil && p >= 0 && p < len(Levels) {
+		entry.Level = Levels[p]
+	}
+	entry.PID, _ = strconv.Atoi(field(fields, "_PID"))
+	if usec, err := strconv.ParseInt(field(fields, "__REALTIME_TIMESTAMP"), 10, 64); err == nil {
+		entry.Timestamp = time.UnixMicro(usec)
+	}
+	return entry, nil
+}
+
+// field decodes a journal field, which is a string, or an array of bytes for
+// binary-safe values
+func field(fields map[string]json.RawMessage, name string) string {
+	raw, ok := fields[name]
+	if !ok {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var ints []int
+	if err := json.Unmarshal(raw, &ints); err == nil {
+		b := make([]byte, len(ints))
+		for i, v := range ints {
+			b[i] = byte(v)
+		}
+		return string(b)
+	}
+	return ""
+}
@@ -0,0 +1,248 @@
+package logquery
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// Log formats
+const (
+	FormatJournal     = "journal"
+	FormatJSON        = "json"
+	FormatSyslog      = "syslog"
+	FormatNginxAccess = "nginx_access"
+	FormatNginxError  = "nginx_error"
+	FormatPlain       = "plain"
+)
+
+// detectSample is how many non-empty lines format detection looks at
+const detectSample = 10
+
+var (
+	// syslogPattern matches "Jan  5 10:00:00 host prog[123]: message"
+	syslogPattern = regexp.MustCompile(`^([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}) (\S+) ([^:\[\s]+)(?:\[(\d+)\])?: ?(.*)$`)
+	// syslogISOPattern matches the RFC 3339 timestamps of rsyslog's high-precision format
+	syslogISOPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\S+) (\S+) ([^:\[\s]+)(?:\[(\d+)\])?: ?(.*)$`)
+	// nginxErrorPattern matches "2024/01/15 10:00:00 [error] 1234#1234: *1 message"
+	nginxErrorPattern = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[(\w+)\] (\d+)#\d+: (.*)$`)
+	// accessPattern matches the combined and common access log formats
+	accessPattern = regexp.MustCompile(`^(\S+) \S+ \S+ \[([^\]]+)\] "[^"]*" (\d{3}) \S+`)
+)
+
+// jsonFieldNames are the keys tried, in order, for each field of JSON-lines logs
+var jsonFieldNames = struct {
+	time, level, message, source, unit, pid, host []string
+}{
+	time:    []string{"time", "timestamp", "ts", "@timestamp", "date"},
+	level:   []string{"level", "severity", "lvl", "log.level"},
+	message: []string{"msg", "message", "log"},
+	source:  []string{"logger", "source", "app", "component"},
+	unit:    []string{"unit", "service"},
+	pid:     []string{"pid"},
+	host:    []string{"host", "hostname"},
+}
+
+// lineParser parses one line of a file format; ok is false when the line does not match
+type lineParser func(line string, now time.Time) (entry resources.LogEntry, ok bool)
+
+// parsers lists the file formats in detection order
+var parsers = []struct {
+	format string
+	parse  lineParser
+}{
+	{FormatJSON, parseJSONLine},
+	{FormatNginxError, parseNginxErrorLine},
+	{FormatNginxAccess, parseAccessLine},
+	{FormatSyslog, parseSyslogLine},
+}
+
+// detectFormat picks the format that parses most of the sample lines
+func detectFormat(sample []string, now time.Time) (string, lineParser) {
+	bestFormat, bestParser, bestScore := FormatPlain, lineParser(parsePlainLine), 0
+	for _, p := range parsers {
+		score := 0
+		for _, line := range sample {
+			if _, ok := p.parse(line, now); ok {
+				score++
+			}
+		}
+		if score > bestScore && score*2 >= len(sample) {
+			bestFormat, bestParser, bestScore = p.format, p.parse, score
+		}
+	}
+	return bestFormat, bestParser
+}
+
+// parsePlainLine keeps the whole line as the message
+func parsePlainLine(line string, now time.Time) (resources.LogEntry, bool) {
+	return resources.LogEntry{Message: line, Level: guessLevel(line)}, true
+}
+
+// parseJSONLine parses structured JSON logs such as those of zap, logrus, slog or bunyan
+func parseJSONLine(line string, now time.Time) (resources.LogEntry, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return resources.LogEntry{}, false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return resources.LogEntry{}, false
+	}
+
+	get := func(names []string) any {
+		for _, name := range names {
+			if v, ok := fields[name]; ok {
+				return v
+			}
+		}
+		return nil
+	}
+	str := func(names []string) string {
+		switch v := get(names).(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return ""
+	}
+
+	entry := resources.LogEntry{
+		Message: str(jsonFieldNames.message),
+		Level:   normalizeLevel(str(jsonFieldNames.level)),
+		Source:  str(jsonFieldNames.source),
+		Unit:    str(jsonFieldNames.unit),
+		Host:    str(jsonFieldNames.host),
+	}
+	entry.PID, _ = strconv.Atoi(str(jsonFieldNames.pid))
+	if entry.Message == "" {
+		entry.Message = line
+	}
+
+	switch v := get(jsonFieldNames.time).(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			entry.Timestamp = t
+		}
+	case float64:
+		// Unix seconds, or milliseconds for values too large to be seconds
+		if v > 1e12 {
+			entry.Timestamp = time.UnixMilli(int64(v))
+		} else {
+			entry.Timestamp = time.Unix(0, int64(v*float64(time.Second)))
+		}
+	}
+	return entry, true
+}
+
+// parseNginxErrorLine parses nginx error logs; timestamps are in local time
+func parseNginxErrorLine(line string, now time.Time) (resources.LogEntry, bool) {
+	m := nginxErrorPattern.FindStringSubmatch(line)
+	if m == nil {
+		return resources.LogEntry{}, false
+	}
+	entry := resources.LogEntry{Message: m[4], Level: normalizeLevel(m[2]), Source: "nginx"}
+	entry.PID, _ = strconv.Atoi(m[3])
+	entry.Timestamp, _ = time.ParseInLocation("2006/01/02 15:04:05", m[1], now.Location())
+	return entry, true
+}
+
+// parseAccessLine parses nginx or Apache access logs; 5xx responses are errors and 4xx warnings
+func parseAccessLine(line string, now time.Time) (resources.LogEntry, bool) {
+	m := accessPattern.FindStringSubmatch(line)
+	if m == nil {
+		return resources.LogEntry{}, false
+	}
+	t, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[2])
+	if err != nil {
+		return resources.LogEntry{}, false
+	}
+
+	entry := resources.LogEntry{Timestamp: t, Message: line, Level: "info", Source: m[1]}
+	switch status, _ := strconv.Atoi(m[3]); {
+	case status >= 500:
+		entry.Level = "error"
+	case status >= 400:
+		entry.Level = "warning"
+	}
+	return entry, true
+}
+
+// parseSyslogLine parses traditional and RFC 3339 syslog lines. The classic
+// format has no year, so the current one is assumed unless that puts the
+// entry in the future. The level is guessed from the message.
+func parseSyslogLine(line string, now time.Time) (resources.LogEntry, bool) {
+	var entry resources.LogEntry
+	if m := syslogISOPattern.FindStringSubmatch(line); m != nil {
+		t, err := time.Parse(time.RFC3339Nano, m[1])
+		if err != nil {
+			return resources.LogEntry{}, false
+		}
+		entry = syslogEntry(t, m)
+	} else if m := syslogPattern.FindStringSubmatch(line); m != nil {
+		t, err := time.ParseInLocation("Jan _2 15:04:05 2006", m[1]+" "+strconv.Itoa(now.Year()), now.Location())
+		if err != nil {
+			return resources.LogEntry{}, false
+		}
+		if t.After(now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		entry = syslogEntry(t, m)
+	} else {
+		return resources.LogEntry{}, false
+	}
+	entry.Level = guessLevel(entry.Message)
+	return entry, true
+}
+
+// syslogEntry builds an entry from the timestamp, host, program, PID and message groups
+func syslogEntry(t time.Time, m []string) resources.LogEntry {
+	entry := resources.LogEntry{Timestamp: t, Host: m[2], Source: m[3], Message: m[5]}
+	entry.PID, _ = strconv.Atoi(m[4])
+	return entry
+}
+
+// normalizeLevel maps the level names used by common loggers to the journal level names
+func normalizeLevel(level string) string {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "":
+		return ""
+	case "emerg", "emergency":
+		return "emergency"
+	case "alert":
+		return "alert"
+	case "crit", "critical", "fatal", "panic", "dpanic":
+		return "critical"
+	case "err", "error", "eror":
+		return "error"
+	case "warn", "warning", "wrn":
+		return "warning"
+	case "notice":
+		return "notice"
+	case "info", "information", "inf":
+		return "info"
+	case "debug", "trace", "dbg", "trc":
+		return "debug"
+	default:
+		return strings.ToLower(level)
+	}
+}
+
+// guessLevel infers a level from keywords for formats that do not record one
+func guessLevel(message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "panic") || strings.Contains(lower, "fatal") || strings.Contains(lower, "critical"):
+		return "critical"
+	case strings.Contains(lower, "error") || strings.Contains(lower, "fail"):
+		return "error"
+	case strings.Contains(lower, "warn"):
+		return "warning"
+	default:
+		return "info"
+	}
+}
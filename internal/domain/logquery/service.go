@@ -0,0 +1,600 @@
+// Package logquery searches the systemd journal and plain log files by time
+// range, priority, unit, PID, boot and message pattern, with cursor-based
+// paging and grouped counts.
+package logquery
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/domain/journal"
+	"mini-mcp/internal/shared/errors"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/types/resources"
+)
+
+const (
+	// SourceJournal selects the systemd journal
+	SourceJournal = "journal"
+	// DefaultLogRoot is the only directory log files are read from
+	DefaultLogRoot = "/var/log"
+
+	// DefaultLimit is the page size when none is given
+	DefaultLimit = 100
+	// MaxLimit caps the page size
+	MaxLimit = 1000
+	// DefaultWindow is how far back a query without since or cursor looks
+	DefaultWindow = time.Hour
+
+	// maxScanned stops a query after reading this many entries
+	maxScanned = 500000
+	// maxLineSize is the longest log line read; longer lines are truncated
+	maxLineSize = 1024 * 1024
+)
+
+// GroupFields are the entry fields results can be grouped by
+var GroupFields = []string{"unit", "level", "source", "host"}
+
+// StreamOpener starts a command and streams its standard output
+type StreamOpener func(ctx context.Context, command string, args ...string) (io.ReadCloser, error)
+
+// Query selects log entries
+type Query struct {
+	// Source is SourceJournal or the path of a file under the log root
+	Source string
+	// Since and Until bound the entry timestamps; zero means unbounded
+	Since time.Time
+	Until time.Time
+	// Priority keeps entries at this priority or more severe (0-7); -1 keeps all
+	Priority int
+	// Unit keeps entries logged by this systemd unit or program
+	Unit string
+	// PID keeps entries logged by this process
+	PID int
+	// BootID keeps journal entries from this boot (an ID, or an offset such as -1)
+	BootID string
+	// Pattern keeps entries whose message matches
+	Pattern *regexp.Regexp
+	// Limit is the page size
+	Limit int
+	// Cursor continues a previous query
+	Cursor string
+	// GroupBy counts matches per value of these fields instead of listing entries
+	GroupBy []string
+	// Interval counts matches per time bucket of this size instead of listing entries
+	Interval time.Duration
+}
+
+// grouping reports whether the query asks for counts rather than entries
+func (q Query) grouping() bool {
+	return len(q.GroupBy) > 0 || q.Interval > 0
+}
+
+// Service defines the interface for log query domain services
+type Service interface {
+	Query(ctx context.Context, q Query) (*resources.LogQueryResult, error)
+}
+
+// ServiceImpl implements the log query domain service
+type ServiceImpl struct {
+	open      StreamOpener
+	validator security.PathValidator
+	logRoot   string
+	logger    logging.Logger
+	now       func() time.Time
+	// scanLimit stops a query after reading this many entries
+	scanLimit int
+}
+
+// NewService creates a log query service; open runs journalctl and validator
+// guards file access on top of the log root restriction
+func NewService(open StreamOpener, validator security.PathValidator, logger logging.Logger) Service {
+	return &ServiceImpl{
+		open:      open,
+		validator: validator,
+		logRoot:   DefaultLogRoot,
+		logger:    logger,
+		now:       time.Now,
+		scanLimit: maxScanned,
+	}
+}
+
+// entryReader yields entries with the cursor that resumes after each one
+type entryReader interface {
+	next() (resources.LogEntry, string, error)
+	Close() error
+}
+
+// Query runs a log query. Entries come back oldest first, one page at a
+// time; with GroupBy or Interval set the whole range is counted instead.
+func (s *ServiceImpl) Query(ctx context.Context, q Query) (*resources.LogQueryResult, error) {
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	q.Limit = min(q.Limit, MaxLimit)
+	if q.Since.IsZero() && q.Cursor == "" {
+		q.Since = s.now().Add(-DefaultWindow)
+	}
+	for _, field := range q.GroupBy {
+		if !isGroupField(field) {
+			return nil, errors.NewInvalidInputError(fmt.Sprintf("cannot group by %q (use %s)", field, strings.Join(GroupFields, ", ")))
+		}
+	}
+
+	var (
+		reader entryReader
+		format string
+		err    error
+	)
+	if q.Source == "" || q.Source == SourceJournal {
+		q.Source, format = SourceJournal, FormatJournal
+		reader, err = s.openJournal(ctx, q)
+	} else {
+		reader, format, err = s.openFile(q)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	result := &resources.LogQueryResult{Source: q.Source, Format: format}
+	groups := make(map[string]*resources.LogGroup)
+	// lastCursor resumes after the last returned entry, scannedCursor after
+	// the last entry read
+	lastCursor, scannedCursor := "", ""
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entry, cursor, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to read "+q.Source)
+		}
+		result.Scanned++
+		if result.Scanned > s.scanLimit {
+			// Every entry up to the previous one was returned or did not
+			// match, so the next query resumes after it
+			result.Truncated = true
+			result.NextCursor = scannedCursor
+			break
+		}
+		scannedCursor = cursor
+		if !matches(entry, q) {
+			continue
+		}
+		result.Matched++
+
+		if q.grouping() {
+			addToGroup(groups, entry, q)
+			continue
+		}
+		if len(result.Entries) == q.Limit {
+			// One more match exists, so the page ends at the previous entry
+			result.Matched--
+			result.NextCursor = lastCursor
+			break
+		}
+		result.Entries = append(result.Entries, entry)
+		lastCursor = cursor
+	}
+
+	if q.grouping() {
+		// Only a truncated scan leaves a cursor to count the rest from
+		result.Groups = sortedGroups(groups)
+	}
+	return result, nil
+}
+
+// matches applies the query filters to an entry
+func matches(entry resources.LogEntry, q Query) bool {
+	if !entry.Timestamp.IsZero() {
+		if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && entry.Timestamp.After(q.Until) {
+			return false
+		}
+	}
+	if q.Priority >= 0 {
+		if p := journal.LevelPriority(entry.Level); p < 0 || p > q.Priority {
+			return false
+		}
+	}
+	if q.Unit != "" && !sameUnit(entry, q.Unit) {
+		return false
+	}
+	if q.PID > 0 && entry.PID != q.PID {
+		return false
+	}
+	if q.Pattern != nil && !q.Pattern.MatchString(entry.Message) {
+		return false
+	}
+	return true
+}
+
+// sameUnit matches a unit filter against the unit or program of an entry, with or without .service
+func sameUnit(entry resources.LogEntry, unit string) bool {
+	unit = strings.TrimSuffix(unit, ".service")
+	for _, candidate := range []string{entry.Unit, entry.Source} {
+		if candidate != "" && strings.EqualFold(strings.TrimSuffix(candidate, ".service"), unit) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGroupField reports whether entries can be grouped by field
+func isGroupField(field string) bool {
+	for _, f := range GroupFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// addToGroup counts an entry in its group
+func addToGroup(groups map[string]*resources.LogGroup, entry resources.LogEntry, q Query) {
+	key := make(map[string]string, len(q.GroupBy))
+	var id strings.Builder
+	for _, field := range q.GroupBy {
+		var value string
+		switch field {
+		case "unit":
+			value = entry.Unit
+			if value == "" {
+				value = entry.Source
+			}
+		case "level":
+			value = entry.Level
+		case "source":
+			value = entry.Source
+		case "host":
+			value = entry.Host
+		}
+		key[field] = value
+		fmt.Fprintf(&id, "%s=%s\x00", field, value)
+	}
+
+	var bucket *time.Time
+	if q.Interval > 0 && !entry.Timestamp.IsZero() {
+		start := entry.Timestamp.Truncate(q.Interval)
+		bucket = &start
+		fmt.Fprintf(&id, "@%d", start.UnixNano())
+	}
+
+	group, ok := groups[id.String()]
+	if !ok {
+		group = &resources.LogGroup{Bucket: bucket}
+		if len(key) > 0 {
+			group.Key = key
+		}
+		groups[id.String()] = group
+	}
+	group.Count++
+}
+
+// sortedGroups orders groups by bucket, then by descending count
+func sortedGroups(groups map[string]*resources.LogGroup) []resources.LogGroup {
+	out := make([]resources.LogGroup, 0, len(groups))
+	for _, group := range groups {
+		out = append(out, *group)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if (a.Bucket == nil) != (b.Bucket == nil) {
+			return a.Bucket == nil
+		}
+		if a.Bucket != nil && !a.Bucket.Equal(*b.Bucket) {
+			return a.Bucket.Before(*b.Bucket)
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return fmt.Sprint(a.Key) < fmt.Sprint(b.Key)
+	})
+	return out
+}
+
+// encodeCursor makes an opaque cursor from a kind prefix and position
+func encodeCursor(kind, position string) string {
+	if position == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(kind + ":" + position))
+}
+
+// decodeCursor reverses encodeCursor and checks the cursor belongs to this kind of source
+func decodeCursor(cursor, kind string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", errors.NewInvalidInputError("invalid cursor")
+	}
+	prefix, position, ok := strings.Cut(string(raw), ":")
+	if !ok || prefix != kind {
+		return "", errors.NewInvalidInputError("cursor does not belong to this source")
+	}
+	return position, nil
+}
+
+// journalReader streams journalctl -o json output
+type journalReader struct {
+	stream  io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+// openJournal starts journalctl with the filters it can apply itself
+func (s *ServiceImpl) openJournal(ctx context.Context, q Query) (entryReader, error) {
+	args := []string{"--no-pager", "--output=json"}
+	if q.Cursor != "" {
+		position, err := decodeCursor(q.Cursor, "j")
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--after-cursor="+position)
+	}
+	if !q.Since.IsZero() {
+		args = append(args, fmt.Sprintf("--since=@%d", q.Since.Unix()))
+	}
+	if !q.Until.IsZero() {
+		args = append(args, fmt.Sprintf("--until=@%d", q.Until.Unix()+1))
+	}
+	if q.Priority >= 0 {
+		args = append(args, "--priority="+strconv.Itoa(q.Priority))
+	}
+	if q.Unit != "" {
+		args = append(args, "--unit="+q.Unit)
+	}
+	if q.BootID != "" {
+		args = append(args, "--boot="+q.BootID)
+	}
+	if q.PID > 0 {
+		args = append(args, "_PID="+strconv.Itoa(q.PID))
+	}
+
+	stream, err := s.open(ctx, "journalctl", args...)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to read the journal")
+	}
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &journalReader{stream: stream, scanner: scanner}, nil
+}
+
+func (r *journalReader) next() (resources.LogEntry, string, error) {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := journal.ParseEntry(line)
+		if err != nil {
+			return resources.LogEntry{}, "", err
+		}
+		return entry.LogEntry, encodeCursor("j", entry.Cursor), nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return resources.LogEntry{}, "", err
+	}
+	return resources.LogEntry{}, "", io.EOF
+}
+
+func (r *journalReader) Close() error {
+	return r.stream.Close()
+}
+
+// fileReader reads a log file line by line and tracks the byte offset
+type fileReader struct {
+	file   *os.File
+	reader *bufio.Reader
+	offset int64
+	parse  lineParser
+	now    time.Time
+	// compressed files are gzip streams that can only be read forwards
+	compressed bool
+	// last is the previous entry; unparseable lines such as stack traces inherit its time
+	last resources.LogEntry
+}
+
+// openFile opens a log file under the log root, resumes at the cursor offset and detects its format
+func (s *ServiceImpl) openFile(q Query) (entryReader, string, error) {
+	if q.BootID != "" {
+		return nil, "", errors.NewInvalidInputError("boot_id only applies to the journal")
+	}
+	path, err := s.resolveLogPath(q.Source)
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", errors.NewFileNotFoundError(q.Source)
+		}
+		if os.IsPermission(err) {
+			return nil, "", errors.NewPermissionDeniedError(q.Source)
+		}
+		return nil, "", errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to open "+q.Source)
+	}
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		_ = f.Close()
+		return nil, "", errors.NewInvalidInputError(q.Source + " is a directory")
+	}
+
+	r := &fileReader{file: f, reader: bufio.NewReaderSize(f, 64*1024), now: s.now(), compressed: strings.HasSuffix(path, ".gz")}
+	if err := r.seek(0); err != nil {
+		_ = f.Close()
+		return nil, "", errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to read "+q.Source)
+	}
+
+	// Detect the format from the start of the file, then rewind or skip to the cursor
+	var sample []string
+	for len(sample) < detectSample {
+		line, err := r.readLine()
+		if line != "" {
+			sample = append(sample, line)
+		}
+		if err != nil {
+			break
+		}
+	}
+	format, parse := detectFormat(sample, r.now)
+	r.parse = parse
+
+	var target int64
+	if q.Cursor != "" {
+		position, err := decodeCursor(q.Cursor, "f")
+		if err != nil {
+			_ = f.Close()
+			return nil, "", err
+		}
+		target, err = strconv.ParseInt(position, 10, 64)
+		if err != nil || target < 0 {
+			_ = f.Close()
+			return nil, "", errors.NewInvalidInputError("invalid cursor")
+		}
+	}
+	if err := r.seek(target); err != nil {
+		_ = f.Close()
+		return nil, "", errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to read "+q.Source)
+	}
+	return r, format, nil
+}
+
+// seek positions the reader at an absolute offset of the (decompressed) content
+func (r *fileReader) seek(target int64) error {
+	if !r.compressed {
+		if _, err := r.file.Seek(target, io.SeekStart); err != nil {
+			return err
+		}
+		r.reader.Reset(r.file)
+		r.offset = target
+		return nil
+	}
+	// Compressed files cannot seek; reopen and discard up to the offset
+	if _, err := r.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	gz, err := gzip.NewReader(r.file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(io.Discard, gz, target); err != nil && err != io.EOF {
+		return err
+	}
+	r.reader.Reset(gz)
+	r.offset = target
+	return nil
+}
+
+// readLine reads one line without its newline and advances the offset
+func (r *fileReader) readLine() (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		r.offset += int64(len(chunk))
+		if len(line) < maxLineSize {
+			line = append(line, chunk[:min(len(chunk), maxLineSize-len(line))]...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return strings.TrimRight(string(line), "\r\n"), err
+	}
+}
+
+func (r *fileReader) next() (resources.LogEntry, string, error) {
+	for {
+		line, err := r.readLine()
+		if line == "" {
+			if err != nil {
+				return resources.LogEntry{}, "", io.EOF
+			}
+			continue
+		}
+
+		entry, ok := r.parse(line, r.now)
+		if !ok {
+			// A continuation line, e.g. a stack trace: keep it with the previous entry's context
+			entry = r.last
+			entry.Message = line
+		}
+		r.last = entry
+		return entry, encodeCursor("f", strconv.FormatInt(r.offset, 10)), nil
+	}
+}
+
+func (r *fileReader) Close() error {
+	return r.file.Close()
+}
+
+// resolveLogPath checks a path lies within the log root, also after resolving
+// symlinks, and passes the security validator
+func (s *ServiceImpl) resolveLogPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.logRoot, path)
+	}
+	path = filepath.Clean(path)
+	if err := s.validator.ValidatePath(path); err != nil {
+		return "", errors.WrapError(err, errors.ErrorCodePathBlocked, "Path validation failed for log query")
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.NewFileNotFoundError(path)
+		}
+		return "", errors.WrapError(err, errors.ErrorCodeInternalError, "Failed to resolve "+path)
+	}
+	root, err := filepath.EvalSymlinks(s.logRoot)
+	if err != nil {
+		root = s.logRoot
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.NewPermissionDeniedError(fmt.Sprintf("%s (only files under %s can be queried)", path, s.logRoot))
+	}
+	return resolved, nil
+}
+
+// timeLayouts are the absolute time formats ParseTime accepts
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// ParseTime parses an absolute time (RFC 3339, "2006-01-02 15:04:05" or a
+// date, in local time when no zone is given) or a relative age such as
+// "15m", "2h" or "7d" meaning that long before now
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if age, err := time.ParseDuration(value); err == nil && age >= 0 {
+		return now.Add(-age), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339, \"2006-01-02 15:04:05\" or an age such as 15m, 2h, 7d)", value)
+}
@@ -0,0 +1,325 @@
+package logquery
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowAll is a path validator that accepts every path
+type allowAll struct{}
+
+func (allowAll) ValidatePath(string) error { return nil }
+func (allowAll) IsPathAllowed(string) bool { return true }
+
+var testNow = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func newTestService(t *testing.T, open StreamOpener) (*ServiceImpl, string) {
+	root := t.TempDir()
+	return &ServiceImpl{
+		open:      open,
+		validator: allowAll{},
+		logRoot:   root,
+		logger:    logging.NewLogger(io.Discard, logging.LogLevelError),
+		now:       func() time.Time { return testNow },
+		scanLimit: maxScanned,
+	}, root
+}
+
+func writeLog(t *testing.T, root, name string, lines ...string) string {
+	path := filepath.Join(root, name)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644))
+	return path
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		line   string
+		format string
+	}{
+		{`{"time":"2024-03-10T11:00:00Z","level":"warn","msg":"slow"}`, FormatJSON},
+		{`2024/03/10 11:00:00 [error] 123#123: *1 open() failed`, FormatNginxError},
+		{`10.0.0.1 - - [10/Mar/2024:11:00:00 +0000] "GET / HTTP/1.1" 502 12 "-" "curl"`, FormatNginxAccess},
+		{`Mar 10 11:00:00 web1 sshd[42]: Accepted publickey`, FormatSyslog},
+		{`2024-03-10T11:00:00.123+00:00 web1 cron[7]: job done`, FormatSyslog},
+		{`just some text`, FormatPlain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			format, parse := detectFormat([]string{tt.line}, testNow)
+			assert.Equal(t, tt.format, format)
+			_, ok := parse(tt.line, testNow)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	entry, ok := parseSyslogLine(`Mar 10 11:00:00 web1 sshd[42]: error: connection reset`, testNow)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 10, 11, 0, 0, 0, time.UTC), entry.Timestamp)
+	assert.Equal(t, "web1", entry.Host)
+	assert.Equal(t, "sshd", entry.Source)
+	assert.Equal(t, 42, entry.PID)
+	assert.Equal(t, "error", entry.Level)
+
+	// A December line read in March belongs to the previous year
+	entry, ok = parseSyslogLine(`Dec 31 23:00:00 web1 kernel: hello`, testNow)
+	require.True(t, ok)
+	assert.Equal(t, 2023, entry.Timestamp.Year())
+
+	entry, ok = parseAccessLine(`10.0.0.1 - - [10/Mar/2024:11:00:00 +0000] "GET / HTTP/1.1" 404 12`, testNow)
+	require.True(t, ok)
+	assert.Equal(t, "warning", entry.Level)
+
+	entry, ok = parseJSONLine(`{"ts":1710068400,"severity":"ERROR","message":"boom","service":"api","pid":9}`, testNow)
+	require.True(t, ok)
+	assert.Equal(t, "error", entry.Level)
+	assert.Equal(t, "api", entry.Unit)
+	assert.Equal(t, 9, entry.PID)
+	assert.Equal(t, int64(1710068400), entry.Timestamp.Unix())
+}
+
+func TestQuery_FilePagination(t *testing.T) {
+	svc, root := newTestService(t, nil)
+	var lines []string
+	for i := 0; i < 25; i++ {
+		lines = append(lines, fmt.Sprintf("Mar 10 11:%02d:00 web1 app[%d]: request %d done", i, 100+i%2, i))
+	}
+	writeLog(t, root, "app.log", lines...)
+
+	q := Query{Source: "app.log", Since: testNow.Add(-2 * time.Hour), Priority: -1, Limit: 10}
+	var messages []string
+	pages := 0
+	for {
+		result, err := svc.Query(context.Background(), q)
+		require.NoError(t, err)
+		assert.Equal(t, FormatSyslog, result.Format)
+		for _, entry := range result.Entries {
+			messages = append(messages, entry.Message)
+		}
+		pages++
+		if result.NextCursor == "" {
+			break
+		}
+		q.Cursor = result.NextCursor
+	}
+	assert.Equal(t, 3, pages)
+	require.Len(t, messages, 25)
+	assert.Equal(t, "request 0 done", messages[0])
+	assert.Equal(t, "request 24 done", messages[24])
+}
+
+func TestQuery_ScanLimitResumes(t *testing.T) {
+	svc, root := newTestService(t, nil)
+	svc.scanLimit = 4
+	var lines []string
+	for i := 0; i < 10; i++ {
+		lines = append(lines, fmt.Sprintf("Mar 10 11:%02d:00 web1 app[100]: request %d done", i, i))
+	}
+	lines = append(lines, "Mar 10 11:30:00 web1 app[100]: request failed")
+	writeLog(t, root, "app.log", lines...)
+
+	// No entry matches within a scan budget, yet every page can be resumed
+	q := Query{Source: "app.log", Since: testNow.Add(-2 * time.Hour), Priority: -1, Pattern: regexp.MustCompile("failed")}
+	var messages []string
+	for pages := 0; pages < 10; pages++ {
+		result, err := svc.Query(context.Background(), q)
+		require.NoError(t, err)
+		for _, entry := range result.Entries {
+			messages = append(messages, entry.Message)
+		}
+		if !result.Truncated {
+			break
+		}
+		require.NotEmpty(t, result.NextCursor)
+		q.Cursor = result.NextCursor
+	}
+	assert.Equal(t, []string{"request failed"}, messages)
+}
+
+func TestQuery_FileFilters(t *testing.T) {
+	svc, root := newTestService(t, nil)
+	writeLog(t, root, "syslog",
+		"Mar 10 09:00:00 web1 nginx[10]: error: too old",
+		"Mar 10 11:00:00 web1 nginx[10]: error: upstream timed out",
+		"Mar 10 11:01:00 web1 nginx[11]: worker started",
+		"Mar 10 11:02:00 web1 sshd[20]: error: auth failed",
+		"Mar 10 11:03:00 web1 nginx[10]: warning: slow upstream",
+	)
+
+	result, err := svc.Query(context.Background(), Query{Source: "syslog", Priority: 3, Unit: "nginx.service"})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "error: upstream timed out", result.Entries[0].Message)
+	assert.Equal(t, 5, result.Scanned)
+
+	result, err = svc.Query(context.Background(), Query{Source: "syslog", Priority: -1, PID: 10, Pattern: regexp.MustCompile(`upstream`)})
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+}
+
+func TestQuery_Grouping(t *testing.T) {
+	svc, root := newTestService(t, nil)
+	writeLog(t, root, "syslog",
+		"Mar 10 11:00:10 web1 nginx[10]: error: a",
+		"Mar 10 11:02:00 web1 nginx[10]: error: b",
+		"Mar 10 11:03:00 web1 sshd[20]: error: c",
+		"Mar 10 11:06:00 web1 nginx[10]: error: d",
+		"Mar 10 11:07:00 web1 nginx[10]: all good",
+	)
+
+	result, err := svc.Query(context.Background(), Query{
+		Source: "syslog", Priority: 3, GroupBy: []string{"unit"}, Interval: 5 * time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Entries)
+	assert.Equal(t, 4, result.Matched)
+	require.Len(t, result.Groups, 3)
+
+	first := time.Date(2024, 3, 10, 11, 0, 0, 0, time.UTC)
+	assert.Equal(t, first, *result.Groups[0].Bucket)
+	assert.Equal(t, map[string]string{"unit": "nginx"}, result.Groups[0].Key)
+	assert.Equal(t, 2, result.Groups[0].Count)
+	assert.Equal(t, "sshd", result.Groups[1].Key["unit"])
+	assert.Equal(t, first.Add(5*time.Minute), *result.Groups[2].Bucket)
+
+	_, err = svc.Query(context.Background(), Query{Source: "syslog", Priority: -1, GroupBy: []string{"message"}})
+	assert.Error(t, err)
+}
+
+func TestQuery_GzipAndContinuation(t *testing.T) {
+	svc, root := newTestService(t, nil)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, line := range []string{
+		`{"time":"2024-03-10T11:00:00Z","level":"error","msg":"panic: boom"}`,
+		`goroutine 1 [running]:`,
+		`{"time":"2024-03-10T11:01:00Z","level":"info","msg":"restarted"}`,
+	} {
+		_, _ = gz.Write([]byte(line + "\n"))
+	}
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.log.1.gz"), buf.Bytes(), 0644))
+
+	q := Query{Source: "app.log.1.gz", Priority: -1, Limit: 2}
+	result, err := svc.Query(context.Background(), q)
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, result.Format)
+	require.Len(t, result.Entries, 2)
+	assert.Equal(t, "goroutine 1 [running]:", result.Entries[1].Message)
+	assert.Equal(t, result.Entries[0].Timestamp, result.Entries[1].Timestamp)
+	require.NotEmpty(t, result.NextCursor)
+
+	q.Cursor = result.NextCursor
+	result, err = svc.Query(context.Background(), q)
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "restarted", result.Entries[0].Message)
+	assert.Empty(t, result.NextCursor)
+}
+
+func TestQuery_FileAccess(t *testing.T) {
+	svc, root := newTestService(t, nil)
+	outside := writeLog(t, t.TempDir(), "secret", "Mar 10 11:00:00 web1 app: secret")
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "link")))
+
+	for _, source := range []string{outside, "../secret", "link"} {
+		_, err := svc.Query(context.Background(), Query{Source: source, Priority: -1})
+		assert.Error(t, err, source)
+	}
+
+	writeLog(t, root, "syslog", "Mar 10 11:00:00 web1 app: hi")
+	_, err := svc.Query(context.Background(), Query{Source: "syslog", Priority: -1, BootID: "-1"})
+	assert.Error(t, err)
+	_, err = svc.Query(context.Background(), Query{Source: "syslog", Priority: -1, Cursor: encodeCursor("j", "s=1")})
+	assert.Error(t, err)
+}
+
+func TestQuery_Journal(t *testing.T) {
+	var gotArgs []string
+	open := func(ctx context.Context, command string, args ...string) (io.ReadCloser, error) {
+		assert.Equal(t, "journalctl", command)
+		gotArgs = args
+		var out strings.Builder
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(&out, `{"__CURSOR":"s=%d","__REALTIME_TIMESTAMP":"%d","PRIORITY":"3","MESSAGE":"failure %d","_SYSTEMD_UNIT":"nginx.service","_PID":"10"}`+"\n",
+				i, testNow.Add(time.Duration(i-10)*time.Minute).UnixMicro(), i)
+		}
+		return io.NopCloser(strings.NewReader(out.String())), nil
+	}
+	svc, _ := newTestService(t, open)
+
+	result, err := svc.Query(context.Background(), Query{
+		Priority: 3, Unit: "nginx", PID: 10, BootID: "-1", Limit: 2, Cursor: encodeCursor("j", "s=0"),
+		Pattern: regexp.MustCompile(`failure`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, SourceJournal, result.Source)
+	require.Len(t, result.Entries, 2)
+	assert.Equal(t, "nginx.service", result.Entries[0].Unit)
+	assert.Equal(t, "error", result.Entries[0].Level)
+
+	position, err := decodeCursor(result.NextCursor, "j")
+	require.NoError(t, err)
+	assert.Equal(t, "s=1", position)
+
+	assert.Contains(t, gotArgs, "--output=json")
+	assert.Contains(t, gotArgs, "--after-cursor=s=0")
+	assert.Contains(t, gotArgs, "--priority=3")
+	assert.Contains(t, gotArgs, "--unit=nginx")
+	assert.Contains(t, gotArgs, "--boot=-1")
+	assert.Contains(t, gotArgs, "_PID=10")
+}
+
+func TestQuery_JournalThroughDefaultPolicy(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "journal.out")
+	line := fmt.Sprintf(`{"__CURSOR":"s=0","__REALTIME_TIMESTAMP":"%d","PRIORITY":"3","MESSAGE":"failure","_SYSTEMD_UNIT":"nginx.service"}`,
+		testNow.Add(-10*time.Minute).UnixMicro())
+	require.NoError(t, os.WriteFile(data, []byte(line+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "journalctl"), []byte(fmt.Sprintf("#!/bin/sh\nexec cat %q\n", data)), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	sec := security.NewSecureCommandExecutor(security.DefaultSecurityConfig())
+	svc, _ := newTestService(t, registry.NewCommandExecutor(sec, logger).StreamSystemCommand)
+
+	result, err := svc.Query(context.Background(), Query{Priority: -1})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "failure", result.Entries[0].Message)
+	assert.Empty(t, sec.PolicyDenials())
+}
+
+func TestParseTime(t *testing.T) {
+	got, err := ParseTime("15m", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, testNow.Add(-15*time.Minute), got)
+
+	got, err = ParseTime("7d", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, testNow.AddDate(0, 0, -7), got)
+
+	got, err = ParseTime("2024-03-10T10:00:00Z", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, testNow.Add(-2*time.Hour), got)
+
+	_, err = ParseTime("yesterday", testNow)
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// Aggregations combine the samples within one downsampling step
+const (
+	AggregationAvg  = "avg"
+	AggregationMin  = "min"
+	AggregationMax  = "max"
+	AggregationLast = "last"
+)
+
+// Query defaults and limits
+const (
+	DefaultMaxPoints = 120
+	MaxPoints        = 2000
+	// MaxQuerySeries caps the series returned by one query
+	MaxQuerySeries = 50
+)
+
+// Aggregations lists the supported aggregations
+var Aggregations = []string{AggregationAvg, AggregationMin, AggregationMax, AggregationLast}
+
+// Query selects series and the window and resolution to return them at
+type Query struct {
+	// Metric is a metric name or a glob such as disk_* or process_*
+	Metric string
+	// Labels keeps series whose labels match; values may be globs
+	Labels map[string]string
+	// Since and Until bound the window; zero values mean one hour ago and now
+	Since, Until time.Time
+	// Step is the width of a downsampled point; zero derives it from MaxPoints.
+	// A step that gives more than the MaxPoints limit per series is refused.
+	Step time.Duration
+	// MaxPoints caps the points per series when Step is zero
+	MaxPoints int
+	// Aggregation combines the samples within a step (default avg)
+	Aggregation string
+}
+
+// Query returns the matching series over the window with statistics computed
+// from the raw samples and a history downsampled to the requested resolution
+func (s *Store) Query(q Query, now time.Time) (*resources.MetricQueryResult, error) {
+	if q.Metric == "" {
+		return nil, fmt.Errorf("metric is required")
+	}
+	if _, err := path.Match(q.Metric, ""); err != nil {
+		return nil, fmt.Errorf("invalid metric pattern %q: %w", q.Metric, err)
+	}
+	if q.Until.IsZero() {
+		q.Until = now
+	}
+	if q.Since.IsZero() {
+		q.Since = q.Until.Add(-time.Hour)
+	}
+	if !q.Since.Before(q.Until) {
+		return nil, fmt.Errorf("since must be before until")
+	}
+	if q.Aggregation == "" {
+		q.Aggregation = AggregationAvg
+	}
+	if !slices.Contains(Aggregations, q.Aggregation) {
+		return nil, fmt.Errorf("unsupported aggregation %q (use one of: %s)", q.Aggregation, strings.Join(Aggregations, ", "))
+	}
+	step := q.Step
+	if step <= 0 {
+		maxPoints := q.MaxPoints
+		if maxPoints <= 0 {
+			maxPoints = DefaultMaxPoints
+		}
+		step = (q.Until.Sub(q.Since) + time.Duration(maxPoints) - 1) / time.Duration(maxPoints)
+	}
+	// Steps finer than the sampling interval would only repeat samples
+	step = max(step, s.interval, time.Second).Round(time.Second)
+	if points := (q.Until.Sub(q.Since) + step - 1) / step; points > MaxPoints {
+		return nil, fmt.Errorf("step %s gives %d points per series, more than the maximum of %d; use a larger step or a shorter window", step, points, MaxPoints)
+	}
+
+	result := &resources.MetricQueryResult{
+		Since:       q.Since,
+		Until:       q.Until,
+		StepSeconds: step.Seconds(),
+		Aggregation: q.Aggregation,
+		Series:      make([]resources.MetricSeries, 0),
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from, to := q.Since.UnixMilli(), q.Until.UnixMilli()
+	for _, key := range s.sortedKeys() {
+		ser := s.series[key]
+		if !matchSeries(ser, q) {
+			continue
+		}
+		var window []point
+		for _, p := range ser.ordered() {
+			if p.t >= from && p.t <= to {
+				window = append(window, p)
+			}
+		}
+		if len(window) == 0 {
+			continue
+		}
+		if len(result.Series) == MaxQuerySeries {
+			result.Truncated = true
+			break
+		}
+		result.Series = append(result.Series, buildSeries(ser, window, from, step.Milliseconds(), q.Aggregation))
+	}
+	return result, nil
+}
+
+// matchSeries reports whether a series matches the metric pattern and label filters
+func matchSeries(ser *series, q Query) bool {
+	if ok, _ := path.Match(q.Metric, ser.name); !ok {
+		return false
+	}
+	for name, want := range q.Labels {
+		got, ok := ser.labels[name]
+		if !ok {
+			return false
+		}
+		if matched, err := path.Match(want, got); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// buildSeries computes the statistics of the window and downsamples it into
+// steps aligned to the window start; steps without samples are left out
+func buildSeries(ser *series, window []point, from, step int64, aggregation string) resources.MetricSeries {
+	values := make([]float64, len(window))
+	sum := 0.0
+	for i, p := range window {
+		values[i] = p.v
+		sum += p.v
+	}
+	first, last := window[0].v, window[len(window)-1].v
+
+	out := resources.MetricSeries{
+		Name:    ser.name,
+		Labels:  ser.labels,
+		Samples: len(window),
+		MetricData: resources.MetricData{
+			Values: map[string]float64{"first": first, "last": last, "change": last - first},
+			Avg:    sum / float64(len(values)),
+			P95:    percentile(values, 95),
+			Unit:   Units[ser.name],
+		},
+	}
+	out.Min, out.Max = slices.Min(values), slices.Max(values)
+
+	bucketStart := func(t int64) int64 { return from + (t-from)/step*step }
+	for i := 0; i < len(window); {
+		start := bucketStart(window[i].t)
+		j := i
+		for j < len(window) && bucketStart(window[j].t) == start {
+			j++
+		}
+		out.History = append(out.History, resources.MetricPoint{
+			Timestamp: time.UnixMilli(start),
+			Value:     aggregate(values[i:j], aggregation),
+		})
+		i = j
+	}
+	return out
+}
+
+// aggregate combines the values of one step
+func aggregate(values []float64, aggregation string) float64 {
+	switch aggregation {
+	case AggregationMin:
+		return slices.Min(values)
+	case AggregationMax:
+		return slices.Max(values)
+	case AggregationLast:
+		return values[len(values)-1]
+	default:
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	}
+}
+
+// percentile returns the nearest-rank percentile of values
+func percentile(values []float64, p float64) float64 {
+	sorted := slices.Clone(values)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,194 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// Sampler defaults
+const (
+	// topProcesses is how many of the busiest processes by CPU and by memory are recorded
+	topProcesses = 10
+	// maxProcessWindow caps how long process CPU usage is measured over each round
+	maxProcessWindow = time.Second
+	// saveInterval is how often the store is persisted while sampling
+	saveInterval = 5 * time.Minute
+)
+
+// Units maps recorded metric names to their units
+var Units = map[string]string{
+	"cpu_percent":              "percent",
+	"load1":                    "load",
+	"load5":                    "load",
+	"load15":                   "load",
+	"memory_used_percent":      "percent",
+	"memory_used_bytes":        "bytes",
+	"swap_used_percent":        "percent",
+	"disk_used_percent":        "percent",
+	"disk_used_bytes":          "bytes",
+	"network_rx_bytes_per_sec": "bytes/s",
+	"network_tx_bytes_per_sec": "bytes/s",
+	"process_cpu_percent":      "percent",
+	"process_memory_bytes":     "bytes",
+}
+
+// netCounters are the cumulative traffic counters of an interface
+type netCounters struct {
+	rx, tx uint64
+}
+
+// Sampler periodically reads host and process metrics into a Store.
+// Rates (CPU usage and network throughput) are computed from the counter
+// deltas between rounds, so the first round records only gauges.
+type Sampler struct {
+	collector *procfs.Collector
+	store     *Store
+	logger    logging.Logger
+
+	prevAt  time.Time
+	prevCPU procfs.CPUTimes
+	prevNet map[string]netCounters
+}
+
+// NewSampler creates a sampler that records into store at the store's interval
+func NewSampler(collector *procfs.Collector, store *Store, logger logging.Logger) *Sampler {
+	return &Sampler{
+		collector: collector,
+		store:     store,
+		logger:    logger,
+		prevNet:   make(map[string]netCounters),
+	}
+}
+
+// Run samples until ctx is cancelled, persisting the store periodically and on return
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.store.Interval())
+	defer ticker.Stop()
+	saveTicker := time.NewTicker(saveInterval)
+	defer saveTicker.Stop()
+
+	s.store.Append(time.Now(), s.Sample(ctx))
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.store.Save(); err != nil {
+				s.logger.Error("Failed to persist metrics history", err, nil)
+			}
+			return
+		case <-ticker.C:
+			s.store.Append(time.Now(), s.Sample(ctx))
+		case <-saveTicker.C:
+			if err := s.store.Save(); err != nil {
+				s.logger.Error("Failed to persist metrics history", err, nil)
+			}
+		}
+	}
+}
+
+// Sample reads one round of metrics. Sources that cannot be read are logged
+// at debug level and skipped so the others are still recorded.
+func (s *Sampler) Sample(ctx context.Context) []Sample {
+	now := time.Now()
+	var samples []Sample
+	add := func(name string, value float64, labels map[string]string) {
+		samples = append(samples, Sample{Name: name, Labels: labels, Value: value})
+	}
+	skip := func(source string, err error) {
+		s.logger.Debug("Metrics source unavailable", map[string]any{"source": source, "error": err.Error()})
+	}
+	elapsed := now.Sub(s.prevAt).Seconds()
+	first := s.prevAt.IsZero()
+	s.prevAt = now
+
+	if times, err := s.collector.CPUTimes(); err != nil {
+		skip("cpu", err)
+	} else {
+		if !first && s.prevCPU.Total > 0 {
+			add("cpu_percent", times.UsagePercent(s.prevCPU), nil)
+		}
+		s.prevCPU = times
+	}
+
+	if uptime, err := s.collector.Uptime(); err != nil {
+		skip("load", err)
+	} else {
+		add("load1", uptime.LoadAverage[0], nil)
+		add("load5", uptime.LoadAverage[1], nil)
+		add("load15", uptime.LoadAverage[2], nil)
+	}
+
+	if mem, err := s.collector.Memory(); err != nil {
+		skip("memory", err)
+	} else {
+		add("memory_used_percent", mem.UsedPercent, nil)
+		add("memory_used_bytes", float64(mem.Used), nil)
+		if mem.Swap != nil {
+			add("swap_used_percent", mem.Swap.UsedPercent, nil)
+		}
+	}
+
+	if disks, err := s.collector.Disks(); err != nil {
+		skip("disk", err)
+	} else {
+		for _, disk := range disks {
+			labels := map[string]string{"mount": disk.Path}
+			add("disk_used_percent", disk.UsedPercent, labels)
+			add("disk_used_bytes", float64(disk.Used), labels)
+		}
+	}
+
+	if ifaces, err := s.collector.Interfaces(); err != nil {
+		skip("network", err)
+	} else {
+		current := make(map[string]netCounters, len(ifaces))
+		for _, iface := range ifaces {
+			counters := netCounters{rx: iface.BytesRecv, tx: iface.BytesSent}
+			current[iface.Name] = counters
+			prev, ok := s.prevNet[iface.Name]
+			// Counters reset when an interface is recreated
+			if first || !ok || elapsed <= 0 || counters.rx < prev.rx || counters.tx < prev.tx {
+				continue
+			}
+			labels := map[string]string{"interface": iface.Name}
+			add("network_rx_bytes_per_sec", float64(counters.rx-prev.rx)/elapsed, labels)
+			add("network_tx_bytes_per_sec", float64(counters.tx-prev.tx)/elapsed, labels)
+		}
+		s.prevNet = current
+	}
+
+	window := min(s.store.Interval()/4, maxProcessWindow)
+	if procs, err := s.collector.SampleProcesses(ctx, window, false); err != nil {
+		skip("processes", err)
+	} else {
+		for _, proc := range busiestProcesses(procs, topProcesses) {
+			labels := map[string]string{"pid": strconv.Itoa(proc.PID), "name": proc.Name}
+			add("process_cpu_percent", proc.CPUPercent, labels)
+			add("process_memory_bytes", float64(proc.MemoryUsage), labels)
+		}
+	}
+	return samples
+}
+
+// busiestProcesses returns the union of the top n processes by CPU and by memory
+func busiestProcesses(procs []resources.Process, n int) []resources.Process {
+	seen := make(map[int]bool)
+	var out []resources.Process
+	pick := func(less func(a, b resources.Process) bool) {
+		sort.SliceStable(procs, func(i, j int) bool { return less(procs[i], procs[j]) })
+		for _, proc := range procs[:min(n, len(procs))] {
+			if !seen[proc.PID] {
+				seen[proc.PID] = true
+				out = append(out, proc)
+			}
+		}
+	}
+	pick(func(a, b resources.Process) bool { return a.CPUPercent > b.CPUPercent })
+	pick(func(a, b resources.Process) bool { return a.MemoryUsage > b.MemoryUsage })
+	return out
+}
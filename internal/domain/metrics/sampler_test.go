@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProc writes files below a fake proc root
+func writeProc(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// netDev renders /proc/net/dev with one interface
+func netDev(rx, tx string) string {
+	return "Inter-|   Receive                                                |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"  eth0: " + rx + "    4000    0    0    0     0          0         0   " + tx + "    2000    0    0    0     0       0          0\n"
+}
+
+func TestSampler_Sample(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, map[string]string{
+		"stat":     "cpu  100 0 50 800 50 0 0 0 0 0\nbtime 1700000000\n",
+		"uptime":   "1000.00 4000.00\n",
+		"loadavg":  "0.50 0.40 0.30 1/100 1234\n",
+		"meminfo":  "MemTotal:       1000000 kB\nMemFree:         200000 kB\nMemAvailable:    250000 kB\nSwapTotal:       100000 kB\nSwapFree:         50000 kB\n",
+		"mounts":   "",
+		"net/dev":  netDev("1000", "500"),
+		"1/stat":   "1 (init) S 0 1 1 0 -1 4194560 0 0 0 0 3000 2000 0 0 20 0 1 0 50000 1000 250 18446744073709551615\n",
+		"1/status": "Name:\tinit\nUid:\t0\t0\t0\t0\nVmRSS:\t    10000 kB\nThreads:\t1\n",
+	})
+
+	store := NewStore(StoreOptions{Interval: 40 * time.Millisecond})
+	sampler := NewSampler(procfs.NewCollector(root), store, logging.NewLogger(io.Discard, logging.LogLevelError))
+
+	values := func(samples []Sample) map[string]float64 {
+		out := make(map[string]float64)
+		for _, s := range samples {
+			out[s.Name] = s.Value
+		}
+		return out
+	}
+
+	// The first round has no previous counters, so rates are not recorded yet
+	first := values(sampler.Sample(context.Background()))
+	assert.NotContains(t, first, "cpu_percent")
+	assert.NotContains(t, first, "network_rx_bytes_per_sec")
+	assert.Equal(t, 75.0, first["memory_used_percent"])
+	assert.Equal(t, 50.0, first["swap_used_percent"])
+	assert.Equal(t, 0.5, first["load1"])
+	assert.Equal(t, 0.3, first["load15"])
+	assert.Contains(t, first, "process_memory_bytes")
+
+	writeProc(t, root, map[string]string{
+		"stat":    "cpu  130 0 70 850 50 0 0 0 0 0\n",
+		"net/dev": netDev("2000", "500"),
+	})
+	second := values(sampler.Sample(context.Background()))
+	assert.InDelta(t, 50.0, second["cpu_percent"], 0.001)
+	assert.Greater(t, second["network_rx_bytes_per_sec"], 0.0)
+	assert.Equal(t, 0.0, second["network_tx_bytes_per_sec"])
+}
+
+func TestSampler_RunPersistsOnShutdown(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, map[string]string{
+		"meminfo": "MemTotal:       1000000 kB\nMemAvailable:    250000 kB\n",
+	})
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	store := NewStore(StoreOptions{Interval: 20 * time.Millisecond, Path: path})
+	sampler := NewSampler(procfs.NewCollector(root), store, logging.NewLogger(io.Discard, logging.LogLevelError))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sampler.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		for _, s := range store.List().Series {
+			if s.Name == "memory_used_percent" && s.Samples >= 2 {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	restored := NewStore(StoreOptions{Interval: 20 * time.Millisecond, Path: path})
+	require.NoError(t, restored.Load(time.Now()))
+	assert.NotEmpty(t, restored.List().Series)
+}
@@ -0,0 +1,326 @@
+// Package metrics records host and process metrics in the background and
+// answers time-series queries over the recorded history. Samples are kept in
+// bounded in-memory ring buffers and can be persisted to a file across restarts.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// Store defaults
+const (
+	DefaultInterval  = 15 * time.Second
+	DefaultRetention = 24 * time.Hour
+	DefaultMaxSeries = 500
+)
+
+// storeFileVersion is the format version of the persistence file
+const storeFileVersion = 1
+
+// StoreOptions configures a Store
+type StoreOptions struct {
+	// Interval is the expected time between samples; with Retention it sizes the ring buffers
+	Interval time.Duration
+	// Retention is how long samples are kept
+	Retention time.Duration
+	// MaxSeries caps the number of series; the least recently written series is evicted first
+	MaxSeries int
+	// Path is the file samples are persisted to; empty keeps them in memory only
+	Path string
+}
+
+// Sample is one value of a series taken at the time passed to Append
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// point is a stored sample; times are Unix milliseconds to keep the ring compact
+type point struct {
+	t int64
+	v float64
+}
+
+// series is a ring buffer of the points of one metric and label set
+type series struct {
+	name   string
+	labels map[string]string
+	points []point
+	// next is the index the next point is written to once the ring is full
+	next int
+}
+
+// append adds a point, overwriting the oldest once the ring holds capacity points.
+// The ring grows on demand so short-lived series stay small.
+func (s *series) append(p point, capacity int) {
+	if len(s.points) < capacity {
+		s.points = append(s.points, p)
+		return
+	}
+	s.points[s.next] = p
+	s.next = (s.next + 1) % capacity
+}
+
+// ordered returns the points oldest first
+func (s *series) ordered() []point {
+	out := make([]point, 0, len(s.points))
+	out = append(out, s.points[s.next:]...)
+	return append(out, s.points[:s.next]...)
+}
+
+// newest returns the time of the latest point
+func (s *series) newest() int64 {
+	if len(s.points) == 0 {
+		return 0
+	}
+	return s.points[(s.next+len(s.points)-1)%len(s.points)].t
+}
+
+// Store holds recorded series. Memory is bounded by MaxSeries times the ring
+// capacity of Retention/Interval points of 16 bytes each.
+type Store struct {
+	mu        sync.RWMutex
+	interval  time.Duration
+	retention time.Duration
+	capacity  int
+	maxSeries int
+	path      string
+	series    map[string]*series
+}
+
+// NewStore creates an empty store; zero options take the defaults
+func NewStore(opts StoreOptions) *Store {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	if opts.MaxSeries <= 0 {
+		opts.MaxSeries = DefaultMaxSeries
+	}
+	return &Store{
+		interval:  opts.Interval,
+		retention: opts.Retention,
+		capacity:  int(opts.Retention/opts.Interval) + 1,
+		maxSeries: opts.MaxSeries,
+		path:      opts.Path,
+		series:    make(map[string]*series),
+	}
+}
+
+// Interval returns the expected time between samples
+func (s *Store) Interval() time.Duration { return s.interval }
+
+// Retention returns how long samples are kept
+func (s *Store) Retention() time.Duration { return s.retention }
+
+// seriesKey identifies a series by its name and sorted labels
+func seriesKey(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteString("\x00" + k + "=" + labels[k])
+	}
+	return b.String()
+}
+
+// Append records samples taken at t, then drops series with no samples
+// within the retention period and evicts the stalest series over MaxSeries
+func (s *Store) Append(t time.Time, samples []Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := point{t: t.UnixMilli()}
+	for _, sample := range samples {
+		if math.IsNaN(sample.Value) || math.IsInf(sample.Value, 0) {
+			continue
+		}
+		key := seriesKey(sample.Name, sample.Labels)
+		ser, ok := s.series[key]
+		if !ok {
+			ser = &series{name: sample.Name, labels: sample.Labels}
+			s.series[key] = ser
+		}
+		p.v = sample.Value
+		ser.append(p, s.capacity)
+	}
+	s.prune(t)
+}
+
+// prune removes expired series and, over the cap, the least recently written ones
+func (s *Store) prune(now time.Time) {
+	cutoff := now.Add(-s.retention).UnixMilli()
+	for key, ser := range s.series {
+		if ser.newest() < cutoff {
+			delete(s.series, key)
+		}
+	}
+	if len(s.series) <= s.maxSeries {
+		return
+	}
+
+	keys := make([]string, 0, len(s.series))
+	for key := range s.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return s.series[keys[i]].newest() < s.series[keys[j]].newest()
+	})
+	for _, key := range keys[:len(keys)-s.maxSeries] {
+		delete(s.series, key)
+	}
+}
+
+// List describes every recorded series
+func (s *Store) List() *resources.MetricCatalog {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	catalog := &resources.MetricCatalog{
+		IntervalSeconds:  s.interval.Seconds(),
+		RetentionSeconds: s.retention.Seconds(),
+		Series:           make([]resources.MetricSeriesInfo, 0, len(s.series)),
+	}
+	for _, key := range s.sortedKeys() {
+		ser := s.series[key]
+		points := ser.ordered()
+		catalog.Series = append(catalog.Series, resources.MetricSeriesInfo{
+			Name:    ser.name,
+			Labels:  ser.labels,
+			Unit:    Units[ser.name],
+			Samples: len(points),
+			Oldest:  time.UnixMilli(points[0].t),
+			Newest:  time.UnixMilli(points[len(points)-1].t),
+		})
+	}
+	return catalog
+}
+
+// sortedKeys returns the series keys in name and label order; the caller holds the lock
+func (s *Store) sortedKeys() []string {
+	keys := make([]string, 0, len(s.series))
+	for key := range s.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// storeFile is the persisted form of a store
+type storeFile struct {
+	Version int          `json:"version"`
+	Series  []fileSeries `json:"series"`
+}
+
+// fileSeries is a persisted series; points are [unix_ms, value] pairs
+type fileSeries struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Points [][2]float64      `json:"points"`
+}
+
+// Save writes every series to the persistence file, replacing it atomically.
+// It does nothing when the store has no path.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	file := storeFile{Version: storeFileVersion, Series: make([]fileSeries, 0, len(s.series))}
+	for _, key := range s.sortedKeys() {
+		ser := s.series[key]
+		fs := fileSeries{Name: ser.name, Labels: ser.labels, Points: make([][2]float64, 0, len(ser.points))}
+		for _, p := range ser.ordered() {
+			fs.Points = append(fs.Points, [2]float64{float64(p.t), p.v})
+		}
+		file.Series = append(file.Series, fs)
+	}
+	s.mu.RUnlock()
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save metrics: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save metrics: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save metrics: %w", err)
+	}
+	return nil
+}
+
+// Load reads the persistence file into the store, dropping samples older than
+// the retention period. It is meant to be called once, before sampling
+// starts. A missing file is not an error.
+func (s *Store) Load(now time.Time) error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load metrics: %w", err)
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to load metrics from %s: %w", s.path, err)
+	}
+	if file.Version != storeFileVersion {
+		return fmt.Errorf("unsupported metrics file version %d in %s", file.Version, s.path)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := now.Add(-s.retention).UnixMilli()
+	for _, fs := range file.Series {
+		key := seriesKey(fs.Name, fs.Labels)
+		ser, ok := s.series[key]
+		if !ok {
+			ser = &series{name: fs.Name, labels: fs.Labels}
+		}
+		for _, p := range fs.Points {
+			if t := int64(p[0]); t >= cutoff {
+				ser.append(point{t: t, v: p[1]}, s.capacity)
+			}
+		}
+		if len(ser.points) > 0 {
+			s.series[key] = ser
+		}
+	}
+	s.prune(now)
+	return nil
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// fillStore records memory_used_percent climbing by one each minute for an hour,
+// and a disk series per mount
+func fillStore(t *testing.T, store *Store) {
+	t.Helper()
+	for i := 0; i < 60; i++ {
+		store.Append(epoch.Add(time.Duration(i)*time.Minute), []Sample{
+			{Name: "memory_used_percent", Value: float64(i)},
+			{Name: "disk_used_percent", Labels: map[string]string{"mount": "/"}, Value: 40},
+			{Name: "disk_used_percent", Labels: map[string]string{"mount": "/var"}, Value: 70},
+		})
+	}
+}
+
+func TestStore_RingBufferKeepsRetention(t *testing.T) {
+	store := NewStore(StoreOptions{Interval: time.Minute, Retention: 10 * time.Minute})
+	fillStore(t, store)
+
+	catalog := store.List()
+	require.Len(t, catalog.Series, 3)
+	mem := catalog.Series[2]
+	assert.Equal(t, "memory_used_percent", mem.Name)
+	assert.Equal(t, "percent", mem.Unit)
+	assert.Equal(t, 11, mem.Samples)
+	assert.Equal(t, epoch.Add(49*time.Minute), mem.Oldest.UTC())
+	assert.Equal(t, epoch.Add(59*time.Minute), mem.Newest.UTC())
+}
+
+func TestStore_EvictsStaleSeries(t *testing.T) {
+	store := NewStore(StoreOptions{Interval: time.Minute, Retention: time.Hour, MaxSeries: 2})
+	store.Append(epoch, []Sample{{Name: "process_cpu_percent", Labels: map[string]string{"pid": "1"}, Value: 1}})
+	store.Append(epoch.Add(time.Minute), []Sample{{Name: "process_cpu_percent", Labels: map[string]string{"pid": "2"}, Value: 2}})
+	store.Append(epoch.Add(2*time.Minute), []Sample{{Name: "process_cpu_percent", Labels: map[string]string{"pid": "3"}, Value: 3}})
+
+	catalog := store.List()
+	require.Len(t, catalog.Series, 2)
+	assert.Equal(t, "2", catalog.Series[0].Labels["pid"])
+	assert.Equal(t, "3", catalog.Series[1].Labels["pid"])
+
+	// Series without samples within the retention period expire
+	store.Append(epoch.Add(2*time.Hour), []Sample{{Name: "cpu_percent", Value: 5}})
+	catalog = store.List()
+	require.Len(t, catalog.Series, 1)
+	assert.Equal(t, "cpu_percent", catalog.Series[0].Name)
+}
+
+func TestStore_Query(t *testing.T) {
+	store := NewStore(StoreOptions{Interval: time.Minute, Retention: 24 * time.Hour})
+	fillStore(t, store)
+	now := epoch.Add(time.Hour)
+
+	result, err := store.Query(Query{Metric: "memory_used_percent", MaxPoints: 6}, now)
+	require.NoError(t, err)
+	require.Len(t, result.Series, 1)
+	assert.Equal(t, 600.0, result.StepSeconds)
+	assert.Equal(t, "avg", result.Aggregation)
+
+	series := result.Series[0]
+	assert.Equal(t, 60, series.Samples)
+	assert.Equal(t, 0.0, series.Min)
+	assert.Equal(t, 59.0, series.Max)
+	assert.Equal(t, 29.5, series.Avg)
+	assert.Equal(t, 56.0, series.P95)
+	assert.Equal(t, 59.0, series.Values["change"])
+	require.Len(t, series.History, 6)
+	assert.Equal(t, epoch, series.History[0].Timestamp.UTC())
+	assert.Equal(t, 4.5, series.History[0].Value)
+	assert.Equal(t, 54.5, series.History[5].Value)
+
+	result, err = store.Query(Query{Metric: "memory_used_percent", Since: epoch.Add(30 * time.Minute), Step: 15 * time.Minute, Aggregation: AggregationMax}, now)
+	require.NoError(t, err)
+	require.Len(t, result.Series, 1)
+	assert.Equal(t, 30, result.Series[0].Samples)
+	require.Len(t, result.Series[0].History, 2)
+	assert.Equal(t, 44.0, result.Series[0].History[0].Value)
+	assert.Equal(t, 59.0, result.Series[0].History[1].Value)
+
+	// Globs match metric names and label values
+	result, err = store.Query(Query{Metric: "disk_*", Labels: map[string]string{"mount": "/v*"}}, now)
+	require.NoError(t, err)
+	require.Len(t, result.Series, 1)
+	assert.Equal(t, "/var", result.Series[0].Labels["mount"])
+	assert.Equal(t, 70.0, result.Series[0].P95)
+
+	// Steps never go below the sampling interval
+	result, err = store.Query(Query{Metric: "memory_used_percent", Step: time.Second}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 60.0, result.StepSeconds)
+
+	// An explicit step may not exceed the point limit
+	_, err = store.Query(Query{Metric: "memory_used_percent", Since: now.Add(-7 * 24 * time.Hour), Step: time.Minute}, now)
+	assert.Error(t, err)
+	_, err = store.Query(Query{Metric: "memory_used_percent", Since: now.Add(-24 * time.Hour), Step: time.Hour}, now)
+	assert.NoError(t, err)
+
+	_, err = store.Query(Query{Metric: "memory_used_percent", Aggregation: "median"}, now)
+	assert.Error(t, err)
+	_, err = store.Query(Query{Metric: "memory_used_percent", Since: now, Until: epoch}, now)
+	assert.Error(t, err)
+	_, err = store.Query(Query{Metric: "[", Since: epoch}, now)
+	assert.Error(t, err)
+}
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	store := NewStore(StoreOptions{Interval: time.Minute, Retention: 24 * time.Hour, Path: path})
+	fillStore(t, store)
+	require.NoError(t, store.Save())
+
+	// Reloading with a shorter retention drops the older samples
+	restored := NewStore(StoreOptions{Interval: time.Minute, Retention: 30 * time.Minute, Path: path})
+	require.NoError(t, restored.Load(epoch.Add(time.Hour)))
+	catalog := restored.List()
+	require.Len(t, catalog.Series, 3)
+	assert.Equal(t, 30, catalog.Series[2].Samples)
+	assert.Equal(t, epoch.Add(30*time.Minute), catalog.Series[2].Oldest.UTC())
+
+	missing := NewStore(StoreOptions{Path: filepath.Join(t.TempDir(), "absent.json")})
+	assert.NoError(t, missing.Load(epoch))
+	assert.Empty(t, missing.List().Series)
+}
@@ -0,0 +1,39 @@
+package network
+
+import (
+	"os"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// readResolvConf parses nameserver, search and domain lines from a resolv.conf file
+func readResolvConf(path string) (*resources.DNSConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dns := &resources.DNSConfig{Nameservers: make([]string, 0), ResolvConf: string(data)}
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.IndexAny(line, "#;"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			dns.Nameservers = append(dns.Nameservers, fields[1])
+		case "search":
+			// The last search line wins, as in the resolver
+			dns.SearchDomains = fields[1:]
+		case "domain":
+			if len(dns.SearchDomains) == 0 {
+				dns.SearchDomains = fields[1:2]
+			}
+		}
+	}
+	return dns, nil
+}
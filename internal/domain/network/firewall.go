@@ -0,0 +1,346 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// FirewallRules lists the firewall rules from `nft -j list ruleset`, falling
+// back to iptables-save when nft is unavailable or its ruleset is empty
+// (legacy iptables hosts)
+func (s *ServiceImpl) FirewallRules(ctx context.Context) ([]resources.FirewallRule, error) {
+	nftOut, nftErr := s.run(ctx, "nft", "-j", "list", "ruleset")
+	if nftErr == nil {
+		rules, err := parseNftRuleset(nftOut)
+		if err != nil {
+			nftErr = err
+		} else if len(rules) > 0 {
+			return rules, nil
+		}
+	}
+
+	saveOut, saveErr := s.run(ctx, "iptables-save")
+	if saveErr == nil {
+		return parseIptablesSave(saveOut), nil
+	}
+	if nftErr == nil {
+		return nil, nil // nftables is in use and has no rules
+	}
+	return nil, fmt.Errorf("nft: %v; iptables-save: %v", nftErr, saveErr)
+}
+
+// ruleMatchesInterface reports whether a rule can apply to traffic on iface:
+// rules without an interface match apply everywhere
+func ruleMatchesInterface(rule resources.FirewallRule, iface string) bool {
+	if rule.InInterface == "" && rule.OutInterface == "" {
+		return true
+	}
+	return interfaceSpecMatches(rule.InInterface, iface) || interfaceSpecMatches(rule.OutInterface, iface)
+}
+
+// interfaceSpecMatches matches an interface spec such as "eth0", "!eth0", "eth+" or "eth*"
+func interfaceSpecMatches(spec, iface string) bool {
+	if spec == "" {
+		return false
+	}
+	negated := strings.HasPrefix(spec, "!")
+	spec = strings.TrimSpace(strings.TrimPrefix(spec, "!"))
+
+	matched := spec == iface
+	if prefix, ok := strings.CutSuffix(spec, "+"); ok {
+		matched = strings.HasPrefix(iface, prefix)
+	} else if prefix, ok := strings.CutSuffix(spec, "*"); ok {
+		matched = strings.HasPrefix(iface, prefix)
+	}
+	return matched != negated
+}
+
+// nftRule is a rule object in nft's JSON output
+type nftRule struct {
+	Family  string                       `json:"family"`
+	Table   string                       `json:"table"`
+	Chain   string                       `json:"chain"`
+	Comment string                       `json:"comment"`
+	Expr    []map[string]json.RawMessage `json:"expr"`
+}
+
+// nftMatch is the match statement: left op right
+type nftMatch struct {
+	Op    string          `json:"op"`
+	Left  json.RawMessage `json:"left"`
+	Right json.RawMessage `json:"right"`
+}
+
+// nftVerdicts are statements that end rule evaluation and become the rule target
+var nftVerdicts = map[string]bool{
+	"accept": true, "drop": true, "reject": true, "return": true, "queue": true,
+	"continue": true, "masquerade": true, "snat": true, "dnat": true, "redirect": true,
+}
+
+// parseNftRuleset converts the rules in `nft -j list ruleset` output
+func parseNftRuleset(output string) ([]resources.FirewallRule, error) {
+	var ruleset struct {
+		Nftables []map[string]json.RawMessage `json:"nftables"`
+	}
+	if err := json.Unmarshal([]byte(output), &ruleset); err != nil {
+		return nil, fmt.Errorf("invalid nft JSON: %w", err)
+	}
+
+	var rules []resources.FirewallRule
+	for _, object := range ruleset.Nftables {
+		raw, ok := object["rule"]
+		if !ok {
+			continue
+		}
+		var rule nftRule
+		if err := json.Unmarshal(raw, &rule); err != nil {
+			return nil, fmt.Errorf("invalid nft rule: %w", err)
+		}
+		rules = append(rules, convertNftRule(rule))
+	}
+	return rules, nil
+}
+
+// convertNftRule extracts the common fields of an nft rule and renders it in nft syntax
+func convertNftRule(rule nftRule) resources.FirewallRule {
+	out := resources.FirewallRule{
+		Chain: rule.Chain,
+		Table: strings.TrimSpace(rule.Family + " " + rule.Table),
+	}
+
+	var text []string
+	for _, stmt := range rule.Expr {
+		for key, raw := range stmt {
+			switch {
+			case key == "match":
+				var m nftMatch
+				if json.Unmarshal(raw, &m) != nil {
+					continue
+				}
+				left := nftExpression(m.Left)
+				right := nftExpression(m.Right)
+				op := ""
+				if m.Op != "==" && m.Op != "in" {
+					op = m.Op
+				}
+				text = append(text, strings.Join(nonEmpty(left, op, right), " "))
+
+				value := right
+				if op == "!=" {
+					value = "!" + right
+				}
+				switch {
+				case strings.HasSuffix(left, " saddr"):
+					out.Source = value
+				case strings.HasSuffix(left, " daddr"):
+					out.Destination = value
+				case left == "meta iifname" || left == "iifname":
+					out.InInterface = value
+				case left == "meta oifname" || left == "oifname":
+					out.OutInterface = value
+				case left == "meta l4proto" || left == "ip protocol" || left == "ip6 nexthdr":
+					out.Protocol = right
+				case strings.HasSuffix(left, " dport") || strings.HasSuffix(left, " sport"):
+					if out.Protocol == "" {
+						out.Protocol = strings.Fields(left)[0]
+					}
+				}
+			case key == "jump" || key == "goto":
+				var target struct {
+					Target string `json:"target"`
+				}
+				if json.Unmarshal(raw, &target) == nil {
+					out.Target = target.Target
+					text = append(text, key+" "+target.Target)
+				}
+			case nftVerdicts[key]:
+				out.Target = strings.ToUpper(key)
+				text = append(text, key)
+			default:
+				text = append(text, key)
+			}
+		}
+	}
+	if rule.Comment != "" {
+		text = append(text, fmt.Sprintf("comment %q", rule.Comment))
+	}
+	out.Rule = strings.Join(text, " ")
+	return out
+}
+
+// nftExpression renders an nft JSON expression in nft syntax
+func nftExpression(raw json.RawMessage) string {
+	var scalar any
+	if err := json.Unmarshal(raw, &scalar); err != nil {
+		return ""
+	}
+	switch v := scalar.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	case []any:
+		return renderSet(raw)
+	}
+
+	var expr struct {
+		Payload *struct {
+			Protocol string `json:"protocol"`
+			Field    string `json:"field"`
+		} `json:"payload"`
+		Meta *struct {
+			Key string `json:"key"`
+		} `json:"meta"`
+		Prefix *struct {
+			Addr string `json:"addr"`
+			Len  int    `json:"len"`
+		} `json:"prefix"`
+		Range []json.RawMessage `json:"range"`
+		Set   json.RawMessage   `json:"set"`
+		Ct    *struct {
+			Key string `json:"key"`
+		} `json:"ct"`
+	}
+	if err := json.Unmarshal(raw, &expr); err != nil {
+		return ""
+	}
+	switch {
+	case expr.Payload != nil:
+		return expr.Payload.Protocol + " " + expr.Payload.Field
+	case expr.Meta != nil:
+		if expr.Meta.Key == "iifname" || expr.Meta.Key == "oifname" {
+			return expr.Meta.Key
+		}
+		return "meta " + expr.Meta.Key
+	case expr.Prefix != nil:
+		return fmt.Sprintf("%s/%d", expr.Prefix.Addr, expr.Prefix.Len)
+	case len(expr.Range) == 2:
+		return nftExpression(expr.Range[0]) + "-" + nftExpression(expr.Range[1])
+	case expr.Set != nil:
+		return renderSet(expr.Set)
+	case expr.Ct != nil:
+		return "ct " + expr.Ct.Key
+	}
+	return string(raw)
+}
+
+// renderSet renders an anonymous set as "{ a, b }" or a single value
+func renderSet(raw json.RawMessage) string {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(raw, &elements); err != nil {
+		return nftExpression(raw)
+	}
+	parts := make([]string, 0, len(elements))
+	for _, element := range elements {
+		parts = append(parts, nftExpression(element))
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+// parseIptablesSave converts the -A lines of iptables-save output
+func parseIptablesSave(output string) []resources.FirewallRule {
+	var rules []resources.FirewallRule
+	table := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "*"):
+			table = strings.TrimPrefix(line, "*")
+			continue
+		case !strings.HasPrefix(line, "-A "):
+			continue
+		}
+
+		args := splitArgs(line)
+		if len(args) < 2 {
+			continue
+		}
+		rule := resources.FirewallRule{
+			Chain: args[1],
+			Table: table,
+			Rule:  strings.TrimSpace(strings.TrimPrefix(line, "-A "+args[1])),
+		}
+		negate := false
+		for i := 2; i < len(args); i++ {
+			if args[i] == "!" {
+				negate = true
+				continue
+			}
+			value := ""
+			if i+1 < len(args) {
+				value = args[i+1]
+			}
+			if negate {
+				value = "!" + value
+			}
+			switch args[i] {
+			case "-p", "--protocol":
+				rule.Protocol = value
+			case "-s", "--source":
+				rule.Source = value
+			case "-d", "--destination":
+				rule.Destination = value
+			case "-i", "--in-interface":
+				rule.InInterface = value
+			case "-o", "--out-interface":
+				rule.OutInterface = value
+			case "-j", "--jump", "-g", "--goto":
+				rule.Target = strings.TrimPrefix(value, "!")
+			default:
+				negate = false
+				continue
+			}
+			negate = false
+			i++
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// splitArgs splits an iptables-save line into arguments, honouring double quotes
+func splitArgs(line string) []string {
+	var args []string
+	var current strings.Builder
+	inQuotes, escaped, started := false, false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			started = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// nonEmpty returns the non-empty strings
+func nonEmpty(values ...string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
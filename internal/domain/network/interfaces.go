@@ -0,0 +1,113 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// interfaceFlags maps IFF_* bits in /sys/class/net/<name>/flags to the names net.Flags uses
+var interfaceFlags = []struct {
+	bit  uint64
+	name string
+}{
+	{0x1, "up"},
+	{0x2, "broadcast"},
+	{0x8, "loopback"},
+	{0x10, "pointtopoint"},
+	{0x40, "running"},
+	{0x1000, "multicast"},
+}
+
+// Interfaces reads every network device, or only the named one, from /sys/class/net
+func (s *ServiceImpl) Interfaces(filter string) ([]resources.NetworkInterface, error) {
+	entries, err := os.ReadDir(s.sysRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if filter == "" || entry.Name() == filter {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	ifaces := make([]resources.NetworkInterface, 0, len(names))
+	for _, name := range names {
+		ifaces = append(ifaces, s.readInterface(name))
+	}
+	return ifaces, nil
+}
+
+// readInterface reads one device; attributes that cannot be read are left empty
+func (s *ServiceImpl) readInterface(name string) resources.NetworkInterface {
+	attr := func(rel ...string) string {
+		data, err := os.ReadFile(filepath.Join(append([]string{s.sysRoot, name}, rel...)...))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	counter := func(stat string) uint64 {
+		v, _ := strconv.ParseUint(attr("statistics", stat), 10, 64)
+		return v
+	}
+
+	iface := resources.NetworkInterface{
+		Name:         name,
+		HardwareAddr: attr("address"),
+		BytesRecv:    counter("rx_bytes"),
+		BytesSent:    counter("tx_bytes"),
+		PacketsRecv:  counter("rx_packets"),
+		PacketsSent:  counter("tx_packets"),
+	}
+	if iface.HardwareAddr == "00:00:00:00:00:00" {
+		iface.HardwareAddr = ""
+	}
+	iface.MTU, _ = strconv.Atoi(attr("mtu"))
+	if flags, err := strconv.ParseUint(strings.TrimPrefix(attr("flags"), "0x"), 16, 32); err == nil {
+		for _, flag := range interfaceFlags {
+			if flags&flag.bit != 0 {
+				iface.Flags = append(iface.Flags, flag.name)
+			}
+		}
+	}
+	if addrs, err := s.addresses(name); err == nil {
+		iface.Addresses = addrs
+	}
+	return iface
+}
+
+// interfaceExists reports whether a device of that name exists
+func (s *ServiceImpl) interfaceExists(name string) bool {
+	if strings.ContainsAny(name, "/\x00") || name == "." || name == ".." {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(s.sysRoot, name))
+	return err == nil
+}
+
+// interfaceAddresses lists the addresses of an interface in CIDR notation via netlink
+func interfaceAddresses(name string) ([]string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("interface %s: %w", name, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("interface %s: %w", name, err)
+	}
+	out := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		out = append(out, addr.String())
+	}
+	return out, nil
+}
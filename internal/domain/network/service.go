@@ -0,0 +1,164 @@
+// Package network reports interfaces, routes, DNS settings, socket states and
+// firewall rules, reading /sys, /proc and resolv.conf directly and asking
+// nft or iptables-save only for the firewall ruleset.
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+const (
+	// DefaultSysRoot is where the kernel exposes network devices
+	DefaultSysRoot = "/sys/class/net"
+	// DefaultResolvConf is the resolver configuration file
+	DefaultResolvConf = "/etc/resolv.conf"
+)
+
+// Options selects the sections of the report
+type Options struct {
+	Interfaces  bool
+	Connections bool
+	Routing     bool
+	DNS         bool
+	Firewall    bool
+	// Interface restricts interfaces, routes, connections and firewall rules to one interface
+	Interface string
+}
+
+// Service defines the interface for network inspection domain services
+type Service interface {
+	Info(ctx context.Context, opts Options) (*resources.NetworkInfo, error)
+}
+
+// ServiceImpl implements the network inspection domain service
+type ServiceImpl struct {
+	collector  *procfs.Collector
+	sysRoot    string
+	resolvConf string
+	run        registry.CommandRunner
+	logger     logging.Logger
+
+	// addresses lists the addresses of an interface; replaced in tests
+	addresses func(name string) ([]string, error)
+}
+
+// NewService creates a network inspection service; run executes the firewall listing commands
+func NewService(collector *procfs.Collector, run registry.CommandRunner, logger logging.Logger) Service {
+	return &ServiceImpl{
+		collector:  collector,
+		sysRoot:    DefaultSysRoot,
+		resolvConf: DefaultResolvConf,
+		run:        run,
+		logger:     logger,
+		addresses:  interfaceAddresses,
+	}
+}
+
+// Info builds the requested sections. A section that cannot be read is
+// reported in Warnings rather than failing the whole call; an unknown
+// filter interface is an error.
+func (s *ServiceImpl) Info(ctx context.Context, opts Options) (*resources.NetworkInfo, error) {
+	info := &resources.NetworkInfo{}
+	warn := func(section string, err error) {
+		logging.FromContext(ctx, s.logger).Warning("Failed to read network section", map[string]any{"section": section, "error": err.Error()})
+		info.Warnings = append(info.Warnings, fmt.Sprintf("%s: %v", section, err))
+	}
+
+	var filterAddrs []string
+	if opts.Interface != "" {
+		if !s.interfaceExists(opts.Interface) {
+			return nil, fmt.Errorf("interface %q not found", opts.Interface)
+		}
+		filterAddrs, _ = s.addresses(opts.Interface)
+	}
+
+	if opts.Interfaces {
+		ifaces, err := s.Interfaces(opts.Interface)
+		if err != nil {
+			warn("interfaces", err)
+		}
+		info.Interfaces = ifaces
+	}
+
+	if opts.Routing {
+		routes, err := s.collector.Routes()
+		if err != nil {
+			warn("routing", err)
+		}
+		for _, route := range routes {
+			if opts.Interface == "" || route.Interface == opts.Interface {
+				info.RoutingTable = append(info.RoutingTable, route)
+			}
+		}
+	}
+
+	if opts.DNS {
+		dns, err := readResolvConf(s.resolvConf)
+		if err != nil {
+			warn("dns", err)
+		}
+		info.DNSConfig = dns
+	}
+
+	if opts.Connections {
+		if err := s.connections(info, opts.Interface, filterAddrs); err != nil {
+			warn("connections", err)
+		}
+	}
+
+	if opts.Firewall {
+		rules, err := s.FirewallRules(ctx)
+		if err != nil {
+			warn("firewall", err)
+		}
+		for _, rule := range rules {
+			if opts.Interface == "" || ruleMatchesInterface(rule, opts.Interface) {
+				info.FirewallRules = append(info.FirewallRules, rule)
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// connections summarizes TCP and UDP sockets by protocol and state and lists
+// the listening ones. With an interface filter only sockets bound to one of
+// its addresses, or to a wildcard address, are counted.
+func (s *ServiceImpl) connections(info *resources.NetworkInfo, iface string, addrs []string) error {
+	conns, err := s.collector.Connections(procfs.ProtocolTCP, procfs.ProtocolTCP6, procfs.ProtocolUDP, procfs.ProtocolUDP6)
+	if err != nil {
+		return err
+	}
+
+	local := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		if ip, _, err := net.ParseCIDR(addr); err == nil {
+			local[ip.String()] = true
+		}
+	}
+
+	summary := &resources.ConnectionSummary{ByProtocol: make(map[string]int), ByState: make(map[string]int)}
+	for _, conn := range conns {
+		if iface != "" {
+			ip := net.ParseIP(conn.LocalAddress)
+			if ip == nil || (!ip.IsUnspecified() && !local[ip.String()]) {
+				continue
+			}
+		}
+		summary.Total++
+		summary.ByProtocol[conn.Protocol]++
+		summary.ByState[conn.State]++
+		if procfs.IsListening(conn) {
+			info.Connections = append(info.Connections, conn)
+		}
+	}
+	info.ConnectionSummary = summary
+	return nil
+}
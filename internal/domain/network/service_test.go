@@ -0,0 +1,274 @@
+package network
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/types/resources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostWord encodes an IPv4 address as a host-order hex word, as /proc/net uses
+func hostWord(ip string) string {
+	raw := make([]byte, 4)
+	binary.NativeEndian.PutUint32(raw, binary.BigEndian.Uint32(net.ParseIP(ip).To4()))
+	return strings.ToUpper(hex.EncodeToString(raw))
+}
+
+const nftRuleset = `{"nftables": [
+  {"metainfo": {"version": "1.0.6", "json_schema_version": 1}},
+  {"table": {"family": "inet", "name": "filter", "handle": 1}},
+  {"chain": {"family": "inet", "table": "filter", "name": "input", "handle": 1, "type": "filter", "hook": "input", "prio": 0, "policy": "drop"}},
+  {"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 4, "expr": [
+    {"match": {"op": "==", "left": {"meta": {"key": "iifname"}}, "right": "lo"}},
+    {"accept": null}]}},
+  {"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 5, "comment": "ssh from lan", "expr": [
+    {"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "saddr"}}, "right": {"prefix": {"addr": "10.0.0.0", "len": 8}}}},
+    {"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": {"set": [22, 2222]}}},
+    {"counter": {"packets": 0, "bytes": 0}},
+    {"accept": null}]}},
+  {"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 6, "expr": [
+    {"match": {"op": "!=", "left": {"meta": {"key": "iifname"}}, "right": "eth0"}},
+    {"jump": {"target": "internal"}}]}}
+]}`
+
+const iptablesSave = `# Generated by iptables-save v1.8.7
+*filter
+:INPUT DROP [0:0]
+:FORWARD DROP [0:0]
+-A INPUT -i lo -j ACCEPT
+-A INPUT -s 192.168.1.0/24 -p tcp -m tcp --dport 22 -m comment --comment "ssh from lan" -j ACCEPT
+-A FORWARD -i docker0 ! -o docker0 -j ACCEPT
+COMMIT
+*nat
+-A POSTROUTING -s 172.17.0.0/16 ! -o docker0 -j MASQUERADE
+COMMIT
+`
+
+// newTestService builds a service over fixture /proc, /sys and resolv.conf trees
+func newTestService(t *testing.T, run registry.CommandRunner) *ServiceImpl {
+	t.Helper()
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("sys/lo/address", "00:00:00:00:00:00\n")
+	write("sys/lo/mtu", "65536\n")
+	write("sys/lo/flags", "0x9\n")
+	write("sys/lo/statistics/rx_bytes", "100\n")
+	write("sys/eth0/address", "52:54:00:12:34:56\n")
+	write("sys/eth0/mtu", "1500\n")
+	write("sys/eth0/flags", "0x1043\n")
+	write("sys/eth0/statistics/rx_bytes", "123456\n")
+	write("sys/eth0/statistics/tx_bytes", "654321\n")
+	write("sys/eth0/statistics/rx_packets", "100\n")
+	write("sys/eth0/statistics/tx_packets", "90\n")
+
+	write("proc/net/route", "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"+
+		fmt.Sprintf("eth0\t%s\t%s\t0003\t0\t0\t100\t%s\t0\t0\t0\n", hostWord("0.0.0.0"), hostWord("10.0.0.1"), hostWord("0.0.0.0"))+
+		fmt.Sprintf("eth0\t%s\t%s\t0001\t0\t0\t100\t%s\t0\t0\t0\n", hostWord("10.0.0.0"), hostWord("0.0.0.0"), hostWord("255.255.255.0"))+
+		fmt.Sprintf("docker0\t%s\t%s\t0000\t0\t0\t0\t%s\t0\t0\t0\n", hostWord("172.17.0.0"), hostWord("0.0.0.0"), hostWord("255.255.0.0")))
+	write("proc/net/ipv6_route",
+		"fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0\n"+
+			"00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo\n")
+	write("proc/net/tcp", "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+		"   0: "+hostWord("0.0.0.0")+":0016 "+hostWord("0.0.0.0")+":0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1\n"+
+		"   1: "+hostWord("10.0.0.5")+":0016 "+hostWord("10.0.0.9")+":C93A 01 00000000:00000000 00:00000000 00000000     0        0 2 1\n"+
+		"   2: "+hostWord("127.0.0.1")+":1F90 "+hostWord("127.0.0.1")+":D431 06 00000000:00000000 00:00000000 00000000     0        0 0 1\n")
+
+	write("resolv.conf", "# managed by systemd-resolved\nnameserver 127.0.0.53\nnameserver 1.1.1.1 ; backup\nsearch corp.example lan\noptions edns0\n")
+
+	return &ServiceImpl{
+		collector:  procfs.NewCollector(filepath.Join(root, "proc")),
+		sysRoot:    filepath.Join(root, "sys"),
+		resolvConf: filepath.Join(root, "resolv.conf"),
+		run:        run,
+		logger:     logging.NewLogger(io.Discard, logging.LogLevelError),
+		addresses: func(name string) ([]string, error) {
+			if name == "eth0" {
+				return []string{"10.0.0.5/24", "fe80::5054:ff:fe12:3456/64"}, nil
+			}
+			return []string{"127.0.0.1/8"}, nil
+		},
+	}
+}
+
+// fakeRunner returns canned output per command and an error for anything else
+func fakeRunner(outputs map[string]string) registry.CommandRunner {
+	return func(ctx context.Context, command string, args ...string) (string, error) {
+		if out, ok := outputs[command]; ok {
+			return out, nil
+		}
+		return "", fmt.Errorf("system command not allowed: %s", command)
+	}
+}
+
+func allSections(iface string) Options {
+	return Options{Interfaces: true, Connections: true, Routing: true, DNS: true, Firewall: true, Interface: iface}
+}
+
+func TestInfo_AllSections(t *testing.T) {
+	svc := newTestService(t, fakeRunner(map[string]string{"nft": nftRuleset}))
+
+	info, err := svc.Info(context.Background(), allSections(""))
+	require.NoError(t, err)
+	assert.Empty(t, info.Warnings)
+
+	require.Len(t, info.Interfaces, 2)
+	eth0 := info.Interfaces[0]
+	assert.Equal(t, "eth0", eth0.Name)
+	assert.Equal(t, "52:54:00:12:34:56", eth0.HardwareAddr)
+	assert.Equal(t, 1500, eth0.MTU)
+	assert.Equal(t, []string{"up", "broadcast", "running", "multicast"}, eth0.Flags)
+	assert.Equal(t, uint64(654321), eth0.BytesSent)
+	assert.Contains(t, eth0.Addresses, "10.0.0.5/24")
+	assert.Empty(t, info.Interfaces[1].HardwareAddr)
+	assert.Equal(t, []string{"up", "loopback"}, info.Interfaces[1].Flags)
+
+	assert.Equal(t, []resources.Route{
+		{Destination: "0.0.0.0/0", Gateway: "10.0.0.1", Interface: "eth0", Metric: 100},
+		{Destination: "10.0.0.0/24", Gateway: "0.0.0.0", Interface: "eth0", Metric: 100},
+		{Destination: "fe80::/64", Gateway: "::", Interface: "eth0", Metric: 256},
+	}, info.RoutingTable, "down and reject routes are dropped")
+
+	require.NotNil(t, info.DNSConfig)
+	assert.Equal(t, []string{"127.0.0.53", "1.1.1.1"}, info.DNSConfig.Nameservers)
+	assert.Equal(t, []string{"corp.example", "lan"}, info.DNSConfig.SearchDomains)
+
+	require.NotNil(t, info.ConnectionSummary)
+	assert.Equal(t, 3, info.ConnectionSummary.Total)
+	assert.Equal(t, map[string]int{"LISTEN": 1, "ESTABLISHED": 1, "TIME_WAIT": 1}, info.ConnectionSummary.ByState)
+	require.Len(t, info.Connections, 1)
+	assert.Equal(t, 22, info.Connections[0].LocalPort)
+
+	require.Len(t, info.FirewallRules, 3)
+	ssh := info.FirewallRules[1]
+	assert.Equal(t, "inet filter", ssh.Table)
+	assert.Equal(t, "input", ssh.Chain)
+	assert.Equal(t, "ACCEPT", ssh.Target)
+	assert.Equal(t, "tcp", ssh.Protocol)
+	assert.Equal(t, "10.0.0.0/8", ssh.Source)
+	assert.Equal(t, `ip saddr 10.0.0.0/8 tcp dport { 22, 2222 } counter accept comment "ssh from lan"`, ssh.Rule)
+	assert.Equal(t, "internal", info.FirewallRules[2].Target)
+	assert.Equal(t, "!eth0", info.FirewallRules[2].InInterface)
+}
+
+func TestInfo_FilterInterface(t *testing.T) {
+	svc := newTestService(t, fakeRunner(map[string]string{"nft": nftRuleset}))
+
+	info, err := svc.Info(context.Background(), allSections("eth0"))
+	require.NoError(t, err)
+
+	require.Len(t, info.Interfaces, 1)
+	assert.Equal(t, "eth0", info.Interfaces[0].Name)
+	for _, route := range info.RoutingTable {
+		assert.Equal(t, "eth0", route.Interface)
+	}
+	// The loopback TIME_WAIT socket is not on eth0; the wildcard listener is
+	assert.Equal(t, 2, info.ConnectionSummary.Total)
+	// The lo-only rule is dropped, the "not eth0" rule too
+	require.Len(t, info.FirewallRules, 1)
+	assert.Equal(t, "10.0.0.0/8", info.FirewallRules[0].Source)
+
+	_, err = svc.Info(context.Background(), allSections("wlan9"))
+	assert.Error(t, err)
+	_, err = svc.Info(context.Background(), allSections("../eth0"))
+	assert.Error(t, err)
+}
+
+func TestFirewallRules_IptablesFallback(t *testing.T) {
+	svc := newTestService(t, fakeRunner(map[string]string{"iptables-save": iptablesSave}))
+
+	rules, err := svc.FirewallRules(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rules, 4)
+
+	assert.Equal(t, resources.FirewallRule{
+		Chain:    "INPUT",
+		Table:    "filter",
+		Rule:     `-s 192.168.1.0/24 -p tcp -m tcp --dport 22 -m comment --comment "ssh from lan" -j ACCEPT`,
+		Target:   "ACCEPT",
+		Protocol: "tcp",
+		Source:   "192.168.1.0/24",
+	}, rules[1])
+	assert.Equal(t, "docker0", rules[2].InInterface)
+	assert.Equal(t, "!docker0", rules[2].OutInterface)
+	assert.Equal(t, "nat", rules[3].Table)
+	assert.Equal(t, "MASQUERADE", rules[3].Target)
+
+	assert.True(t, ruleMatchesInterface(rules[3], "eth0"))
+	assert.False(t, ruleMatchesInterface(rules[0], "eth0"))
+}
+
+func TestFirewallRules_Unavailable(t *testing.T) {
+	svc := newTestService(t, fakeRunner(nil))
+
+	_, err := svc.FirewallRules(context.Background())
+	assert.Error(t, err)
+
+	info, err := svc.Info(context.Background(), Options{Firewall: true, DNS: true})
+	require.NoError(t, err, "a failing section is reported as a warning")
+	require.Len(t, info.Warnings, 1)
+	assert.Contains(t, info.Warnings[0], "firewall")
+	assert.NotNil(t, info.DNSConfig)
+
+	empty := newTestService(t, func(ctx context.Context, command string, args ...string) (string, error) {
+		if command == "nft" {
+			return `{"nftables": [{"metainfo": {"version": "1.0.6"}}]}`, nil
+		}
+		return "", errors.New("not found")
+	})
+	rules, err := empty.FirewallRules(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+// installCommands puts scripts printing the given output first on PATH
+func installCommands(t *testing.T, outputs map[string]string) {
+	dir := t.TempDir()
+	for name, output := range outputs {
+		data := filepath.Join(dir, name+".out")
+		require.NoError(t, os.WriteFile(data, []byte(output), 0644))
+		script := fmt.Sprintf("#!/bin/sh\nexec cat %q\n", data)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestFirewallRules_ThroughDefaultPolicy(t *testing.T) {
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	sec := security.NewSecureCommandExecutor(security.DefaultSecurityConfig())
+	svc := newTestService(t, registry.NewCommandExecutor(sec, logger).ExecuteSystemCommand)
+
+	installCommands(t, map[string]string{"nft": nftRuleset})
+	rules, err := svc.FirewallRules(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, rules, 3)
+
+	installCommands(t, map[string]string{
+		"nft":           `{"nftables": [{"metainfo": {"version": "1.0.6"}}]}`,
+		"iptables-save": iptablesSave,
+	})
+	rules, err = svc.FirewallRules(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, rules, 4)
+	assert.Empty(t, sec.PolicyDenials())
+}
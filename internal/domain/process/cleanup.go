@@ -0,0 +1,288 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// Port cleanup outcomes
+const (
+	OutcomeReleased   = "released"
+	OutcomeStillBound = "still_bound"
+	OutcomeFailed     = "failed"
+)
+
+const (
+	// DefaultGracePeriod is how long processes get to exit after SIGTERM
+	DefaultGracePeriod = 5 * time.Second
+	// killWait is how long to wait for the kernel to reap a process after SIGKILL
+	killWait = 2 * time.Second
+)
+
+// PortRange is an inclusive range of ports
+type PortRange struct {
+	From int
+	To   int
+}
+
+// Contains reports whether port lies within the range
+func (r PortRange) Contains(port int) bool {
+	return port >= r.From && port <= r.To
+}
+
+// ParsePortRanges parses a list such as "8000-8100,9090"
+func ParsePortRanges(spec string) ([]PortRange, error) {
+	var ranges []PortRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			toStr = fromStr
+		}
+		from, err1 := strconv.Atoi(strings.TrimSpace(fromStr))
+		to, err2 := strconv.Atoi(strings.TrimSpace(toStr))
+		if err1 != nil || err2 != nil || from < 1 || to > 65535 || from > to {
+			return nil, fmt.Errorf("invalid port range %q", part)
+		}
+		ranges = append(ranges, PortRange{From: from, To: to})
+	}
+	return ranges, nil
+}
+
+// CleanupOptions selects which listening ports clean_ports acts on
+type CleanupOptions struct {
+	// Ports restricts cleanup to these port ranges
+	Ports []PortRange
+	// ProcessNames restricts cleanup to processes matching these glob patterns
+	ProcessNames []string
+	// Execute signals processes; otherwise only the plan is returned
+	Execute bool
+	// GracePeriod is the wait between SIGTERM and SIGKILL (DefaultGracePeriod when zero)
+	GracePeriod time.Duration
+}
+
+// CleanPorts plans, and when requested executes, the termination of processes
+// listening on the selected ports. At least one port range or process name
+// filter is required so a call can never sweep every listening port.
+func (s *ServiceImpl) CleanPorts(ctx context.Context, opts CleanupOptions) (*resources.PortCleanupReport, error) {
+	if len(opts.Ports) == 0 && len(opts.ProcessNames) == 0 {
+		return nil, fmt.Errorf("clean_ports requires a port range or process name filter")
+	}
+	if opts.GracePeriod <= 0 {
+		opts.GracePeriod = DefaultGracePeriod
+	}
+
+	procs, err := s.collector.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read processes: %w", err)
+	}
+	tree := newProcessTree(procs)
+	own := s.protector.ownTree(tree)
+
+	listeners, err := s.listeners()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &resources.PortCleanupReport{
+		DryRun:  !opts.Execute,
+		Targets: make([]resources.PortCleanupTarget, 0),
+	}
+	for _, conn := range listeners {
+		if len(opts.Ports) > 0 && !inRanges(opts.Ports, conn.LocalPort) {
+			continue
+		}
+
+		target := resources.PortCleanupTarget{
+			Port:     conn.LocalPort,
+			Protocol: conn.Protocol,
+			Address:  conn.LocalAddress,
+			PID:      conn.PID,
+		}
+		proc, known := tree.byPID[conn.PID]
+		if known {
+			target.Command = proc.CommandLine
+			if target.Command == "" {
+				target.Command = proc.Name
+			}
+			target.User = proc.User
+		}
+
+		if len(opts.ProcessNames) > 0 && (!known || !matchesAny(proc, opts.ProcessNames)) {
+			continue
+		}
+		if !known {
+			target.Reason = "owning process not visible (insufficient privileges?)"
+			report.Skipped = append(report.Skipped, target)
+			continue
+		}
+		if reason := s.protector.check(proc, own); reason != "" {
+			target.Reason = reason
+			report.Skipped = append(report.Skipped, target)
+			continue
+		}
+		report.Targets = append(report.Targets, target)
+	}
+
+	pids := targetPIDs(report.Targets)
+	if !opts.Execute {
+		report.Summary = fmt.Sprintf("dry run: %d process(es) on %d socket(s) would be terminated, %d skipped; set execute to proceed",
+			len(pids), len(report.Targets), len(report.Skipped))
+		return report, nil
+	}
+
+	s.terminate(ctx, pids, tree, opts.GracePeriod, report)
+	s.verifyReleased(report)
+
+	released := 0
+	for _, target := range report.Targets {
+		if target.Outcome == OutcomeReleased {
+			released++
+		}
+	}
+	report.Summary = fmt.Sprintf("%d/%d socket(s) released, %d skipped", released, len(report.Targets), len(report.Skipped))
+	logging.FromContext(ctx, s.logger).Info("Port cleanup executed", map[string]any{
+		"pids":     pids,
+		"released": released,
+		"targets":  len(report.Targets),
+		"skipped":  len(report.Skipped),
+	})
+	return report, nil
+}
+
+// listeners returns listening TCP and bound UDP sockets
+func (s *ServiceImpl) listeners() ([]resources.NetworkConnection, error) {
+	conns, err := s.collector.Connections(procfs.ProtocolTCP, procfs.ProtocolTCP6, procfs.ProtocolUDP, procfs.ProtocolUDP6)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sockets: %w", err)
+	}
+	listening := make([]resources.NetworkConnection, 0, len(conns))
+	for _, conn := range conns {
+		if procfs.IsListening(conn) {
+			listening = append(listening, conn)
+		}
+	}
+	return listening, nil
+}
+
+// terminate sends SIGTERM, waits for the grace period and escalates to SIGKILL
+func (s *ServiceImpl) terminate(ctx context.Context, pids []int, tree *processTree, grace time.Duration, report *resources.PortCleanupReport) {
+	signalled := make(map[int]string)
+	failed := make(map[int]error)
+
+	for _, pid := range pids {
+		if err := s.sendSignal(ctx, pid, tree.byPID[pid], syscall.SIGTERM); err != nil {
+			failed[pid] = err
+			continue
+		}
+		signalled[pid] = "SIGTERM"
+	}
+
+	survivors := s.waitForExit(ctx, keys(signalled), tree, grace)
+	for _, pid := range survivors {
+		if err := s.sendSignal(ctx, pid, tree.byPID[pid], syscall.SIGKILL); err != nil {
+			failed[pid] = err
+			continue
+		}
+		signalled[pid] = "SIGKILL"
+	}
+	if len(survivors) > 0 {
+		s.waitForExit(ctx, survivors, tree, killWait)
+	}
+
+	for i := range report.Targets {
+		target := &report.Targets[i]
+		target.Signal = signalled[target.PID]
+		if err := failed[target.PID]; err != nil {
+			target.Outcome = OutcomeFailed
+			target.Error = err.Error()
+		}
+	}
+}
+
+// verifyReleased re-reads the socket tables and records whether each target port is free
+func (s *ServiceImpl) verifyReleased(report *resources.PortCleanupReport) {
+	listeners, err := s.listeners()
+	if err != nil {
+		for i := range report.Targets {
+			if report.Targets[i].Outcome == "" {
+				report.Targets[i].Outcome = OutcomeFailed
+				report.Targets[i].Error = err.Error()
+			}
+		}
+		return
+	}
+
+	bound := make(map[string]int)
+	for _, conn := range listeners {
+		bound[conn.Protocol+"/"+strconv.Itoa(conn.LocalPort)] = conn.PID
+	}
+	for i := range report.Targets {
+		target := &report.Targets[i]
+		if target.Outcome == OutcomeFailed {
+			continue
+		}
+		owner, stillBound := bound[target.Protocol+"/"+strconv.Itoa(target.Port)]
+		switch {
+		case !stillBound:
+			target.Outcome = OutcomeReleased
+		case owner == target.PID:
+			target.Outcome = OutcomeStillBound
+			target.Error = "process is still listening"
+		default:
+			target.Outcome = OutcomeStillBound
+			target.Error = fmt.Sprintf("port is now held by PID %d", owner)
+		}
+	}
+}
+
+// inRanges reports whether port is in any of the ranges
+func inRanges(ranges []PortRange, port int) bool {
+	for _, r := range ranges {
+		if r.Contains(port) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether a process matches any of the name patterns
+func matchesAny(proc resources.Process, patterns []string) bool {
+	for _, pattern := range patterns {
+		if MatchName(proc, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetPIDs returns the distinct PIDs of the targets in ascending order
+func targetPIDs(targets []resources.PortCleanupTarget) []int {
+	seen := make(map[int]bool)
+	for _, target := range targets {
+		seen[target.PID] = true
+	}
+	return keys(seen)
+}
+
+// keys returns the sorted keys of a PID-indexed map
+func keys[V any](m map[int]V) []int {
+	out := make([]int, 0, len(m))
+	for pid := range m {
+		out = append(out, pid)
+	}
+	sort.Ints(out)
+	return out
+}
@@ -0,0 +1,118 @@
+// Package process implements process-control operations on top of the /proc
+// collector, enforcing the rules for which processes may be signalled.
+package process
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// Protector decides whether a process may be signalled
+type Protector struct {
+	// patterns are glob patterns matched against the process name and executable
+	patterns []string
+	// self is the PID of this server
+	self int
+}
+
+// NewProtector creates a protector for the given name patterns and own PID
+func NewProtector(patterns []string, self int) *Protector {
+	return &Protector{patterns: patterns, self: self}
+}
+
+// processTree indexes processes by PID and by parent for walking the tree
+type processTree struct {
+	byPID    map[int]resources.Process
+	children map[int][]int
+}
+
+// newProcessTree indexes a process list
+func newProcessTree(procs []resources.Process) *processTree {
+	tree := &processTree{
+		byPID:    make(map[int]resources.Process, len(procs)),
+		children: make(map[int][]int),
+	}
+	for _, proc := range procs {
+		tree.byPID[proc.PID] = proc
+		tree.children[proc.PPID] = append(tree.children[proc.PPID], proc.PID)
+	}
+	return tree
+}
+
+// descendants returns every PID below pid, deepest first so children can be
+// signalled before their parents
+func (t *processTree) descendants(pid int) []int {
+	var out []int
+	var walk func(int)
+	walk = func(parent int) {
+		for _, child := range t.children[parent] {
+			if child == parent {
+				continue
+			}
+			walk(child)
+			out = append(out, child)
+		}
+	}
+	walk(pid)
+	return out
+}
+
+// ancestors returns the chain of parents above pid
+func (t *processTree) ancestors(pid int) []int {
+	var out []int
+	seen := map[int]bool{pid: true}
+	for {
+		proc, ok := t.byPID[pid]
+		if !ok || proc.PPID <= 0 || seen[proc.PPID] {
+			return out
+		}
+		pid = proc.PPID
+		seen[pid] = true
+		out = append(out, pid)
+	}
+}
+
+// ownTree returns the PIDs of this server, its ancestors and its descendants
+func (p *Protector) ownTree(tree *processTree) map[int]bool {
+	own := map[int]bool{p.self: true}
+	for _, pid := range tree.ancestors(p.self) {
+		own[pid] = true
+	}
+	for _, pid := range tree.descendants(p.self) {
+		own[pid] = true
+	}
+	return own
+}
+
+// check returns why a process must not be signalled, or "" when it may be
+func (p *Protector) check(proc resources.Process, own map[int]bool) string {
+	switch {
+	case proc.PID <= 1:
+		return "PID 1 is never signalled"
+	case own[proc.PID]:
+		return "process belongs to the mini-mcp process tree"
+	}
+
+	for _, pattern := range p.patterns {
+		if MatchName(proc, pattern) {
+			return fmt.Sprintf("process %q matches protected pattern %q", proc.Name, pattern)
+		}
+	}
+	return ""
+}
+
+// MatchName reports whether a process name or executable matches a glob pattern
+func MatchName(proc resources.Process, pattern string) bool {
+	if matched, _ := path.Match(pattern, proc.Name); matched {
+		return true
+	}
+	if fields := strings.Fields(proc.CommandLine); len(fields) > 0 {
+		matched, _ := path.Match(pattern, filepath.Base(fields[0]))
+		return matched
+	}
+	return false
+}
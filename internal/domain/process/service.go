@@ -0,0 +1,106 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// pollInterval is how often exits are checked while waiting
+const pollInterval = 100 * time.Millisecond
+
+// Service defines the interface for process-control domain services
+type Service interface {
+	CleanPorts(ctx context.Context, opts CleanupOptions) (*resources.PortCleanupReport, error)
+	SignalProcess(ctx context.Context, opts SignalOptions) (*resources.SignalResult, error)
+}
+
+// ServiceImpl implements the process-control domain service
+type ServiceImpl struct {
+	collector *procfs.Collector
+	protector *Protector
+	logger    logging.Logger
+
+	// kill delivers a signal; replaced in tests
+	kill         func(pid int, sig syscall.Signal) error
+	pollInterval time.Duration
+}
+
+// NewService creates a process-control service that refuses to signal PID 1,
+// its own process tree and processes matching the protected name patterns
+func NewService(collector *procfs.Collector, protected []string, logger logging.Logger) Service {
+	return &ServiceImpl{
+		collector:    collector,
+		protector:    NewProtector(protected, os.Getpid()),
+		logger:       logger,
+		kill:         syscall.Kill,
+		pollInterval: pollInterval,
+	}
+}
+
+// sendSignal signals pid after confirming it is still the process that was
+// inspected, so a recycled PID is never hit
+func (s *ServiceImpl) sendSignal(ctx context.Context, pid int, expected resources.Process, sig syscall.Signal) error {
+	if !s.sameProcess(pid, expected) {
+		return fmt.Errorf("process %d exited or its PID was reused before %s", pid, signalName(sig))
+	}
+	if err := s.kill(pid, sig); err != nil && err != syscall.ESRCH {
+		logging.FromContext(ctx, s.logger).Error("Failed to signal process", err, map[string]any{"pid": pid, "signal": signalName(sig)})
+		return err
+	}
+	logging.FromContext(ctx, s.logger).Info("Signalled process", map[string]any{"pid": pid, "name": expected.Name, "signal": signalName(sig)})
+	return nil
+}
+
+// sameProcess reports whether pid still refers to the expected process
+func (s *ServiceImpl) sameProcess(pid int, expected resources.Process) bool {
+	current, err := s.collector.Process(pid)
+	if err != nil {
+		return false
+	}
+	if current.Status == "zombie" {
+		return false
+	}
+	return current.StartTime.Equal(expected.StartTime)
+}
+
+// waitForExit polls until the processes are gone or the timeout passes and
+// returns those still alive
+func (s *ServiceImpl) waitForExit(ctx context.Context, pids []int, tree *processTree, timeout time.Duration) []int {
+	deadline := time.Now().Add(timeout)
+	alive := pids
+	for {
+		remaining := alive[:0:0]
+		for _, pid := range alive {
+			if s.sameProcess(pid, tree.byPID[pid]) {
+				remaining = append(remaining, pid)
+			}
+		}
+		alive = remaining
+		if len(alive) == 0 || time.Now().After(deadline) {
+			return alive
+		}
+
+		select {
+		case <-ctx.Done():
+			return alive
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// signalName returns the conventional name of a signal
+func signalName(sig syscall.Signal) string {
+	for name, value := range signalNames {
+		if value == sig {
+			return "SIG" + name
+		}
+	}
+	return fmt.Sprintf("signal %d", int(sig))
+}
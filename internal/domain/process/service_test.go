@@ -0,0 +1,226 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProc struct {
+	pid, ppid int
+	name      string
+	// ignoreTerm makes the process survive SIGTERM
+	ignoreTerm bool
+	// reuseOnTerm replaces the process with a new one under the same PID on SIGTERM
+	reuseOnTerm bool
+	start       int
+}
+
+type fakeSocket struct {
+	port  int
+	inode uint64
+	pid   int
+}
+
+// fakeSystem renders a proc tree and simulates process exits on signals
+type fakeSystem struct {
+	t       *testing.T
+	root    string
+	mu      sync.Mutex
+	procs   map[int]*fakeProc
+	sockets []fakeSocket
+	signals []string
+}
+
+func newFakeSystem(t *testing.T, procs []*fakeProc, sockets []fakeSocket) *fakeSystem {
+	f := &fakeSystem{t: t, root: t.TempDir(), procs: make(map[int]*fakeProc), sockets: sockets}
+	for _, p := range procs {
+		f.procs[p.pid] = p
+	}
+	f.render()
+	return f
+}
+
+// procAddr encodes an IPv4 address the way the running kernel would in /proc/net
+func procAddr(ip string, port int) string {
+	v4 := net.ParseIP(ip).To4()
+	raw := make([]byte, 4)
+	binary.NativeEndian.PutUint32(raw, binary.BigEndian.Uint32(v4))
+	return fmt.Sprintf("%s:%04X", hex.EncodeToString(raw), port)
+}
+
+func (f *fakeSystem) render() {
+	require.NoError(f.t, os.RemoveAll(f.root))
+	require.NoError(f.t, os.MkdirAll(filepath.Join(f.root, "net"), 0755))
+	require.NoError(f.t, os.WriteFile(filepath.Join(f.root, "stat"), []byte("cpu  1 0 1 100\nbtime 1700000000\n"), 0644))
+
+	for _, p := range f.procs {
+		dir := filepath.Join(f.root, fmt.Sprint(p.pid))
+		require.NoError(f.t, os.MkdirAll(filepath.Join(dir, "fd"), 0755))
+		stat := fmt.Sprintf("%d (%s) S %d 0 0 0 -1 0 0 0 0 0 1 1 0 0 20 0 1 0 %d 0 0\n", p.pid, p.name, p.ppid, 1000+p.pid+p.start)
+		require.NoError(f.t, os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+		require.NoError(f.t, os.WriteFile(filepath.Join(dir, "cmdline"), []byte("/usr/bin/"+p.name+"\x00"), 0644))
+	}
+
+	var tcp strings.Builder
+	tcp.WriteString("  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n")
+	for i, s := range f.sockets {
+		if _, alive := f.procs[s.pid]; !alive && s.pid != 0 {
+			continue
+		}
+		fmt.Fprintf(&tcp, "%4d: %s %s 0A 00000000:00000000 00:00000000 00000000     0        0 %d 1\n",
+			i, procAddr("0.0.0.0", s.port), procAddr("0.0.0.0", 0), s.inode)
+		if s.pid != 0 {
+			link := filepath.Join(f.root, fmt.Sprint(s.pid), "fd", fmt.Sprint(10+i))
+			require.NoError(f.t, os.Symlink(fmt.Sprintf("socket:[%d]", s.inode), link))
+		}
+	}
+	require.NoError(f.t, os.WriteFile(filepath.Join(f.root, "net", "tcp"), []byte(tcp.String()), 0644))
+}
+
+func (f *fakeSystem) kill(pid int, sig syscall.Signal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.procs[pid]
+	if !ok {
+		return syscall.ESRCH
+	}
+	f.signals = append(f.signals, fmt.Sprintf("%d:%s", pid, signalName(sig)))
+	switch {
+	case p.reuseOnTerm:
+		f.procs[pid] = &fakeProc{pid: pid, ppid: 1, name: "newcomer", start: 5000}
+		f.render()
+	case sig == syscall.SIGKILL || (sig == syscall.SIGTERM && !p.ignoreTerm):
+		delete(f.procs, pid)
+		f.render()
+	}
+	return nil
+}
+
+func newTestService(f *fakeSystem, self int) *ServiceImpl {
+	return &ServiceImpl{
+		collector:    procfs.NewCollector(f.root),
+		protector:    NewProtector([]string{"sshd", "systemd-*"}, self),
+		logger:       logging.NewLogger(io.Discard, logging.LogLevelError),
+		kill:         f.kill,
+		pollInterval: time.Millisecond,
+	}
+}
+
+func standardSystem(t *testing.T) *fakeSystem {
+	return newFakeSystem(t,
+		[]*fakeProc{
+			{pid: 1, ppid: 0, name: "init"},
+			{pid: 10, ppid: 1, name: "sshd"},
+			{pid: 20, ppid: 1, name: "systemd-resolved"},
+			{pid: 30, ppid: 1, name: "node"},
+			{pid: 40, ppid: 1, name: "python3", ignoreTerm: true},
+			{pid: 50, ppid: 1, name: "bash"},
+			{pid: 51, ppid: 50, name: "mini-mcp"},
+			{pid: 52, ppid: 51, name: "helper"},
+		},
+		[]fakeSocket{
+			{port: 22, inode: 100, pid: 10},
+			{port: 53, inode: 101, pid: 20},
+			{port: 3000, inode: 102, pid: 30},
+			{port: 8000, inode: 103, pid: 40},
+			{port: 8001, inode: 104, pid: 52},
+			{port: 8002, inode: 105, pid: 1},
+			{port: 8003, inode: 106, pid: 0},
+		})
+}
+
+func TestParsePortRanges(t *testing.T) {
+	ranges, err := ParsePortRanges("8000-8100, 9090")
+	require.NoError(t, err)
+	assert.Equal(t, []PortRange{{8000, 8100}, {9090, 9090}}, ranges)
+
+	for _, bad := range []string{"0", "70000", "9000-8000", "abc", "1-x"} {
+		_, err := ParsePortRanges(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestCleanPorts_RequiresFilter(t *testing.T) {
+	svc := newTestService(standardSystem(t), 51)
+
+	_, err := svc.CleanPorts(context.Background(), CleanupOptions{Execute: true})
+	assert.Error(t, err)
+}
+
+func TestCleanPorts_DryRunPlan(t *testing.T) {
+	f := standardSystem(t)
+	svc := newTestService(f, 51)
+
+	report, err := svc.CleanPorts(context.Background(), CleanupOptions{Ports: []PortRange{{1, 65535}}})
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Empty(t, f.signals)
+
+	var targeted []int
+	for _, target := range report.Targets {
+		targeted = append(targeted, target.Port)
+		assert.Empty(t, target.Outcome)
+	}
+	assert.ElementsMatch(t, []int{3000, 8000}, targeted)
+	assert.Equal(t, "/usr/bin/node", report.Targets[0].Command)
+
+	skipped := make(map[int]string)
+	for _, target := range report.Skipped {
+		skipped[target.Port] = target.Reason
+	}
+	assert.Contains(t, skipped[22], "protected pattern")
+	assert.Contains(t, skipped[53], "systemd-*")
+	assert.Contains(t, skipped[8001], "process tree")
+	assert.Contains(t, skipped[8002], "PID 1")
+	assert.Contains(t, skipped[8003], "not visible")
+	assert.Contains(t, report.Summary, "dry run")
+}
+
+func TestCleanPorts_ExecuteAndVerify(t *testing.T) {
+	f := standardSystem(t)
+	svc := newTestService(f, 51)
+
+	report, err := svc.CleanPorts(context.Background(), CleanupOptions{
+		Ports:       []PortRange{{3000, 3000}, {8000, 8000}},
+		Execute:     true,
+		GracePeriod: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Targets, 2)
+
+	assert.Equal(t, []string{"30:SIGTERM", "40:SIGTERM", "40:SIGKILL"}, f.signals)
+	for _, target := range report.Targets {
+		assert.Equal(t, OutcomeReleased, target.Outcome, target.Port)
+	}
+	assert.Equal(t, "SIGKILL", report.Targets[1].Signal)
+	assert.Contains(t, report.Summary, "2/2")
+}
+
+func TestCleanPorts_ProcessNameFilter(t *testing.T) {
+	f := standardSystem(t)
+	svc := newTestService(f, 51)
+
+	report, err := svc.CleanPorts(context.Background(), CleanupOptions{ProcessNames: []string{"pyth*"}})
+	require.NoError(t, err)
+	require.Len(t, report.Targets, 1)
+	assert.Equal(t, 40, report.Targets[0].PID)
+	assert.Empty(t, report.Skipped)
+}
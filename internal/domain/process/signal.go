@@ -0,0 +1,190 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// Signal outcomes
+const (
+	// OutcomeExited means the process is gone
+	OutcomeExited = "exited"
+	// OutcomeReused means the process exited and its PID now belongs to another process
+	OutcomeReused = "reused"
+	// OutcomeRunning means the process survived a terminating signal
+	OutcomeRunning = "running"
+	// OutcomeDelivered means a non-terminating signal was sent
+	OutcomeDelivered = "delivered"
+	// OutcomeSkipped means the process is protected and was not signalled
+	OutcomeSkipped = "skipped"
+)
+
+// signalNames maps the supported signal names to their numbers
+var signalNames = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"TERM": syscall.SIGTERM,
+	"CONT": syscall.SIGCONT,
+	"STOP": syscall.SIGSTOP,
+	"TSTP": syscall.SIGTSTP,
+}
+
+// terminatingSignals are waited on and escalated to SIGKILL
+var terminatingSignals = map[syscall.Signal]bool{
+	syscall.SIGINT:  true,
+	syscall.SIGQUIT: true,
+	syscall.SIGTERM: true,
+	syscall.SIGKILL: true,
+}
+
+// ParseSignal parses "TERM", "SIGTERM", "term" or "15"; empty means SIGTERM
+func ParseSignal(value string) (syscall.Signal, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return syscall.SIGTERM, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		for _, sig := range signalNames {
+			if int(sig) == n {
+				return sig, nil
+			}
+		}
+		return 0, fmt.Errorf("unsupported signal number: %d", n)
+	}
+	if sig, ok := signalNames[strings.TrimPrefix(value, "SIG")]; ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("unsupported signal: %s", value)
+}
+
+// SignalOptions controls SignalProcess
+type SignalOptions struct {
+	// PID is the process to signal
+	PID int
+	// Signal is the signal to send
+	Signal syscall.Signal
+	// Tree also signals every descendant, children first
+	Tree bool
+	// GracePeriod is how long to wait for terminating signals (DefaultGracePeriod when zero)
+	GracePeriod time.Duration
+	// NoEscalate disables SIGKILL for processes that outlive the grace period
+	NoEscalate bool
+}
+
+// SignalProcess sends a signal to a process, optionally with its descendants.
+// Terminating signals are followed by a wait, escalation to SIGKILL and a
+// check that each PID is gone or now belongs to a different process.
+func (s *ServiceImpl) SignalProcess(ctx context.Context, opts SignalOptions) (*resources.SignalResult, error) {
+	if opts.Signal == 0 {
+		opts.Signal = syscall.SIGTERM
+	}
+	if opts.GracePeriod <= 0 {
+		opts.GracePeriod = DefaultGracePeriod
+	}
+
+	procs, err := s.collector.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read processes: %w", err)
+	}
+	tree := newProcessTree(procs)
+	own := s.protector.ownTree(tree)
+
+	root, ok := tree.byPID[opts.PID]
+	if !ok {
+		return nil, fmt.Errorf("process %d not found", opts.PID)
+	}
+	if reason := s.protector.check(root, own); reason != "" {
+		logging.FromContext(ctx, s.logger).Warning("Refused to signal protected process", map[string]any{"pid": opts.PID, "reason": reason})
+		return nil, fmt.Errorf("refusing to signal process %d: %s", opts.PID, reason)
+	}
+
+	pids := []int{opts.PID}
+	if opts.Tree {
+		pids = append(tree.descendants(opts.PID), opts.PID)
+	}
+
+	result := &resources.SignalResult{
+		Signal:    signalName(opts.Signal),
+		Tree:      opts.Tree,
+		Processes: make([]resources.SignalOutcome, 0, len(pids)),
+	}
+	index := make(map[int]int, len(pids))
+	var signalled []int
+
+	for _, pid := range pids {
+		proc := tree.byPID[pid]
+		outcome := resources.SignalOutcome{PID: pid, Name: proc.Name, Command: proc.CommandLine, User: proc.User}
+
+		if reason := s.protector.check(proc, own); reason != "" {
+			outcome.Outcome = OutcomeSkipped
+			outcome.Reason = reason
+		} else if err := s.sendSignal(ctx, pid, proc, opts.Signal); err != nil {
+			outcome.Outcome = OutcomeFailed
+			outcome.Reason = err.Error()
+		} else {
+			outcome.Signal = signalName(opts.Signal)
+			outcome.Outcome = OutcomeDelivered
+			signalled = append(signalled, pid)
+		}
+
+		index[pid] = len(result.Processes)
+		result.Processes = append(result.Processes, outcome)
+	}
+
+	if terminatingSignals[opts.Signal] && len(signalled) > 0 {
+		survivors := s.waitForExit(ctx, signalled, tree, opts.GracePeriod)
+		if !opts.NoEscalate && opts.Signal != syscall.SIGKILL && len(survivors) > 0 {
+			for _, pid := range survivors {
+				outcome := &result.Processes[index[pid]]
+				if err := s.sendSignal(ctx, pid, tree.byPID[pid], syscall.SIGKILL); err != nil {
+					outcome.Reason = err.Error()
+					continue
+				}
+				outcome.Signal = signalName(syscall.SIGKILL)
+				outcome.Escalated = true
+			}
+			s.waitForExit(ctx, survivors, tree, killWait)
+		}
+
+		for _, pid := range signalled {
+			result.Processes[index[pid]].Outcome = s.verifyExit(pid, tree.byPID[pid])
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, outcome := range result.Processes {
+		counts[outcome.Outcome]++
+	}
+	parts := make([]string, 0, len(counts))
+	for _, name := range []string{OutcomeExited, OutcomeReused, OutcomeRunning, OutcomeDelivered, OutcomeSkipped, OutcomeFailed} {
+		if counts[name] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[name], name))
+		}
+	}
+	result.Summary = fmt.Sprintf("%s sent to %d process(es): %s", result.Signal, len(signalled), strings.Join(parts, ", "))
+	return result, nil
+}
+
+// verifyExit classifies a signalled process after waiting
+func (s *ServiceImpl) verifyExit(pid int, expected resources.Process) string {
+	current, err := s.collector.Process(pid)
+	switch {
+	case err != nil || current.Status == "zombie":
+		return OutcomeExited
+	case !current.StartTime.Equal(expected.StartTime):
+		return OutcomeReused
+	default:
+		return OutcomeRunning
+	}
+}
@@ -0,0 +1,134 @@
+package process
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func treeSystem(t *testing.T) *fakeSystem {
+	return newFakeSystem(t,
+		[]*fakeProc{
+			{pid: 1, ppid: 0, name: "init"},
+			{pid: 100, ppid: 1, name: "supervisor"},
+			{pid: 101, ppid: 100, name: "worker"},
+			{pid: 102, ppid: 100, name: "stubborn", ignoreTerm: true},
+			{pid: 103, ppid: 101, name: "sshd"},
+			{pid: 200, ppid: 1, name: "recycled", reuseOnTerm: true},
+			{pid: 300, ppid: 1, name: "reloadable"},
+			{pid: 900, ppid: 1, name: "mini-mcp"},
+		}, nil)
+}
+
+func outcomes(result []string) map[string]bool {
+	m := make(map[string]bool)
+	for _, r := range result {
+		m[r] = true
+	}
+	return m
+}
+
+func TestParseSignal(t *testing.T) {
+	for input, want := range map[string]syscall.Signal{
+		"":        syscall.SIGTERM,
+		"term":    syscall.SIGTERM,
+		"SIGKILL": syscall.SIGKILL,
+		"9":       syscall.SIGKILL,
+		"hup":     syscall.SIGHUP,
+	} {
+		got, err := ParseSignal(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, bad := range []string{"SIGFOO", "999"} {
+		_, err := ParseSignal(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSignalProcess_TreeWithEscalation(t *testing.T) {
+	f := treeSystem(t)
+	svc := newTestService(f, 900)
+
+	result, err := svc.SignalProcess(context.Background(), SignalOptions{
+		PID:         100,
+		Signal:      syscall.SIGTERM,
+		Tree:        true,
+		GracePeriod: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	// Children are signalled before their parents; the protected grandchild is skipped
+	assert.Equal(t, []string{"101:SIGTERM", "102:SIGTERM", "100:SIGTERM", "102:SIGKILL"}, f.signals)
+	require.Len(t, result.Processes, 4)
+
+	byPID := make(map[int]string)
+	for _, p := range result.Processes {
+		byPID[p.PID] = p.Outcome
+	}
+	assert.Equal(t, OutcomeSkipped, byPID[103])
+	assert.Equal(t, OutcomeExited, byPID[101])
+	assert.Equal(t, OutcomeExited, byPID[102])
+	assert.Equal(t, OutcomeExited, byPID[100])
+
+	for _, p := range result.Processes {
+		if p.PID == 102 {
+			assert.True(t, p.Escalated)
+			assert.Equal(t, "SIGKILL", p.Signal)
+		}
+	}
+	assert.Contains(t, result.Summary, "3 exited")
+}
+
+func TestSignalProcess_NoEscalate(t *testing.T) {
+	f := treeSystem(t)
+	svc := newTestService(f, 900)
+
+	result, err := svc.SignalProcess(context.Background(), SignalOptions{
+		PID:         102,
+		GracePeriod: 10 * time.Millisecond,
+		NoEscalate:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeRunning, result.Processes[0].Outcome)
+	assert.False(t, result.Processes[0].Escalated)
+}
+
+func TestSignalProcess_DetectsReuse(t *testing.T) {
+	f := treeSystem(t)
+	svc := newTestService(f, 900)
+
+	result, err := svc.SignalProcess(context.Background(), SignalOptions{PID: 200, GracePeriod: 10 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeReused, result.Processes[0].Outcome)
+	assert.Equal(t, []string{"200:SIGTERM"}, f.signals)
+}
+
+func TestSignalProcess_NonTerminatingSignal(t *testing.T) {
+	f := treeSystem(t)
+	svc := newTestService(f, 900)
+
+	result, err := svc.SignalProcess(context.Background(), SignalOptions{PID: 300, Signal: syscall.SIGHUP})
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeDelivered, result.Processes[0].Outcome)
+	assert.Equal(t, "SIGHUP", result.Signal)
+}
+
+func TestSignalProcess_Protected(t *testing.T) {
+	f := treeSystem(t)
+	svc := newTestService(f, 900)
+	ctx := context.Background()
+
+	for _, pid := range []int{1, 103, 900} {
+		_, err := svc.SignalProcess(ctx, SignalOptions{PID: pid})
+		assert.Error(t, err, pid)
+	}
+	_, err := svc.SignalProcess(ctx, SignalOptions{PID: 4242})
+	assert.Error(t, err)
+	assert.Empty(t, f.signals)
+}
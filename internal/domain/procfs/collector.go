@@ -0,0 +1,120 @@
+// Package procfs collects process, socket, interface, memory and filesystem
+// information by parsing the Linux /proc filesystem directly, without
+// shelling out to ps, netstat, ss, lsof, free or df.
+package procfs
+
+import (
+	"bufio"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// DefaultRoot is the mount point of the proc filesystem
+const DefaultRoot = "/proc"
+
+// clockTicks is USER_HZ, the unit of CPU times and start times in /proc;
+// it is 100 on every mainstream Linux architecture
+const clockTicks = 100
+
+// Collector reads process and network state from a proc filesystem tree
+type Collector struct {
+	root string
+
+	// lookupUser resolves a numeric UID to a user name
+	lookupUser func(uid string) (string, error)
+	// statfs reports filesystem usage for a mount point
+	statfs func(path string, buf *syscall.Statfs_t) error
+
+	mu    sync.Mutex
+	users map[string]string
+}
+
+// NewCollector creates a collector rooted at root; an empty root means DefaultRoot.
+// Tests point root at a fixture tree.
+func NewCollector(root string) *Collector {
+	if root == "" {
+		root = DefaultRoot
+	}
+	return &Collector{
+		root: root,
+		lookupUser: func(uid string) (string, error) {
+			u, err := user.LookupId(uid)
+			if err != nil {
+				return "", err
+			}
+			return u.Username, nil
+		},
+		statfs: syscall.Statfs,
+		users:  make(map[string]string),
+	}
+}
+
+// path joins elements below the collector root
+func (c *Collector) path(elem ...string) string {
+	return filepath.Join(append([]string{c.root}, elem...)...)
+}
+
+// userName resolves a UID, caching results and falling back to the number
+func (c *Collector) userName(uid string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+	name, err := c.lookupUser(uid)
+	if err != nil || name == "" {
+		name = uid
+	}
+	c.users[uid] = name
+	return name
+}
+
+// pids lists the numeric entries of the proc root
+func (c *Collector) pids() ([]int, error) {
+	entries, err := os.ReadDir(c.root)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		if pid, err := strconv.Atoi(entry.Name()); err == nil && pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// readKeyValues parses "Key: value" files such as status and meminfo
+func readKeyValues(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values, scanner.Err()
+}
+
+// parseKilobytes parses values like "1234 kB" into bytes
+func parseKilobytes(value string) uint64 {
+	n, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n * 1024
+}
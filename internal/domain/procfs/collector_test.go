@@ -0,0 +1,196 @@
+package procfs
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// procAddr encodes an address the way the running kernel would in /proc/net
+func procAddr(ip string, port int) string {
+	parsed := net.ParseIP(ip)
+	if v4 := parsed.To4(); v4 != nil {
+		parsed = v4
+	}
+	raw := make([]byte, len(parsed))
+	for i := 0; i < len(parsed); i += 4 {
+		binary.NativeEndian.PutUint32(raw[i:], binary.BigEndian.Uint32(parsed[i:]))
+	}
+	return fmt.Sprintf("%s:%04X", hex.EncodeToString(raw), port)
+}
+
+// writeProcFixture builds a minimal proc tree with two processes and a few sockets
+func writeProcFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	socket := func(pid, fd int, inode uint64) {
+		dir := filepath.Join(root, fmt.Sprint(pid), "fd")
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.Symlink(fmt.Sprintf("socket:[%d]", inode), filepath.Join(dir, fmt.Sprint(fd))))
+	}
+
+	write("stat", "cpu  1 2 3 4\nbtime 1700000000\n")
+	write("uptime", "1000.00 4000.00\n")
+	write("meminfo", "MemTotal:       1000000 kB\nMemFree:         500000 kB\n")
+
+	// utime+stime = 5000 ticks = 50s over 500s of lifetime = 10%
+	write("1/stat", "1 (init) S 0 1 1 0 -1 4194560 0 0 0 0 3000 2000 0 0 20 0 1 0 50000 1000 250 18446744073709551615\n")
+	write("1/status", "Name:\tinit\nUid:\t0\t0\t0\t0\nVmRSS:\t    10000 kB\nThreads:\t1\n")
+	write("1/cmdline", "/sbin/init\x00splash\x00")
+
+	write("42/stat", "42 (my (odd) app) R 1 42 42 0 -1 0 0 0 0 0 10 10 0 0 20 0 4 0 90000 1000 250 0\n")
+	write("42/status", "Name:\tmy (odd) app\nUid:\t1000\t1000\t1000\t1000\nVmRSS:\t    2048 kB\n")
+	write("42/cmdline", "/usr/bin/app\x00--port\x008080\x00")
+	socket(42, 3, 1001)
+	socket(42, 4, 1003)
+	require.NoError(t, os.Symlink("/dev/null", filepath.Join(root, "42", "fd", "0")))
+
+	write("net/tcp", "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+		fmt.Sprintf("   0: %s %s 0A 00000000:00000000 00:00000000 00000000  1000        0 1001 1 0000000000000000 100 0 0 10 0\n", procAddr("127.0.0.1", 8080), procAddr("0.0.0.0", 0))+
+		fmt.Sprintf("   1: %s %s 01 00000000:00000000 00:00000000 00000000  1000        0 1002 1 0000000000000000 100 0 0 10 0\n", procAddr("10.0.0.5", 22), procAddr("10.0.0.9", 51514)))
+	write("net/tcp6", "  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+		fmt.Sprintf("   0: %s %s 0A 00000000:00000000 00:00000000 00000000     0        0 1003 1 0000000000000000 100 0 0 10 0\n", procAddr("::", 443), procAddr("::", 0)))
+	write("net/udp", "   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops\n"+
+		fmt.Sprintf("  100: %s %s 07 00000000:00000000 00:00000000 00000000   101        0 1004 2 0000000000000000 0\n", procAddr("127.0.0.53", 53), procAddr("0.0.0.0", 0)))
+	write("net/unix", "Num       RefCount Protocol Flags    Type St Inode Path\n"+
+		"0000000000000000: 00000002 00000000 00010000 0001 01 1005 /run/app.sock\n"+
+		"0000000000000000: 00000003 00000000 00000000 0001 03 1006\n")
+	write("net/dev", "Inter-|   Receive                                                |  Transmit\n"+
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n"+
+		"    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0\n"+
+		"  eth0: 5000000    4000    0    0    0     0          0         0   300000    2000    0    0    0     0       0          0\n")
+
+	return root
+}
+
+func newTestCollector(t *testing.T) *Collector {
+	t.Helper()
+	c := NewCollector(writeProcFixture(t))
+	c.lookupUser = func(uid string) (string, error) {
+		if uid == "0" {
+			return "root", nil
+		}
+		return "", fmt.Errorf("unknown user %s", uid)
+	}
+	return c
+}
+
+func TestProcesses(t *testing.T) {
+	c := newTestCollector(t)
+
+	procs, err := c.Processes()
+	require.NoError(t, err)
+	require.Len(t, procs, 2)
+
+	initProc := procs[0]
+	assert.Equal(t, 1, initProc.PID)
+	assert.Equal(t, "init", initProc.Name)
+	assert.Equal(t, "root", initProc.User)
+	assert.Equal(t, "/sbin/init splash", initProc.CommandLine)
+	assert.Equal(t, "sleeping", initProc.Status)
+	assert.Equal(t, uint64(10000*1024), initProc.MemoryUsage)
+	assert.InDelta(t, 1.0, initProc.MemoryPercent, 0.001)
+	assert.InDelta(t, 10.0, initProc.CPUPercent, 0.001)
+	assert.Equal(t, time.Unix(1700000500, 0), initProc.StartTime)
+
+	app := procs[1]
+	assert.Equal(t, "my (odd) app", app.Name)
+	assert.Equal(t, 1, app.PPID)
+	assert.Equal(t, "1000", app.User)
+	assert.Equal(t, "running", app.Status)
+	assert.Equal(t, 4, app.Threads)
+}
+
+func TestProcess_NotFound(t *testing.T) {
+	c := newTestCollector(t)
+
+	_, err := c.Process(999)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = c.Process(0)
+	assert.Error(t, err)
+}
+
+func TestConnections(t *testing.T) {
+	c := newTestCollector(t)
+
+	conns, err := c.Connections()
+	require.NoError(t, err)
+	require.Len(t, conns, 6)
+
+	byInode := make(map[uint64]resources.NetworkConnection)
+	for _, conn := range conns {
+		byInode[conn.Inode] = conn
+	}
+
+	listener := byInode[1001]
+	assert.Equal(t, ProtocolTCP, listener.Protocol)
+	assert.Equal(t, "127.0.0.1", listener.LocalAddress)
+	assert.Equal(t, 8080, listener.LocalPort)
+	assert.Equal(t, StateListen, listener.State)
+	assert.Equal(t, 42, listener.PID)
+	assert.Equal(t, "my (odd) app", listener.ProcessName)
+	assert.True(t, IsListening(listener))
+
+	established := byInode[1002]
+	assert.Equal(t, "10.0.0.9", established.RemoteAddress)
+	assert.Equal(t, 51514, established.RemotePort)
+	assert.Equal(t, "ESTABLISHED", established.State)
+	assert.Zero(t, established.PID)
+	assert.False(t, IsListening(established))
+
+	v6 := byInode[1003]
+	assert.Equal(t, "::", v6.LocalAddress)
+	assert.Equal(t, 443, v6.LocalPort)
+	assert.Equal(t, 42, v6.PID)
+
+	udp := byInode[1004]
+	assert.Equal(t, StateUnconnected, udp.State)
+	assert.True(t, IsListening(udp))
+
+	unix := byInode[1005]
+	assert.Equal(t, "/run/app.sock", unix.LocalAddress)
+	assert.Equal(t, StateListen, unix.State)
+	assert.Equal(t, "ESTABLISHED", byInode[1006].State)
+
+	tcpOnly, err := c.Connections(ProtocolTCP)
+	require.NoError(t, err)
+	assert.Len(t, tcpOnly, 2)
+
+	_, err = c.Connections("sctp")
+	assert.Error(t, err)
+}
+
+func TestInterfaces(t *testing.T) {
+	c := newTestCollector(t)
+
+	ifaces, err := c.Interfaces()
+	require.NoError(t, err)
+	require.Len(t, ifaces, 2)
+	assert.Equal(t, "eth0", ifaces[1].Name)
+	assert.Equal(t, uint64(5000000), ifaces[1].BytesRecv)
+	assert.Equal(t, uint64(300000), ifaces[1].BytesSent)
+	assert.Equal(t, uint64(2000), ifaces[1].PacketsSent)
+}
+
+func TestParseSocketAddress_Malformed(t *testing.T) {
+	for _, bad := range []string{"", "0100007F", "zz:0050", "0100007F:zz", "01:0050"} {
+		_, _, err := parseSocketAddress(bad)
+		assert.Error(t, err, bad)
+	}
+}
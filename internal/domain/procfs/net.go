@@ -0,0 +1,319 @@
+package procfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// Socket protocols, named after their /proc/net files
+const (
+	ProtocolTCP  = "tcp"
+	ProtocolTCP6 = "tcp6"
+	ProtocolUDP  = "udp"
+	ProtocolUDP6 = "udp6"
+	ProtocolUnix = "unix"
+)
+
+// Connection states reported for sockets that are waiting for peers
+const (
+	StateListen = "LISTEN"
+	// StateUnconnected is a bound UDP or unix socket without a peer
+	StateUnconnected = "UNCONN"
+)
+
+// AllProtocols lists every protocol Connections understands
+var AllProtocols = []string{ProtocolTCP, ProtocolTCP6, ProtocolUDP, ProtocolUDP6, ProtocolUnix}
+
+// tcpStates maps the hex st column of /proc/net/tcp to names used by ss and netstat
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": StateListen,
+	"0B": "CLOSING",
+	"0C": "NEW_SYN_RECV",
+}
+
+// unixStates maps the St column of /proc/net/unix
+var unixStates = map[string]string{
+	"01": StateUnconnected,
+	"02": "CONNECTING",
+	"03": "ESTABLISHED",
+	"04": "DISCONNECTING",
+}
+
+// unixAcceptCon is the __SO_ACCEPTCON flag marking a listening unix socket
+const unixAcceptCon = 0x10000
+
+// IsListening reports whether a connection is waiting for peers: a listening
+// TCP or unix stream socket, or a bound UDP socket
+func IsListening(conn resources.NetworkConnection) bool {
+	switch conn.Protocol {
+	case ProtocolUDP, ProtocolUDP6:
+		return conn.State == StateUnconnected
+	default:
+		return conn.State == StateListen
+	}
+}
+
+// Connections returns sockets for the given protocols (all when none are given)
+// with the owning process resolved where the caller may inspect it
+func (c *Collector) Connections(protocols ...string) ([]resources.NetworkConnection, error) {
+	if len(protocols) == 0 {
+		protocols = AllProtocols
+	}
+
+	var conns []resources.NetworkConnection
+	read := 0
+	for _, proto := range protocols {
+		var (
+			parsed []resources.NetworkConnection
+			err    error
+		)
+		switch proto {
+		case ProtocolTCP, ProtocolTCP6, ProtocolUDP, ProtocolUDP6:
+			parsed, err = c.readInetSockets(proto)
+		case ProtocolUnix:
+			parsed, err = c.readUnixSockets()
+		default:
+			return nil, fmt.Errorf("unsupported protocol: %s", proto)
+		}
+		if os.IsNotExist(err) {
+			// IPv6 may be disabled; skip missing tables
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		read++
+		conns = append(conns, parsed...)
+	}
+	if read == 0 {
+		return nil, fmt.Errorf("no socket tables found under %s", c.path("net"))
+	}
+
+	owners := c.SocketOwners()
+	names := make(map[int]string)
+	for i := range conns {
+		pid, ok := owners[conns[i].Inode]
+		if !ok {
+			continue
+		}
+		conns[i].PID = pid
+		if _, ok := names[pid]; !ok {
+			names[pid] = c.processName(pid)
+		}
+		conns[i].ProcessName = names[pid]
+	}
+	return conns, nil
+}
+
+// SocketOwners maps socket inodes to the PID holding them open by scanning
+// /proc/<pid>/fd. Processes whose descriptors cannot be read are skipped, so
+// unprivileged callers only see their own sockets.
+func (c *Collector) SocketOwners() map[uint64]int {
+	owners := make(map[uint64]int)
+	pids, err := c.pids()
+	if err != nil {
+		return owners
+	}
+
+	for _, pid := range pids {
+		dir := c.path(strconv.Itoa(pid), "fd")
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			target, err := os.Readlink(dir + "/" + entry.Name())
+			if err != nil {
+				continue
+			}
+			inode, ok := strings.CutPrefix(target, "socket:[")
+			if !ok {
+				continue
+			}
+			if n, err := strconv.ParseUint(strings.TrimSuffix(inode, "]"), 10, 64); err == nil {
+				if _, seen := owners[n]; !seen {
+					owners[n] = pid
+				}
+			}
+		}
+	}
+	return owners
+}
+
+// processName returns the command name of a process from its stat file
+func (c *Collector) processName(pid int) string {
+	data, err := os.ReadFile(c.path(strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return ""
+	}
+	stat, err := parseProcStat(string(data))
+	if err != nil {
+		return ""
+	}
+	return stat.name
+}
+
+// readInetSockets parses /proc/net/{tcp,tcp6,udp,udp6}
+func (c *Collector) readInetSockets(proto string) ([]resources.NetworkConnection, error) {
+	f, err := os.Open(c.path("net", proto))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	udp := strings.HasPrefix(proto, ProtocolUDP)
+	var conns []resources.NetworkConnection
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		localIP, localPort, err := parseSocketAddress(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", proto, err)
+		}
+		remoteIP, remotePort, err := parseSocketAddress(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", proto, err)
+		}
+		inode, _ := strconv.ParseUint(fields[9], 10, 64)
+
+		state := tcpStates[fields[3]]
+		if udp {
+			// UDP reuses TCP_ESTABLISHED for connected sockets and TCP_CLOSE for bound ones
+			if fields[3] == "01" {
+				state = "ESTABLISHED"
+			} else {
+				state = StateUnconnected
+			}
+		}
+
+		conns = append(conns, resources.NetworkConnection{
+			Protocol:      proto,
+			LocalAddress:  localIP,
+			LocalPort:     localPort,
+			RemoteAddress: remoteIP,
+			RemotePort:    remotePort,
+			State:         state,
+			Inode:         inode,
+		})
+	}
+	return conns, scanner.Err()
+}
+
+// parseSocketAddress decodes "0100007F:1F90" style addresses. The address is
+// a sequence of 32-bit words in host byte order, the port is big-endian hex.
+func parseSocketAddress(s string) (string, int, error) {
+	addrHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("malformed socket address %q", s)
+	}
+
+	raw, err := hex.DecodeString(addrHex)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", 0, fmt.Errorf("malformed socket address %q", s)
+	}
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], binary.NativeEndian.Uint32(raw[i:]))
+	}
+
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed socket port %q", s)
+	}
+	return ip.String(), int(port), nil
+}
+
+// readUnixSockets parses /proc/net/unix; the socket path is reported as the local address
+func (c *Collector) readUnixSockets() ([]resources.NetworkConnection, error) {
+	f, err := os.Open(c.path("net", ProtocolUnix))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var conns []resources.NetworkConnection
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// Num RefCount Protocol Flags Type St Inode [Path]
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			continue
+		}
+
+		flags, _ := strconv.ParseUint(fields[3], 16, 64)
+		inode, _ := strconv.ParseUint(fields[6], 10, 64)
+		state := unixStates[fields[5]]
+		if flags&unixAcceptCon != 0 {
+			state = StateListen
+		}
+
+		conn := resources.NetworkConnection{
+			Protocol: ProtocolUnix,
+			State:    state,
+			Inode:    inode,
+		}
+		if len(fields) > 7 {
+			conn.LocalAddress = strings.Join(fields[7:], " ")
+		}
+		conns = append(conns, conn)
+	}
+	return conns, scanner.Err()
+}
+
+// Interfaces returns per-interface traffic counters from /proc/net/dev
+func (c *Collector) Interfaces() ([]resources.NetworkInterface, error) {
+	f, err := os.Open(c.path("net", "dev"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var ifaces []resources.NetworkInterface
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue // the two header lines have no colon
+		}
+		// Receive: bytes packets errs drop fifo frame compressed multicast, then transmit
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			continue
+		}
+		value := func(i int) uint64 {
+			v, _ := strconv.ParseUint(fields[i], 10, 64)
+			return v
+		}
+		ifaces = append(ifaces, resources.NetworkInterface{
+			Name:        strings.TrimSpace(name),
+			BytesRecv:   value(0),
+			PacketsRecv: value(1),
+			BytesSent:   value(8),
+			PacketsSent: value(9),
+		})
+	}
+	return ifaces, scanner.Err()
+}
@@ -0,0 +1,184 @@
+package procfs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// processStates maps the state letter in /proc/<pid>/stat to a readable status
+var processStates = map[string]string{
+	"R": "running",
+	"S": "sleeping",
+	"D": "disk-sleep",
+	"Z": "zombie",
+	"T": "stopped",
+	"t": "tracing-stop",
+	"X": "dead",
+	"x": "dead",
+	"I": "idle",
+	"K": "wakekill",
+	"W": "waking",
+	"P": "parked",
+}
+
+// procStat holds the fields of /proc/<pid>/stat that are used
+type procStat struct {
+	name      string
+	state     string
+	ppid      int
+	utime     uint64
+	stime     uint64
+	threads   int
+	startTime uint64 // clock ticks after boot
+}
+
+// systemTimes holds system-wide values needed to derive per-process figures
+type systemTimes struct {
+	bootTime time.Time
+	uptime   float64
+	memTotal uint64
+}
+
+// Processes returns every process visible in the proc tree, ordered by PID.
+// Processes that exit while being read are skipped.
+func (c *Collector) Processes() ([]resources.Process, error) {
+	pids, err := c.pids()
+	if err != nil {
+		return nil, err
+	}
+	sys := c.systemTimes()
+
+	processes := make([]resources.Process, 0, len(pids))
+	for _, pid := range pids {
+		proc, err := c.readProcess(pid, sys)
+		if err != nil {
+			continue
+		}
+		processes = append(processes, *proc)
+	}
+	sort.Slice(processes, func(i, j int) bool { return processes[i].PID < processes[j].PID })
+	return processes, nil
+}
+
+// Process returns a single process; the error wraps os.ErrNotExist when it does not exist
+func (c *Collector) Process(pid int) (*resources.Process, error) {
+	if pid <= 0 {
+		return nil, fmt.Errorf("invalid process ID: %d", pid)
+	}
+	return c.readProcess(pid, c.systemTimes())
+}
+
+// readProcess assembles a process record from stat, status and cmdline
+func (c *Collector) readProcess(pid int, sys systemTimes) (*resources.Process, error) {
+	dir := strconv.Itoa(pid)
+
+	data, err := os.ReadFile(c.path(dir, "stat"))
+	if err != nil {
+		return nil, fmt.Errorf("process %d: %w", pid, err)
+	}
+	stat, err := parseProcStat(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("process %d: %w", pid, err)
+	}
+
+	proc := &resources.Process{
+		PID:     pid,
+		PPID:    stat.ppid,
+		Name:    stat.name,
+		Status:  processStates[stat.state],
+		Threads: stat.threads,
+	}
+	if proc.Status == "" {
+		proc.Status = stat.state
+	}
+
+	if status, err := readKeyValues(c.path(dir, "status")); err == nil {
+		if fields := strings.Fields(status["Uid"]); len(fields) > 0 {
+			proc.User = c.userName(fields[0])
+		}
+		proc.MemoryUsage = parseKilobytes(status["VmRSS"])
+	}
+
+	if cmdline, err := os.ReadFile(c.path(dir, "cmdline")); err == nil {
+		proc.CommandLine = strings.TrimSpace(string(bytes.ReplaceAll(bytes.TrimRight(cmdline, "\x00"), []byte{0}, []byte{' '})))
+	}
+
+	started := float64(stat.startTime) / clockTicks
+	if !sys.bootTime.IsZero() {
+		proc.StartTime = sys.bootTime.Add(time.Duration(started * float64(time.Second)))
+	}
+	if elapsed := sys.uptime - started; elapsed > 0 {
+		// Average CPU usage over the lifetime of the process, as ps reports it
+		proc.CPUPercent = float64(stat.utime+stat.stime) / clockTicks / elapsed * 100
+	}
+	if sys.memTotal > 0 {
+		proc.MemoryPercent = float64(proc.MemoryUsage) / float64(sys.memTotal) * 100
+	}
+
+	return proc, nil
+}
+
+// parseProcStat parses /proc/<pid>/stat; the command name is delimited by the
+// last ')' because it may itself contain spaces and parentheses
+func parseProcStat(data string) (procStat, error) {
+	open := strings.IndexByte(data, '(')
+	end := strings.LastIndexByte(data, ')')
+	if open < 0 || end < open {
+		return procStat{}, fmt.Errorf("malformed stat line")
+	}
+
+	// Fields after the name, starting with state (field 3 in proc(5))
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 20 {
+		return procStat{}, fmt.Errorf("malformed stat line: %d fields", len(fields))
+	}
+
+	field := func(n int) uint64 {
+		v, _ := strconv.ParseUint(fields[n-3], 10, 64)
+		return v
+	}
+	return procStat{
+		name:      data[open+1 : end],
+		state:     fields[0],
+		ppid:      int(field(4)),
+		utime:     field(14),
+		stime:     field(15),
+		threads:   int(field(20)),
+		startTime: field(22),
+	}, nil
+}
+
+// systemTimes reads boot time, uptime and total memory; missing values stay zero
+func (c *Collector) systemTimes() systemTimes {
+	var sys systemTimes
+
+	if data, err := os.ReadFile(c.path("stat")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if rest, ok := strings.CutPrefix(line, "btime "); ok {
+				if secs, err := strconv.ParseInt(strings.TrimSpace(rest), 10, 64); err == nil {
+					sys.bootTime = time.Unix(secs, 0)
+				}
+				break
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(c.path("uptime")); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			sys.uptime, _ = strconv.ParseFloat(fields[0], 64)
+		}
+	}
+
+	if meminfo, err := readKeyValues(c.path("meminfo")); err == nil {
+		sys.memTotal = parseKilobytes(meminfo["MemTotal"])
+	}
+
+	return sys
+}
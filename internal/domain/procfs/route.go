@@ -0,0 +1,125 @@
+package procfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// Route flags from linux/route.h
+const (
+	routeUp     = 0x0001
+	routeReject = 0x0200
+)
+
+// Routes returns the usable IPv4 and IPv6 routes from /proc/net/route and
+// /proc/net/ipv6_route. Destinations are in CIDR notation and the gateway is
+// the unspecified address for directly connected networks. A missing
+// ipv6_route (IPv6 disabled) is not an error.
+func (c *Collector) Routes() ([]resources.Route, error) {
+	routes, err := c.readIPv4Routes()
+	if err != nil {
+		return nil, err
+	}
+	v6, err := c.readIPv6Routes()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return append(routes, v6...), nil
+}
+
+// readIPv4Routes parses /proc/net/route:
+// Iface Destination Gateway Flags RefCnt Use Metric Mask MTU Window IRTT
+func (c *Collector) readIPv4Routes() ([]resources.Route, error) {
+	f, err := os.Open(c.path("net", "route"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var routes []resources.Route
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		flags, _ := strconv.ParseUint(fields[3], 16, 32)
+		if flags&routeUp == 0 || flags&routeReject != 0 {
+			continue
+		}
+		dest, err1 := parseRouteIPv4(fields[1])
+		gateway, err2 := parseRouteIPv4(fields[2])
+		mask, err3 := parseRouteIPv4(fields[7])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		ones, _ := net.IPMask(mask.To4()).Size()
+		metric, _ := strconv.Atoi(fields[6])
+
+		routes = append(routes, resources.Route{
+			Destination: fmt.Sprintf("%s/%d", dest, ones),
+			Gateway:     gateway.String(),
+			Interface:   fields[0],
+			Metric:      metric,
+		})
+	}
+	return routes, scanner.Err()
+}
+
+// readIPv6Routes parses /proc/net/ipv6_route:
+// dest dest_len src src_len next_hop metric refcnt use flags iface
+func (c *Collector) readIPv6Routes() ([]resources.Route, error) {
+	f, err := os.Open(c.path("net", "ipv6_route"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var routes []resources.Route
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		flags, _ := strconv.ParseUint(fields[8], 16, 32)
+		if flags&routeUp == 0 || flags&routeReject != 0 {
+			continue
+		}
+		dest, err1 := hex.DecodeString(fields[0])
+		gateway, err2 := hex.DecodeString(fields[4])
+		prefix, err3 := strconv.ParseUint(fields[1], 16, 8)
+		if err1 != nil || err2 != nil || err3 != nil || len(dest) != net.IPv6len || len(gateway) != net.IPv6len {
+			continue
+		}
+		metric, _ := strconv.ParseUint(fields[5], 16, 32)
+
+		routes = append(routes, resources.Route{
+			Destination: fmt.Sprintf("%s/%d", net.IP(dest), prefix),
+			Gateway:     net.IP(gateway).String(),
+			Interface:   fields[9],
+			Metric:      int(metric),
+		})
+	}
+	return routes, scanner.Err()
+}
+
+// parseRouteIPv4 decodes an address from /proc/net/route, a hex word in host byte order
+func parseRouteIPv4(s string) (net.IP, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("malformed route address %q", s)
+	}
+	raw := make([]byte, 4)
+	binary.NativeEndian.PutUint32(raw, uint32(v))
+	return net.IPv4(raw[0], raw[1], raw[2], raw[3]), nil
+}
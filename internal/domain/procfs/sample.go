@@ -0,0 +1,112 @@
+package procfs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// processCounters are the cumulative per-process counters compared between samples
+type processCounters struct {
+	startTime uint64
+	cpuTicks  uint64
+	// io is nil when /proc/<pid>/io is not readable (other users' processes without privileges)
+	io *resources.ProcessIOStats
+}
+
+// SampleProcesses reads every process twice, interval apart, and reports CPU
+// usage and storage I/O rates over that interval instead of lifetime averages.
+// CPU usage is per core as in top, so a busy multi-threaded process can exceed 100%.
+// Processes that start during the interval keep their lifetime figures.
+func (c *Collector) SampleProcesses(ctx context.Context, interval time.Duration, includeIO bool) ([]resources.Process, error) {
+	before, err := c.snapshotCounters(includeIO)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(interval):
+	}
+
+	procs, err := c.Processes()
+	if err != nil {
+		return nil, err
+	}
+	c.applySample(procs, before, time.Since(start).Seconds(), includeIO)
+	return procs, nil
+}
+
+// snapshotCounters reads the counters of every process
+func (c *Collector) snapshotCounters(includeIO bool) (map[int]processCounters, error) {
+	pids, err := c.pids()
+	if err != nil {
+		return nil, err
+	}
+	counters := make(map[int]processCounters, len(pids))
+	for _, pid := range pids {
+		if current, err := c.readCounters(pid, includeIO); err == nil {
+			counters[pid] = current
+		}
+	}
+	return counters, nil
+}
+
+// applySample rereads the counters of procs and replaces their CPU figures
+// and I/O rates with the change since before over elapsed seconds
+func (c *Collector) applySample(procs []resources.Process, before map[int]processCounters, elapsed float64, includeIO bool) {
+	for i := range procs {
+		proc := &procs[i]
+		after, err := c.readCounters(proc.PID, includeIO)
+		if err != nil {
+			continue
+		}
+		proc.IOStats = after.io
+
+		prev, ok := before[proc.PID]
+		if !ok || prev.startTime != after.startTime || elapsed <= 0 {
+			continue // new process or recycled PID
+		}
+		proc.CPUPercent = float64(after.cpuTicks-min(prev.cpuTicks, after.cpuTicks)) / clockTicks / elapsed * 100
+		if prev.io != nil && after.io != nil {
+			after.io.ReadBytesPerSec = float64(after.io.ReadBytes-min(prev.io.ReadBytes, after.io.ReadBytes)) / elapsed
+			after.io.WriteBytesPerSec = float64(after.io.WriteBytes-min(prev.io.WriteBytes, after.io.WriteBytes)) / elapsed
+		}
+	}
+}
+
+// readCounters reads the CPU ticks and, optionally, the I/O counters of a process
+func (c *Collector) readCounters(pid int, includeIO bool) (processCounters, error) {
+	dir := strconv.Itoa(pid)
+	data, err := os.ReadFile(c.path(dir, "stat"))
+	if err != nil {
+		return processCounters{}, err
+	}
+	stat, err := parseProcStat(string(data))
+	if err != nil {
+		return processCounters{}, fmt.Errorf("process %d: %w", pid, err)
+	}
+
+	counters := processCounters{startTime: stat.startTime, cpuTicks: stat.utime + stat.stime}
+	if includeIO {
+		if values, err := readKeyValues(c.path(dir, "io")); err == nil {
+			value := func(key string) uint64 {
+				v, _ := strconv.ParseUint(values[key], 10, 64)
+				return v
+			}
+			counters.io = &resources.ProcessIOStats{
+				ReadBytes:       value("read_bytes"),
+				WriteBytes:      value("write_bytes"),
+				ReadOperations:  value("syscr"),
+				WriteOperations: value("syscw"),
+			}
+		}
+	}
+	return counters, nil
+}
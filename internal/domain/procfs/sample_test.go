@@ -0,0 +1,55 @@
+package procfs
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySample(t *testing.T) {
+	c := newTestCollector(t)
+	write := func(rel, content string) {
+		require.NoError(t, os.WriteFile(c.path(rel), []byte(content), 0644))
+	}
+	write("42/io", "rchar: 5000\nwchar: 100\nsyscr: 10\nsyscw: 2\nread_bytes: 4096\nwrite_bytes: 0\ncancelled_write_bytes: 0\n")
+
+	before, err := c.snapshotCounters(true)
+	require.NoError(t, err)
+	require.Contains(t, before, 42)
+	assert.Nil(t, before[1].io, "init has no readable io file")
+
+	// Over two seconds PID 42 uses 150 ticks (75%), reads 8 KiB and writes 2 MiB;
+	// PID 1 is replaced by a process with a different start time
+	write("42/stat", "42 (my (odd) app) R 1 42 42 0 -1 0 0 0 0 0 110 60 0 0 20 0 4 0 90000 1000 250 0\n")
+	write("42/io", "rchar: 9000\nwchar: 100\nsyscr: 20\nsyscw: 4\nread_bytes: 12288\nwrite_bytes: 2097152\ncancelled_write_bytes: 0\n")
+	write("1/stat", "1 (init) S 0 1 1 0 -1 4194560 0 0 0 0 3000 9000 0 0 20 0 1 0 60000 1000 250 18446744073709551615\n")
+
+	procs, err := c.Processes()
+	require.NoError(t, err)
+	c.applySample(procs, before, 2, true)
+
+	require.Len(t, procs, 2)
+	app := procs[1]
+	assert.InDelta(t, 75.0, app.CPUPercent, 0.001)
+	require.NotNil(t, app.IOStats)
+	assert.Equal(t, uint64(12288), app.IOStats.ReadBytes)
+	assert.Equal(t, uint64(20), app.IOStats.ReadOperations)
+	assert.InDelta(t, 4096.0, app.IOStats.ReadBytesPerSec, 0.001)
+	assert.InDelta(t, 1048576.0, app.IOStats.WriteBytesPerSec, 0.001)
+
+	// The recycled PID keeps its lifetime average: 120s of CPU over 400s = 30%
+	assert.InDelta(t, 30.0, procs[0].CPUPercent, 0.001)
+}
+
+func TestSampleProcesses_Cancelled(t *testing.T) {
+	c := newTestCollector(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.SampleProcesses(ctx, time.Minute, false)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,314 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"mini-mcp/internal/types/resources"
+)
+
+// virtualFilesystems are mount types that never hold user data and are left out of disk usage
+var virtualFilesystems = map[string]bool{
+	"autofs": true, "binfmt_misc": true, "bpf": true, "cgroup": true, "cgroup2": true,
+	"configfs": true, "debugfs": true, "devpts": true, "devtmpfs": true, "fusectl": true,
+	"hugetlbfs": true, "mqueue": true, "nsfs": true, "proc": true, "pstore": true,
+	"securityfs": true, "selinuxfs": true, "sysfs": true, "tracefs": true, "rpc_pipefs": true,
+}
+
+// Memory reads RAM and swap usage from /proc/meminfo. Used memory is
+// MemTotal minus MemAvailable, matching what free reports.
+func (c *Collector) Memory() (*resources.MemoryDetails, error) {
+	meminfo, err := readKeyValues(c.path("meminfo"))
+	if err != nil {
+		return nil, err
+	}
+	value := func(key string) uint64 { return parseKilobytes(meminfo[key]) }
+
+	mem := &resources.MemoryDetails{
+		Total:   value("MemTotal"),
+		Free:    value("MemFree"),
+		Buffers: value("Buffers"),
+		Cached:  value("Cached") + value("SReclaimable"),
+	}
+	if mem.Total == 0 {
+		return nil, fmt.Errorf("meminfo has no MemTotal")
+	}
+
+	available, ok := meminfo["MemAvailable"]
+	if ok {
+		mem.Used = mem.Total - min(parseKilobytes(available), mem.Total)
+	} else {
+		// Kernels before 3.14 do not report MemAvailable
+		mem.Used = mem.Total - min(mem.Free+mem.Buffers+mem.Cached, mem.Total)
+	}
+	mem.UsedPercent = percent(mem.Used, mem.Total)
+
+	if swapTotal := value("SwapTotal"); swapTotal > 0 {
+		swapFree := value("SwapFree")
+		mem.Swap = &resources.SwapMemory{
+			Total: swapTotal,
+			Free:  swapFree,
+			Used:  swapTotal - min(swapFree, swapTotal),
+		}
+		mem.Swap.UsedPercent = percent(mem.Swap.Used, swapTotal)
+	}
+	return mem, nil
+}
+
+// Uptime reads uptime from /proc/uptime, load and scheduler counts from
+// /proc/loadavg and the boot time from /proc/stat
+func (c *Collector) Uptime() (*resources.UptimeInfo, error) {
+	sys := c.systemTimes()
+	if sys.uptime == 0 {
+		return nil, fmt.Errorf("failed to read %s", c.path("uptime"))
+	}
+
+	data, err := os.ReadFile(c.path("loadavg"))
+	if err != nil {
+		return nil, err
+	}
+	// e.g. "0.52 0.58 0.59 2/1234 56789"
+	fields := strings.Fields(string(data))
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("malformed loadavg: %q", strings.TrimSpace(string(data)))
+	}
+
+	info := &resources.UptimeInfo{
+		Uptime:        FormatUptime(time.Duration(sys.uptime * float64(time.Second))),
+		UptimeSeconds: sys.uptime,
+		BootTime:      sys.bootTime,
+		LoadAverage:   make([]float64, 3),
+	}
+	for i := range info.LoadAverage {
+		if info.LoadAverage[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return nil, fmt.Errorf("malformed load average %q: %w", fields[i], err)
+		}
+	}
+	if running, total, ok := strings.Cut(fields[3], "/"); ok {
+		info.RunningProcesses, _ = strconv.Atoi(running)
+		info.TotalProcesses, _ = strconv.Atoi(total)
+	}
+	return info, nil
+}
+
+// Disks reports usage for every mounted block or network filesystem listed in
+// /proc/mounts. Pseudo filesystems and mounts with no capacity are skipped and
+// later mounts over the same path replace earlier ones.
+func (c *Collector) Disks() ([]resources.DiskInfo, error) {
+	f, err := os.Open(c.path("mounts"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var disks []resources.DiskInfo
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// device mountpoint fstype options dump pass
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || virtualFilesystems[fields[2]] {
+			continue
+		}
+		mountPoint := unescapeMountField(fields[1])
+
+		var st syscall.Statfs_t
+		if err := c.statfs(mountPoint, &st); err != nil || st.Blocks == 0 {
+			continue // unreadable mounts (e.g. stale NFS, no permission) are skipped
+		}
+		blockSize := uint64(st.Bsize)
+		used := (st.Blocks - st.Bfree) * blockSize
+		avail := st.Bavail * blockSize
+
+		disk := resources.DiskInfo{
+			Path:       mountPoint,
+			Device:     unescapeMountField(fields[0]),
+			FileSystem: fields[2],
+			Total:      st.Blocks * blockSize,
+			Free:       avail,
+			Used:       used,
+			// Reserved blocks are excluded, as in df
+			UsedPercent: percent(used, used+avail),
+		}
+		if i, seen := index[mountPoint]; seen {
+			disks[i] = disk
+			continue
+		}
+		index[mountPoint] = len(disks)
+		disks = append(disks, disk)
+	}
+	return disks, scanner.Err()
+}
+
+// CPUTimes are the cumulative clock ticks all CPUs spent busy and in total since boot
+type CPUTimes struct {
+	Busy  uint64
+	Total uint64
+}
+
+// CPUTimes reads the aggregate cpu line of /proc/stat. Idle and iowait count
+// as idle time; guest time is already included in user time and is not added again.
+func (c *Collector) CPUTimes() (CPUTimes, error) {
+	data, err := os.ReadFile(c.path("stat"))
+	if err != nil {
+		return CPUTimes{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		// user nice system idle iowait irq softirq steal
+		var times CPUTimes
+		for i, field := range fields[1:min(len(fields), 9)] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return CPUTimes{}, fmt.Errorf("malformed cpu line: %q", line)
+			}
+			times.Total += v
+			if i != 3 && i != 4 {
+				times.Busy += v
+			}
+		}
+		return times, nil
+	}
+	return CPUTimes{}, fmt.Errorf("%s has no cpu line", c.path("stat"))
+}
+
+// UsagePercent returns the busy share of the time elapsed since an earlier reading
+func (t CPUTimes) UsagePercent(earlier CPUTimes) float64 {
+	if t.Total <= earlier.Total || t.Busy < earlier.Busy {
+		return 0
+	}
+	return percent(t.Busy-earlier.Busy, t.Total-earlier.Total)
+}
+
+// KernelRelease reads the running kernel version from /proc/sys/kernel/osrelease
+func (c *Collector) KernelRelease() (string, error) {
+	data, err := os.ReadFile(c.path("sys", "kernel", "osrelease"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// CPU reads the model, vendor, clock speed and core counts from /proc/cpuinfo.
+// Physical cores are counted as distinct (physical id, core id) pairs; when the
+// kernel does not report them every logical CPU is counted as a core.
+func (c *Collector) CPU() (*resources.CPUDetails, error) {
+	f, err := os.Open(c.path("cpuinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	cpu := &resources.CPUDetails{}
+	cores := make(map[string]bool)
+	var speeds float64
+	var speedCount int
+	var physical string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "processor":
+			cpu.LogicalCores++
+		case "model name", "Model":
+			if cpu.Model == "" {
+				cpu.Model = value
+			}
+		case "vendor_id", "CPU implementer":
+			if cpu.Vendor == "" {
+				cpu.Vendor = value
+			}
+		case "cpu MHz":
+			if mhz, err := strconv.ParseFloat(value, 64); err == nil {
+				speeds += mhz
+				speedCount++
+			}
+		case "physical id":
+			physical = value
+		case "core id":
+			cores[physical+"/"+value] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cpu.LogicalCores == 0 {
+		return nil, fmt.Errorf("cpuinfo lists no processors")
+	}
+
+	cpu.PhysicalCores = len(cores)
+	if cpu.PhysicalCores == 0 {
+		cpu.PhysicalCores = cpu.LogicalCores
+	}
+	if speedCount > 0 {
+		cpu.SpeedMHz = speeds / float64(speedCount)
+	}
+	return cpu, nil
+}
+
+// unescapeMountField decodes the octal escapes (\040 for space etc.) used in /proc/mounts
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// FormatUptime renders a duration as "3 days, 4 hours, 5 minutes"
+func FormatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, plural(days, "day"))
+	}
+	if hours > 0 {
+		parts = append(parts, plural(hours, "hour"))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, plural(minutes, "minute"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural formats a count with a singular or plural unit
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// percent returns part as a percentage of whole
+func percent(part, whole uint64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
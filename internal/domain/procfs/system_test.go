@@ -0,0 +1,149 @@
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	c := newTestCollector(t)
+	require.NoError(t, os.WriteFile(c.path("meminfo"), []byte(
+		"MemTotal:        1000 kB\nMemFree:          200 kB\nMemAvailable:     600 kB\n"+
+			"Buffers:           50 kB\nCached:           300 kB\nSReclaimable:      20 kB\n"+
+			"SwapTotal:        400 kB\nSwapFree:         300 kB\n"), 0644))
+
+	mem, err := c.Memory()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000*1024), mem.Total)
+	assert.Equal(t, uint64(200*1024), mem.Free)
+	assert.Equal(t, uint64(400*1024), mem.Used)
+	assert.Equal(t, uint64(320*1024), mem.Cached)
+	assert.InDelta(t, 40.0, mem.UsedPercent, 0.001)
+	require.NotNil(t, mem.Swap)
+	assert.Equal(t, uint64(100*1024), mem.Swap.Used)
+	assert.InDelta(t, 25.0, mem.Swap.UsedPercent, 0.001)
+}
+
+func TestMemory_WithoutMemAvailable(t *testing.T) {
+	c := newTestCollector(t)
+
+	// The fixture only has MemTotal and MemFree and no swap
+	mem, err := c.Memory()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(500000*1024), mem.Used)
+	assert.Nil(t, mem.Swap)
+}
+
+func TestUptime(t *testing.T) {
+	c := newTestCollector(t)
+	require.NoError(t, os.WriteFile(c.path("loadavg"), []byte("0.52 0.58 1.25 3/1234 56789\n"), 0644))
+
+	info, err := c.Uptime()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.52, 0.58, 1.25}, info.LoadAverage)
+	assert.Equal(t, 3, info.RunningProcesses)
+	assert.Equal(t, 1234, info.TotalProcesses)
+	assert.Equal(t, 1000.0, info.UptimeSeconds)
+	assert.Equal(t, "16 minutes", info.Uptime)
+	assert.Equal(t, time.Unix(1700000000, 0), info.BootTime)
+
+	require.NoError(t, os.WriteFile(c.path("loadavg"), []byte("garbage\n"), 0644))
+	_, err = c.Uptime()
+	assert.Error(t, err)
+}
+
+func TestDisks(t *testing.T) {
+	c := newTestCollector(t)
+	require.NoError(t, os.WriteFile(filepath.Join(c.root, "mounts"), []byte(
+		"/dev/sda1 / ext4 rw,relatime 0 0\n"+
+			"proc /proc proc rw 0 0\n"+
+			"tmpfs /run tmpfs rw 0 0\n"+
+			"/dev/sdb1 /mnt/my\\040data xfs rw 0 0\n"+
+			"/dev/sdc1 /mnt/gone ext4 rw 0 0\n"+
+			"/dev/sda2 / ext4 rw 0 0\n"), 0644))
+
+	var statted []string
+	c.statfs = func(path string, buf *syscall.Statfs_t) error {
+		statted = append(statted, path)
+		switch path {
+		case "/":
+			*buf = syscall.Statfs_t{Bsize: 4096, Blocks: 1000, Bfree: 300, Bavail: 250}
+		case "/run":
+			*buf = syscall.Statfs_t{Bsize: 4096}
+		case "/mnt/my data":
+			*buf = syscall.Statfs_t{Bsize: 1024, Blocks: 100, Bfree: 100, Bavail: 100}
+		default:
+			return syscall.EACCES
+		}
+		return nil
+	}
+
+	disks, err := c.Disks()
+	require.NoError(t, err)
+	assert.NotContains(t, statted, "/proc")
+	require.Len(t, disks, 2)
+
+	root := disks[0]
+	assert.Equal(t, "/", root.Path)
+	assert.Equal(t, "/dev/sda2", root.Device, "the later mount over / wins")
+	assert.Equal(t, uint64(1000*4096), root.Total)
+	assert.Equal(t, uint64(700*4096), root.Used)
+	assert.Equal(t, uint64(250*4096), root.Free)
+	assert.InDelta(t, 700.0/950*100, root.UsedPercent, 0.001)
+
+	assert.Equal(t, "/mnt/my data", disks[1].Path)
+	assert.Equal(t, "xfs", disks[1].FileSystem)
+	assert.Zero(t, disks[1].UsedPercent)
+}
+
+func TestFormatUptime(t *testing.T) {
+	assert.Equal(t, "0 minutes", FormatUptime(30*time.Second))
+	assert.Equal(t, "1 hour, 1 minute", FormatUptime(61*time.Minute))
+	assert.Equal(t, "3 days, 2 minutes", FormatUptime(72*time.Hour+2*time.Minute))
+}
+
+func TestCPU(t *testing.T) {
+	c := newTestCollector(t)
+	var cpuinfo string
+	for i := 0; i < 4; i++ {
+		// Two cores with two hyperthreads each
+		cpuinfo += fmt.Sprintf("processor\t: %d\nvendor_id\t: GenuineIntel\nmodel name\t: Intel(R) Xeon(R) CPU\n"+
+			"cpu MHz\t\t: %d.000\nphysical id\t: 0\ncore id\t\t: %d\n\n", i, 2000+1000*(i%2), i/2)
+	}
+	require.NoError(t, os.WriteFile(c.path("cpuinfo"), []byte(cpuinfo), 0644))
+
+	cpu, err := c.CPU()
+	require.NoError(t, err)
+	assert.Equal(t, "Intel(R) Xeon(R) CPU", cpu.Model)
+	assert.Equal(t, "GenuineIntel", cpu.Vendor)
+	assert.Equal(t, 4, cpu.LogicalCores)
+	assert.Equal(t, 2, cpu.PhysicalCores)
+	assert.InDelta(t, 2500.0, cpu.SpeedMHz, 0.001)
+
+	require.NoError(t, os.MkdirAll(c.path("sys", "kernel"), 0755))
+	require.NoError(t, os.WriteFile(c.path("sys", "kernel", "osrelease"), []byte("6.1.0-18-amd64\n"), 0644))
+	release, err := c.KernelRelease()
+	require.NoError(t, err)
+	assert.Equal(t, "6.1.0-18-amd64", release)
+}
+
+func TestCPUTimes(t *testing.T) {
+	c := newTestCollector(t)
+	require.NoError(t, os.WriteFile(c.path("stat"), []byte("cpu  100 0 50 800 50 0 0 0 10 0\ncpu0 100 0 50 800 50 0 0 0\nbtime 1700000000\n"), 0644))
+	before, err := c.CPUTimes()
+	require.NoError(t, err)
+	assert.Equal(t, CPUTimes{Busy: 150, Total: 1000}, before)
+
+	require.NoError(t, os.WriteFile(c.path("stat"), []byte("cpu  130 0 70 850 50 0 0 0 10 0\n"), 0644))
+	after, err := c.CPUTimes()
+	require.NoError(t, err)
+	assert.InDelta(t, 50.0, after.UsagePercent(before), 0.001)
+	assert.Zero(t, before.UsagePercent(after))
+}
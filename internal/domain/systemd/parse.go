@@ -0,0 +1,120 @@
+package systemd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/domain/journal"
+	"mini-mcp/internal/types/resources"
+)
+
+// showProperties are the unit properties read with systemctl show
+var showProperties = []string{
+	"Id", "Description", "LoadState", "ActiveState", "SubState", "UnitFileState",
+	"ActiveEnterTimestamp", "MainPID", "Result", "ExecMainStatus", "NRestarts",
+}
+
+// parseListUnits parses `systemctl list-units --plain --no-legend`: unit load active sub description
+func parseListUnits(output string) []resources.Service {
+	services := make([]resources.Service, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		// Failed units may be prefixed with a marker
+		if fields[0] == "●" || fields[0] == "*" {
+			fields = fields[1:]
+			if len(fields) < 4 {
+				continue
+			}
+		}
+		services = append(services, resources.Service{
+			Name:        fields[0],
+			LoadState:   fields[1],
+			Status:      fields[2],
+			SubState:    fields[3],
+			Description: strings.Join(fields[4:], " "),
+		})
+	}
+	return services
+}
+
+// parseShow parses `systemctl show` output: Key=Value lines, one blank-line separated block per unit
+func parseShow(output string) []resources.Service {
+	var services []resources.Service
+	for _, block := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		props := make(map[string]string)
+		for _, line := range strings.Split(block, "\n") {
+			if key, value, ok := strings.Cut(line, "="); ok {
+				props[key] = value
+			}
+		}
+		if props["Id"] == "" {
+			continue
+		}
+
+		svc := resources.Service{
+			Name:          props["Id"],
+			Description:   props["Description"],
+			Status:        props["ActiveState"],
+			LoadState:     props["LoadState"],
+			SubState:      props["SubState"],
+			UnitFileState: props["UnitFileState"],
+			Result:        props["Result"],
+			Enabled:       props["UnitFileState"] == "enabled" || props["UnitFileState"] == "enabled-runtime",
+			StartTime:     parseTimestamp(props["ActiveEnterTimestamp"]),
+		}
+		svc.MainPID, _ = strconv.Atoi(props["MainPID"])
+		svc.ExitStatus, _ = strconv.Atoi(props["ExecMainStatus"])
+		svc.Restarts, _ = strconv.Atoi(props["NRestarts"])
+		services = append(services, svc)
+	}
+	return services
+}
+
+// parseTimestamp parses "@1705312800" (--timestamp=unix) or the default
+// "Mon 2024-01-15 10:00:00 UTC" format; empty or unknown values give the zero time
+func parseTimestamp(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if secs, ok := strings.CutPrefix(value, "@"); ok {
+		if n, err := strconv.ParseInt(secs, 10, 64); err == nil {
+			return time.Unix(n, 0)
+		}
+	}
+	if t, err := time.Parse("Mon 2006-01-02 15:04:05 MST", value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// journal reads the most recent journal entries of a unit with journalctl -o json
+func (s *ServiceImpl) journal(ctx context.Context, unit string, lines int) ([]resources.LogEntry, error) {
+	if lines <= 0 {
+		lines = 20
+	}
+	out, err := s.run(ctx, "journalctl", "--no-pager", "-o", "json", "-n", strconv.Itoa(lines), "-u", unit)
+	if err != nil {
+		return nil, err
+	}
+	return parseJournal(out)
+}
+
+// parseJournal parses journalctl -o json output, one JSON object per line
+func parseJournal(output string) ([]resources.LogEntry, error) {
+	entries := make([]resources.LogEntry, 0)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		entry, err := journal.ParseEntry([]byte(line))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry.LogEntry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,240 @@
+// Package systemd inspects and controls systemd services through systemctl
+// and reads their journal through journalctl.
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// Service control actions
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionReload  = "reload"
+	ActionEnable  = "enable"
+	ActionDisable = "disable"
+)
+
+const (
+	// bootedMarker exists when the system was booted with systemd (sd_booted)
+	bootedMarker = "/run/systemd/system"
+	// maxLogUnits caps how many units get journal entries in one call
+	maxLogUnits = 5
+	// failureLogLines is how many journal lines accompany a failed control action
+	failureLogLines = 10
+)
+
+// Actions lists the supported control actions
+var Actions = []string{ActionStart, ActionStop, ActionRestart, ActionReload, ActionEnable, ActionDisable}
+
+// unitNamePattern matches valid unit names; it also keeps names from being read as options
+var unitNamePattern = regexp.MustCompile(`^[A-Za-z0-9:_.@\\-]+$`)
+
+// ListOptions selects which services service_info reports and how much detail
+type ListOptions struct {
+	// Filter is a unit glob or substring; empty lists every service
+	Filter string
+	// Status adds enablement, main PID, result and restart details
+	Status bool
+	// Logs adds recent journal entries when at most maxLogUnits units match
+	Logs bool
+	// LogLines is the number of journal entries per unit
+	LogLines int
+}
+
+// Service defines the interface for systemd domain services
+type Service interface {
+	List(ctx context.Context, opts ListOptions) (*resources.ServiceInfo, error)
+	Control(ctx context.Context, unit, action string) (*resources.ServiceControlResult, error)
+}
+
+// ServiceImpl implements the systemd domain service
+type ServiceImpl struct {
+	run          registry.CommandRunner
+	controllable []string
+	logger       logging.Logger
+	// bootedMarker is checked to decide whether systemd is running; replaced in tests
+	bootedMarker string
+}
+
+// NewService creates a systemd service. run executes systemctl and journalctl;
+// controllable lists the unit patterns Control may act on.
+func NewService(run registry.CommandRunner, controllable []string, logger logging.Logger) Service {
+	return &ServiceImpl{
+		run:          run,
+		controllable: controllable,
+		logger:       logger,
+		bootedMarker: bootedMarker,
+	}
+}
+
+// NormalizeUnit validates a unit name and adds the .service suffix when no unit type is given
+func NormalizeUnit(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.HasPrefix(name, "-") || !unitNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid unit name %q", name)
+	}
+	if !strings.Contains(name, ".") {
+		name += ".service"
+	}
+	return name, nil
+}
+
+// IsValidAction reports whether action is a supported control action
+func IsValidAction(action string) bool {
+	for _, a := range Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// List reports systemd services matching the filter with their state and,
+// on request, status details and recent journal entries
+func (s *ServiceImpl) List(ctx context.Context, opts ListOptions) (*resources.ServiceInfo, error) {
+	info := &resources.ServiceInfo{SystemdServices: make([]resources.Service, 0)}
+	if _, err := os.Stat(s.bootedMarker); err != nil {
+		info.Warnings = append(info.Warnings, "systemd is not running on this host")
+		return info, nil
+	}
+	info.SystemdActive = true
+
+	args := []string{"list-units", "--type=service", "--all", "--plain", "--no-legend", "--no-pager"}
+	if opts.Filter != "" {
+		pattern := opts.Filter
+		if !strings.ContainsAny(pattern, "*?[") && !strings.Contains(pattern, ".") {
+			pattern = "*" + pattern + "*"
+		}
+		args = append(args, "--", pattern)
+	}
+	out, err := s.run(ctx, "systemctl", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+	info.SystemdServices = parseListUnits(out)
+	info.ServiceCount = len(info.SystemdServices)
+
+	if opts.Status && len(info.SystemdServices) > 0 {
+		if err := s.addStatus(ctx, info.SystemdServices); err != nil {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("status: %v", err))
+		}
+	}
+
+	if opts.Logs {
+		if len(info.SystemdServices) > maxLogUnits {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("logs are only included when at most %d units match; narrow filter_service", maxLogUnits))
+		} else {
+			for i := range info.SystemdServices {
+				entries, err := s.journal(ctx, info.SystemdServices[i].Name, opts.LogLines)
+				if err != nil {
+					info.Warnings = append(info.Warnings, fmt.Sprintf("logs for %s: %v", info.SystemdServices[i].Name, err))
+					continue
+				}
+				info.SystemdServices[i].RecentLogs = entries
+			}
+		}
+	}
+	return info, nil
+}
+
+// addStatus fills status details from one systemctl show call for all units
+func (s *ServiceImpl) addStatus(ctx context.Context, services []resources.Service) error {
+	args := []string{"show", "--timestamp=unix", "--property=" + strings.Join(showProperties, ","), "--"}
+	for _, svc := range services {
+		args = append(args, svc.Name)
+	}
+	out, err := s.run(ctx, "systemctl", args...)
+	if err != nil {
+		return err
+	}
+
+	details := make(map[string]resources.Service)
+	for _, svc := range parseShow(out) {
+		details[svc.Name] = svc
+	}
+	for i := range services {
+		if detail, ok := details[services[i].Name]; ok {
+			if detail.Description == "" {
+				detail.Description = services[i].Description
+			}
+			services[i] = detail
+		}
+	}
+	return nil
+}
+
+// Control performs an action on an allowlisted unit and reports its state
+// afterwards; when the action fails, recent journal entries are included
+func (s *ServiceImpl) Control(ctx context.Context, unit, action string) (*resources.ServiceControlResult, error) {
+	unit, err := NormalizeUnit(unit)
+	if err != nil {
+		return nil, err
+	}
+	if !IsValidAction(action) {
+		return nil, fmt.Errorf("unsupported action %q (use one of: %s)", action, strings.Join(Actions, ", "))
+	}
+	if !s.isControllable(unit) {
+		logging.FromContext(ctx, s.logger).Warning("Refused service control for unit outside allowlist", map[string]any{"unit": unit, "action": action})
+		return nil, fmt.Errorf("unit %s is not in the controllable units allowlist", unit)
+	}
+
+	result := &resources.ServiceControlResult{Unit: unit, Action: action, Success: true}
+	if _, err := s.run(ctx, "systemctl", "--no-ask-password", action, "--", unit); err != nil {
+		result.Success = false
+		result.Error = commandError(err)
+		logging.FromContext(ctx, s.logger).Error("Service control failed", err, map[string]any{"unit": unit, "action": action})
+	} else {
+		logging.FromContext(ctx, s.logger).Info("Service control executed", map[string]any{"unit": unit, "action": action})
+	}
+
+	out, err := s.run(ctx, "systemctl", "show", "--timestamp=unix", "--property="+strings.Join(showProperties, ","), "--", unit)
+	if err == nil {
+		if services := parseShow(out); len(services) > 0 {
+			result.Service = &services[0]
+		}
+	}
+	if !result.Success && result.Service != nil {
+		result.Service.RecentLogs, _ = s.journal(ctx, unit, failureLogLines)
+	}
+	return result, nil
+}
+
+// commandError describes a failed command, including its stderr when the runner kept it
+func commandError(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+			return fmt.Sprintf("%v: %s", err, stderr)
+		}
+	}
+	return err.Error()
+}
+
+// isControllable reports whether a unit matches the allowlist
+func (s *ServiceImpl) isControllable(unit string) bool {
+	for _, pattern := range s.controllable {
+		pattern = strings.TrimSpace(pattern)
+		if normalized, err := NormalizeUnit(pattern); err == nil {
+			pattern = normalized
+		} else if !strings.Contains(pattern, ".") {
+			pattern += ".service" // globs such as "app-*"
+		}
+		if matched, _ := path.Match(pattern, unit); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const listUnitsOutput = `nginx.service       loaded active   running Nginx web server
+● app-worker.service loaded failed   failed  App background worker
+ssh.service         loaded active   running OpenBSD Secure Shell server
+`
+
+const showOutput = `Id=nginx.service
+Description=Nginx web server
+LoadState=loaded
+ActiveState=active
+SubState=running
+UnitFileState=enabled
+ActiveEnterTimestamp=@1705312800
+MainPID=812
+Result=success
+ExecMainStatus=0
+NRestarts=0
+
+Id=app-worker.service
+Description=App background worker
+LoadState=loaded
+ActiveState=failed
+SubState=failed
+UnitFileState=disabled
+ActiveEnterTimestamp=
+MainPID=0
+Result=exit-code
+ExecMainStatus=3
+NRestarts=5
+`
+
+const journalOutput = `{"__REALTIME_TIMESTAMP":"1705312800123456","PRIORITY":"3","SYSLOG_IDENTIFIER":"app-worker","MESSAGE":"cannot connect to database"}
+{"__REALTIME_TIMESTAMP":"1705312801000000","PRIORITY":"6","_COMM":"systemd","MESSAGE":[104,105]}
+`
+
+// fakeSystemctl records commands and answers systemctl and journalctl calls
+type fakeSystemctl struct {
+	calls    []string
+	failWith map[string]error
+}
+
+func (f *fakeSystemctl) run(ctx context.Context, command string, args ...string) (string, error) {
+	call := command + " " + strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	for prefix, err := range f.failWith {
+		if strings.HasPrefix(call, prefix) {
+			return "", err
+		}
+	}
+
+	switch {
+	case command == "journalctl":
+		return journalOutput, nil
+	case len(args) > 0 && args[0] == "list-units":
+		return listUnitsOutput, nil
+	case len(args) > 0 && args[0] == "show":
+		if strings.HasSuffix(call, "-- app-worker.service") {
+			return showOutput[strings.Index(showOutput, "Id=app-worker"):], nil
+		}
+		return showOutput, nil
+	}
+	return "", nil
+}
+
+func newTestService(t *testing.T, fake *fakeSystemctl, controllable ...string) *ServiceImpl {
+	marker := filepath.Join(t.TempDir(), "system")
+	require.NoError(t, os.Mkdir(marker, 0755))
+	return &ServiceImpl{
+		run:          fake.run,
+		controllable: controllable,
+		logger:       logging.NewLogger(io.Discard, logging.LogLevelError),
+		bootedMarker: marker,
+	}
+}
+
+func TestNormalizeUnit(t *testing.T) {
+	for input, want := range map[string]string{
+		"nginx":              "nginx.service",
+		"nginx.service":      "nginx.service",
+		"getty@tty1.service": "getty@tty1.service",
+		"backup.timer":       "backup.timer",
+	} {
+		got, err := NormalizeUnit(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got)
+	}
+
+	for _, bad := range []string{"", "--all", "nginx; reboot", "a b", "../x"} {
+		_, err := NormalizeUnit(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestList_WithStatusAndLogs(t *testing.T) {
+	fake := &fakeSystemctl{}
+	svc := newTestService(t, fake)
+
+	info, err := svc.List(context.Background(), ListOptions{Filter: "app", Status: true, Logs: true, LogLines: 5})
+	require.NoError(t, err)
+	assert.True(t, info.SystemdActive)
+	assert.Equal(t, 3, info.ServiceCount)
+	assert.Contains(t, fake.calls[0], "-- *app*")
+
+	worker := info.SystemdServices[1]
+	assert.Equal(t, "app-worker.service", worker.Name)
+	assert.Equal(t, "failed", worker.Status)
+	assert.Equal(t, "exit-code", worker.Result)
+	assert.Equal(t, 3, worker.ExitStatus)
+	assert.Equal(t, 5, worker.Restarts)
+	assert.False(t, worker.Enabled)
+
+	nginx := info.SystemdServices[0]
+	assert.True(t, nginx.Enabled)
+	assert.Equal(t, 812, nginx.MainPID)
+	assert.Equal(t, time.Unix(1705312800, 0), nginx.StartTime)
+
+	ssh := info.SystemdServices[2]
+	assert.Equal(t, "OpenBSD Secure Shell server", ssh.Description, "units missing from show keep list-units data")
+
+	require.Len(t, worker.RecentLogs, 2)
+	assert.Equal(t, "cannot connect to database", worker.RecentLogs[0].Message)
+	assert.Equal(t, "error", worker.RecentLogs[0].Level)
+	assert.Equal(t, "app-worker", worker.RecentLogs[0].Source)
+	assert.Equal(t, time.UnixMicro(1705312800123456), worker.RecentLogs[0].Timestamp)
+	assert.Equal(t, "hi", worker.RecentLogs[1].Message)
+	assert.Equal(t, "systemd", worker.RecentLogs[1].Source)
+	assert.Contains(t, fake.calls, "journalctl --no-pager -o json -n 5 -u app-worker.service")
+	assert.Empty(t, info.Warnings)
+}
+
+func TestList_NotSystemd(t *testing.T) {
+	fake := &fakeSystemctl{}
+	svc := newTestService(t, fake)
+	svc.bootedMarker = filepath.Join(t.TempDir(), "missing")
+
+	info, err := svc.List(context.Background(), ListOptions{})
+	require.NoError(t, err)
+	assert.False(t, info.SystemdActive)
+	assert.NotEmpty(t, info.Warnings)
+	assert.Empty(t, fake.calls)
+}
+
+func TestControl(t *testing.T) {
+	fake := &fakeSystemctl{}
+	svc := newTestService(t, fake, "nginx", "app-*")
+
+	result, err := svc.Control(context.Background(), "nginx", ActionRestart)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "nginx.service", result.Unit)
+	require.NotNil(t, result.Service)
+	assert.Equal(t, "active", result.Service.Status)
+	assert.Equal(t, "systemctl --no-ask-password restart -- nginx.service", fake.calls[0])
+
+	_, err = svc.Control(context.Background(), "ssh", ActionStop)
+	assert.ErrorContains(t, err, "allowlist")
+	_, err = svc.Control(context.Background(), "nginx", "mask")
+	assert.Error(t, err)
+	assert.Len(t, fake.calls, 2, "refused actions never reach systemctl")
+}
+
+func TestControl_FailureIncludesLogs(t *testing.T) {
+	fake := &fakeSystemctl{failWith: map[string]error{
+		"systemctl --no-ask-password start": errors.New("Job for app-worker.service failed"),
+	}}
+	svc := newTestService(t, fake, "app-*")
+
+	result, err := svc.Control(context.Background(), "app-worker", ActionStart)
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "failed")
+	require.NotNil(t, result.Service)
+	assert.Equal(t, "exit-code", result.Service.Result)
+	assert.Len(t, result.Service.RecentLogs, 2)
+}
+
+func TestControl_NoAllowlist(t *testing.T) {
+	svc := newTestService(t, &fakeSystemctl{})
+
+	_, err := svc.Control(context.Background(), "nginx", ActionStart)
+	assert.Error(t, err)
+}
+
+func TestCommandError_IncludesStderr(t *testing.T) {
+	_, err := exec.Command("sh", "-c", "echo 'Unit nope.service not found.' >&2; exit 5").Output()
+	assert.Contains(t, commandError(fmt.Errorf("system command failed: %w", err)), "Unit nope.service not found.")
+}
+
+// installCommands puts scripts printing the given output first on PATH
+func installCommands(t *testing.T, outputs map[string]string) {
+	dir := t.TempDir()
+	for name, output := range outputs {
+		data := filepath.Join(dir, name+".out")
+		require.NoError(t, os.WriteFile(data, []byte(output), 0644))
+		script := fmt.Sprintf("#!/bin/sh\nexec cat %q\n", data)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestList_ThroughDefaultPolicy(t *testing.T) {
+	installCommands(t, map[string]string{"systemctl": listUnitsOutput, "journalctl": journalOutput})
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	newService := func(config *security.SecurityConfig) (*ServiceImpl, *security.SecureCommandExecutor) {
+		sec := security.NewSecureCommandExecutor(config)
+		svc := newTestService(t, &fakeSystemctl{})
+		svc.run = registry.NewCommandExecutor(sec, logger).ExecuteSystemCommand
+		return svc, sec
+	}
+
+	svc, sec := newService(security.DefaultSecurityConfig())
+	info, err := svc.List(context.Background(), ListOptions{Filter: "app", Logs: true, LogLines: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 3, info.ServiceCount)
+	require.Len(t, info.SystemdServices[1].RecentLogs, 2)
+	assert.Empty(t, sec.PolicyDenials())
+
+	// Without the system commands the allowlist alone refuses systemctl
+	config := security.DefaultSecurityConfig()
+	config.SystemCommands = nil
+	svc, sec = newService(config)
+	_, err = svc.List(context.Background(), ListOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed")
+	assert.NotEmpty(t, sec.PolicyDenials())
+}
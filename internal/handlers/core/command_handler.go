@@ -35,7 +35,7 @@ func (h *CommandHandlerImpl) ExecuteCommand(ctx context.Context, args map[string
 	// Extract command from args
 	command, ok := args["command"].(string)
 	if !ok {
-		h.logger.Error("Invalid command argument", fmt.Errorf("invalid command argument"), map[string]any{"args": args})
+		logging.FromContext(ctx, h.logger).Error("Invalid command argument", fmt.Errorf("invalid command argument"), map[string]any{"args": args})
 		return "", fmt.Errorf("invalid command argument")
 	}
 
@@ -45,26 +45,10 @@ func (h *CommandHandlerImpl) ExecuteCommand(ctx context.Context, args map[string
 		timeout = int(timeoutVal)
 	}
 
-	// Record metrics
-	defer func() {
-		duration := time.Since(start)
-		success := true
-
-		// Get logger metrics
-		if logger, ok := h.logger.(*logging.LoggerImpl); ok {
-			logger.GetMetrics().RecordRequest("run", duration, success)
-		}
-	}()
-
-	// Execute command
+	// Execute command; the tool registry records call metrics
 	result, err := h.commandService.ExecuteCommand(ctx, command, timeout)
 	if err != nil {
-		// Record error in metrics
-		if logger, ok := h.logger.(*logging.LoggerImpl); ok {
-			logger.GetMetrics().RecordError("run")
-		}
-
-		h.logger.Error("Command execution failed", err, map[string]any{
+		logging.FromContext(ctx, h.logger).Error("Command execution failed", err, map[string]any{
 			"command":  command,
 			"timeout":  timeout,
 			"duration": time.Since(start).String(),
@@ -72,7 +56,7 @@ func (h *CommandHandlerImpl) ExecuteCommand(ctx context.Context, args map[string
 		return "", err
 	}
 
-	h.logger.Info("Command executed successfully", map[string]any{
+	logging.FromContext(ctx, h.logger).Info("Command executed successfully", map[string]any{
 		"command":       command,
 		"output_length": len(result),
 		"duration":      time.Since(start).String(),
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	appconfig "mini-mcp/internal/application/configfile"
+	domainconfig "mini-mcp/internal/domain/configfile"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+)
+
+// ConfigHandler handles structured configuration file requests
+type ConfigHandler interface {
+	GetValue(ctx context.Context, args map[string]any) (*resources.ConfigValue, error)
+	SetValue(ctx context.Context, args map[string]any) (*resources.ConfigChange, error)
+	DeleteValue(ctx context.Context, args map[string]any) (*resources.ConfigChange, error)
+}
+
+// ConfigHandlerImpl implements the ConfigHandler interface
+type ConfigHandlerImpl struct {
+	configService appconfig.Service
+	logger        logging.Logger
+}
+
+// NewConfigHandler creates a new configuration file handler
+func NewConfigHandler(configService appconfig.Service, logger logging.Logger) ConfigHandler {
+	return &ConfigHandlerImpl{
+		configService: configService,
+		logger:        logger,
+	}
+}
+
+// GetValue reads the value at a key path
+func (h *ConfigHandlerImpl) GetValue(ctx context.Context, args map[string]any) (*resources.ConfigValue, error) {
+	path, key, err := h.pathAndKey(args)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.configService.Get(ctx, path, key, stringArg(args, "format"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Config read failed", err, map[string]any{"path": path, "key": key})
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SetValue writes the value at a key path
+func (h *ConfigHandlerImpl) SetValue(ctx context.Context, args map[string]any) (*resources.ConfigChange, error) {
+	path, key, err := h.pathAndKey(args)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := args["value"]
+	if !ok {
+		logging.FromContext(ctx, h.logger).Error("Missing value argument", fmt.Errorf("missing value argument"), map[string]any{"args": args})
+		return nil, fmt.Errorf("missing value argument")
+	}
+
+	result, err := h.configService.Set(ctx, path, key, value, editOptions(args))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Config update failed", err, map[string]any{"path": path, "key": key})
+		return nil, err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Config value set", map[string]any{"path": path, "key": key, "dry_run": result.DryRun})
+	return result, nil
+}
+
+// DeleteValue removes the value at a key path
+func (h *ConfigHandlerImpl) DeleteValue(ctx context.Context, args map[string]any) (*resources.ConfigChange, error) {
+	path, key, err := h.pathAndKey(args)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.configService.Delete(ctx, path, key, editOptions(args))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Config delete failed", err, map[string]any{"path": path, "key": key})
+		return nil, err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Config value deleted", map[string]any{"path": path, "key": key, "dry_run": result.DryRun})
+	return result, nil
+}
+
+// pathAndKey extracts the path and key arguments
+func (h *ConfigHandlerImpl) pathAndKey(args map[string]any) (string, string, error) {
+	path, ok := args["path"].(string)
+	if !ok {
+		h.logger.Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return "", "", fmt.Errorf("invalid path argument")
+	}
+	key, ok := args["key"].(string)
+	if !ok {
+		h.logger.Error("Invalid key argument", fmt.Errorf("invalid key argument"), map[string]any{"args": args})
+		return "", "", fmt.Errorf("invalid key argument")
+	}
+	return path, key, nil
+}
+
+// editOptions builds edit options from request arguments
+func editOptions(args map[string]any) domainconfig.EditOptions {
+	return domainconfig.EditOptions{
+		Format: stringArg(args, "format"),
+		Create: boolArg(args, "create"),
+		DryRun: boolArg(args, "dry_run"),
+	}
+}
@@ -3,17 +3,30 @@ package core
 import (
 	"context"
 	"fmt"
+	"os"
 
 	appfile "mini-mcp/internal/application/file"
+	domainfile "mini-mcp/internal/domain/file"
 	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
 )
 
 // FileHandler handles file operation requests
 type FileHandler interface {
 	ReadFile(ctx context.Context, args map[string]any) (string, error)
 	WriteFile(ctx context.Context, args map[string]any) (string, error)
-	ListDirectory(ctx context.Context, args map[string]any) (string, error)
+	ListDirectory(ctx context.Context, args map[string]any) (*resources.DirectoryListing, error)
 	DeleteFile(ctx context.Context, args map[string]any) (string, error)
+	CreateDirectory(ctx context.Context, args map[string]any) (string, error)
+	MovePath(ctx context.Context, args map[string]any) (string, error)
+	CopyPath(ctx context.Context, args map[string]any) (*resources.CopyResult, error)
+	ChangeMode(ctx context.Context, args map[string]any) (string, error)
+	ChangeOwner(ctx context.Context, args map[string]any) (string, error)
+	StatPath(ctx context.Context, args map[string]any) (*resources.FileStat, error)
+	Checksum(ctx context.Context, args map[string]any) (*resources.FileChecksum, error)
+	CreateArchive(ctx context.Context, args map[string]any) (*resources.ArchiveResult, error)
+	ExtractArchive(ctx context.Context, args map[string]any) (*resources.ArchiveResult, error)
+	TailFile(ctx context.Context, args map[string]any) (*resources.FileTail, error)
 }
 
 // FileHandlerImpl implements the FileHandler interface
@@ -34,17 +47,17 @@ func NewFileHandler(fileService appfile.Service, logger logging.Logger) FileHand
 func (h *FileHandlerImpl) ReadFile(ctx context.Context, args map[string]any) (string, error) {
 	path, ok := args["path"].(string)
 	if !ok {
-		h.logger.Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
 		return "", fmt.Errorf("invalid path argument")
 	}
 
 	result, err := h.fileService.ReadFile(ctx, path)
 	if err != nil {
-		h.logger.Error("File read failed", err, map[string]any{"path": path})
+		logging.FromContext(ctx, h.logger).Error("File read failed", err, map[string]any{"path": path})
 		return "", err
 	}
 
-	h.logger.Info("File read successfully", map[string]any{"path": path})
+	logging.FromContext(ctx, h.logger).Info("File read successfully", map[string]any{"path": path})
 	return result, nil
 }
 
@@ -52,41 +65,55 @@ func (h *FileHandlerImpl) ReadFile(ctx context.Context, args map[string]any) (st
 func (h *FileHandlerImpl) WriteFile(ctx context.Context, args map[string]any) (string, error) {
 	path, ok := args["path"].(string)
 	if !ok {
-		h.logger.Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
 		return "", fmt.Errorf("invalid path argument")
 	}
 
 	content, ok := args["content"].(string)
 	if !ok {
-		h.logger.Error("Invalid content argument", fmt.Errorf("invalid content argument"), map[string]any{"args": args})
+		logging.FromContext(ctx, h.logger).Error("Invalid content argument", fmt.Errorf("invalid content argument"), map[string]any{"args": args})
 		return "", fmt.Errorf("invalid content argument")
 	}
 
 	err := h.fileService.WriteFile(ctx, path, content)
 	if err != nil {
-		h.logger.Error("File write failed", err, map[string]any{"path": path})
+		logging.FromContext(ctx, h.logger).Error("File write failed", err, map[string]any{"path": path})
 		return "", err
 	}
 
-	h.logger.Info("File written successfully", map[string]any{"path": path})
+	logging.FromContext(ctx, h.logger).Info("File written successfully", map[string]any{"path": path})
 	return "File written successfully", nil
 }
 
-// ListDirectory lists directory contents
-func (h *FileHandlerImpl) ListDirectory(ctx context.Context, args map[string]any) (string, error) {
+// ListDirectory lists directory contents with metadata
+func (h *FileHandlerImpl) ListDirectory(ctx context.Context, args map[string]any) (*resources.DirectoryListing, error) {
 	path, ok := args["path"].(string)
 	if !ok {
-		h.logger.Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
-		return "", fmt.Errorf("invalid path argument")
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return nil, fmt.Errorf("invalid path argument")
+	}
+
+	opts := domainfile.ListOptions{
+		Recursive:     boolArg(args, "recursive"),
+		MaxDepth:      intArg(args, "max_depth"),
+		IncludeHidden: boolArg(args, "include_hidden"),
+		SortBy:        stringArg(args, "sort_by"),
+		Reverse:       boolArg(args, "reverse"),
+		Offset:        intArg(args, "offset"),
+		Limit:         intArg(args, "limit"),
+		DirSizes:      boolArg(args, "dir_sizes"),
 	}
 
-	result, err := h.fileService.ListDirectory(ctx, path)
+	result, err := h.fileService.ListDirectory(ctx, path, opts)
 	if err != nil {
-		h.logger.Error("Directory listing failed", err, map[string]any{"path": path})
-		return "", err
+		logging.FromContext(ctx, h.logger).Error("Directory listing failed", err, map[string]any{"path": path})
+		return nil, err
 	}
 
-	h.logger.Info("Directory listed successfully", map[string]any{"path": path})
+	logging.FromContext(ctx, h.logger).Info("Directory listed successfully", map[string]any{
+		"path":    path,
+		"entries": len(result.Entries),
+	})
 	return result, nil
 }
 
@@ -94,16 +121,286 @@ func (h *FileHandlerImpl) ListDirectory(ctx context.Context, args map[string]any
 func (h *FileHandlerImpl) DeleteFile(ctx context.Context, args map[string]any) (string, error) {
 	path, ok := args["path"].(string)
 	if !ok {
-		h.logger.Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
 		return "", fmt.Errorf("invalid path argument")
 	}
 
 	err := h.fileService.DeleteFile(ctx, path)
 	if err != nil {
-		h.logger.Error("File deletion failed", err, map[string]any{"path": path})
+		logging.FromContext(ctx, h.logger).Error("File deletion failed", err, map[string]any{"path": path})
 		return "", err
 	}
 
-	h.logger.Info("File deleted successfully", map[string]any{"path": path})
+	logging.FromContext(ctx, h.logger).Info("File deleted successfully", map[string]any{"path": path})
 	return "File deleted successfully", nil
 }
+
+// CreateDirectory creates a directory and its parents
+func (h *FileHandlerImpl) CreateDirectory(ctx context.Context, args map[string]any) (string, error) {
+	path, ok := args["path"].(string)
+	if !ok {
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return "", fmt.Errorf("invalid path argument")
+	}
+
+	var mode os.FileMode
+	if modeStr := stringArg(args, "mode"); modeStr != "" {
+		parsed, err := domainfile.ParseMode(modeStr)
+		if err != nil {
+			return "", err
+		}
+		mode = parsed
+	}
+
+	if err := h.fileService.CreateDirectory(ctx, path, mode); err != nil {
+		logging.FromContext(ctx, h.logger).Error("Directory creation failed", err, map[string]any{"path": path})
+		return "", err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Directory created successfully", map[string]any{"path": path})
+	return "Directory created successfully", nil
+}
+
+// MovePath moves or renames a file or directory
+func (h *FileHandlerImpl) MovePath(ctx context.Context, args map[string]any) (string, error) {
+	src, dst, err := h.sourceAndDestination(args)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.fileService.MovePath(ctx, src, dst, boolArg(args, "overwrite")); err != nil {
+		logging.FromContext(ctx, h.logger).Error("Move failed", err, map[string]any{"source": src, "destination": dst})
+		return "", err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Path moved successfully", map[string]any{"source": src, "destination": dst})
+	return fmt.Sprintf("Moved %s to %s", src, dst), nil
+}
+
+// CopyPath copies a file or directory tree
+func (h *FileHandlerImpl) CopyPath(ctx context.Context, args map[string]any) (*resources.CopyResult, error) {
+	src, dst, err := h.sourceAndDestination(args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := domainfile.CopyOptions{
+		Recursive:     boolArg(args, "recursive"),
+		Overwrite:     boolArg(args, "overwrite"),
+		PreserveOwner: boolArg(args, "preserve_owner"),
+	}
+
+	result, err := h.fileService.CopyPath(ctx, src, dst, opts)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Copy failed", err, map[string]any{"source": src, "destination": dst})
+		return nil, err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Path copied successfully", map[string]any{"source": src, "destination": result.Destination})
+	return result, nil
+}
+
+// ChangeMode changes permission bits
+func (h *FileHandlerImpl) ChangeMode(ctx context.Context, args map[string]any) (string, error) {
+	path, ok := args["path"].(string)
+	if !ok {
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return "", fmt.Errorf("invalid path argument")
+	}
+
+	mode, err := domainfile.ParseMode(stringArg(args, "mode"))
+	if err != nil {
+		return "", err
+	}
+
+	changed, err := h.fileService.ChangeMode(ctx, path, mode, boolArg(args, "recursive"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Chmod failed", err, map[string]any{"path": path})
+		return "", err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Permissions changed successfully", map[string]any{"path": path, "changed": changed})
+	return fmt.Sprintf("Permissions changed on %d path(s)", changed), nil
+}
+
+// ChangeOwner changes the owning user and group
+func (h *FileHandlerImpl) ChangeOwner(ctx context.Context, args map[string]any) (string, error) {
+	path, ok := args["path"].(string)
+	if !ok {
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return "", fmt.Errorf("invalid path argument")
+	}
+
+	changed, err := h.fileService.ChangeOwner(ctx, path, stringArg(args, "owner"), stringArg(args, "group"), boolArg(args, "recursive"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Chown failed", err, map[string]any{"path": path})
+		return "", err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Ownership changed successfully", map[string]any{"path": path, "changed": changed})
+	return fmt.Sprintf("Ownership changed on %d path(s)", changed), nil
+}
+
+// StatPath returns detailed metadata for a path
+func (h *FileHandlerImpl) StatPath(ctx context.Context, args map[string]any) (*resources.FileStat, error) {
+	path, ok := args["path"].(string)
+	if !ok {
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return nil, fmt.Errorf("invalid path argument")
+	}
+
+	result, err := h.fileService.StatPath(ctx, path)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Stat failed", err, map[string]any{"path": path})
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Checksum computes a file digest
+func (h *FileHandlerImpl) Checksum(ctx context.Context, args map[string]any) (*resources.FileChecksum, error) {
+	path, ok := args["path"].(string)
+	if !ok {
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return nil, fmt.Errorf("invalid path argument")
+	}
+
+	result, err := h.fileService.Checksum(ctx, path, stringArg(args, "algorithm"))
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Checksum failed", err, map[string]any{"path": path})
+		return nil, err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Checksum computed successfully", map[string]any{"path": path, "algorithm": result.Algorithm})
+	return result, nil
+}
+
+// CreateArchive packs a file or directory into an archive
+func (h *FileHandlerImpl) CreateArchive(ctx context.Context, args map[string]any) (*resources.ArchiveResult, error) {
+	src, archive, err := h.sourceAndDestination(args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := domainfile.ArchiveOptions{
+		Format:    stringArg(args, "format"),
+		Include:   stringSliceArg(args, "include"),
+		Exclude:   stringSliceArg(args, "exclude"),
+		Overwrite: boolArg(args, "overwrite"),
+	}
+
+	result, err := h.fileService.CreateArchive(ctx, src, archive, opts)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Archive creation failed", err, map[string]any{"source": src, "archive": archive})
+		return nil, err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Archive created successfully", map[string]any{"archive": archive, "entries": result.Entries})
+	return result, nil
+}
+
+// ExtractArchive unpacks an archive into a directory
+func (h *FileHandlerImpl) ExtractArchive(ctx context.Context, args map[string]any) (*resources.ArchiveResult, error) {
+	archive, dest, err := h.sourceAndDestination(args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := domainfile.ExtractOptions{
+		Format:     stringArg(args, "format"),
+		MaxBytes:   int64(intArg(args, "max_bytes")),
+		MaxEntries: intArg(args, "max_entries"),
+		Overwrite:  boolArg(args, "overwrite"),
+	}
+
+	result, err := h.fileService.ExtractArchive(ctx, archive, dest, opts)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Archive extraction failed", err, map[string]any{"archive": archive, "destination": dest})
+		return nil, err
+	}
+
+	logging.FromContext(ctx, h.logger).Info("Archive extracted successfully", map[string]any{"archive": archive, "entries": result.Entries})
+	return result, nil
+}
+
+// TailFile returns the last lines of a file or the lines appended since a cursor
+func (h *FileHandlerImpl) TailFile(ctx context.Context, args map[string]any) (*resources.FileTail, error) {
+	path, ok := args["path"].(string)
+	if !ok {
+		logging.FromContext(ctx, h.logger).Error("Invalid path argument", fmt.Errorf("invalid path argument"), map[string]any{"args": args})
+		return nil, fmt.Errorf("invalid path argument")
+	}
+
+	opts := domainfile.TailOptions{
+		Lines:  intArg(args, "lines"),
+		Cursor: stringArg(args, "cursor"),
+	}
+
+	result, err := h.fileService.TailFile(ctx, path, opts)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Tail failed", err, map[string]any{"path": path})
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// sourceAndDestination extracts the source and destination arguments
+func (h *FileHandlerImpl) sourceAndDestination(args map[string]any) (string, string, error) {
+	src, ok := args["source"].(string)
+	if !ok {
+		h.logger.Error("Invalid source argument", fmt.Errorf("invalid source argument"), map[string]any{"args": args})
+		return "", "", fmt.Errorf("invalid source argument")
+	}
+	dst, ok := args["destination"].(string)
+	if !ok {
+		h.logger.Error("Invalid destination argument", fmt.Errorf("invalid destination argument"), map[string]any{"args": args})
+		return "", "", fmt.Errorf("invalid destination argument")
+	}
+	return src, dst, nil
+}
+
+// boolArg extracts an optional boolean argument
+func boolArg(args map[string]any, key string) bool {
+	value, _ := args[key].(bool)
+	return value
+}
+
+// intArg extracts an optional integer argument, accepting JSON-decoded floats
+func intArg(args map[string]any, key string) int {
+	switch value := args[key].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	case int64:
+		return int(value)
+	default:
+		return 0
+	}
+}
+
+// stringArg extracts an optional string argument
+func stringArg(args map[string]any, key string) string {
+	value, _ := args[key].(string)
+	return value
+}
+
+// stringSliceArg extracts an optional list of strings, accepting JSON-decoded []any
+func stringSliceArg(args map[string]any, key string) []string {
+	switch value := args[key].(type) {
+	case []string:
+		return value
+	case []any:
+		out := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -32,11 +32,11 @@ func NewSystemHandler(systemService appsystem.Service, logger logging.Logger) Sy
 func (h *SystemHandlerImpl) GetSystemInfo(ctx context.Context, args map[string]any) (string, error) {
 	result, err := h.systemService.GetSystemInfo(ctx)
 	if err != nil {
-		h.logger.Error("System info retrieval failed", err, nil)
+		logging.FromContext(ctx, h.logger).Error("System info retrieval failed", err, nil)
 		return "", err
 	}
 
-	h.logger.Info("System info retrieved successfully", nil)
+	logging.FromContext(ctx, h.logger).Info("System info retrieved successfully", nil)
 	return result, nil
 }
 
@@ -44,11 +44,11 @@ func (h *SystemHandlerImpl) GetSystemInfo(ctx context.Context, args map[string]a
 func (h *SystemHandlerImpl) GetHealth(ctx context.Context, args map[string]any) (string, error) {
 	result, err := h.systemService.GetHealth(ctx)
 	if err != nil {
-		h.logger.Error("Health check failed", err, nil)
+		logging.FromContext(ctx, h.logger).Error("Health check failed", err, nil)
 		return "", err
 	}
 
-	h.logger.Info("Health check completed successfully", nil)
+	logging.FromContext(ctx, h.logger).Info("Health check completed successfully", nil)
 	return result, nil
 }
 
@@ -56,10 +56,10 @@ func (h *SystemHandlerImpl) GetHealth(ctx context.Context, args map[string]any)
 func (h *SystemHandlerImpl) GetMetrics(ctx context.Context, args map[string]any) (string, error) {
 	result, err := h.systemService.GetMetrics(ctx)
 	if err != nil {
-		h.logger.Error("Metrics retrieval failed", err, nil)
+		logging.FromContext(ctx, h.logger).Error("Metrics retrieval failed", err, nil)
 		return "", err
 	}
 
-	h.logger.Info("Metrics retrieved successfully", nil)
+	logging.FromContext(ctx, h.logger).Info("Metrics retrieved successfully", nil)
 	return result, nil
 }
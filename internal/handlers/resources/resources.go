@@ -1,25 +1,26 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"runtime"
 	"strings"
 
+	"mini-mcp/internal/registry"
 	"mini-mcp/internal/types/resources"
 )
 
 // This file contains handlers for accessing resources.
 
-// AccessResource retrieves the content of a resource by URI.
+// AccessResource retrieves the content of a resource by URI, running commands through run.
 // It returns the resource data or an error if the resource is not found.
-func AccessResource(uri string) (any, error) {
+func AccessResource(ctx context.Context, uri string, run registry.CommandRunner) (any, error) {
 	switch uri {
 	case "system/info":
-		return getSystemInfo()
+		return getSystemInfo(ctx, run)
 	case "docker/info":
-		return getDockerInfo()
+		return getDockerInfo(ctx, run)
 	case "docs/commands":
 		return getCommandDocs()
 	default:
@@ -28,7 +29,7 @@ func AccessResource(uri string) (any, error) {
 }
 
 // getSystemInfo returns basic system information.
-func getSystemInfo() (*resources.SystemInfo, error) {
+func getSystemInfo(ctx context.Context, run registry.CommandRunner) (*resources.SystemInfo, error) {
 	// Create a new SystemInfo instance
 	info := &resources.SystemInfo{
 		OS:   runtime.GOOS,
@@ -37,14 +38,14 @@ func getSystemInfo() (*resources.SystemInfo, error) {
 	}
 
 	// Get hostname
-	if hostname, err := exec.Command("hostname").Output(); err == nil {
-		info.Hostname = strings.TrimSpace(string(hostname))
+	if hostname, err := run(ctx, "hostname"); err == nil {
+		info.Hostname = strings.TrimSpace(hostname)
 	}
 
 	// Get memory info if on Linux
 	if runtime.GOOS == "linux" {
-		if memInfo, err := exec.Command("free", "-m").Output(); err == nil {
-			info.Memory = strings.TrimSpace(string(memInfo))
+		if memInfo, err := run(ctx, "free", "-m"); err == nil {
+			info.Memory = strings.TrimSpace(memInfo)
 		}
 	}
 
@@ -52,22 +53,17 @@ func getSystemInfo() (*resources.SystemInfo, error) {
 }
 
 // getDockerInfo returns Docker system information.
-func getDockerInfo() (any, error) {
-	// Check if Docker is installed
-	if _, err := exec.LookPath("docker"); err != nil {
-		return resources.NewDockerError("Docker not found on system"), nil
-	}
-
-	// Get Docker info
-	output, err := exec.Command("docker", "info", "--format", "{{json .}}").Output()
+func getDockerInfo(ctx context.Context, run registry.CommandRunner) (any, error) {
+	// Get Docker info; a missing docker binary fails here too
+	output, err := run(ctx, "docker", "info", "--format", "{{json .}}")
 	if err != nil {
 		return resources.NewDockerError(fmt.Sprintf("Failed to get Docker info: %v", err)), nil
 	}
 
 	// Parse JSON output
 	var info any
-	if err := json.Unmarshal(output, &info); err != nil {
-		return resources.NewDockerRawOutput(string(output)), nil
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		return resources.NewDockerRawOutput(output), nil
 	}
 
 	// In a real implementation, we would create a DockerInfo instance and populate its fields
@@ -0,0 +1,374 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/registry"
+)
+
+// maxBodyBytes bounds how much of an HTTP response body_regex is matched against
+const maxBodyBytes = 1 << 20
+
+// maxOutputBytes bounds the command output kept in check details
+const maxOutputBytes = 512
+
+// RecordTypes lists the DNS record types DNSCheck looks up
+var RecordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS"}
+
+// HTTPCheckOptions configure HTTPEndpointCheck
+type HTTPCheckOptions struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+	// ExpectStatus lists the accepted status codes; empty accepts any 2xx
+	ExpectStatus []int
+	// BodyRegex must match the response body when set
+	BodyRegex *regexp.Regexp
+	// TLSExpiryWarning reports the check degraded when the server certificate
+	// expires within it; an expired certificate is unhealthy
+	TLSExpiryWarning   time.Duration
+	InsecureSkipVerify bool
+}
+
+// HTTPEndpointCheck requests a URL and checks the status code, the body and
+// the expiry of the server certificate
+func HTTPEndpointCheck(opts HTTPCheckOptions) HealthCheck {
+	method := opts.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- opted into per check
+	}
+	client := &http.Client{Transport: transport}
+
+	return func(ctx context.Context) CheckResult {
+		details := map[string]any{"url": opts.URL}
+
+		req, err := http.NewRequestWithContext(ctx, method, opts.URL, nil)
+		if err != nil {
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("Failed to create request: %v", err), Details: details}
+		}
+		for name, value := range opts.Headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("HTTP check failed: %v", err), Details: details}
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				slog.Error("Failed to close response body", "error", err)
+			}
+		}()
+		details["status_code"] = resp.StatusCode
+
+		if !statusAccepted(resp.StatusCode, opts.ExpectStatus) {
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("Unexpected HTTP status: %d", resp.StatusCode), Details: details}
+		}
+
+		if opts.BodyRegex != nil {
+			body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+			if err != nil {
+				return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("Failed to read response body: %v", err), Details: details}
+			}
+			if !opts.BodyRegex.Match(body) {
+				return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("Response body does not match %q", opts.BodyRegex), Details: details}
+			}
+		}
+
+		if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+			notAfter := resp.TLS.PeerCertificates[0].NotAfter
+			remaining := time.Until(notAfter)
+			details["tls_expires_at"] = notAfter
+			details["tls_days_left"] = int(remaining.Hours() / 24)
+			switch {
+			case remaining <= 0:
+				return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("TLS certificate expired at %s", notAfter.Format(time.RFC3339)), Details: details}
+			case remaining < opts.TLSExpiryWarning:
+				return CheckResult{Status: HealthStatusDegraded, Message: fmt.Sprintf("TLS certificate expires at %s", notAfter.Format(time.RFC3339)), Details: details}
+			}
+		}
+
+		return CheckResult{Status: HealthStatusHealthy, Message: fmt.Sprintf("HTTP check successful: %d", resp.StatusCode), Details: details}
+	}
+}
+
+// statusAccepted reports whether code is one of expected, or any 2xx when expected is empty
+func statusAccepted(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 300
+	}
+	return slices.Contains(expected, code)
+}
+
+// TCPCheck connects to a host:port address
+func TCPCheck(address string) HealthCheck {
+	return func(ctx context.Context) CheckResult {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return CheckResult{
+				Status:  HealthStatusUnhealthy,
+				Message: fmt.Sprintf("TCP connection failed: %v", err),
+				Details: map[string]any{"address": address},
+			}
+		}
+		_ = conn.Close()
+		return CheckResult{
+			Status:  HealthStatusHealthy,
+			Message: fmt.Sprintf("TCP connection to %s successful", address),
+			Details: map[string]any{"address": address},
+		}
+	}
+}
+
+// DNSCheckOptions configure DNSCheck
+type DNSCheckOptions struct {
+	Host string
+	// RecordType is one of RecordTypes; empty looks up A records
+	RecordType string
+	// Expect must be among the answers when set
+	Expect string
+	// Resolver is a host:port DNS server to query instead of the system resolver
+	Resolver string
+}
+
+// DNSCheck resolves a name and optionally requires an expected answer
+func DNSCheck(opts DNSCheckOptions) HealthCheck {
+	resolver := net.DefaultResolver
+	if opts.Resolver != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, opts.Resolver)
+			},
+		}
+	}
+	recordType := opts.RecordType
+	if recordType == "" {
+		recordType = "A"
+	}
+
+	return func(ctx context.Context) CheckResult {
+		details := map[string]any{"host": opts.Host, "record_type": recordType}
+		answers, err := lookup(ctx, resolver, recordType, opts.Host)
+		if err != nil {
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("DNS lookup failed: %v", err), Details: details}
+		}
+		details["answers"] = answers
+		if len(answers) == 0 {
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("No %s records for %s", recordType, opts.Host), Details: details}
+		}
+		if opts.Expect != "" && !slices.ContainsFunc(answers, func(a string) bool {
+			return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(opts.Expect, "."))
+		}) {
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("%s does not resolve to %s", opts.Host, opts.Expect), Details: details}
+		}
+		return CheckResult{Status: HealthStatusHealthy, Message: fmt.Sprintf("%s resolves to %s", opts.Host, strings.Join(answers, ", ")), Details: details}
+	}
+}
+
+// lookup returns the answers of one record type as strings
+func lookup(ctx context.Context, resolver *net.Resolver, recordType, host string) ([]string, error) {
+	var answers []string
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case "MX":
+		records, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, mx.Host)
+		}
+	case "TXT":
+		return resolver.LookupTXT(ctx, host)
+	case "NS":
+		records, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range records {
+			answers = append(answers, ns.Host)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+	return answers, nil
+}
+
+// ProcessNameCheck requires at least minCount processes with the given name,
+// matched against the process name or the base name of its executable
+func ProcessNameCheck(collector *procfs.Collector, name string, minCount int) HealthCheck {
+	if minCount <= 0 {
+		minCount = 1
+	}
+	return func(ctx context.Context) CheckResult {
+		processes, err := collector.Processes()
+		if err != nil {
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("Failed to list processes: %v", err)}
+		}
+
+		var pids []int
+		for _, p := range processes {
+			if processMatches(p.Name, p.CommandLine, name) {
+				pids = append(pids, p.PID)
+			}
+		}
+		details := map[string]any{"process": name, "count": len(pids), "pids": pids}
+		if len(pids) < minCount {
+			return CheckResult{
+				Status:  HealthStatusUnhealthy,
+				Message: fmt.Sprintf("%d %s processes running, want at least %d", len(pids), name, minCount),
+				Details: details,
+			}
+		}
+		return CheckResult{Status: HealthStatusHealthy, Message: fmt.Sprintf("%d %s processes running", len(pids), name), Details: details}
+	}
+}
+
+// processMatches compares name with the process name, which the kernel
+// truncates to 15 bytes, and with the executable of the command line
+func processMatches(procName, commandLine, name string) bool {
+	if procName == name {
+		return true
+	}
+	if fields := strings.Fields(commandLine); len(fields) > 0 {
+		return filepath.Base(fields[0]) == name
+	}
+	return false
+}
+
+// FileFreshnessCheck requires a file to exist and, when maxAge is set, to
+// have been modified within it
+func FileFreshnessCheck(path string, maxAge time.Duration) HealthCheck {
+	return func(ctx context.Context) CheckResult {
+		info, err := os.Stat(path)
+		if err != nil {
+			return CheckResult{
+				Status:  HealthStatusUnhealthy,
+				Message: fmt.Sprintf("Cannot stat %s: %v", path, err),
+				Details: map[string]any{"path": path},
+			}
+		}
+		age := time.Since(info.ModTime())
+		details := map[string]any{
+			"path":        path,
+			"modified_at": info.ModTime(),
+			"age":         age.Round(time.Second).String(),
+			"size":        info.Size(),
+		}
+		if maxAge > 0 && age > maxAge {
+			return CheckResult{
+				Status:  HealthStatusUnhealthy,
+				Message: fmt.Sprintf("%s was last modified %s ago, more than %s", path, age.Round(time.Second), maxAge),
+				Details: details,
+			}
+		}
+		return CheckResult{Status: HealthStatusHealthy, Message: fmt.Sprintf("%s is fresh", path), Details: details}
+	}
+}
+
+// DiskUsageCheck reports the filesystem holding path degraded at warnPercent
+// used and unhealthy at critPercent used
+func DiskUsageCheck(path string, warnPercent, critPercent float64) HealthCheck {
+	return func(ctx context.Context) CheckResult {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(path, &stat); err != nil {
+			return CheckResult{
+				Status:  HealthStatusUnhealthy,
+				Message: fmt.Sprintf("Cannot stat filesystem of %s: %v", path, err),
+				Details: map[string]any{"path": path},
+			}
+		}
+		total := stat.Blocks * uint64(stat.Bsize)
+		free := stat.Bavail * uint64(stat.Bsize)
+		var used float64
+		if total > 0 {
+			used = float64(total-free) / float64(total) * 100
+		}
+		details := map[string]any{
+			"path":         path,
+			"used_percent": used,
+			"free_bytes":   free,
+			"total_bytes":  total,
+		}
+
+		switch {
+		case critPercent > 0 && used >= critPercent:
+			return CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("Disk usage of %s is %.1f%%", path, used), Details: details}
+		case warnPercent > 0 && used >= warnPercent:
+			return CheckResult{Status: HealthStatusDegraded, Message: fmt.Sprintf("Disk usage of %s is %.1f%%", path, used), Details: details}
+		}
+		return CheckResult{Status: HealthStatusHealthy, Message: fmt.Sprintf("Disk usage of %s is %.1f%%", path, used), Details: details}
+	}
+}
+
+// CommandCheck runs a command and requires it to exit with expectExit
+func CommandCheck(run registry.CommandRunner, command string, args []string, expectExit int) HealthCheck {
+	return func(ctx context.Context) CheckResult {
+		output, err := run(ctx, command, args...)
+		exitCode := 0
+		if err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				return CheckResult{
+					Status:  HealthStatusUnhealthy,
+					Message: fmt.Sprintf("Command failed to run: %v", err),
+					Details: map[string]any{"command": command},
+				}
+			}
+			exitCode = exitErr.ExitCode()
+		}
+
+		if len(output) > maxOutputBytes {
+			output = output[:maxOutputBytes]
+		}
+		details := map[string]any{"command": command, "exit_code": exitCode, "output": strings.TrimSpace(output)}
+		if exitCode != expectExit {
+			return CheckResult{
+				Status:  HealthStatusUnhealthy,
+				Message: fmt.Sprintf("%s exited with %d, want %d", command, exitCode, expectExit),
+				Details: details,
+			}
+		}
+		return CheckResult{Status: HealthStatusHealthy, Message: fmt.Sprintf("%s exited with %d", command, exitCode), Details: details}
+	}
+}
@@ -0,0 +1,268 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecksConfig(t *testing.T) {
+	cfg, err := ParseChecksConfig([]byte(`
+checks:
+  - name: api
+    type: http
+    url: https://example.com/healthz
+    body_regex: ok
+    critical: true
+  - name: resolver
+    type: dns
+    host: example.com
+    record_type: aaaa
+  - name: data
+    type: disk
+    path: /
+  - name: worker
+    type: process
+    process: worker
+    interval: 1m
+    threshold: 3
+`))
+	require.NoError(t, err)
+	require.Len(t, cfg.Checks, 4)
+	assert.Equal(t, DefaultTLSExpiryWarning, cfg.Checks[0].TLSExpiryWarning)
+	assert.NotNil(t, cfg.Checks[0].bodyRegex)
+	assert.Equal(t, "AAAA", cfg.Checks[1].RecordType)
+	assert.Equal(t, float64(DefaultDiskWarnPercent), cfg.Checks[2].WarnPercent)
+	assert.Equal(t, float64(DefaultDiskCritPercent), cfg.Checks[2].CritPercent)
+	assert.Equal(t, 1, cfg.Checks[3].MinCount)
+	assert.Equal(t, time.Minute, cfg.Checks[3].Interval)
+
+	invalid := map[string]string{
+		"unknown field":    "checks:\n  - name: a\n    type: tcp\n    adress: localhost:1\n",
+		"unknown type":     "checks:\n  - name: a\n    type: ping\n",
+		"missing name":     "checks:\n  - type: tcp\n    address: localhost:1\n",
+		"bad url":          "checks:\n  - name: a\n    type: http\n    url: ftp://example.com\n",
+		"bad status":       "checks:\n  - name: a\n    type: http\n    url: http://example.com\n    expect_status: [42]\n",
+		"bad regex":        "checks:\n  - name: a\n    type: http\n    url: http://example.com\n    body_regex: '('\n",
+		"bad address":      "checks:\n  - name: a\n    type: tcp\n    address: localhost\n",
+		"bad record type":  "checks:\n  - name: a\n    type: dns\n    host: example.com\n    record_type: SRV\n",
+		"warn above crit":  "checks:\n  - name: a\n    type: disk\n    path: /\n    warn_percent: 95\n    crit_percent: 90\n",
+		"missing command":  "checks:\n  - name: a\n    type: command\n",
+		"negative timeout": "checks:\n  - name: a\n    type: file\n    path: /tmp\n    timeout: -1s\n",
+		"duplicate":        "checks:\n  - name: a\n    type: file\n    path: /tmp\n  - name: a\n    type: file\n    path: /var\n",
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseChecksConfig([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadChecksConfig_Example(t *testing.T) {
+	cfg, err := LoadChecksConfig("../../health-checks.yaml.example")
+	require.NoError(t, err)
+	assert.Len(t, cfg.Checks, 7)
+}
+
+func TestHTTPEndpointCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		fmt.Fprint(w, `{"status": "ok"}`)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	headers := map[string]string{"X-Token": "secret"}
+
+	result := HTTPEndpointCheck(HTTPCheckOptions{URL: server.URL, Headers: headers, BodyRegex: regexp.MustCompile(`"status":\s*"ok"`)})(ctx)
+	assert.Equal(t, HealthStatusHealthy, result.Status, result.Message)
+
+	result = HTTPEndpointCheck(HTTPCheckOptions{URL: server.URL, Headers: headers, BodyRegex: regexp.MustCompile(`degraded`)})(ctx)
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+
+	result = HTTPEndpointCheck(HTTPCheckOptions{URL: server.URL + "/missing"})(ctx)
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+	assert.Equal(t, http.StatusNotFound, result.Details["status_code"])
+
+	result = HTTPEndpointCheck(HTTPCheckOptions{URL: server.URL + "/missing", ExpectStatus: []int{http.StatusNotFound}})(ctx)
+	assert.Equal(t, HealthStatusHealthy, result.Status)
+}
+
+func TestHTTPEndpointCheck_TLSExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	ctx := context.Background()
+
+	result := HTTPEndpointCheck(HTTPCheckOptions{URL: server.URL, InsecureSkipVerify: true, TLSExpiryWarning: time.Hour})(ctx)
+	assert.Equal(t, HealthStatusHealthy, result.Status, result.Message)
+	assert.Contains(t, result.Details, "tls_expires_at")
+
+	// The test certificate expires decades from now; a longer warning period reports it degraded
+	result = HTTPEndpointCheck(HTTPCheckOptions{URL: server.URL, InsecureSkipVerify: true, TLSExpiryWarning: 200 * 365 * 24 * time.Hour})(ctx)
+	assert.Equal(t, HealthStatusDegraded, result.Status)
+
+	// Without skipping verification the self-signed certificate is rejected
+	result = HTTPEndpointCheck(HTTPCheckOptions{URL: server.URL})(ctx)
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+}
+
+func TestTCPCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	assert.Equal(t, HealthStatusHealthy, TCPCheck(address)(context.Background()).Status)
+	require.NoError(t, listener.Close())
+	assert.Equal(t, HealthStatusUnhealthy, TCPCheck(address)(context.Background()).Status)
+}
+
+func TestProcessNameCheck(t *testing.T) {
+	collector := procfs.NewCollector(procfs.DefaultRoot)
+	if _, err := collector.Process(os.Getpid()); err != nil {
+		t.Skip("proc filesystem not available")
+	}
+	name := filepath.Base(os.Args[0])
+
+	result := ProcessNameCheck(collector, name, 1)(context.Background())
+	assert.Equal(t, HealthStatusHealthy, result.Status, result.Message)
+	assert.Contains(t, result.Details["pids"], os.Getpid())
+
+	result = ProcessNameCheck(collector, name, 1000)(context.Background())
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+	result = ProcessNameCheck(collector, "no-such-process", 1)(context.Background())
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+}
+
+func TestFileFreshnessCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	ctx := context.Background()
+
+	assert.Equal(t, HealthStatusUnhealthy, FileFreshnessCheck(path, time.Hour)(ctx).Status)
+
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0o644))
+	assert.Equal(t, HealthStatusHealthy, FileFreshnessCheck(path, time.Hour)(ctx).Status)
+
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	assert.Equal(t, HealthStatusUnhealthy, FileFreshnessCheck(path, time.Hour)(ctx).Status)
+	assert.Equal(t, HealthStatusHealthy, FileFreshnessCheck(path, 0)(ctx).Status, "no max_age only requires the file to exist")
+}
+
+func TestDiskUsageCheck(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	result := DiskUsageCheck(dir, 0, 0)(ctx)
+	require.Equal(t, HealthStatusHealthy, result.Status)
+	used := result.Details["used_percent"].(float64)
+	if used == 0 {
+		t.Skip("filesystem reports no usage")
+	}
+
+	assert.Equal(t, HealthStatusDegraded, DiskUsageCheck(dir, used/2, 0)(ctx).Status)
+	assert.Equal(t, HealthStatusUnhealthy, DiskUsageCheck(dir, used/4, used/2)(ctx).Status)
+	assert.Equal(t, HealthStatusUnhealthy, DiskUsageCheck(filepath.Join(dir, "missing"), 80, 90)(ctx).Status)
+}
+
+func TestCommandCheck(t *testing.T) {
+	// run behaves like the command executor, wrapping the exec error
+	run := func(ctx context.Context, command string, args ...string) (string, error) {
+		output, err := exec.CommandContext(ctx, command, args...).Output()
+		if err != nil {
+			return "", fmt.Errorf("system command failed: %w", err)
+		}
+		return string(output), nil
+	}
+	ctx := context.Background()
+
+	result := CommandCheck(run, "sh", []string{"-c", "echo ready"}, 0)(ctx)
+	assert.Equal(t, HealthStatusHealthy, result.Status, result.Message)
+	assert.Equal(t, "ready", result.Details["output"])
+
+	result = CommandCheck(run, "sh", []string{"-c", "exit 3"}, 0)(ctx)
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+	assert.Equal(t, 3, result.Details["exit_code"])
+
+	assert.Equal(t, HealthStatusHealthy, CommandCheck(run, "sh", []string{"-c", "exit 3"}, 3)(ctx).Status)
+
+	denied := func(ctx context.Context, command string, args ...string) (string, error) {
+		return "", fmt.Errorf("system command not allowed: %s", command)
+	}
+	result = CommandCheck(denied, "rm", nil, 0)(ctx)
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+	assert.Contains(t, result.Message, "not allowed")
+}
+
+func TestAddConfiguredChecks_AndRunChecks(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	cfg, err := ParseChecksConfig([]byte(`
+disable_defaults: true
+checks:
+  - name: listener
+    type: tcp
+    address: ` + listener.Addr().String() + `
+    critical: true
+  - name: stale
+    type: file
+    path: /nonexistent/file
+  - name: script
+    type: command
+    command: true
+`))
+	require.NoError(t, err)
+
+	_, err = CreateConfiguredHealthChecker("test", cfg, CheckDeps{})
+	assert.Error(t, err, "command checks need a runner")
+
+	_, err = CreateConfiguredHealthChecker("test", cfg, CheckDeps{
+		Run:            func(ctx context.Context, command string, args ...string) (string, error) { return "", nil },
+		CommandAllowed: func(command string) bool { return command == "systemctl" },
+	})
+	require.Error(t, err, "commands the policy refuses fail to load")
+	assert.Contains(t, err.Error(), `command "true" is not allowed`)
+
+	checker, err := CreateConfiguredHealthChecker("test", cfg, CheckDeps{
+		Run: func(ctx context.Context, command string, args ...string) (string, error) { return "", nil },
+	})
+	require.NoError(t, err)
+
+	info, err := checker.RunChecks(context.Background(), []string{"listener", "script"})
+	require.NoError(t, err)
+	assert.Equal(t, HealthStatusHealthy, info.Status)
+	assert.Contains(t, info.Checks, "script")
+	assert.NotContains(t, info.Checks, "stale")
+	require.Len(t, info.Dependencies, 1)
+	assert.True(t, info.Dependencies[0].Critical)
+
+	info, err = checker.RunChecks(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, HealthStatusUnhealthy, info.Status)
+	assert.Len(t, info.Checks, 2)
+
+	history, ok := checker.History("listener")
+	require.True(t, ok)
+	assert.Len(t, history, 2)
+
+	_, err = checker.RunChecks(context.Background(), []string{"missing"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,305 @@
+package health
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/registry"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Check types of a checks file
+const (
+	CheckTypeHTTP    = "http"
+	CheckTypeTCP     = "tcp"
+	CheckTypeDNS     = "dns"
+	CheckTypeProcess = "process"
+	CheckTypeFile    = "file"
+	CheckTypeDisk    = "disk"
+	CheckTypeCommand = "command"
+)
+
+// Disk thresholds used when a disk check sets neither
+const (
+	DefaultDiskWarnPercent = 80
+	DefaultDiskCritPercent = 90
+)
+
+// DefaultTLSExpiryWarning is how long before certificate expiry an https check turns degraded
+const DefaultTLSExpiryWarning = 14 * 24 * time.Hour
+
+// CheckTypes lists the check types of a checks file
+var CheckTypes = []string{CheckTypeHTTP, CheckTypeTCP, CheckTypeDNS, CheckTypeProcess, CheckTypeFile, CheckTypeDisk, CheckTypeCommand}
+
+// ChecksConfig is a file of health checks added to the defaults
+type ChecksConfig struct {
+	// DisableDefaults leaves out the built-in ping, process, system and filesystem checks
+	DisableDefaults bool          `yaml:"disable_defaults"`
+	Checks          []CheckConfig `yaml:"checks"`
+}
+
+// CheckConfig declares one health check. Type selects which of the type
+// specific fields apply.
+type CheckConfig struct {
+	Name string `yaml:"name"`
+	// Type is http, tcp, dns, process, file, disk or command
+	Type string `yaml:"type"`
+	// Interval, Timeout and Threshold are as in CheckOptions
+	Interval  time.Duration `yaml:"interval"`
+	Timeout   time.Duration `yaml:"timeout"`
+	Threshold int           `yaml:"threshold"`
+	// Critical registers the check as a critical dependency, which makes the
+	// overall status unhealthy whenever the check is
+	Critical bool `yaml:"critical"`
+
+	// http
+	URL                string            `yaml:"url"`
+	Method             string            `yaml:"method"`
+	Headers            map[string]string `yaml:"headers"`
+	ExpectStatus       []int             `yaml:"expect_status"`
+	BodyRegex          string            `yaml:"body_regex"`
+	TLSExpiryWarning   time.Duration     `yaml:"tls_expiry_warning"`
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify"`
+
+	// tcp
+	Address string `yaml:"address"`
+
+	// dns
+	Host       string `yaml:"host"`
+	RecordType string `yaml:"record_type"`
+	Expect     string `yaml:"expect"`
+	Resolver   string `yaml:"resolver"`
+
+	// process
+	Process  string `yaml:"process"`
+	MinCount int    `yaml:"min_count"`
+
+	// file and disk
+	Path   string        `yaml:"path"`
+	MaxAge time.Duration `yaml:"max_age"`
+
+	// disk
+	WarnPercent float64 `yaml:"warn_percent"`
+	CritPercent float64 `yaml:"crit_percent"`
+
+	// command
+	Command    string   `yaml:"command"`
+	Args       []string `yaml:"args"`
+	ExpectExit int      `yaml:"expect_exit"`
+
+	bodyRegex *regexp.Regexp
+}
+
+// CheckDeps are what configured checks need beyond their own settings
+type CheckDeps struct {
+	// Collector reads the process table for process checks
+	Collector *procfs.Collector
+	// Run executes command checks; commands are subject to the command allowlist
+	Run registry.CommandRunner
+	// CommandAllowed reports whether the allowlist lets Run execute a command,
+	// so a command check it would refuse fails to load instead of every run
+	CommandAllowed func(command string) bool
+}
+
+// LoadChecksConfig reads and validates a health checks file
+func LoadChecksConfig(filename string) (*ChecksConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health checks: %w", err)
+	}
+	return ParseChecksConfig(data)
+}
+
+// ParseChecksConfig parses and validates a health checks file, applying defaults
+func ParseChecksConfig(data []byte) (*ChecksConfig, error) {
+	var cfg ChecksConfig
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse health checks: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for i := range cfg.Checks {
+		check := &cfg.Checks[i]
+		if err := check.validate(); err != nil {
+			return nil, fmt.Errorf("check %d (%s): %w", i+1, check.Name, err)
+		}
+		if names[check.Name] {
+			return nil, fmt.Errorf("check %d: duplicate name %q", i+1, check.Name)
+		}
+		names[check.Name] = true
+	}
+	return &cfg, nil
+}
+
+// validate checks a check declaration and fills in defaults
+func (c *CheckConfig) validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if c.Interval < 0 || c.Timeout < 0 || c.Threshold < 0 {
+		return fmt.Errorf("interval, timeout and threshold must not be negative")
+	}
+
+	switch c.Type {
+	case CheckTypeHTTP:
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url must be an http or https URL")
+		}
+		for _, code := range c.ExpectStatus {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("expect_status must be HTTP status codes")
+			}
+		}
+		if c.BodyRegex != "" {
+			re, err := regexp.Compile(c.BodyRegex)
+			if err != nil {
+				return fmt.Errorf("invalid body_regex: %w", err)
+			}
+			c.bodyRegex = re
+		}
+		if c.TLSExpiryWarning == 0 {
+			c.TLSExpiryWarning = DefaultTLSExpiryWarning
+		}
+	case CheckTypeTCP:
+		if _, _, err := net.SplitHostPort(c.Address); err != nil {
+			return fmt.Errorf("address must be host:port")
+		}
+	case CheckTypeDNS:
+		if c.Host == "" {
+			return fmt.Errorf("host is required")
+		}
+		if c.RecordType == "" {
+			c.RecordType = "A"
+		}
+		c.RecordType = strings.ToUpper(c.RecordType)
+		if !slices.Contains(RecordTypes, c.RecordType) {
+			return fmt.Errorf("record_type must be one of: %s", strings.Join(RecordTypes, ", "))
+		}
+		if c.Resolver != "" {
+			if _, _, err := net.SplitHostPort(c.Resolver); err != nil {
+				return fmt.Errorf("resolver must be host:port")
+			}
+		}
+	case CheckTypeProcess:
+		if c.Process == "" {
+			return fmt.Errorf("process is required")
+		}
+		if c.MinCount == 0 {
+			c.MinCount = 1
+		}
+		if c.MinCount < 0 {
+			return fmt.Errorf("min_count must not be negative")
+		}
+	case CheckTypeFile:
+		if c.Path == "" {
+			return fmt.Errorf("path is required")
+		}
+		if c.MaxAge < 0 {
+			return fmt.Errorf("max_age must not be negative")
+		}
+	case CheckTypeDisk:
+		if c.Path == "" {
+			return fmt.Errorf("path is required")
+		}
+		if c.WarnPercent == 0 && c.CritPercent == 0 {
+			c.WarnPercent, c.CritPercent = DefaultDiskWarnPercent, DefaultDiskCritPercent
+		}
+		if c.WarnPercent < 0 || c.WarnPercent > 100 || c.CritPercent < 0 || c.CritPercent > 100 {
+			return fmt.Errorf("warn_percent and crit_percent must be between 0 and 100")
+		}
+		if c.WarnPercent > 0 && c.CritPercent > 0 && c.WarnPercent > c.CritPercent {
+			return fmt.Errorf("warn_percent must not exceed crit_percent")
+		}
+	case CheckTypeCommand:
+		if c.Command == "" {
+			return fmt.Errorf("command is required")
+		}
+		if c.ExpectExit < 0 || c.ExpectExit > 255 {
+			return fmt.Errorf("expect_exit must be between 0 and 255")
+		}
+	default:
+		return fmt.Errorf("type must be one of: %s", strings.Join(CheckTypes, ", "))
+	}
+	return nil
+}
+
+// Check builds the health check a declaration describes
+func (c *CheckConfig) Check(deps CheckDeps) (HealthCheck, error) {
+	switch c.Type {
+	case CheckTypeHTTP:
+		return HTTPEndpointCheck(HTTPCheckOptions{
+			URL:                c.URL,
+			Method:             c.Method,
+			Headers:            c.Headers,
+			ExpectStatus:       c.ExpectStatus,
+			BodyRegex:          c.bodyRegex,
+			TLSExpiryWarning:   c.TLSExpiryWarning,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		}), nil
+	case CheckTypeTCP:
+		return TCPCheck(c.Address), nil
+	case CheckTypeDNS:
+		return DNSCheck(DNSCheckOptions{Host: c.Host, RecordType: c.RecordType, Expect: c.Expect, Resolver: c.Resolver}), nil
+	case CheckTypeProcess:
+		if deps.Collector == nil {
+			return nil, fmt.Errorf("process checks need a process collector")
+		}
+		return ProcessNameCheck(deps.Collector, c.Process, c.MinCount), nil
+	case CheckTypeFile:
+		return FileFreshnessCheck(c.Path, c.MaxAge), nil
+	case CheckTypeDisk:
+		return DiskUsageCheck(c.Path, c.WarnPercent, c.CritPercent), nil
+	case CheckTypeCommand:
+		if deps.Run == nil {
+			return nil, fmt.Errorf("command checks need a command runner")
+		}
+		if deps.CommandAllowed != nil && !deps.CommandAllowed(c.Command) {
+			return nil, fmt.Errorf("command %q is not allowed by the security policy", c.Command)
+		}
+		return CommandCheck(deps.Run, c.Command, c.Args, c.ExpectExit), nil
+	}
+	return nil, fmt.Errorf("unknown check type %q", c.Type)
+}
+
+// AddConfiguredChecks adds the checks of a checks file. Critical checks are
+// added as critical dependencies, the others as checks.
+func (h *HealthChecker) AddConfiguredChecks(cfg *ChecksConfig, deps CheckDeps) error {
+	for i := range cfg.Checks {
+		c := &cfg.Checks[i]
+		check, err := c.Check(deps)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", c.Name, err)
+		}
+		opts := CheckOptions{Interval: c.Interval, Timeout: c.Timeout, Threshold: c.Threshold}
+		if c.Critical {
+			h.AddDependencyWithOptions(c.Name, check, true, opts)
+		} else {
+			h.AddCheckWithOptions(c.Name, check, opts)
+		}
+	}
+	return nil
+}
+
+// CreateConfiguredHealthChecker creates a health checker with the default
+// checks, unless the file disables them, and the checks of the file
+func CreateConfiguredHealthChecker(version string, cfg *ChecksConfig, deps CheckDeps) (*HealthChecker, error) {
+	checker := NewHealthChecker(version)
+	if !cfg.DisableDefaults {
+		checker = CreateDefaultHealthChecker(version)
+	}
+	if err := checker.AddConfiguredChecks(cfg, deps); err != nil {
+		return nil, err
+	}
+	return checker, nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -30,6 +31,12 @@ type CheckResult struct {
 	LastCheck time.Time      `json:"last_check"`
 	Duration  time.Duration  `json:"duration"`
 	Details   map[string]any `json:"details,omitempty"`
+	// Since is when the check entered its current status
+	Since time.Time `json:"since,omitempty"`
+	// PendingStatus is a new status seen fewer than the threshold times in a row
+	PendingStatus HealthStatus `json:"pending_status,omitempty"`
+	// Flapping is set while the check keeps changing status
+	Flapping bool `json:"flapping,omitempty"`
 }
 
 // DependencyStatus represents the status of a dependency
@@ -40,6 +47,7 @@ type DependencyStatus struct {
 	LastCheck time.Time     `json:"last_check"`
 	Duration  time.Duration `json:"duration"`
 	Critical  bool          `json:"critical"`
+	Flapping  bool          `json:"flapping,omitempty"`
 }
 
 // HealthStatus represents the overall health status
@@ -52,13 +60,22 @@ type HealthInfo struct {
 	Uptime       time.Duration          `json:"uptime"`
 }
 
-// HealthChecker provides health checking functionality
+// HealthChecker provides health checking functionality. Checks run on
+// demand in CheckHealth until Start schedules them, after which CheckHealth
+// answers from the cached results.
 type HealthChecker struct {
 	checks       map[string]HealthCheck
+	checkOptions map[string]CheckOptions
 	dependencies map[string]Dependency
+	states       map[string]*checkState
 	startTime    time.Time
 	version      string
 	mu           sync.RWMutex
+
+	// runCtx is the scheduler context; nil until Start
+	runCtx context.Context
+	// cancels stop the scheduler goroutine of each key
+	cancels map[string]context.CancelFunc
 }
 
 // HealthCheck represents a health check function
@@ -70,69 +87,142 @@ type Dependency struct {
 	Check    HealthCheck
 	Critical bool
 	Interval time.Duration
+	// Timeout and Threshold are as in CheckOptions
+	Timeout   time.Duration
+	Threshold int
 }
 
 // NewHealthChecker creates a new health checker
 func NewHealthChecker(version string) *HealthChecker {
 	return &HealthChecker{
 		checks:       make(map[string]HealthCheck),
+		checkOptions: make(map[string]CheckOptions),
 		dependencies: make(map[string]Dependency),
+		states:       make(map[string]*checkState),
+		cancels:      make(map[string]context.CancelFunc),
 		startTime:    time.Now(),
 		version:      version,
 	}
 }
 
-// AddCheck adds a health check
+// AddCheck adds a health check with the default options
 func (h *HealthChecker) AddCheck(name string, check HealthCheck) {
+	h.AddCheckWithOptions(name, check, CheckOptions{})
+}
+
+// AddCheckWithOptions adds a health check with its own interval, timeout and threshold
+func (h *HealthChecker) AddCheckWithOptions(name string, check HealthCheck, opts CheckOptions) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	h.checks[name] = check
+	h.checkOptions[name] = opts.withDefaults()
+	h.states[checkKey(name)] = newCheckState()
+	h.reschedule(checkKey(name))
+}
+
+// RemoveCheck removes a health check and stops its schedule
+func (h *HealthChecker) RemoveCheck(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.checks, name)
+	delete(h.checkOptions, name)
+	h.unschedule(checkKey(name))
 }
 
 // AddDependency adds a dependency check
 func (h *HealthChecker) AddDependency(name string, check HealthCheck, critical bool, interval time.Duration) {
+	h.AddDependencyWithOptions(name, check, critical, CheckOptions{Interval: interval})
+}
+
+// AddDependencyWithOptions adds a dependency check with its own interval, timeout and threshold
+func (h *HealthChecker) AddDependencyWithOptions(name string, check HealthCheck, critical bool, opts CheckOptions) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	opts = opts.withDefaults()
 	h.dependencies[name] = Dependency{
-		Name:     name,
-		Check:    check,
-		Critical: critical,
-		Interval: interval,
+		Name:      name,
+		Check:     check,
+		Critical:  critical,
+		Interval:  opts.Interval,
+		Timeout:   opts.Timeout,
+		Threshold: opts.Threshold,
 	}
+	h.states[dependencyKey(name)] = newCheckState()
+	h.reschedule(dependencyKey(name))
+}
+
+// RemoveDependency removes a dependency check and stops its schedule
+func (h *HealthChecker) RemoveDependency(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.dependencies, name)
+	h.unschedule(dependencyKey(name))
 }
 
-// CheckHealth performs all health checks
+// CheckHealth reports the status of every check and dependency. Before Start
+// all checks run now, concurrently; once scheduled the cached results are
+// returned and only checks that have not completed a run yet are run. A check
+// still on its first run in another goroutine is left out of the report.
 func (h *HealthChecker) CheckHealth(ctx context.Context) *HealthInfo {
+	h.mu.RLock()
+	var pending []target
+	for _, key := range h.keys() {
+		if h.runCtx == nil || !h.states[key].hasResult() {
+			pending = append(pending, h.target(key))
+		}
+	}
+	h.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, t := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.run(ctx, t)
+		}()
+	}
+	wg.Wait()
+
+	return h.snapshot()
+}
+
+// snapshot builds a report from the current check states
+func (h *HealthChecker) snapshot() *HealthInfo {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
 	checks := make(map[string]CheckResult)
 	dependencies := make([]DependencyStatus, 0)
 
-	// Perform regular health checks
-	for name, check := range h.checks {
-		start := time.Now()
-		result := check(ctx)
-		result.LastCheck = start
-		result.Duration = time.Since(start)
-		checks[name] = result
+	for name := range h.checks {
+		if result, ok := h.states[checkKey(name)].result(); ok {
+			checks[name] = result
+		}
 	}
 
-	// Perform dependency checks
-	for name, dep := range h.dependencies {
-		start := time.Now()
-		result := dep.Check(ctx)
-		dependencyStatus := DependencyStatus{
+	names := make([]string, 0, len(h.dependencies))
+	for name := range h.dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result, ok := h.states[dependencyKey(name)].result()
+		if !ok {
+			continue
+		}
+		dependencies = append(dependencies, DependencyStatus{
 			Name:      name,
 			Status:    result.Status,
 			Message:   result.Message,
-			LastCheck: start,
-			Duration:  time.Since(start),
-			Critical:  dep.Critical,
-		}
-		dependencies = append(dependencies, dependencyStatus)
+			LastCheck: result.LastCheck,
+			Duration:  result.Duration,
+			Critical:  h.dependencies[name].Critical,
+			Flapping:  result.Flapping,
+		})
 	}
 
 	// Determine overall status
@@ -162,10 +252,11 @@ func (h *HealthChecker) determineOverallStatus(checks map[string]CheckResult, de
 	degradedCount := 0
 
 	for _, check := range checks {
-		switch check.Status {
-		case HealthStatusUnhealthy:
+		switch {
+		case check.Status == HealthStatusUnhealthy:
 			unhealthyCount++
-		case HealthStatusDegraded:
+		case check.Status == HealthStatusDegraded || check.Flapping:
+			// A flapping check is at best degraded, whatever its current status
 			degradedCount++
 		}
 	}
@@ -743,8 +834,5 @@ func CreateDefaultHealthChecker(version string) *HealthChecker {
 	// Add filesystem checks for common paths
 	checker.AddCheck("filesystem", FileSystemCheck([]string{"/tmp", "/var/tmp", os.TempDir()}))
 
-	// Add network check (localhost)
-	checker.AddCheck("network", NetworkCheck("localhost", 8080, 5*time.Second))
-
 	return checker
 }
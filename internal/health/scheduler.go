@@ -0,0 +1,370 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scheduling defaults
+const (
+	DefaultCheckInterval = 30 * time.Second
+	DefaultCheckTimeout  = 10 * time.Second
+	// DefaultThreshold changes state on the first result with a new status
+	DefaultThreshold = 1
+	// HistorySize is how many results are kept per check
+	HistorySize = 50
+)
+
+const (
+	// flapWindow is how many recent results flapping detection looks at
+	flapWindow = 20
+	// flapStart and flapStop are the status changes within the window at which
+	// a check starts and stops flapping; the gap keeps the flag itself stable
+	flapStart = 6
+	flapStop  = 3
+)
+
+// CheckOptions control how a check is scheduled and how its state changes
+type CheckOptions struct {
+	// Interval is the time between runs once scheduled
+	Interval time.Duration
+	// Timeout bounds one run; a run that exceeds it counts as unhealthy
+	Timeout time.Duration
+	// Threshold is how many consecutive results with a new status it takes to change state
+	Threshold int
+}
+
+// withDefaults fills in unset options
+func (o CheckOptions) withDefaults() CheckOptions {
+	if o.Interval <= 0 {
+		o.Interval = DefaultCheckInterval
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultCheckTimeout
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = DefaultThreshold
+	}
+	return o
+}
+
+// HistoryEntry is one recorded result of a check
+type HistoryEntry struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Status    HealthStatus  `json:"status"`
+	Message   string        `json:"message"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// checkState tracks the confirmed status of a check and its recent results
+type checkState struct {
+	// current is the confirmed result; its status only changes after threshold results agree
+	current *CheckResult
+	// candidate is a new status seen streak times in a row
+	candidate HealthStatus
+	streak    int
+	flapping  bool
+	running   bool
+
+	// history is a ring of the latest results; next is the slot written next once full
+	history []HistoryEntry
+	next    int
+}
+
+// newCheckState creates the state of a check that has not run yet
+func newCheckState() *checkState {
+	return &checkState{history: make([]HistoryEntry, 0, HistorySize)}
+}
+
+// hasResult reports whether the check has completed a run
+func (s *checkState) hasResult() bool {
+	return s.current != nil
+}
+
+// result returns the confirmed result annotated with the pending status and flapping flag
+func (s *checkState) result() (CheckResult, bool) {
+	if s.current == nil {
+		return CheckResult{}, false
+	}
+	result := *s.current
+	result.PendingStatus = s.candidate
+	result.Flapping = s.flapping
+	return result, true
+}
+
+// record adds a result, changing the confirmed status once threshold
+// consecutive results agree on a new one, and updates the flapping flag
+func (s *checkState) record(r CheckResult, threshold int) {
+	entry := HistoryEntry{Timestamp: r.LastCheck, Status: r.Status, Message: r.Message, Duration: r.Duration}
+	if len(s.history) < HistorySize {
+		s.history = append(s.history, entry)
+	} else {
+		s.history[s.next] = entry
+		s.next = (s.next + 1) % HistorySize
+	}
+
+	switch {
+	case s.current == nil:
+		r.Since = r.LastCheck
+		s.current = &r
+	case r.Status == s.current.Status:
+		r.Since = s.current.Since
+		s.current = &r
+		s.candidate, s.streak = "", 0
+	default:
+		if r.Status == s.candidate {
+			s.streak++
+		} else {
+			s.candidate, s.streak = r.Status, 1
+		}
+		if s.streak >= threshold {
+			r.Since = r.LastCheck
+			s.current = &r
+			s.candidate, s.streak = "", 0
+		}
+	}
+
+	changes := s.statusChanges(flapWindow)
+	if s.flapping {
+		s.flapping = changes > flapStop
+	} else {
+		s.flapping = changes >= flapStart
+	}
+}
+
+// ordered returns the history oldest first
+func (s *checkState) ordered() []HistoryEntry {
+	out := make([]HistoryEntry, 0, len(s.history))
+	out = append(out, s.history[s.next:]...)
+	return append(out, s.history[:s.next]...)
+}
+
+// statusChanges counts status changes between consecutive results among the last n
+func (s *checkState) statusChanges(n int) int {
+	history := s.ordered()
+	if len(history) > n {
+		history = history[len(history)-n:]
+	}
+	changes := 0
+	for i := 1; i < len(history); i++ {
+		if history[i].Status != history[i-1].Status {
+			changes++
+		}
+	}
+	return changes
+}
+
+// target is a check or dependency ready to run
+type target struct {
+	key  string
+	name string
+	run  HealthCheck
+	opts CheckOptions
+}
+
+// checkKey and dependencyKey keep checks and dependencies with the same name apart
+func checkKey(name string) string      { return "check/" + name }
+func dependencyKey(name string) string { return "dependency/" + name }
+
+// keys lists every check and dependency key; the caller holds the lock
+func (h *HealthChecker) keys() []string {
+	keys := make([]string, 0, len(h.states))
+	for key := range h.states {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// target resolves a key; the caller holds the lock
+func (h *HealthChecker) target(key string) target {
+	if name, ok := strings.CutPrefix(key, "dependency/"); ok {
+		dep := h.dependencies[name]
+		return target{key: key, name: name, run: dep.Check, opts: CheckOptions{
+			Interval: dep.Interval, Timeout: dep.Timeout, Threshold: dep.Threshold,
+		}.withDefaults()}
+	}
+	name := strings.TrimPrefix(key, "check/")
+	return target{key: key, name: name, run: h.checks[name], opts: h.checkOptions[name]}
+}
+
+// Start runs every check and dependency on its own interval, concurrently,
+// until ctx is cancelled. Checks added later are scheduled as they are added.
+func (h *HealthChecker) Start(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.runCtx != nil {
+		return
+	}
+	h.runCtx = ctx
+	for _, key := range h.keys() {
+		h.reschedule(key)
+	}
+}
+
+// reschedule starts the schedule of a key once the checker runs, stopping the
+// schedule of a check it replaces; the caller holds the lock
+func (h *HealthChecker) reschedule(key string) {
+	if h.runCtx == nil {
+		return
+	}
+	if cancel, ok := h.cancels[key]; ok {
+		cancel()
+	}
+	ctx, cancel := context.WithCancel(h.runCtx)
+	h.cancels[key] = cancel
+	go h.schedule(ctx, h.target(key))
+}
+
+// unschedule stops the schedule of a key and forgets its state; the caller
+// holds the lock
+func (h *HealthChecker) unschedule(key string) {
+	if cancel, ok := h.cancels[key]; ok {
+		cancel()
+		delete(h.cancels, key)
+	}
+	delete(h.states, key)
+}
+
+// schedule runs a check immediately and then on every interval
+func (h *HealthChecker) schedule(ctx context.Context, t target) {
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.run(ctx, t)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run executes a check once within its timeout and records the result. A run
+// is skipped while the previous run of the same check is still in progress,
+// and its result is dropped when ctx ends first, since a cancelled run says
+// nothing about the health of what it checks.
+func (h *HealthChecker) run(ctx context.Context, t target) {
+	h.mu.Lock()
+	state, ok := h.states[t.key]
+	if !ok || state.running {
+		h.mu.Unlock()
+		return
+	}
+	state.running = true
+	h.mu.Unlock()
+
+	result := runWithTimeout(ctx, t)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state.running = false
+	if ctx.Err() != nil {
+		return
+	}
+	state.record(result, t.opts.Threshold)
+}
+
+// runWithTimeout runs a check, reporting it unhealthy when it panics or does
+// not return within its timeout. A check that ignores its context keeps
+// running in the background but no longer holds up the caller.
+func runWithTimeout(ctx context.Context, t target) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, t.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan CheckResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("check panicked: %v", r)}
+			}
+		}()
+		done <- t.run(ctx)
+	}()
+
+	var result CheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = CheckResult{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("check did not complete within %s", t.opts.Timeout)}
+	}
+	result.LastCheck = start
+	result.Duration = time.Since(start)
+	return result
+}
+
+// History returns the recorded results of a check, or of a dependency when
+// no check has the name, oldest first
+func (h *HealthChecker) History(name string) ([]HistoryEntry, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	state, ok := h.states[checkKey(name)]
+	if !ok {
+		state, ok = h.states[dependencyKey(name)]
+	}
+	if !ok {
+		return nil, false
+	}
+	return state.ordered(), true
+}
+
+// RunChecks runs the named checks and dependencies now, concurrently, and
+// reports their results; no names runs every one. A check whose scheduled
+// run is in progress is reported with its previous result.
+func (h *HealthChecker) RunChecks(ctx context.Context, names []string) (*HealthInfo, error) {
+	h.mu.RLock()
+	var targets []target
+	if len(names) == 0 {
+		for _, key := range h.keys() {
+			targets = append(targets, h.target(key))
+		}
+	}
+	for _, name := range names {
+		found := false
+		for _, key := range []string{checkKey(name), dependencyKey(name)} {
+			if _, ok := h.states[key]; ok {
+				targets = append(targets, h.target(key))
+				found = true
+			}
+		}
+		if !found {
+			h.mu.RUnlock()
+			return nil, fmt.Errorf("unknown health check: %s", name)
+		}
+	}
+	h.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.run(ctx, t)
+		}()
+	}
+	wg.Wait()
+
+	info := h.snapshot()
+	if len(names) == 0 {
+		return info, nil
+	}
+	for name := range info.Checks {
+		if !slices.Contains(names, name) {
+			delete(info.Checks, name)
+		}
+	}
+	info.Dependencies = slices.DeleteFunc(info.Dependencies, func(dep DependencyStatus) bool {
+		return !slices.Contains(names, dep.Name)
+	})
+	info.Status = h.determineOverallStatus(info.Checks, info.Dependencies)
+	return info, nil
+}
@@ -0,0 +1,210 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedCheck returns the statuses in order, repeating the last one
+func scriptedCheck(statuses ...HealthStatus) HealthCheck {
+	var calls atomic.Int32
+	return func(ctx context.Context) CheckResult {
+		i := int(calls.Add(1)) - 1
+		return CheckResult{Status: statuses[min(i, len(statuses)-1)], Message: string(statuses[min(i, len(statuses)-1)])}
+	}
+}
+
+func TestCheckHealth_RunsChecksConcurrently(t *testing.T) {
+	checker := NewHealthChecker("test")
+	slow := func(ctx context.Context) CheckResult {
+		time.Sleep(200 * time.Millisecond)
+		return CheckResult{Status: HealthStatusHealthy}
+	}
+	checker.AddCheck("a", slow)
+	checker.AddCheck("b", slow)
+	checker.AddDependency("c", slow, true, time.Minute)
+
+	start := time.Now()
+	info := checker.CheckHealth(context.Background())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, HealthStatusHealthy, info.Status)
+	assert.Len(t, info.Checks, 2)
+	require.Len(t, info.Dependencies, 1)
+	assert.GreaterOrEqual(t, info.Checks["a"].Duration, 200*time.Millisecond)
+}
+
+func TestCheckHealth_Timeout(t *testing.T) {
+	checker := NewHealthChecker("test")
+	block := make(chan struct{})
+	defer close(block)
+	checker.AddCheckWithOptions("hung", func(ctx context.Context) CheckResult {
+		<-block // ignores its context
+		return CheckResult{Status: HealthStatusHealthy}
+	}, CheckOptions{Timeout: 50 * time.Millisecond})
+	checker.AddCheck("panics", func(ctx context.Context) CheckResult { panic("boom") })
+
+	info := checker.CheckHealth(context.Background())
+	assert.Equal(t, HealthStatusUnhealthy, info.Status)
+	assert.Equal(t, HealthStatusUnhealthy, info.Checks["hung"].Status)
+	assert.Contains(t, info.Checks["hung"].Message, "did not complete within 50ms")
+	assert.Contains(t, info.Checks["panics"].Message, "boom")
+}
+
+func TestCheckHealth_CancelledRunIsNotRecorded(t *testing.T) {
+	checker := NewHealthChecker("test")
+	checker.AddCheck("db", func(ctx context.Context) CheckResult {
+		<-ctx.Done()
+		return CheckResult{Status: HealthStatusUnhealthy, Message: ctx.Err().Error()}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	checker.CheckHealth(ctx)
+
+	history, ok := checker.History("db")
+	require.True(t, ok)
+	assert.Empty(t, history)
+}
+
+func TestCheckHealth_Threshold(t *testing.T) {
+	checker := NewHealthChecker("test")
+	checker.AddCheckWithOptions("db", scriptedCheck(
+		HealthStatusHealthy, HealthStatusUnhealthy, HealthStatusHealthy,
+		HealthStatusUnhealthy, HealthStatusUnhealthy, HealthStatusUnhealthy,
+	), CheckOptions{Threshold: 3})
+	ctx := context.Background()
+
+	first := checker.CheckHealth(ctx).Checks["db"]
+	assert.Equal(t, HealthStatusHealthy, first.Status)
+
+	// A single bad result, interrupted by a good one, does not change state
+	for i := 0; i < 4; i++ {
+		result := checker.CheckHealth(ctx).Checks["db"]
+		assert.Equal(t, HealthStatusHealthy, result.Status, "result %d", i+2)
+		assert.Equal(t, first.Since, result.Since)
+		if i == 3 {
+			assert.Equal(t, HealthStatusUnhealthy, result.PendingStatus)
+		}
+	}
+
+	// The third consecutive unhealthy result is confirmed
+	result := checker.CheckHealth(ctx).Checks["db"]
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+	assert.Empty(t, result.PendingStatus)
+	assert.True(t, result.Since.After(first.Since))
+
+	history, ok := checker.History("db")
+	require.True(t, ok)
+	assert.Len(t, history, 6)
+	assert.Equal(t, HealthStatusUnhealthy, history[1].Status)
+}
+
+func TestCheckHealth_Flapping(t *testing.T) {
+	checker := NewHealthChecker("test")
+	var statuses []HealthStatus
+	for i := 0; i < 8; i++ {
+		statuses = append(statuses, HealthStatusHealthy, HealthStatusUnhealthy)
+	}
+	for i := 0; i < flapWindow; i++ {
+		statuses = append(statuses, HealthStatusHealthy)
+	}
+	checker.AddCheck("api", scriptedCheck(statuses...))
+	ctx := context.Background()
+
+	var info *HealthInfo
+	for i := 0; i < flapStart+1; i++ {
+		info = checker.CheckHealth(ctx)
+	}
+	assert.True(t, info.Checks["api"].Flapping)
+	assert.Equal(t, HealthStatusDegraded, info.Status, "a flapping check degrades the overall status")
+
+	for i := flapStart + 1; i < len(statuses); i++ {
+		info = checker.CheckHealth(ctx)
+	}
+	assert.False(t, info.Checks["api"].Flapping)
+	assert.Equal(t, HealthStatusHealthy, info.Status)
+
+	history, _ := checker.History("api")
+	assert.Len(t, history, min(len(statuses), HistorySize))
+}
+
+func TestStart_SchedulesChecksAndServesCachedResults(t *testing.T) {
+	checker := NewHealthChecker("test")
+	var fast, slow atomic.Int32
+	checker.AddCheckWithOptions("fast", func(ctx context.Context) CheckResult {
+		fast.Add(1)
+		return CheckResult{Status: HealthStatusHealthy}
+	}, CheckOptions{Interval: 10 * time.Millisecond})
+	checker.AddDependency("slow", func(ctx context.Context) CheckResult {
+		if slow.Add(1) > 1 {
+			time.Sleep(time.Second)
+		}
+		return CheckResult{Status: HealthStatusHealthy}
+	}, false, 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checker.Start(ctx)
+
+	require.Eventually(t, func() bool { return fast.Load() >= 5 && slow.Load() >= 2 }, 2*time.Second, 5*time.Millisecond)
+
+	// The slow dependency is mid-run, yet the report is served from the cache
+	start := time.Now()
+	info := checker.CheckHealth(ctx)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Contains(t, info.Checks, "fast")
+	require.Len(t, info.Dependencies, 1)
+	assert.Equal(t, HealthStatusHealthy, info.Dependencies[0].Status)
+
+	// Checks added after Start are scheduled too
+	var late atomic.Int32
+	checker.AddCheckWithOptions("late", func(ctx context.Context) CheckResult {
+		late.Add(1)
+		return CheckResult{Status: HealthStatusHealthy}
+	}, CheckOptions{Interval: 10 * time.Millisecond})
+	require.Eventually(t, func() bool { return late.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+	stopped := fast.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stopped, fast.Load(), "checks stop with the context")
+}
+
+func TestStart_ReplacingAndRemovingChecksStopsTheirSchedule(t *testing.T) {
+	checker := NewHealthChecker("test")
+	counter := func(n *atomic.Int32) HealthCheck {
+		return func(ctx context.Context) CheckResult {
+			n.Add(1)
+			return CheckResult{Status: HealthStatusHealthy}
+		}
+	}
+	var first, second atomic.Int32
+	opts := CheckOptions{Interval: 10 * time.Millisecond}
+	checker.AddCheckWithOptions("dup", counter(&first), opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checker.Start(ctx)
+	require.Eventually(t, func() bool { return first.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	// Re-registering the name replaces the schedule instead of adding one
+	checker.AddCheckWithOptions("dup", counter(&second), opts)
+	require.Eventually(t, func() bool { return second.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	stopped := first.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stopped, first.Load(), "the replaced check no longer runs")
+
+	checker.RemoveCheck("dup")
+	time.Sleep(30 * time.Millisecond)
+	stopped = second.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stopped, second.Load(), "the removed check no longer runs")
+	assert.NotContains(t, checker.CheckHealth(ctx).Checks, "dup")
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"mini-mcp/internal/proxmox/types"
+	"mini-mcp/internal/shared/tracing"
 )
 
 // HTTPMethod represents HTTP methods - using standard library constants
@@ -113,7 +114,7 @@ func (bc *BaseClient) doRequest(ctx context.Context, method HTTPMethod, path str
 		}
 
 		// Request logging handled by structured logging
-		resp, err := bc.httpClient.Do(req)
+		resp, err := bc.send(req, attempt)
 		if err != nil {
 			if attempt < maxRetries-1 {
 				delay := baseDelay * time.Duration(1<<attempt) // Exponential backoff
@@ -160,6 +161,34 @@ func (bc *BaseClient) doRequest(ctx context.Context, method HTTPMethod, path str
 	return nil, fmt.Errorf("max retries exceeded")
 }
 
+// send performs one attempt of a request in a client span, propagating the
+// trace to the Proxmox API in the traceparent header
+func (bc *BaseClient) send(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), "HTTP "+req.Method, tracing.SpanKindClient, map[string]any{
+		"http.request.method": req.Method,
+		"url.full":            req.URL.String(),
+		"server.address":      req.URL.Hostname(),
+	})
+	defer span.End()
+	if attempt > 0 {
+		span.SetAttribute("http.request.resend_count", attempt)
+	}
+	if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+		req.Header.Set(tracing.TraceparentKey, traceparent)
+	}
+
+	resp, err := bc.httpClient.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= 400 {
+		span.SetStatus(tracing.StatusError, fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+	return resp, nil
+}
+
 // makeRequest makes an HTTP request and returns the response body
 func (bc *BaseClient) makeRequest(ctx context.Context, method HTTPMethod, path, body, contentType string) ([]byte, error) {
 	resp, err := bc.doRequest(ctx, method, path, strings.NewReader(body))
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -36,7 +37,11 @@ func NewClient(authConfig *types.AuthConfig) interfaces.ProxmoxClient {
 		Timeout:   time.Duration(config.GetTimeout(authConfig)) * time.Second,
 	}
 
-	baseURL := fmt.Sprintf("https://%s:8006/api2/json", config.GetHost(authConfig))
+	host := config.GetHost(authConfig)
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "8006")
+	}
+	baseURL := fmt.Sprintf("https://%s/api2/json", host)
 	baseClient := NewBaseClient(baseURL, httpClient, authConfig)
 
 	return &client{
@@ -100,6 +105,20 @@ func (c *client) GetNodeStatus(ctx context.Context, nodeName string) (*types.Nod
 	return &status, err
 }
 
+// GetVersion returns the version of the Proxmox API
+func (c *client) GetVersion(ctx context.Context) (*types.Version, error) {
+	var version types.Version
+	err := c.GetAndUnmarshal(ctx, "/version", nil, &version)
+	return &version, err
+}
+
+// GetClusterResources returns the nodes, guests and storage of the cluster
+func (c *client) GetClusterResources(ctx context.Context) ([]types.ClusterResource, error) {
+	var resources []types.ClusterResource
+	err := c.GetListAndUnmarshal(ctx, "/cluster/resources", nil, &resources)
+	return resources, err
+}
+
 // GetVMs returns a list of all VMs on a specific node
 func (c *client) GetVMs(ctx context.Context, nodeName string) ([]types.VM, error) {
 	var vms []types.VM
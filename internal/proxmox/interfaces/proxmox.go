@@ -18,6 +18,10 @@ type ProxmoxClient interface {
 	GetNodes(ctx context.Context) ([]types.Node, error)
 	GetNodeStatus(ctx context.Context, nodeName string) (*types.NodeStatus, error)
 
+	// Cluster operations
+	GetVersion(ctx context.Context) (*types.Version, error)
+	GetClusterResources(ctx context.Context) ([]types.ClusterResource, error)
+
 	// VM operations
 	GetVMs(ctx context.Context, nodeName string) ([]types.VM, error)
 	GetVM(ctx context.Context, nodeName string, vmid int) (*types.VM, error)
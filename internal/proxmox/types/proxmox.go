@@ -29,6 +29,32 @@ type NodeStatus struct {
 	LoadAvg []float64 `json:"loadavg"`
 }
 
+// Version represents the version of the Proxmox API
+type Version struct {
+	Version string `json:"version"`
+	Release string `json:"release"`
+	RepoID  string `json:"repoid"`
+}
+
+// ClusterResource represents an entry of the cluster resources; CPU is a fraction of MaxCPU
+type ClusterResource struct {
+	Type       string  `json:"type"`
+	ID         string  `json:"id"`
+	VMID       int     `json:"vmid"`
+	Name       string  `json:"name"`
+	Node       string  `json:"node"`
+	Storage    string  `json:"storage"`
+	PluginType string  `json:"plugintype"`
+	Status     string  `json:"status"`
+	CPU        float64 `json:"cpu"`
+	MaxCPU     float64 `json:"maxcpu"`
+	Mem        uint64  `json:"mem"`
+	MaxMem     uint64  `json:"maxmem"`
+	Disk       uint64  `json:"disk"`
+	MaxDisk    uint64  `json:"maxdisk"`
+	Uptime     uint64  `json:"uptime"`
+}
+
 // VM represents a virtual machine
 type VM struct {
 	VMID   int    `json:"vmid"`
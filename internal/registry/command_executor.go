@@ -3,15 +3,20 @@ package registry
 import (
 	"context"
 	"fmt"
+	"io"
 	"os/exec"
-	"strconv"
-	"strings"
 	"time"
 
 	"mini-mcp/internal/shared/logging"
 	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/shared/tracing"
 )
 
+// CommandRunner runs a command and returns its standard output, as
+// ExecuteSystemCommand does. A command that exits non-zero returns an error
+// wrapping *exec.ExitError.
+type CommandRunner func(ctx context.Context, command string, args ...string) (string, error)
+
 // CommandExecutor provides common command execution patterns
 type CommandExecutor struct {
 	security *security.SecureCommandExecutor
@@ -29,7 +34,7 @@ func NewCommandExecutor(security *security.SecureCommandExecutor, logger logging
 // ExecuteCommand executes a command with common security and logging
 func (ce *CommandExecutor) ExecuteCommand(ctx context.Context, command string, timeout int) (string, error) {
 	// Validate command security
-	if !ce.security.IsCommandAllowed(command) {
+	if !ce.checkCommand(ctx, command) {
 		return "", fmt.Errorf("command not allowed: %s", command)
 	}
 
@@ -43,27 +48,29 @@ func (ce *CommandExecutor) ExecuteCommand(ctx context.Context, command string, t
 	defer cancel()
 
 	// Log command execution
-	ce.logger.Info("Executing command", map[string]any{
+	ce.log(ctx).Info("Executing command", map[string]any{
 		"command": command,
 		"timeout": timeout,
 	})
 
 	// Execute command
 	start := time.Now()
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd := newCommand(ctx, "sh", "-c", command)
+	_, span := tracing.StartExec(ctx, "sh", []string{"-c", command})
 	output, err := cmd.Output()
+	tracing.EndExec(span, err)
 	duration := time.Since(start)
 
 	// Log execution result
 	if err != nil {
-		ce.logger.Error("Command execution failed", err, map[string]any{
+		ce.log(ctx).Error("Command execution failed", err, map[string]any{
 			"command":  command,
 			"duration": duration.String(),
 		})
 		return "", fmt.Errorf("command failed: %w", err)
 	}
 
-	ce.logger.Info("Command executed successfully", map[string]any{
+	ce.log(ctx).Info("Command executed successfully", map[string]any{
 		"command":       command,
 		"duration":      duration.String(),
 		"output_length": len(output),
@@ -75,13 +82,13 @@ func (ce *CommandExecutor) ExecuteCommand(ctx context.Context, command string, t
 // ExecuteSSHCommand executes a command over SSH with common patterns
 func (ce *CommandExecutor) ExecuteSSHCommand(ctx context.Context, host, command, user, port, keyPath string, timeout int) (string, error) {
 	// Validate SSH command security
-	if !ce.security.IsCommandAllowed(command) {
+	if !ce.checkCommand(ctx, command) {
 		return "", fmt.Errorf("SSH command not allowed: %s", command)
 	}
 
 	// Validate SSH key path if provided
 	if keyPath != "" {
-		if err := ce.security.ValidatePath(keyPath); err != nil {
+		if err := ce.checkPath(ctx, keyPath); err != nil {
 			return "", fmt.Errorf("SSH key path validation failed: %w", err)
 		}
 	}
@@ -111,7 +118,7 @@ func (ce *CommandExecutor) ExecuteSSHCommand(ctx context.Context, host, command,
 	defer cancel()
 
 	// Log SSH command execution
-	ce.logger.Info("Executing SSH command", map[string]any{
+	ce.log(ctx).Info("Executing SSH command", map[string]any{
 		"host":    host,
 		"command": command,
 		"user":    user,
@@ -120,13 +127,15 @@ func (ce *CommandExecutor) ExecuteSSHCommand(ctx context.Context, host, command,
 
 	// Execute SSH command
 	start := time.Now()
-	cmd := exec.CommandContext(ctx, sshCmd[0], sshCmd[1:]...)
+	cmd := newCommand(ctx, sshCmd[0], sshCmd[1:]...)
+	_, span := tracing.StartExec(ctx, sshCmd[0], sshCmd[1:])
 	output, err := cmd.Output()
+	tracing.EndExec(span, err)
 	duration := time.Since(start)
 
 	// Log execution result
 	if err != nil {
-		ce.logger.Error("SSH command execution failed", err, map[string]any{
+		ce.log(ctx).Error("SSH command execution failed", err, map[string]any{
 			"host":     host,
 			"command":  command,
 			"duration": duration.String(),
@@ -134,7 +143,7 @@ func (ce *CommandExecutor) ExecuteSSHCommand(ctx context.Context, host, command,
 		return "", fmt.Errorf("SSH command failed: %w", err)
 	}
 
-	ce.logger.Info("SSH command executed successfully", map[string]any{
+	ce.log(ctx).Info("SSH command executed successfully", map[string]any{
 		"host":          host,
 		"command":       command,
 		"duration":      duration.String(),
@@ -147,7 +156,7 @@ func (ce *CommandExecutor) ExecuteSSHCommand(ctx context.Context, host, command,
 // ExecuteDockerCompose executes Docker Compose commands with common patterns
 func (ce *CommandExecutor) ExecuteDockerCompose(ctx context.Context, path, command string, detached, removeVolumes bool) (string, error) {
 	// Validate Docker Compose path
-	if err := ce.security.ValidatePath(path); err != nil {
+	if err := ce.checkPath(ctx, path); err != nil {
 		return "", fmt.Errorf("docker compose path validation failed: %w", err)
 	}
 
@@ -185,7 +194,7 @@ func (ce *CommandExecutor) ExecuteDockerCompose(ctx context.Context, path, comma
 	}
 
 	// Log Docker Compose command execution
-	ce.logger.Info("Executing Docker Compose command", map[string]any{
+	ce.log(ctx).Info("Executing Docker Compose command", map[string]any{
 		"path":           path,
 		"command":        command,
 		"detached":       detached,
@@ -194,13 +203,15 @@ func (ce *CommandExecutor) ExecuteDockerCompose(ctx context.Context, path, comma
 
 	// Execute command
 	start := time.Now()
-	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd := newCommand(ctx, args[0], args[1:]...)
+	_, span := tracing.StartExec(ctx, args[0], args[1:])
 	output, err := cmd.Output()
+	tracing.EndExec(span, err)
 	duration := time.Since(start)
 
 	// Log execution result
 	if err != nil {
-		ce.logger.Error("Docker Compose command execution failed", err, map[string]any{
+		ce.log(ctx).Error("Docker Compose command execution failed", err, map[string]any{
 			"path":     path,
 			"command":  command,
 			"duration": duration.String(),
@@ -208,7 +219,7 @@ func (ce *CommandExecutor) ExecuteDockerCompose(ctx context.Context, path, comma
 		return "", fmt.Errorf("docker-compose command failed: %w", err)
 	}
 
-	ce.logger.Info("Docker Compose command executed successfully", map[string]any{
+	ce.log(ctx).Info("Docker Compose command executed successfully", map[string]any{
 		"path":          path,
 		"command":       command,
 		"duration":      duration.String(),
@@ -221,25 +232,27 @@ func (ce *CommandExecutor) ExecuteDockerCompose(ctx context.Context, path, comma
 // ExecuteSystemCommand executes system commands with common patterns
 func (ce *CommandExecutor) ExecuteSystemCommand(ctx context.Context, command string, args ...string) (string, error) {
 	// Validate command security
-	if !ce.security.IsCommandAllowed(command) {
+	if !ce.checkSystemCommand(ctx, command) {
 		return "", fmt.Errorf("system command not allowed: %s", command)
 	}
 
 	// Log system command execution
-	ce.logger.Info("Executing system command", map[string]any{
+	ce.log(ctx).Info("Executing system command", map[string]any{
 		"command": command,
 		"args":    args,
 	})
 
 	// Execute command
 	start := time.Now()
-	cmd := exec.CommandContext(ctx, command, args...)
+	cmd := newCommand(ctx, command, args...)
+	_, span := tracing.StartExec(ctx, command, args)
 	output, err := cmd.Output()
+	tracing.EndExec(span, err)
 	duration := time.Since(start)
 
 	// Log execution result
 	if err != nil {
-		ce.logger.Error("System command execution failed", err, map[string]any{
+		ce.log(ctx).Error("System command execution failed", err, map[string]any{
 			"command":  command,
 			"args":     args,
 			"duration": duration.String(),
@@ -247,7 +260,7 @@ func (ce *CommandExecutor) ExecuteSystemCommand(ctx context.Context, command str
 		return "", fmt.Errorf("system command failed: %w", err)
 	}
 
-	ce.logger.Info("System command executed successfully", map[string]any{
+	ce.log(ctx).Info("System command executed successfully", map[string]any{
 		"command":       command,
 		"args":          args,
 		"duration":      duration.String(),
@@ -257,55 +270,102 @@ func (ce *CommandExecutor) ExecuteSystemCommand(ctx context.Context, command str
 	return string(output), nil
 }
 
-// ParsePortNumbers parses port numbers from netstat/ss output
-func (ce *CommandExecutor) ParsePortNumbers(output string) []int {
-	ports := make([]int, 0)
-	lines := strings.Split(output, "\n")
+// StreamSystemCommand starts an allowed system command and returns its
+// standard output as a stream. Closing the stream stops the command.
+func (ce *CommandExecutor) StreamSystemCommand(ctx context.Context, command string, args ...string) (io.ReadCloser, error) {
+	if !ce.checkSystemCommand(ctx, command) {
+		return nil, fmt.Errorf("system command not allowed: %s", command)
+	}
 
-	for _, line := range lines {
-		// Skip header lines
-		if strings.Contains(line, "LISTEN") || strings.Contains(line, "Local Address") {
-			continue
-		}
+	ce.log(ctx).Info("Streaming system command", map[string]any{
+		"command": command,
+		"args":    args,
+	})
 
-		// Extract port from lines like "*:8080 *:* LISTEN"
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			address := parts[1] // Should be something like "*:8080"
-			if strings.Contains(address, ":") {
-				portStr := strings.Split(address, ":")[1]
-				if port, err := strconv.Atoi(portStr); err == nil && port > 0 {
-					ports = append(ports, port)
-				}
-			}
-		}
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := newCommand(ctx, command, args...)
+	_, span := tracing.StartExec(ctx, command, args)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		tracing.EndExec(span, err)
+		return nil, fmt.Errorf("system command failed: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		tracing.EndExec(span, err)
+		return nil, fmt.Errorf("system command failed: %w", err)
 	}
+	return &commandStream{ReadCloser: stdout, cmd: cmd, cancel: cancel, span: span}, nil
+}
 
-	return ports
+// commandStream is the output of a running command; Close stops and reaps it
+type commandStream struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	// span lasts until the stream is closed; stopping the command is not a failure
+	span *tracing.Span
 }
 
-// KillProcessGracefully kills a process by PID with graceful shutdown
-func (ce *CommandExecutor) KillProcessGracefully(ctx context.Context, pid int) bool {
-	// First try SIGTERM (graceful)
-	cmd := exec.CommandContext(ctx, "kill", "-TERM", strconv.Itoa(pid))
-	err := cmd.Run()
+// Close stops the command if it is still running and waits for it to exit
+func (s *commandStream) Close() error {
+	s.cancel()
+	_ = s.cmd.Wait()
+	s.span.End()
+	return nil
+}
 
-	if err != nil {
-		// If SIGTERM fails, try SIGKILL (forceful)
-		cmd = exec.CommandContext(ctx, "kill", "-KILL", strconv.Itoa(pid))
-		err = cmd.Run()
+// log returns the logger for the tool call ctx belongs to
+func (ce *CommandExecutor) log(ctx context.Context) logging.Logger {
+	return logging.FromContext(ctx, ce.logger)
+}
+
+// checkCommand checks a command against the security policy, logging a denial
+func (ce *CommandExecutor) checkCommand(ctx context.Context, command string) bool {
+	if ce.security.CheckCommand(ctx, command) {
+		return true
 	}
+	ce.logCommandDenied(ctx, command)
+	return false
+}
 
-	success := err == nil
-	if success {
-		ce.logger.Info("Process killed successfully", map[string]any{
-			"pid": pid,
-		})
-	} else {
-		ce.logger.Error("Failed to kill process", err, map[string]any{
-			"pid": pid,
+// checkSystemCommand checks a command a tool runs itself against the security
+// policy, logging a denial
+func (ce *CommandExecutor) checkSystemCommand(ctx context.Context, command string) bool {
+	if ce.security.CheckSystemCommand(ctx, command) {
+		return true
+	}
+	ce.logCommandDenied(ctx, command)
+	return false
+}
+
+// logCommandDenied logs a command the security policy refused
+func (ce *CommandExecutor) logCommandDenied(ctx context.Context, command string) {
+	ce.log(ctx).Warning("Command denied by policy", map[string]any{
+		"command": command,
+		"policy":  security.PolicyCommand,
+		"code":    security.ErrCodeCommandNotAllowed,
+	})
+}
+
+// checkPath checks a path against the security policy, logging a denial
+func (ce *CommandExecutor) checkPath(ctx context.Context, path string) error {
+	err := ce.security.CheckPath(ctx, path)
+	if err != nil {
+		ce.log(ctx).Warning("Path denied by policy", map[string]any{
+			"path":   path,
+			"policy": security.PolicyPath,
+			"error":  err.Error(),
 		})
 	}
+	return err
+}
 
-	return success
+// newCommand prepares a subprocess that carries the correlation ID of the
+// tool call ctx belongs to
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	return cmd
 }
@@ -3,9 +3,14 @@ package registry
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"regexp"
+	"time"
 
+	"mini-mcp/internal/shared/errors"
 	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/tracing"
 
 	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -91,9 +96,9 @@ type TypeValidationStrategy[T any] struct {
 
 // Validate validates arguments using the strategy
 func (tvs *TypeValidationStrategy[T]) Validate(args any) error {
-	typedArgs, ok := args.(T)
-	if !ok {
-		return NewValidationError("type_mismatch", "invalid argument type")
+	typedArgs, err := decodeArgs[T](args)
+	if err != nil {
+		return NewValidationError("type_mismatch", err.Error())
 	}
 	return tvs.validator(typedArgs)
 }
@@ -121,34 +126,95 @@ func RegisterTypeSafeTool[T any](tsr *TypeSafeToolRegistry, def TypeSafeToolDefi
 
 // createTypeSafeWrapper creates a wrapper with cross-cutting concerns (Decorator Pattern)
 func createTypeSafeWrapper[T any](tsr *TypeSafeToolRegistry, def TypeSafeToolDefinition[T]) func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
-	return func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args any) (result *mcp.CallToolResult, data any, err error) {
+		// Record the call count, latency and error code of every call
+		metrics := tsr.logger.GetMetrics()
+		start := time.Now()
+		errorCode := ""
+		if metrics != nil {
+			metrics.ToolCallStarted(def.Name)
+			defer func() {
+				metrics.RecordToolCall(def.Name, time.Since(start), errorCode)
+			}()
+		}
+
+		// Tag everything done for the call with its correlation ID
+		call := logging.Call{CorrelationID: correlationID(req), Tool: def.Name}
+		if req != nil && req.Session != nil {
+			call.SessionID = req.Session.ID()
+		}
+		ctx = logging.ContextWithCall(ctx, call)
+		logger := logging.FromContext(ctx, tsr.logger)
+		defer func() {
+			if result != nil {
+				if result.Meta == nil {
+					result.Meta = mcp.Meta{}
+				}
+				result.Meta[logging.CorrelationMetaKey] = call.CorrelationID
+			}
+		}()
+
+		// Trace the call as a child of the client's span, if _meta names one
+		if req != nil && req.Params != nil {
+			ctx = tracing.ContextWithMeta(ctx, req.Params.Meta)
+		}
+		ctx, span := tracing.Start(ctx, "tools/call "+def.Name, tracing.SpanKindServer, map[string]any{
+			"rpc.system":    "mcp",
+			"rpc.method":    "tools/call",
+			"mcp.tool.name": def.Name,
+		})
+		if span.IsRecording() {
+			span.SetAttribute("mcp.tool.arguments", tracing.RedactArguments(args))
+		}
+		defer func() {
+			if errorCode != "" {
+				span.SetAttribute("mcp.tool.error_code", errorCode)
+				span.SetStatus(tracing.StatusError, errorCode)
+			}
+			span.End()
+		}()
+
 		// Type-safe argument handling
-		typedArgs, ok := args.(T)
-		if !ok {
-			return createErrorResult(tsr, "type_mismatch", "invalid argument type")
+		typedArgs, decodeErr := decodeArgs[T](args)
+		if decodeErr != nil {
+			errorCode = string(errors.ErrorCodeInvalidInput)
+			return createErrorResult(tsr, "type_mismatch", decodeErr.Error())
 		}
 
 		// Validate arguments using strategy pattern
 		if def.Validator != nil {
-			if err := def.Validator(typedArgs); err != nil {
-				tsr.logger.Error("Tool validation failed", err, map[string]any{
+			_, validateSpan := tracing.Start(ctx, "validate "+def.Name, tracing.SpanKindInternal, nil)
+			err := def.Validator(typedArgs)
+			validateSpan.RecordError(err)
+			validateSpan.End()
+			if err != nil {
+				logger.Error("Tool validation failed", err, map[string]any{
 					"tool": def.Name,
 				})
+				errorCode = string(errors.ErrorCodeInvalidInput)
 				return createErrorResult(tsr, "validation_failed", err.Error())
 			}
 		}
 
 		// Execute the actual handler
-		result, data, err := def.Handler(ctx, req, typedArgs)
+		result, data, err = def.Handler(ctx, req, typedArgs)
 		if err != nil {
-			tsr.logger.Error("Tool execution failed", err, map[string]any{
+			var response *errors.ErrorResponse
+			if stderrors.As(err, &response) && response.RequestID == "" {
+				response.WithRequestID(call.CorrelationID)
+			}
+			logger.Error("Tool execution failed", err, map[string]any{
 				"tool": def.Name,
 			})
+			errorCode = string(resultErrorCode(err.Error()))
 			return createErrorResult(tsr, "execution_failed", err.Error())
 		}
+		if result != nil && result.IsError {
+			errorCode = string(resultErrorCode(resultText(result)))
+		}
 
 		// Log successful execution
-		tsr.logger.Info("Tool executed successfully", map[string]any{
+		logger.Info("Tool executed successfully", map[string]any{
 			"tool": def.Name,
 		})
 
@@ -168,6 +234,42 @@ func RegisterTypeSafeSimpleTool[T any](tsr *TypeSafeToolRegistry, name, descript
 
 // ===== UTILITY FUNCTIONS =====
 
+// decodeArgs converts tool arguments into T. The SDK hands the wrapper the
+// arguments as decoded JSON (a map[string]any), so they are round-tripped
+// through JSON into the tool's argument type.
+func decodeArgs[T any](args any) (T, error) {
+	var typed T
+	if v, ok := args.(T); ok {
+		return v, nil
+	}
+	if args == nil {
+		return typed, nil
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return typed, fmt.Errorf("invalid argument type: %w", err)
+	}
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return typed, fmt.Errorf("invalid argument type: %w", err)
+	}
+	return typed, nil
+}
+
+// correlationIDPattern limits client-supplied correlation IDs to what is safe
+// in log lines and environment variables
+var correlationIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:/-]{1,128}$`)
+
+// correlationID returns the correlation ID the client or the transport put
+// in the call's _meta, or a new one when there is none or it is malformed
+func correlationID(req *mcp.CallToolRequest) string {
+	if req != nil && req.Params != nil {
+		if id, ok := req.Params.Meta[logging.CorrelationMetaKey].(string); ok && correlationIDPattern.MatchString(id) {
+			return id
+		}
+	}
+	return logging.NewCorrelationID()
+}
+
 // createErrorResult creates a standardized error result
 func createErrorResult(tsr *TypeSafeToolRegistry, code, message string) (*mcp.CallToolResult, any, error) {
 	return &mcp.CallToolResult{
@@ -176,16 +278,40 @@ func createErrorResult(tsr *TypeSafeToolRegistry, code, message string) (*mcp.Ca
 	}, nil, nil
 }
 
+// resultErrorCode classifies a failed call by the error code in its error text
+func resultErrorCode(text string) errors.ErrorCode {
+	if code, ok := errors.ParseErrorCode(text); ok {
+		return code
+	}
+	return errors.ErrorCodeInternalError
+}
+
+// resultText returns the text of the first text content of a result
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
 // CreateErrorResult creates a standardized error result (public method)
-func (tsr *TypeSafeToolRegistry) CreateErrorResult(message string, details map[string]any) (*mcp.CallToolResult, any, error) {
-	tsr.logger.Error("Tool error", fmt.Errorf("%s", message), map[string]any{
+func (tsr *TypeSafeToolRegistry) CreateErrorResult(ctx context.Context, message string, details map[string]any) (*mcp.CallToolResult, any, error) {
+	logging.FromContext(ctx, tsr.logger).Error("Tool error", fmt.Errorf("%s", message), map[string]any{
 		"message": message,
 		"details": details,
 	})
 
+	// The structured error carries the correlation ID of the call, so that a
+	// client can quote it when reporting the error
+	response := errors.NewErrorResponse(resultErrorCode(message), message).
+		WithDetails(details).
+		WithRequestID(logging.CorrelationID(ctx))
 	return &mcp.CallToolResult{
-		IsError: true,
-		Content: []mcp.Content{&mcp.TextContent{Text: message}},
+		IsError:           true,
+		Content:           []mcp.Content{&mcp.TextContent{Text: message}},
+		StructuredContent: response,
 	}, nil, nil
 }
 
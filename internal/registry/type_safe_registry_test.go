@@ -1,12 +1,22 @@
 package registry
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mini-mcp/internal/shared/errors"
 	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+	"mini-mcp/internal/shared/tracing"
 
 	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -82,12 +92,29 @@ func TestTypeSafeToolRegistry_CreateErrorResult(t *testing.T) {
 	logger := logging.NewLogger(os.Stderr, logging.LogLevel("INFO"))
 	registry := NewTypeSafeToolRegistry(server, logger)
 
-	result, data, err := registry.CreateErrorResult("test error", map[string]any{"key": "value"})
+	result, data, err := registry.CreateErrorResult(context.Background(), "test error", map[string]any{"key": "value"})
 
 	assert.NoError(t, err) // CreateErrorResult doesn't return an error, it returns a result
 	assert.Nil(t, data)
 	assert.NotNil(t, result)
 	assert.True(t, result.IsError)
+
+	// The structured error names the call's correlation ID
+	ctx := logging.ContextWithCall(context.Background(), logging.Call{CorrelationID: "c1", Tool: "ssh"})
+	result, _, _ = registry.CreateErrorResult(ctx, "[COMMAND_BLOCKED] reboot is not allowed", map[string]any{"command": "reboot"})
+	response, ok := result.StructuredContent.(*errors.ErrorResponse)
+	require.True(t, ok)
+	assert.Equal(t, "c1", response.RequestID)
+	assert.Equal(t, errors.ErrorCodeCommandBlocked, response.Code)
+	assert.Equal(t, "reboot", response.Details["command"])
+}
+
+func TestResultErrorCode(t *testing.T) {
+	assert.Equal(t, errors.ErrorCodeFileNotFound, resultErrorCode("[FILE_NOT_FOUND] File not found: /tmp/x"))
+	assert.Equal(t, errors.ErrorCodePathBlocked, resultErrorCode("security error [PATH_BLOCKED]: access denied"))
+	assert.Equal(t, errors.ErrorCodeInternalError, resultErrorCode("grep output: [FILE_NOT_FOUND] in log"))
+	assert.Equal(t, errors.ErrorCodeInternalError, resultErrorCode("[SOME_RANDOM_TOKEN] from user input"))
+	assert.Equal(t, errors.ErrorCodeInternalError, resultErrorCode("command failed"))
 }
 
 func TestTypeSafeToolRegistry_CreateTextResult(t *testing.T) {
@@ -128,3 +155,221 @@ func TestToolBuilder_Register(t *testing.T) {
 	err := builder.Register()
 	assert.NoError(t, err)
 }
+
+func TestTypeSafeWrapper_RecordsToolCalls(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	registry := NewTypeSafeToolRegistry(server, logger)
+
+	wrapper := createTypeSafeWrapper(registry, TypeSafeToolDefinition[string]{
+		Name: "read",
+		Handler: func(ctx context.Context, req *mcp.CallToolRequest, args string) (*mcp.CallToolResult, any, error) {
+			if args == "missing" {
+				return registry.CreateErrorResult(ctx, "[FILE_NOT_FOUND] File not found: /tmp/missing", nil)
+			}
+			if args == "broken" {
+				return registry.CreateErrorResult(ctx, "something went wrong", nil)
+			}
+			return registry.CreateTextResult(args)
+		},
+		Validator: func(args string) error {
+			if args == "" {
+				return NewValidationError("missing_path", "path is required")
+			}
+			return nil
+		},
+	})
+
+	for _, args := range []string{"ok", "ok", "missing", "broken", ""} {
+		_, _, err := wrapper(context.Background(), nil, args)
+		require.NoError(t, err)
+	}
+
+	stats := logger.GetMetrics().ToolCallStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "read", stats[0].Tool)
+	assert.Equal(t, int64(5), stats[0].Total)
+	assert.Equal(t, int64(0), stats[0].InFlight)
+	assert.Equal(t, map[string]int64{"FILE_NOT_FOUND": 1, "INTERNAL_ERROR": 1, "INVALID_INPUT": 1}, stats[0].Errors)
+	assert.Equal(t, uint64(5), stats[0].Count)
+}
+
+func TestTypeSafeWrapper_TracesToolCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	exporter, err := tracing.NewFileExporter(path)
+	require.NoError(t, err)
+	tracer := tracing.NewTracer(tracing.Options{Exporters: []tracing.Exporter{exporter}})
+	tracing.SetGlobalTracer(tracer)
+	t.Cleanup(func() { tracing.SetGlobalTracer(nil) })
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	registry := NewTypeSafeToolRegistry(server, logging.NewLogger(io.Discard, logging.LogLevelError))
+
+	type loginArgs struct {
+		Host     string `json:"host"`
+		Password string `json:"password"`
+	}
+	wrapper := createTypeSafeWrapper(registry, TypeSafeToolDefinition[loginArgs]{
+		Name: "login",
+		Handler: func(ctx context.Context, req *mcp.CallToolRequest, args loginArgs) (*mcp.CallToolResult, any, error) {
+			_, span := tracing.StartExec(ctx, "true", nil)
+			tracing.EndExec(span, nil)
+			return registry.CreateErrorResult(ctx, "[UNAUTHORIZED] login refused", nil)
+		},
+		Validator: func(args loginArgs) error { return nil },
+	})
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Meta: mcp.Meta{tracing.TraceparentKey: parent},
+		Name: "login",
+	}}
+	_, _, err = wrapper(context.Background(), req, loginArgs{Host: "pve", Password: "hunter2"})
+	require.NoError(t, err)
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	spans, err := tracing.ReadSpans(f)
+	require.NoError(t, err)
+	byName := make(map[string]tracing.SpanData)
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	require.Len(t, byName, 3)
+
+	root := byName["tools/call login"]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", root.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", root.ParentSpanID.String())
+	assert.Equal(t, tracing.SpanKindServer, root.Kind)
+	assert.Equal(t, tracing.StatusError, root.Status)
+	assert.Equal(t, "UNAUTHORIZED", root.Attributes["mcp.tool.error_code"])
+	assert.Contains(t, root.Attributes["mcp.tool.arguments"], `"host":"pve"`)
+	assert.NotContains(t, root.Attributes["mcp.tool.arguments"], "hunter2")
+
+	for _, name := range []string{"validate login", "exec true"} {
+		require.Contains(t, byName, name)
+		assert.Equal(t, root.TraceID, byName[name].TraceID, name)
+		assert.Equal(t, root.SpanID, byName[name].ParentSpanID, name)
+	}
+}
+
+func TestTypeSafeWrapper_TagsLogEntriesWithCall(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(&buf, logging.LogLevelWarning)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	registry := NewTypeSafeToolRegistry(server, logger)
+	executor := NewCommandExecutor(security.NewSecureCommandExecutor(&security.SecurityConfig{AllowedCommands: []string{"ls"}}), logger)
+
+	wrapper := createTypeSafeWrapper(registry, TypeSafeToolDefinition[string]{
+		Name: "run",
+		Handler: func(ctx context.Context, req *mcp.CallToolRequest, args string) (*mcp.CallToolResult, any, error) {
+			_, err := executor.ExecuteSystemCommand(ctx, args)
+			return nil, nil, err
+		},
+	})
+	_, _, err := wrapper(context.Background(), nil, "reboot")
+	require.NoError(t, err)
+
+	var entries []logging.LogEntry
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry logging.LogEntry
+		require.NoError(t, json.Unmarshal(line, &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Command denied by policy", entries[0].Message)
+	assert.Equal(t, "reboot", entries[0].Fields["command"])
+	assert.Equal(t, "Tool execution failed", entries[1].Message)
+	for _, entry := range entries {
+		assert.Equal(t, "run", entry.Fields["tool"])
+		assert.NotEmpty(t, entry.Fields["correlation_id"])
+		assert.Equal(t, entries[0].Fields["correlation_id"], entry.Fields["correlation_id"])
+	}
+}
+
+func TestTypeSafeWrapper_PropagatesCorrelationID(t *testing.T) {
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	registry := NewTypeSafeToolRegistry(server, logger)
+	executor := NewCommandExecutor(security.NewSecureCommandExecutor(&security.SecurityConfig{AllowedCommands: []string{"sh"}}), logger)
+
+	notFound := errors.NewFileNotFoundError("/tmp/missing")
+	wrapper := createTypeSafeWrapper(registry, TypeSafeToolDefinition[string]{
+		Name: "env",
+		Handler: func(ctx context.Context, req *mcp.CallToolRequest, args string) (*mcp.CallToolResult, any, error) {
+			if args == "missing" {
+				return nil, nil, notFound
+			}
+			output, err := executor.ExecuteSystemCommand(ctx, "sh", "-c", `printf %s "$`+logging.CorrelationEnvVar+`"`)
+			require.NoError(t, err)
+			return registry.CreateTextResult(output)
+		},
+	})
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Meta: mcp.Meta{logging.CorrelationMetaKey: "req-1"},
+		Name: "env",
+	}}
+
+	result, _, err := wrapper(context.Background(), req, "")
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", result.Content[0].(*mcp.TextContent).Text, "subprocess environment")
+	assert.Equal(t, "req-1", result.Meta[logging.CorrelationMetaKey])
+
+	result, _, err = wrapper(context.Background(), req, "missing")
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "req-1", result.Meta[logging.CorrelationMetaKey])
+	assert.Equal(t, "req-1", notFound.RequestID)
+}
+
+func TestTypeSafeTool_CallOverTransport(t *testing.T) {
+	type greetArgs struct {
+		Name  string `json:"name"`
+		Times int    `json:"times"`
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	registry := NewTypeSafeToolRegistry(server, logging.NewLogger(io.Discard, logging.LogLevelError))
+	require.NoError(t, NewToolBuilder[greetArgs](registry, "greet", "Greets someone").
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args greetArgs) (*mcp.CallToolResult, any, error) {
+			return registry.CreateTextResult(strings.Repeat("hello "+args.Name+";", args.Times))
+		}).
+		WithValidator(func(args greetArgs) error {
+			if args.Name == "" {
+				return NewValidationError("missing_name", "name is required")
+			}
+			return nil
+		}).
+		Register())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "greet",
+		Arguments: map[string]any{"name": "ops", "times": 2},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%v", result.Content)
+	assert.Equal(t, "hello ops;hello ops;", result.Content[0].(*mcp.TextContent).Text)
+
+	// Arguments are still validated after decoding
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "greet", Arguments: map[string]any{"times": 1}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "name is required")
+
+	// Arguments of the wrong shape are rejected
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "greet", Arguments: map[string]any{"name": "ops", "times": "two"}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "invalid argument type")
+}
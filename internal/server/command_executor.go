@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
-	"strconv"
-	"strings"
 	"time"
 
 	"mini-mcp/internal/shared/logging"
@@ -260,57 +258,3 @@ func (ce *CommandExecutor) ExecuteSystemCommand(ctx context.Context, command str
 
 	return string(output), nil
 }
-
-// ParsePortNumbers parses port numbers from netstat/ss output
-func (ce *CommandExecutor) ParsePortNumbers(output string) []int {
-	ports := make([]int, 0)
-	lines := strings.Split(output, "\n")
-
-	for _, line := range lines {
-		// Skip header lines
-		if strings.Contains(line, "LISTEN") || strings.Contains(line, "Local Address") {
-			continue
-		}
-
-		// Extract port from lines like "*:8080 *:* LISTEN"
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			address := parts[1] // Should be something like "*:8080"
-			if strings.Contains(address, ":") {
-				portStr := strings.Split(address, ":")[1]
-				if port, err := strconv.Atoi(portStr); err == nil && port > 0 {
-					ports = append(ports, port)
-				}
-			}
-		}
-	}
-
-	return ports
-}
-
-// KillProcessGracefully kills a process by PID with graceful shutdown
-func (ce *CommandExecutor) KillProcessGracefully(ctx context.Context, pid int) bool {
-	// First try SIGTERM (graceful)
-	cmd := exec.CommandContext(ctx, "kill", "-TERM", strconv.Itoa(pid))
-	err := cmd.Run()
-
-	if err != nil {
-		// If SIGTERM fails, try SIGKILL (forceful)
-		cmd = exec.CommandContext(ctx, "kill", "-KILL", strconv.Itoa(pid))
-		err = cmd.Run()
-	}
-
-	success := err == nil
-	if success {
-		ce.logger.Info("Process killed successfully", map[string]any{
-			"pid": pid,
-		})
-	} else {
-		ce.logger.Error("Failed to kill process", err, map[string]any{
-			"pid":   pid,
-			"error": err.Error(),
-		})
-	}
-
-	return success
-}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// methodCallTool is the MCP request that calls a tool
+const methodCallTool = "tools/call"
+
+// WithCorrelationIDs wraps a transport so that every tool call carries a
+// correlation ID made of its session and JSON-RPC ID, e.g. "3f2a9c1d:7".
+// The SDK does not expose the JSON-RPC ID to handlers, so the ID is put in
+// the call's _meta, where a client may also supply its own.
+func WithCorrelationIDs(transport mcp.Transport) mcp.Transport {
+	return &correlatingTransport{Transport: transport}
+}
+
+// correlatingTransport adds correlation IDs to the tool calls it receives
+type correlatingTransport struct {
+	mcp.Transport
+}
+
+// Connect connects the wrapped transport
+func (t *correlatingTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Transports without sessions, such as stdio, get an ID per connection
+	session := conn.SessionID()
+	if session == "" {
+		session = logging.NewCorrelationID()
+	}
+	return &correlatingConn{Connection: conn, session: session}, nil
+}
+
+// correlatingConn adds correlation IDs to the tool calls read from a connection
+type correlatingConn struct {
+	mcp.Connection
+	session string
+}
+
+// Read reads the next message, adding a correlation ID to a tool call that
+// has none
+func (c *correlatingConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	msg, err := c.Connection.Read(ctx)
+	if err != nil {
+		return msg, err
+	}
+	if req, ok := msg.(*jsonrpc.Request); ok && req.Method == methodCallTool && req.ID.IsValid() {
+		if params, err := withCorrelationID(req.Params, fmt.Sprintf("%s:%v", c.session, req.ID.Raw())); err == nil {
+			req.Params = params
+		}
+	}
+	return msg, nil
+}
+
+// withCorrelationID sets the correlation ID in the _meta of raw params unless
+// the client supplied one. Params that are not an object are left for the
+// SDK to reject.
+func withCorrelationID(raw json.RawMessage, id string) (json.RawMessage, error) {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, err
+	}
+	if params == nil {
+		params = make(map[string]json.RawMessage)
+	}
+	meta := make(map[string]json.RawMessage)
+	if data, ok := params["_meta"]; ok {
+		if err := json.Unmarshal(data, &meta); err != nil || meta == nil {
+			return nil, fmt.Errorf("invalid _meta")
+		}
+	}
+	if _, ok := meta[logging.CorrelationMetaKey]; ok {
+		return raw, nil
+	}
+	meta[logging.CorrelationMetaKey], _ = json.Marshal(id)
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	params["_meta"] = data
+	return json.Marshal(params)
+}
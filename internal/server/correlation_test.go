@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+	params, err := withCorrelationID(json.RawMessage(`{"name":"ping","_meta":{"progressToken":12345678901}}`), "s:1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"ping","_meta":{"progressToken":12345678901,"mini-mcp/correlation_id":"s:1"}}`, string(params))
+
+	params, err = withCorrelationID(json.RawMessage(`{"name":"ping"}`), "s:2")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"ping","_meta":{"mini-mcp/correlation_id":"s:2"}}`, string(params))
+
+	// A client-supplied ID is kept
+	raw := json.RawMessage(`{"name":"ping","_meta":{"mini-mcp/correlation_id":"client-7"}}`)
+	params, err = withCorrelationID(raw, "s:3")
+	require.NoError(t, err)
+	assert.Equal(t, string(raw), string(params))
+
+	_, err = withCorrelationID(json.RawMessage(`[1]`), "s:4")
+	assert.Error(t, err)
+	_, err = withCorrelationID(json.RawMessage(`{"_meta":"x"}`), "s:5")
+	assert.Error(t, err)
+}
+
+func TestWithCorrelationIDs_ToolCalls(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	tools := registry.NewTypeSafeToolRegistry(server, logging.NewLogger(io.Discard, logging.LogLevelError))
+	require.NoError(t, registry.NewToolBuilder[map[string]any](tools, "whoami", "Returns the correlation ID").
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			return tools.CreateTextResult(logging.CorrelationID(ctx))
+		}).
+		Register())
+	require.NoError(t, registry.NewToolBuilder[map[string]any](tools, "fail", "Always fails").
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			return tools.CreateErrorResult(ctx, "[COMMAND_BLOCKED] not allowed", nil)
+		}).
+		Register())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, WithCorrelationIDs(serverTransport), nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	call := func(meta mcp.Meta) (string, any) {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Meta: meta, Name: "whoami", Arguments: map[string]any{}})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		return result.Content[0].(*mcp.TextContent).Text, result.Meta[logging.CorrelationMetaKey]
+	}
+
+	// Derived from the connection and the JSON-RPC ID
+	first, meta := call(nil)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{16}:\d+$`), first)
+	assert.Equal(t, first, meta)
+	second, _ := call(nil)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, first[:17], second[:17], "same connection")
+
+	// Supplied by the client
+	id, meta := call(mcp.Meta{logging.CorrelationMetaKey: "client-42"})
+	assert.Equal(t, "client-42", id)
+	assert.Equal(t, "client-42", meta)
+
+	// Malformed IDs are replaced
+	id, _ = call(mcp.Meta{logging.CorrelationMetaKey: "bad id\n"})
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{16}$`), id)
+
+	// Error results carry it in their structured error
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Meta:      mcp.Meta{logging.CorrelationMetaKey: "client-43"},
+		Name:      "fail",
+		Arguments: map[string]any{},
+	})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	structured, ok := result.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "client-43", structured["request_id"])
+	assert.Equal(t, "COMMAND_BLOCKED", structured["code"])
+}
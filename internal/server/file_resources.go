@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	domainfile "mini-mcp/internal/domain/file"
+	"mini-mcp/internal/handlers/core"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// fileResourceTemplate exposes files as file:///absolute/path resources.
+// Reads return the tail of the file; "?lines=N" selects how many lines.
+const fileResourceTemplate = "file:///{+path}"
+
+// fileResources serves file:// resource reads and change subscriptions
+type fileResources struct {
+	server    *mcp.Server
+	handler   *core.FileHandlerImpl
+	validator security.PathValidator
+	watcher   *domainfile.Watcher
+	logger    logging.Logger
+
+	// sessions holds the URIs each session subscribed to, so that their
+	// watches are released when the session closes
+	mu       sync.Mutex
+	sessions map[*mcp.ServerSession]map[string]bool
+}
+
+// newFileResources creates the file resource glue; attach must be called once the server exists
+func newFileResources(validator security.PathValidator, logger logging.Logger) *fileResources {
+	f := &fileResources{
+		validator: validator,
+		logger:    logger,
+		sessions:  make(map[*mcp.ServerSession]map[string]bool),
+	}
+	f.watcher = domainfile.NewWatcher(domainfile.DefaultWatchInterval, f.notify)
+	return f
+}
+
+// attach registers the file resource template on the server
+func (f *fileResources) attach(server *mcp.Server, handler *core.FileHandlerImpl) {
+	f.server = server
+	f.handler = handler
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "file_tail",
+		Description: "Tail of a file (e.g. file:///var/log/syslog?lines=200); subscribe to be notified as it grows, rotates or is truncated",
+		MIMEType:    "text/plain",
+		URITemplate: fileResourceTemplate,
+	}, f.read)
+}
+
+// read returns the last lines of the file, with the tail cursor in _meta
+func (f *fileResources) read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	path, lines, err := parseFileURI(req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	tail, err := f.handler.TailFile(ctx, map[string]any{
+		"path":  path,
+		"lines": lines,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.Join(tail.Lines, "\n")
+	if len(tail.Lines) > 0 {
+		text += "\n"
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      req.Params.URI,
+			MIMEType: "text/plain",
+			Text:     text,
+			Meta: mcp.Meta{
+				"cursor": tail.Cursor,
+				"size":   tail.Size,
+			},
+		}},
+	}, nil
+}
+
+// subscribe starts watching the file behind a file:// URI
+func (f *fileResources) subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	path, _, err := parseFileURI(req.Params.URI)
+	if err != nil {
+		return err
+	}
+	if err := f.validator.ValidatePath(path); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	uris, known := f.sessions[req.Session]
+	if uris[req.Params.URI] {
+		// The session is already subscribed
+		return nil
+	}
+	if err := f.watcher.Watch(req.Params.URI, path); err != nil {
+		return err
+	}
+	if !known {
+		uris = make(map[string]bool)
+		f.sessions[req.Session] = uris
+		if req.Session != nil {
+			go f.release(req.Session)
+		}
+	}
+	uris[req.Params.URI] = true
+
+	logging.FromContext(ctx, f.logger).Info("Resource subscription added", map[string]any{"uri": req.Params.URI})
+	return nil
+}
+
+// unsubscribe stops watching a file:// URI for the session
+func (f *fileResources) unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.sessions[req.Session][req.Params.URI] {
+		return nil
+	}
+	delete(f.sessions[req.Session], req.Params.URI)
+	f.watcher.Unwatch(req.Params.URI)
+
+	logging.FromContext(ctx, f.logger).Info("Resource subscription removed", map[string]any{"uri": req.Params.URI})
+	return nil
+}
+
+// release drops the watches of a session once it closes
+func (f *fileResources) release(session *mcp.ServerSession) {
+	_ = session.Wait()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for uri := range f.sessions[session] {
+		f.watcher.Unwatch(uri)
+	}
+	delete(f.sessions, session)
+}
+
+// completePath completes the path of a file:// URI with the entries of its
+// directory that the path policy allows; directories end with a slash. The
+// directory itself must be allowed, so that completion cannot reveal what
+// exists outside of the allowed paths.
+func (f *fileResources) completePath(value string) ([]string, error) {
+	dir, prefix := filepath.Split("/" + value)
+	if err := f.validator.ValidatePath(dir); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	values := []string{}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if !f.validator.IsPathAllowed(path) {
+			continue
+		}
+		if entry.IsDir() {
+			path += "/"
+		}
+		values = append(values, strings.TrimPrefix(path, "/"))
+	}
+	return values, nil
+}
+
+// notify forwards watcher events as notifications/resources/updated
+func (f *fileResources) notify(uri, path, event string) {
+	if f.server == nil {
+		return
+	}
+	err := f.server.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{
+		URI:  uri,
+		Meta: mcp.Meta{"event": event},
+	})
+	if err != nil {
+		f.logger.Error("Failed to send resource update", err, map[string]any{"uri": uri, "event": event})
+	}
+}
+
+// parseFileURI extracts the absolute path and optional line count from a file:// URI
+func parseFileURI(uri string) (string, int, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", 0, fmt.Errorf("invalid file URI: %s", uri)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", 0, fmt.Errorf("remote file URIs are not supported: %s", uri)
+	}
+	if u.Path == "" || !filepath.IsAbs(u.Path) {
+		return "", 0, fmt.Errorf("file URI must contain an absolute path: %s", uri)
+	}
+
+	lines := 0
+	if value := u.Query().Get("lines"); value != "" {
+		lines, err = strconv.Atoi(value)
+		if err != nil || lines < 0 {
+			return "", 0, fmt.Errorf("invalid lines parameter: %s", value)
+		}
+	}
+
+	return filepath.Clean(u.Path), lines, nil
+}
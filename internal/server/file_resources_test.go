@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appfile "mini-mcp/internal/application/file"
+	"mini-mcp/internal/handlers/core"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFileURI(t *testing.T) {
+	path, lines, err := parseFileURI("file:///var/log/syslog?lines=50")
+	require.NoError(t, err)
+	assert.Equal(t, "/var/log/syslog", path)
+	assert.Equal(t, 50, lines)
+
+	_, _, err = parseFileURI("file://remote-host/var/log/syslog")
+	assert.Error(t, err)
+	_, _, err = parseFileURI("http:///var/log/syslog")
+	assert.Error(t, err)
+	_, _, err = parseFileURI("file:///var/log/syslog?lines=-1")
+	assert.Error(t, err)
+}
+
+func TestFileResources_ReadAndSubscribe(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "file-resources-")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0644))
+
+	deps := Deps{
+		Logger:   logging.NewLogger(io.Discard, logging.LogLevelError),
+		Security: security.NewSecureCommandExecutor(nil),
+	}
+	server := BuildServer(deps, "1.0.0")
+
+	updates := make(chan *mcp.ResourceUpdatedNotificationParams, 4)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updates <- req.Params
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err = server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	uri := "file://" + path
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri + "?lines=2"})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, "two\nthree\n", result.Contents[0].Text)
+
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("four\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	select {
+	case update := <-updates:
+		assert.Equal(t, uri, update.URI)
+		assert.Equal(t, "grown", update.Meta["event"])
+	case <-ctx.Done():
+		t.Fatal("no resource update notification received")
+	}
+
+	assert.Error(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: "file:///etc/shadow"}))
+	require.NoError(t, session.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}))
+}
+
+func TestFileResources_ReleasesWatchesOfClosedSessions(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "file-resources-")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("one\n"), 0644))
+
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	validator := security.NewSecureCommandExecutor(nil).GetPathValidator()
+	files := newFileResources(validator, logger)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   files.subscribe,
+		UnsubscribeHandler: files.unsubscribe,
+	})
+	files.attach(server, core.NewFileHandler(appfile.NewServiceWithDeps(validator, logger), logger).(*core.FileHandlerImpl))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err = server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+
+	uri := "file://" + path
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+	watched := func() int {
+		files.mu.Lock()
+		defer files.mu.Unlock()
+		return len(files.sessions)
+	}
+	assert.Equal(t, 1, watched())
+
+	require.NoError(t, session.Close())
+	assert.Eventually(t, func() bool { return watched() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,660 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/domain/systemd"
+	resourcehandlers "mini-mcp/internal/handlers/resources"
+	"mini-mcp/internal/health"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+const (
+	// methodListResources is the MCP request that lists resources
+	methodListResources = "resources/list"
+	// refResource is the completion reference type of resource templates
+	refResource = "ref/resource"
+	// maxCompletionValues is the most values a completion may return
+	maxCompletionValues = 100
+	// maxListedPerTemplate is the most resources of one live template that
+	// resources/list returns; the others can still be read and completed
+	maxListedPerTemplate = 100
+	// listCacheTTL is how long resources/list reuses the live resources of a
+	// template instead of listing them again
+	listCacheTTL = 10 * time.Second
+	// containerLogLines is how many log lines a container logs resource returns
+	containerLogLines = 200
+)
+
+// Live resource templates
+const (
+	procTemplate          = "proc://{pid}"
+	serviceTemplate       = "service://{unit}"
+	containerTemplate     = "docker://container/{id}"
+	containerLogsTemplate = "docker://container/{id}/logs"
+	proxmoxVMTemplate     = "proxmox://{node}/qemu/{vmid}"
+)
+
+var (
+	// dockerNamePattern matches container IDs and names; it also keeps them from being read as options
+	dockerNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	// proxmoxNodePattern matches Proxmox node names
+	proxmoxNodePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.-]*$`)
+)
+
+// liveTemplate is a resource template over the live state of the host
+type liveTemplate struct {
+	template *mcp.ResourceTemplate
+	// read returns the content of the resource the variables identify
+	read func(ctx context.Context, vars uritemplate.Values) (*mcp.ResourceContents, error)
+	// list returns the resources that currently exist; nil lists none
+	list func(ctx context.Context) ([]*mcp.Resource, error)
+	// complete returns the values of argument that start with value; vars
+	// holds the arguments the client already resolved
+	complete func(ctx context.Context, argument, value string, vars map[string]string) ([]string, error)
+}
+
+// liveResources serves resource templates over processes, services, Docker
+// containers and Proxmox VMs, lists the resources that currently exist and
+// completes the template arguments
+type liveResources struct {
+	collector *procfs.Collector
+	services  systemd.Service
+	run       registry.CommandRunner
+	health    *health.HealthChecker
+	files     *fileResources
+	logger    logging.Logger
+	// maxListed caps the resources listed per template
+	maxListed int
+	// listTTL is how long a template's listing is reused; now is replaced in tests
+	listTTL time.Duration
+	now     func() time.Time
+
+	templates []liveTemplate
+
+	mu     sync.Mutex
+	listed map[string]liveListing
+}
+
+// liveListing is the cached result of listing the resources of a template
+type liveListing struct {
+	resources []*mcp.Resource
+	err       error
+	at        time.Time
+}
+
+// newLiveResources creates the live resource glue; attach must be called once the server exists
+func newLiveResources(files *fileResources, logger logging.Logger) *liveResources {
+	return &liveResources{
+		files:     files,
+		logger:    logger,
+		maxListed: maxListedPerTemplate,
+		listTTL:   listCacheTTL,
+		now:       time.Now,
+		listed:    make(map[string]liveListing),
+	}
+}
+
+// attach registers the live resource templates and the health and Docker
+// resources on the server. run executes docker and pvesh under the command policy.
+func (l *liveResources) attach(server *mcp.Server, collector *procfs.Collector, services systemd.Service, run registry.CommandRunner, checker *health.HealthChecker) {
+	l.collector = collector
+	l.services = services
+	l.run = run
+	l.health = checker
+
+	l.templates = []liveTemplate{
+		{
+			template: &mcp.ResourceTemplate{
+				Name:        "process",
+				Description: "A process by PID (e.g. proc://1) with its state, user, command line and resource usage",
+				MIMEType:    "application/json",
+				URITemplate: procTemplate,
+			},
+			read:     l.readProcess,
+			list:     l.listProcesses,
+			complete: l.completeProcess,
+		},
+		{
+			template: &mcp.ResourceTemplate{
+				Name:        "service",
+				Description: "A systemd unit (e.g. service://nginx) with its state, enablement, main PID and last result",
+				MIMEType:    "application/json",
+				URITemplate: serviceTemplate,
+			},
+			read:     l.readService,
+			list:     l.listServices,
+			complete: l.completeService,
+		},
+		{
+			template: &mcp.ResourceTemplate{
+				Name:        "docker_container",
+				Description: "A Docker container by ID or name as reported by docker inspect",
+				MIMEType:    "application/json",
+				URITemplate: containerTemplate,
+			},
+			read:     l.readContainer,
+			list:     l.listContainers,
+			complete: l.completeContainer,
+		},
+		{
+			template: &mcp.ResourceTemplate{
+				Name:        "docker_container_logs",
+				Description: fmt.Sprintf("The last %d lines a Docker container wrote to its standard output, with timestamps", containerLogLines),
+				MIMEType:    "text/plain",
+				URITemplate: containerLogsTemplate,
+			},
+			read:     l.readContainerLogs,
+			complete: l.completeContainer,
+		},
+		{
+			template: &mcp.ResourceTemplate{
+				Name:        "proxmox_vm",
+				Description: "The current status of a Proxmox QEMU VM (e.g. proxmox://pve1/qemu/100), read through the local pvesh",
+				MIMEType:    "application/json",
+				URITemplate: proxmoxVMTemplate,
+			},
+			read:     l.readProxmoxVM,
+			list:     l.listProxmoxVMs,
+			complete: l.completeProxmoxVM,
+		},
+	}
+	for _, t := range l.templates {
+		server.AddResourceTemplate(t.template, l.reader(t))
+	}
+
+	server.AddResource(&mcp.Resource{
+		Name:        "health_checks",
+		Description: "The latest result of every health check and dependency. Once checks are scheduled they report their last run; until then, and for checks without a result yet, they run on read",
+		MIMEType:    "application/json",
+		URI:         "health://checks",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return jsonResource(req.Params.URI, l.health.CheckHealth(ctx))
+	})
+
+	// Resources served by the resources handler
+	for _, r := range []struct{ uri, name, description string }{
+		{"docker://info", "docker/info", "Docker daemon information as reported by docker info"},
+		{"docs://commands", "docs/commands", "Usage and documentation links of common commands"},
+	} {
+		server.AddResource(&mcp.Resource{
+			Name:        strings.ReplaceAll(r.name, "/", "_"),
+			Description: r.description,
+			MIMEType:    "application/json",
+			URI:         r.uri,
+		}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			data, err := resourcehandlers.AccessResource(ctx, r.name, l.run)
+			if err != nil {
+				return nil, err
+			}
+			return jsonResource(req.Params.URI, data)
+		})
+	}
+}
+
+// reader adapts a live template to a resource handler
+func (l *liveResources) reader(t liveTemplate) mcp.ResourceHandler {
+	tmpl := uritemplate.MustNew(t.template.URITemplate)
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		vars := tmpl.Match(req.Params.URI)
+		if vars == nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		contents, err := t.read(ctx, vars)
+		if err != nil {
+			return nil, err
+		}
+		contents.URI = req.Params.URI
+		if contents.MIMEType == "" {
+			contents.MIMEType = t.template.MIMEType
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+	}
+}
+
+// listMiddleware appends the live resources to the last page of resources/list.
+// Sources that are unavailable on the host, such as Docker or Proxmox, are skipped.
+func (l *liveResources) listMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+		if err != nil || method != methodListResources {
+			return result, err
+		}
+		list, ok := result.(*mcp.ListResourcesResult)
+		if !ok || list.NextCursor != "" {
+			return result, nil
+		}
+		for _, t := range l.templates {
+			if t.list == nil {
+				continue
+			}
+			resources, err := l.list(ctx, t)
+			if err != nil {
+				logging.FromContext(ctx, l.logger).Debug("Live resources unavailable", map[string]any{
+					"template": t.template.URITemplate,
+					"error":    err.Error(),
+				})
+				continue
+			}
+			if len(resources) > l.maxListed {
+				logging.FromContext(ctx, l.logger).Debug("Live resources truncated", map[string]any{
+					"template": t.template.URITemplate,
+					"count":    len(resources),
+					"listed":   l.maxListed,
+				})
+				resources = resources[:l.maxListed]
+			}
+			list.Resources = append(list.Resources, resources...)
+		}
+		return list, nil
+	}
+}
+
+// list returns the resources of a template, reusing a listing younger than listTTL
+// so that frequent resources/list requests do not run commands every time
+func (l *liveResources) list(ctx context.Context, t liveTemplate) ([]*mcp.Resource, error) {
+	key := t.template.URITemplate
+	now := l.now()
+	l.mu.Lock()
+	cached, ok := l.listed[key]
+	l.mu.Unlock()
+	if ok && now.Sub(cached.at) < l.listTTL {
+		return cached.resources, cached.err
+	}
+
+	resources, err := t.list(ctx)
+	if ctx.Err() != nil {
+		// A cancelled request says nothing about the source
+		return resources, err
+	}
+	l.mu.Lock()
+	l.listed[key] = liveListing{resources: resources, err: err, at: now}
+	l.mu.Unlock()
+	return resources, err
+}
+
+// complete answers completion/complete for the arguments of the resource templates
+func (l *liveResources) complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	params := req.Params
+	if params.Ref == nil || params.Ref.Type != refResource {
+		return nil, fmt.Errorf("completion is only supported for resource templates")
+	}
+	var vars map[string]string
+	if params.Context != nil {
+		vars = params.Context.Arguments
+	}
+
+	var values []string
+	var err error
+	switch params.Ref.URI {
+	case fileResourceTemplate:
+		values, err = l.files.completePath(params.Argument.Value)
+	default:
+		t, ok := l.template(params.Ref.URI)
+		if !ok {
+			return nil, fmt.Errorf("unknown resource template: %s", params.Ref.URI)
+		}
+		values, err = t.complete(ctx, params.Argument.Name, params.Argument.Value, vars)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return completionResult(values), nil
+}
+
+// template returns the live template with the given URI template
+func (l *liveResources) template(uriTemplate string) (liveTemplate, bool) {
+	for _, t := range l.templates {
+		if t.template.URITemplate == uriTemplate {
+			return t, true
+		}
+	}
+	return liveTemplate{}, false
+}
+
+// readProcess reads proc://{pid}
+func (l *liveResources) readProcess(ctx context.Context, vars uritemplate.Values) (*mcp.ResourceContents, error) {
+	uri := "proc://" + vars.Get("pid").String()
+	pid, err := strconv.Atoi(vars.Get("pid").String())
+	if err != nil || pid <= 0 {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	proc, err := l.collector.Process(pid)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jsonContents(proc)
+}
+
+// listProcesses lists a resource per running process
+func (l *liveResources) listProcesses(ctx context.Context) ([]*mcp.Resource, error) {
+	procs, err := l.collector.Processes()
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*mcp.Resource, 0, len(procs))
+	for _, p := range procs {
+		resources = append(resources, &mcp.Resource{
+			Name:        fmt.Sprintf("process %d (%s)", p.PID, p.Name),
+			Description: p.CommandLine,
+			MIMEType:    "application/json",
+			URI:         fmt.Sprintf("proc://%d", p.PID),
+		})
+	}
+	return resources, nil
+}
+
+// completeProcess completes PIDs
+func (l *liveResources) completeProcess(ctx context.Context, argument, value string, vars map[string]string) ([]string, error) {
+	procs, err := l.collector.Processes()
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]string, 0, len(procs))
+	for _, p := range procs {
+		pids = append(pids, strconv.Itoa(p.PID))
+	}
+	return withPrefix(pids, value), nil
+}
+
+// readService reads service://{unit}
+func (l *liveResources) readService(ctx context.Context, vars uritemplate.Values) (*mcp.ResourceContents, error) {
+	unit, err := systemd.NormalizeUnit(vars.Get("unit").String())
+	if err != nil {
+		return nil, err
+	}
+	info, err := l.services.List(ctx, systemd.ListOptions{Filter: unit, Status: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range info.SystemdServices {
+		if svc.Name == unit {
+			return jsonContents(svc)
+		}
+	}
+	if len(info.Warnings) > 0 {
+		return nil, fmt.Errorf("unit %s: %s", unit, strings.Join(info.Warnings, "; "))
+	}
+	return nil, mcp.ResourceNotFoundError("service://" + unit)
+}
+
+// listServices lists a resource per systemd service
+func (l *liveResources) listServices(ctx context.Context) ([]*mcp.Resource, error) {
+	info, err := l.services.List(ctx, systemd.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*mcp.Resource, 0, len(info.SystemdServices))
+	for _, svc := range info.SystemdServices {
+		resources = append(resources, &mcp.Resource{
+			Name:        svc.Name,
+			Description: svc.Description,
+			MIMEType:    "application/json",
+			URI:         "service://" + svc.Name,
+		})
+	}
+	return resources, nil
+}
+
+// completeService completes unit names
+func (l *liveResources) completeService(ctx context.Context, argument, value string, vars map[string]string) ([]string, error) {
+	info, err := l.services.List(ctx, systemd.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	units := make([]string, 0, len(info.SystemdServices))
+	for _, svc := range info.SystemdServices {
+		units = append(units, svc.Name)
+	}
+	return withPrefix(units, value), nil
+}
+
+// containerID validates the container of a docker:// URI
+func containerID(vars uritemplate.Values) (string, error) {
+	id := vars.Get("id").String()
+	if !dockerNamePattern.MatchString(id) {
+		return "", fmt.Errorf("invalid container: %q", id)
+	}
+	return id, nil
+}
+
+// readContainer reads docker://container/{id}
+func (l *liveResources) readContainer(ctx context.Context, vars uritemplate.Values) (*mcp.ResourceContents, error) {
+	id, err := containerID(vars)
+	if err != nil {
+		return nil, err
+	}
+	out, err := l.run(ctx, "docker", "inspect", "--type", "container", "--format", "{{json .}}", id)
+	if err != nil {
+		return nil, fmt.Errorf("docker inspect %s: %w", id, err)
+	}
+	var container any
+	if err := json.Unmarshal([]byte(out), &container); err != nil {
+		return nil, fmt.Errorf("docker inspect %s: %w", id, err)
+	}
+	return jsonContents(container)
+}
+
+// readContainerLogs reads docker://container/{id}/logs
+func (l *liveResources) readContainerLogs(ctx context.Context, vars uritemplate.Values) (*mcp.ResourceContents, error) {
+	id, err := containerID(vars)
+	if err != nil {
+		return nil, err
+	}
+	out, err := l.run(ctx, "docker", "logs", "--timestamps", "--tail", strconv.Itoa(containerLogLines), id)
+	if err != nil {
+		return nil, fmt.Errorf("docker logs %s: %w", id, err)
+	}
+	return &mcp.ResourceContents{Text: out}, nil
+}
+
+// dockerContainerRef is a line of `docker ps --format json` naming a container
+type dockerContainerRef struct {
+	ID, Names, Image, Status string
+}
+
+// containers lists every container
+func (l *liveResources) containers(ctx context.Context) ([]dockerContainerRef, error) {
+	out, err := l.run(ctx, "docker", "ps", "--all", "--format", "{{json .}}")
+	if err != nil {
+		return nil, fmt.Errorf("docker ps: %w", err)
+	}
+	var containers []dockerContainerRef
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var c dockerContainerRef
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return nil, fmt.Errorf("docker ps: %w", err)
+		}
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// listContainers lists a resource per container
+func (l *liveResources) listContainers(ctx context.Context) ([]*mcp.Resource, error) {
+	containers, err := l.containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*mcp.Resource, 0, len(containers))
+	for _, c := range containers {
+		// Linked containers have several comma-separated names
+		name, _, _ := strings.Cut(c.Names, ",")
+		resources = append(resources, &mcp.Resource{
+			Name:        "container " + name,
+			Description: fmt.Sprintf("%s (%s)", c.Image, c.Status),
+			MIMEType:    "application/json",
+			URI:         "docker://container/" + name,
+		})
+	}
+	return resources, nil
+}
+
+// completeContainer completes container names and IDs
+func (l *liveResources) completeContainer(ctx context.Context, argument, value string, vars map[string]string) ([]string, error) {
+	containers, err := l.containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, c := range containers {
+		name, _, _ := strings.Cut(c.Names, ",")
+		names = append(names, name, c.ID)
+	}
+	return withPrefix(names, value), nil
+}
+
+// proxmoxVM is a QEMU VM entry of /cluster/resources
+type proxmoxVM struct {
+	Type   string `json:"type"`
+	VMID   int    `json:"vmid"`
+	Name   string `json:"name"`
+	Node   string `json:"node"`
+	Status string `json:"status"`
+}
+
+// pvesh reads a Proxmox API path through the local pvesh CLI
+func (l *liveResources) pvesh(ctx context.Context, path string, out any) error {
+	output, err := l.run(ctx, "pvesh", "get", path, "--output-format", "json")
+	if err != nil {
+		return fmt.Errorf("pvesh get %s: %w", path, err)
+	}
+	if err := json.Unmarshal([]byte(output), out); err != nil {
+		return fmt.Errorf("pvesh get %s: %w", path, err)
+	}
+	return nil
+}
+
+// proxmoxVMs lists the QEMU VMs of the cluster
+func (l *liveResources) proxmoxVMs(ctx context.Context) ([]proxmoxVM, error) {
+	var items []proxmoxVM
+	if err := l.pvesh(ctx, "/cluster/resources", &items); err != nil {
+		return nil, err
+	}
+	vms := items[:0]
+	for _, item := range items {
+		if item.Type == "qemu" {
+			vms = append(vms, item)
+		}
+	}
+	return vms, nil
+}
+
+// readProxmoxVM reads proxmox://{node}/qemu/{vmid}
+func (l *liveResources) readProxmoxVM(ctx context.Context, vars uritemplate.Values) (*mcp.ResourceContents, error) {
+	node := vars.Get("node").String()
+	if !proxmoxNodePattern.MatchString(node) {
+		return nil, fmt.Errorf("invalid Proxmox node: %q", node)
+	}
+	vmid, err := strconv.Atoi(vars.Get("vmid").String())
+	if err != nil || vmid <= 0 {
+		return nil, fmt.Errorf("invalid VM ID: %s", vars.Get("vmid").String())
+	}
+	var status any
+	if err := l.pvesh(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/status/current", node, vmid), &status); err != nil {
+		return nil, err
+	}
+	return jsonContents(status)
+}
+
+// listProxmoxVMs lists a resource per QEMU VM
+func (l *liveResources) listProxmoxVMs(ctx context.Context) ([]*mcp.Resource, error) {
+	vms, err := l.proxmoxVMs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*mcp.Resource, 0, len(vms))
+	for _, vm := range vms {
+		resources = append(resources, &mcp.Resource{
+			Name:        fmt.Sprintf("VM %d (%s)", vm.VMID, vm.Name),
+			Description: fmt.Sprintf("QEMU VM on %s, %s", vm.Node, vm.Status),
+			MIMEType:    "application/json",
+			URI:         fmt.Sprintf("proxmox://%s/qemu/%d", vm.Node, vm.VMID),
+		})
+	}
+	return resources, nil
+}
+
+// completeProxmoxVM completes node names, and VM IDs on the resolved node
+func (l *liveResources) completeProxmoxVM(ctx context.Context, argument, value string, vars map[string]string) ([]string, error) {
+	vms, err := l.proxmoxVMs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []string
+	for _, vm := range vms {
+		switch argument {
+		case "node":
+			candidates = append(candidates, vm.Node)
+		case "vmid":
+			if node := vars["node"]; node == "" || node == vm.Node {
+				candidates = append(candidates, strconv.Itoa(vm.VMID))
+			}
+		}
+	}
+	return withPrefix(candidates, value), nil
+}
+
+// withPrefix returns the distinct candidates that start with prefix, sorted
+func withPrefix(candidates []string, prefix string) []string {
+	seen := make(map[string]bool)
+	values := []string{}
+	for _, c := range candidates {
+		if c != "" && strings.HasPrefix(c, prefix) && !seen[c] {
+			seen[c] = true
+			values = append(values, c)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// completionResult returns at most maxCompletionValues values, with the total
+func completionResult(values []string) *mcp.CompleteResult {
+	result := &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: values, Total: len(values)}}
+	if len(values) > maxCompletionValues {
+		result.Completion.Values = values[:maxCompletionValues]
+		result.Completion.HasMore = true
+	}
+	return result
+}
+
+// jsonContents marshals v as indented JSON resource contents
+func jsonContents(v any) (*mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	return &mcp.ResourceContents{MIMEType: "application/json", Text: string(data)}, nil
+}
+
+// jsonResource returns v as the JSON contents of the resource at uri
+func jsonResource(uri string, v any) (*mcp.ReadResourceResult, error) {
+	contents, err := jsonContents(v)
+	if err != nil {
+		return nil, err
+	}
+	contents.URI = uri
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+}
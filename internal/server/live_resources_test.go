@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/domain/systemd"
+	"mini-mcp/internal/health"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connect connects an in-memory client to server
+func connect(t *testing.T, ctx context.Context, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+// resourceURIs returns the URIs of resources
+func resourceURIs(resources []*mcp.Resource) []string {
+	uris := make([]string, 0, len(resources))
+	for _, r := range resources {
+		uris = append(uris, r.URI)
+	}
+	return uris
+}
+
+func TestLiveResources_ProcessesHealthAndFiles(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "live-resources-")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.log"), nil, 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "archive"), 0755))
+
+	deps := Deps{
+		Logger:   logging.NewLogger(io.Discard, logging.LogLevelError),
+		Security: security.NewSecureCommandExecutor(nil),
+	}
+	server := BuildServer(deps, "1.0.0")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	session := connect(t, ctx, server)
+	assert.NotNil(t, session.InitializeResult().Capabilities.Completions)
+
+	templates, err := session.ListResourceTemplates(ctx, nil)
+	require.NoError(t, err)
+	var uriTemplates []string
+	for _, tmpl := range templates.ResourceTemplates {
+		uriTemplates = append(uriTemplates, tmpl.URITemplate)
+	}
+	assert.Subset(t, uriTemplates, []string{procTemplate, serviceTemplate, containerTemplate, containerLogsTemplate, proxmoxVMTemplate, fileResourceTemplate})
+
+	pid := strconv.Itoa(os.Getpid())
+	list, err := session.ListResources(ctx, nil)
+	require.NoError(t, err)
+	uris := resourceURIs(list.Resources)
+	assert.Subset(t, uris, []string{"system://info", "health://checks", "docs://commands"})
+	var procs int
+	for _, uri := range uris {
+		if strings.HasPrefix(uri, "proc://") {
+			procs++
+		}
+	}
+	// This process may be beyond the listed ones on a busy host
+	assert.Positive(t, procs)
+	assert.LessOrEqual(t, procs, maxListedPerTemplate)
+
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "proc://" + pid})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, "application/json", result.Contents[0].MIMEType)
+	var proc struct {
+		PID int `json:"pid"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].Text), &proc))
+	assert.Equal(t, os.Getpid(), proc.PID)
+
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "proc://nope"})
+	assert.ErrorContains(t, err, "Resource not found")
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "proc://99999999"})
+	assert.ErrorContains(t, err, "Resource not found")
+
+	result, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "health://checks"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Contents[0].Text, `"status"`)
+
+	result, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "docs://commands"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Contents[0].Text, "kubectl")
+
+	completion, err := session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: refResource, URI: procTemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "pid", Value: pid},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, completion.Completion.Values, pid)
+
+	completion, err = session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: refResource, URI: fileResourceTemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "path", Value: strings.TrimPrefix(dir, "/") + "/a"},
+	})
+	require.NoError(t, err)
+	base := strings.TrimPrefix(dir, "/")
+	assert.Equal(t, []string{base + "/app.log", base + "/archive/"}, completion.Completion.Values)
+
+	// Directories outside of the allowed paths are not listed
+	_, err = session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: refResource, URI: fileResourceTemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "path", Value: "etc/pass"},
+	})
+	assert.Error(t, err)
+
+	_, err = session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: refResource, URI: "unknown://{x}"},
+		Argument: mcp.CompleteParamsArgument{Name: "x"},
+	})
+	assert.Error(t, err)
+}
+
+// fakeRunner answers docker and pvesh commands with canned output
+func fakeRunner(ctx context.Context, command string, args ...string) (string, error) {
+	line := command + " " + strings.Join(args, " ")
+	switch {
+	case strings.HasPrefix(line, "docker ps"):
+		return `{"ID":"abc123","Names":"web","Image":"nginx","Status":"Up 1 hour"}
+{"ID":"def456","Names":"worker,web/worker","Image":"app","Status":"Exited (0)"}
+`, nil
+	case strings.HasPrefix(line, "docker inspect"):
+		return `{"Id":"abc123","Name":"/web"}`, nil
+	case strings.HasPrefix(line, "docker logs"):
+		return "2024-01-15T10:00:00Z started\n", nil
+	case line == "pvesh get /cluster/resources --output-format json":
+		return `[{"type":"node","node":"pve1"},{"type":"qemu","vmid":100,"name":"db","node":"pve1","status":"running"},
+{"type":"qemu","vmid":200,"name":"ci","node":"pve2","status":"stopped"},{"type":"lxc","vmid":300,"node":"pve1"}]`, nil
+	case line == "pvesh get /nodes/pve1/qemu/100/status/current --output-format json":
+		return `{"vmid":100,"status":"running"}`, nil
+	}
+	return "", fmt.Errorf("command not allowed: %s", command)
+}
+
+func TestLiveResources_DockerAndProxmox(t *testing.T) {
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	live := newLiveResources(nil, logger)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ServerOptions{CompletionHandler: live.complete})
+	server.AddReceivingMiddleware(live.listMiddleware)
+	services := systemd.NewService(fakeRunner, nil, logger)
+	live.attach(server, procfs.NewCollector(t.TempDir()), services, fakeRunner, health.NewHealthChecker("1.0.0"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	session := connect(t, ctx, server)
+
+	// The empty proc tree and the missing systemd list nothing
+	list, err := session.ListResources(ctx, nil)
+	require.NoError(t, err)
+	uris := resourceURIs(list.Resources)
+	assert.Subset(t, uris, []string{"docker://container/web", "docker://container/worker", "proxmox://pve1/qemu/100", "proxmox://pve2/qemu/200"})
+	assert.NotContains(t, uris, "proxmox://pve1/qemu/300")
+
+	// Each template lists at most maxListed resources
+	live.maxListed = 1
+	list, err = session.ListResources(ctx, nil)
+	require.NoError(t, err)
+	uris = resourceURIs(list.Resources)
+	assert.Contains(t, uris, "docker://container/web")
+	assert.NotContains(t, uris, "docker://container/worker")
+	assert.Contains(t, uris, "proxmox://pve1/qemu/100")
+	assert.NotContains(t, uris, "proxmox://pve2/qemu/200")
+	live.maxListed = maxListedPerTemplate
+
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "docker://container/web"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Contents[0].Text, `"Id": "abc123"`)
+
+	result, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "docker://container/web/logs"})
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", result.Contents[0].MIMEType)
+	assert.Equal(t, "2024-01-15T10:00:00Z started\n", result.Contents[0].Text)
+
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "docker://container/-rm"})
+	assert.Error(t, err, "names are not passed as options")
+
+	result, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "proxmox://pve1/qemu/100"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Contents[0].Text, `"running"`)
+
+	completion, err := session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: refResource, URI: containerLogsTemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "id", Value: "w"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "worker"}, completion.Completion.Values)
+
+	completion, err = session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: refResource, URI: proxmoxVMTemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "node", Value: "pve"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pve1", "pve2"}, completion.Completion.Values)
+
+	completion, err = session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: refResource, URI: proxmoxVMTemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "vmid"},
+		Context:  &mcp.CompleteContext{Arguments: map[string]string{"node": "pve2"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"200"}, completion.Completion.Values)
+}
+
+func TestLiveResources_ListingsAreCached(t *testing.T) {
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	live := newLiveResources(nil, logger)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	live.now = func() time.Time { return now }
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(live.listMiddleware)
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	run := func(ctx context.Context, command string, args ...string) (string, error) {
+		mu.Lock()
+		calls[command]++
+		mu.Unlock()
+		return fakeRunner(ctx, command, args...)
+	}
+	live.attach(server, procfs.NewCollector(t.TempDir()), systemd.NewService(run, nil, logger), run, health.NewHealthChecker("1.0.0"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	session := connect(t, ctx, server)
+	count := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls["docker"], calls["pvesh"]
+	}
+
+	for i := 0; i < 3; i++ {
+		list, err := session.ListResources(ctx, nil)
+		require.NoError(t, err)
+		assert.Contains(t, resourceURIs(list.Resources), "docker://container/web")
+	}
+	docker, pvesh := count()
+	assert.Equal(t, 1, docker)
+	assert.Equal(t, 1, pvesh)
+
+	// Listed again once the cached listing expires
+	now = now.Add(listCacheTTL)
+	_, err := session.ListResources(ctx, nil)
+	require.NoError(t, err)
+	docker, pvesh = count()
+	assert.Equal(t, 2, docker)
+	assert.Equal(t, 2, pvesh)
+}
+
+func TestCompletionResult(t *testing.T) {
+	values := make([]string, 150)
+	for i := range values {
+		values[i] = strconv.Itoa(i)
+	}
+	result := completionResult(values)
+	assert.Len(t, result.Completion.Values, maxCompletionValues)
+	assert.True(t, result.Completion.HasMore)
+	assert.Equal(t, 150, result.Completion.Total)
+
+	assert.Equal(t, []string{"a", "ab"}, withPrefix([]string{"ab", "b", "a", "ab", ""}, "a"))
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// methodSetLevel is the MCP request that sets the log level
+const methodSetLevel = "logging/setLevel"
+
+// logLevelMiddleware applies the level of logging/setLevel requests to the
+// notifications of the requesting session, after the SDK has recorded it for
+// the session. The server's own outputs keep their configured level, so a
+// client cannot silence the file or syslog sinks; sessionLogs is nil when the
+// logger cannot forward entries to sessions.
+func logLevelMiddleware(logger logging.Logger, sessionLogs *sessionLogForwarder) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if err != nil || method != methodSetLevel || sessionLogs == nil {
+				return result, err
+			}
+			params, ok := req.GetParams().(*mcp.SetLoggingLevelParams)
+			session, isServerSession := req.GetSession().(*mcp.ServerSession)
+			if !ok || params == nil || !isServerSession {
+				return result, nil
+			}
+			level := levelFromMCP(params.Level)
+			sessionLogs.setLevel(session, level)
+			logger.Info("Log level set by client", map[string]any{
+				"level":      string(level),
+				"mcp_level":  string(params.Level),
+				"session_id": session.ID(),
+			})
+			return result, nil
+		}
+	}
+}
+
+// levelFromMCP maps the eight syslog-style MCP levels onto the logger levels
+func levelFromMCP(level mcp.LoggingLevel) logging.LogLevel {
+	switch level {
+	case "debug":
+		return logging.LogLevelDebug
+	case "info", "notice":
+		return logging.LogLevelInfo
+	case "warning":
+		return logging.LogLevelWarning
+	case "error":
+		return logging.LogLevelError
+	case "critical", "alert", "emergency":
+		return logging.LogLevelFatal
+	}
+	return logging.LogLevelInfo
+}
+
+// levelToMCP maps the logger levels onto MCP levels
+func levelToMCP(level logging.LogLevel) mcp.LoggingLevel {
+	switch level {
+	case logging.LogLevelDebug:
+		return "debug"
+	case logging.LogLevelWarning:
+		return "warning"
+	case logging.LogLevelError:
+		return "error"
+	case logging.LogLevelFatal:
+		return "critical"
+	}
+	return "info"
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelFromMCP(t *testing.T) {
+	assert.Equal(t, logging.LogLevelDebug, levelFromMCP("debug"))
+	assert.Equal(t, logging.LogLevelInfo, levelFromMCP("notice"))
+	assert.Equal(t, logging.LogLevelWarning, levelFromMCP("warning"))
+	assert.Equal(t, logging.LogLevelError, levelFromMCP("error"))
+	assert.Equal(t, logging.LogLevelFatal, levelFromMCP("emergency"))
+	assert.Equal(t, logging.LogLevelInfo, levelFromMCP("unknown"))
+}
+
+func TestSetLoggingLevel_AppliesOnlyToSessionNotifications(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(&buf, logging.LogLevelError)
+	server := BuildServer(Deps{
+		Logger:   logger,
+		Security: security.NewSecureCommandExecutor(nil),
+	}, "1.0.0")
+	messages := make(chan *mcp.LoggingMessageParams, 16)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			messages <- req.Params
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	require.NoError(t, session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "debug"}))
+	callCtx := logging.ContextWithCall(ctx, logging.Call{CorrelationID: "c1", Tool: "ssh"})
+	logging.FromContext(callCtx, logger).Debug("after", nil)
+
+	for {
+		select {
+		case msg := <-messages:
+			if msg.Level != "debug" || !strings.Contains(fmt.Sprint(msg.Data), "after") {
+				continue
+			}
+			// The client's level does not reach the server's own outputs
+			assert.Empty(t, buf.String())
+			return
+		case <-ctx.Done():
+			t.Fatal("debug entry not sent to the client")
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 
+	"mini-mcp/internal/domain/alerting"
+
 	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -39,6 +41,29 @@ func registerResources(server *mcp.Server) {
 	})
 }
 
+// registerAlertResources exposes the active alerts of the rules engine
+func registerAlertResources(server *mcp.Server, engine *alerting.Engine) {
+	server.AddResource(&mcp.Resource{
+		Name:        "active_alerts",
+		Description: "Pending and firing alerts raised by the alert rules",
+		MIMEType:    "application/json",
+		URI:         "alerts://active",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		jsonData, err := json.MarshalIndent(engine.Report(alerting.Filter{}, false), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal alerts: %w", err)
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      req.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			}},
+		}, nil
+	})
+}
+
 // getHostname returns the system hostname
 func getHostname() string {
 	hostname, err := os.Hostname()
@@ -1,6 +1,16 @@
 package server
 
 import (
+	appconfig "mini-mcp/internal/application/configfile"
+	appfile "mini-mcp/internal/application/file"
+	"mini-mcp/internal/domain/alerting"
+	"mini-mcp/internal/domain/infra"
+	"mini-mcp/internal/domain/logquery"
+	"mini-mcp/internal/domain/metrics"
+	"mini-mcp/internal/domain/network"
+	"mini-mcp/internal/domain/process"
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/domain/systemd"
 	"mini-mcp/internal/handlers/core"
 	"mini-mcp/internal/health"
 	"mini-mcp/internal/registry"
@@ -16,12 +26,29 @@ type Deps struct {
 	Logger        logging.Logger
 	Security      *security.SecureCommandExecutor
 	HealthChecker *health.HealthChecker
+	// MetricsHistory holds the samples of the background metrics sampler; nil disables metrics_query
+	MetricsHistory *metrics.Store
+	// Alerts is the alert rules engine; nil disables the alerts tool and resource
+	Alerts *alerting.Engine
 }
 
 // BuildServer constructs and returns a configured MCP server instance.
 // version is the semantic version of the implementation, used for handshake metadata.
 func BuildServer(deps Deps, version string) *mcp.Server {
-	server := mcp.NewServer(&mcp.Implementation{Name: "mini-mcp", Version: version}, nil)
+	// File resources need the path validator for subscriptions before the server exists
+	fileResources := newFileResources(deps.Security.GetPathValidator(), deps.Logger)
+	liveResources := newLiveResources(fileResources, deps.Logger)
+	server := mcp.NewServer(&mcp.Implementation{Name: "mini-mcp", Version: version}, &mcp.ServerOptions{
+		SubscribeHandler:   fileResources.subscribe,
+		UnsubscribeHandler: fileResources.unsubscribe,
+		CompletionHandler:  liveResources.complete,
+	})
+
+	// Let clients receive the server's log entries as notifications, at the
+	// level they set with logging/setLevel
+	server.AddReceivingMiddleware(logLevelMiddleware(deps.Logger, newSessionLogForwarder(deps.Logger)))
+	// List the processes, services, containers and VMs behind the resource templates
+	server.AddReceivingMiddleware(liveResources.listMiddleware)
 
 	// Initialize health checker if not provided
 	if deps.HealthChecker == nil {
@@ -34,18 +61,46 @@ func BuildServer(deps Deps, version string) *mcp.Server {
 
 	// Create handlers
 	commandHandler := core.NewCommandHandler(nil, deps.Logger) // Will be properly injected
-	fileHandler := core.NewFileHandler(nil, deps.Logger)       // Will be properly injected
-	systemHandler := core.NewSystemHandler(nil, deps.Logger)   // Will be properly injected
+	fileService := appfile.NewServiceWithDeps(deps.Security.GetPathValidator(), deps.Logger)
+	fileHandler := core.NewFileHandler(fileService, deps.Logger)
+	configService := appconfig.NewServiceWithDeps(deps.Security.GetPathValidator(), deps.Logger)
+	configHandler := core.NewConfigHandler(configService, deps.Logger)
+	systemHandler := core.NewSystemHandler(nil, deps.Logger) // Will be properly injected
+	collector := procfs.NewCollector(procfs.DefaultRoot)
+	processService := process.NewService(collector, deps.Security.GetProtectedProcesses(), deps.Logger)
+	networkService := network.NewService(collector, executor.ExecuteSystemCommand, deps.Logger)
+	systemdService := systemd.NewService(executor.ExecuteSystemCommand, deps.Security.GetControllableUnits(), deps.Logger)
+	logService := logquery.NewService(executor.StreamSystemCommand, deps.Security.GetPathValidator(), deps.Logger)
+	infraService := infra.NewService(collector, networkService, systemdService, executor.ExecuteSystemCommand, deps.Logger)
 
 	// Register tools by category (following SRP)
 	tools.RegisterCommandTools(server, toolRegistry, commandHandler.(*core.CommandHandlerImpl))
 	tools.RegisterFileTools(server, toolRegistry, fileHandler.(*core.FileHandlerImpl))
+	tools.RegisterArchiveTools(server, toolRegistry, fileHandler.(*core.FileHandlerImpl))
+	tools.RegisterConfigTools(server, toolRegistry, configHandler.(*core.ConfigHandlerImpl))
 	tools.RegisterSystemTools(server, toolRegistry, systemHandler.(*core.SystemHandlerImpl), deps.HealthChecker, deps.Logger)
-	tools.RegisterInfrastructureTools(server, toolRegistry, executor)
-	tools.RegisterPortProcessTools(server, toolRegistry, executor)
+	tools.RegisterInfrastructureTools(server, toolRegistry, executor, infraService)
+	tools.RegisterPortProcessTools(server, toolRegistry, collector, processService)
+	tools.RegisterProcessTools(server, toolRegistry, collector, processService)
+	tools.RegisterSystemMonitoringTools(server, toolRegistry, collector)
+	tools.RegisterNetworkTools(server, toolRegistry, networkService)
+	tools.RegisterServiceTools(server, toolRegistry, systemdService)
+	tools.RegisterLogTools(server, toolRegistry, logService)
+	tools.RegisterHealthTools(server, toolRegistry, deps.HealthChecker)
+	if deps.MetricsHistory != nil {
+		tools.RegisterMetricsHistoryTools(server, toolRegistry, deps.MetricsHistory)
+	}
+	if deps.Alerts != nil {
+		tools.RegisterAlertTools(server, toolRegistry, deps.Alerts)
+	}
 
 	// Register resources
 	registerResources(server)
+	if deps.Alerts != nil {
+		registerAlertResources(server, deps.Alerts)
+	}
+	fileResources.attach(server, fileHandler.(*core.FileHandlerImpl))
+	liveResources.attach(server, collector, systemdService, executor.ExecuteSystemCommand, deps.HealthChecker)
 
 	return server
 }
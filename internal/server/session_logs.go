@@ -0,0 +1,243 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// sessionLogQueueSize is how many entries wait for delivery before new ones are dropped
+	sessionLogQueueSize = 256
+	// sessionLogTimeout bounds the delivery of one notification
+	sessionLogTimeout = 5 * time.Second
+	// serverLoggerName names entries that no tool call produced
+	serverLoggerName = "mini-mcp"
+)
+
+// outputLogger is a logger whose outputs can change while it runs
+type outputLogger interface {
+	AddOutput(output logging.Output)
+	RemoveOutput(sink logging.Sink)
+}
+
+// sessionLogForwarder attaches a sessionLogSink to the logger while any
+// session listens for log notifications, from its logging/setLevel until the
+// last such session closes, so that a server whose clients are gone leaves
+// no sink or goroutine behind on the logger
+type sessionLogForwarder struct {
+	logger outputLogger
+
+	// mu serializes attaching and detaching the sink
+	mu   sync.Mutex
+	sink *sessionLogSink
+}
+
+// newSessionLogForwarder returns a forwarder for logger, or nil when its
+// outputs cannot change
+func newSessionLogForwarder(logger logging.Logger) *sessionLogForwarder {
+	outputs, ok := logger.(outputLogger)
+	if !ok {
+		return nil
+	}
+	return &sessionLogForwarder{logger: outputs}
+}
+
+// setLevel forwards entries at level to session until it closes
+func (f *sessionLogForwarder) setLevel(session *mcp.ServerSession, level logging.LogLevel) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sink == nil {
+		f.sink = newSessionLogSink()
+		f.logger.AddOutput(logging.Output{Sink: f.sink})
+	}
+	if f.sink.setSessionLevel(session, level) {
+		go f.forget(session)
+	}
+}
+
+// forget stops forwarding to session once it closes, detaching the sink
+// after the last session
+func (f *sessionLogForwarder) forget(session *mcp.ServerSession) {
+	_ = session.Wait()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sink.removeSession(session) > 0 {
+		return
+	}
+	f.logger.RemoveOutput(f.sink)
+	_ = f.sink.Close()
+	f.sink = nil
+}
+
+// sessionLogSink forwards log entries to the sessions that called
+// logging/setLevel as notifications/message. Entries are queued so that a
+// slow client never blocks the logger; the SDK drops them for sessions that
+// asked for a higher level. The sink takes entries down to the lowest level
+// any session asked for, independently of the levels of the other outputs.
+type sessionLogSink struct {
+	queue chan sessionLogEntry
+	done  chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+	// levels holds the level each session asked for with logging/setLevel
+	levels map[*mcp.ServerSession]logging.LogLevel
+}
+
+// sessionLogEntry is a queued notification and the session it is for
+type sessionLogEntry struct {
+	// inCall reports whether a tool call produced the entry
+	inCall bool
+	// sessionID is the session of the call that produced the entry
+	sessionID string
+	params    *mcp.LoggingMessageParams
+}
+
+// newSessionLogSink starts forwarding entries to the sessions set with setSessionLevel
+func newSessionLogSink() *sessionLogSink {
+	s := &sessionLogSink{
+		queue:  make(chan sessionLogEntry, sessionLogQueueSize),
+		done:   make(chan struct{}),
+		levels: make(map[*mcp.ServerSession]logging.LogLevel),
+	}
+	go s.run()
+	return s
+}
+
+// sessionLogLevels orders the levels a session can ask for from the lowest
+var sessionLogLevels = []logging.LogLevel{
+	logging.LogLevelDebug,
+	logging.LogLevelInfo,
+	logging.LogLevelWarning,
+	logging.LogLevelError,
+	logging.LogLevelFatal,
+}
+
+// Level returns the lowest level a session asked for; the SDK filters each
+// session's notifications by its own level
+func (s *sessionLogSink) Level() logging.LogLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lowest := len(sessionLogLevels) - 1
+	for _, level := range s.levels {
+		for i := 0; i < lowest; i++ {
+			if sessionLogLevels[i] == level {
+				lowest = i
+				break
+			}
+		}
+	}
+	return sessionLogLevels[lowest]
+}
+
+// setSessionLevel records the level session asked for and reports whether
+// the session is new
+func (s *sessionLogSink) setSessionLevel(session *mcp.ServerSession, level logging.LogLevel) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, known := s.levels[session]
+	s.levels[session] = level
+	return !known
+}
+
+// removeSession stops forwarding to session and returns how many sessions remain
+func (s *sessionLogSink) removeSession(session *mcp.ServerSession) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.levels, session)
+	return len(s.levels)
+}
+
+// Write queues the entry, dropping it when the queue is full
+func (s *sessionLogSink) Write(entry logging.LogEntry, line []byte) error {
+	queued := sessionLogEntry{params: sessionLogMessage(entry, line)}
+	_, queued.inCall = entry.Fields["correlation_id"].(string)
+	queued.sessionID, _ = entry.Fields["session_id"].(string)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	select {
+	case s.queue <- queued:
+	default:
+		// The clients are not keeping up; drop the entry
+	}
+	return nil
+}
+
+// Close delivers the queued entries and stops forwarding
+func (s *sessionLogSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+// run delivers queued entries until the sink is closed
+func (s *sessionLogSink) run() {
+	defer close(s.done)
+	for entry := range s.queue {
+		s.deliver(entry)
+	}
+}
+
+// deliver sends the entry to the session of the call that produced it.
+// Entries logged outside of a call are not sent, since they may describe the
+// work of other clients. Transports without session IDs, such as stdio, serve
+// a single session whose ID is empty.
+func (s *sessionLogSink) deliver(entry sessionLogEntry) {
+	if !entry.inCall {
+		return
+	}
+	s.mu.Lock()
+	var sessions []*mcp.ServerSession
+	for session := range s.levels {
+		if session.ID() == entry.sessionID {
+			sessions = append(sessions, session)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, session := range sessions {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionLogTimeout)
+		err := session.Log(ctx, entry.params)
+		cancel()
+		if err != nil {
+			// Logging the failure would forward it again
+			fmt.Fprintf(os.Stderr, "Failed to send log notification: %v\n", err)
+		}
+	}
+}
+
+// sessionLogMessage converts an entry to notification params. The data is the
+// JSON entry with its fields; the logger is the tool whose call produced it.
+func sessionLogMessage(entry logging.LogEntry, line []byte) *mcp.LoggingMessageParams {
+	name := serverLoggerName
+	if tool, ok := entry.Fields["tool"].(string); ok && tool != "" {
+		name = tool
+	}
+	params := &mcp.LoggingMessageParams{
+		Level:  levelToMCP(entry.Level),
+		Logger: name,
+		// The line is copied because the entry is sent after Write returns
+		Data: json.RawMessage(append([]byte(nil), line...)),
+	}
+	if id, ok := entry.Fields["correlation_id"].(string); ok {
+		params.Meta = mcp.Meta{logging.CorrelationMetaKey: id}
+	}
+	return params
+}
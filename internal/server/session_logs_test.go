@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionLogs_ForwardsEntriesToClient(t *testing.T) {
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	server := BuildServer(Deps{
+		Logger:   logger,
+		Security: security.NewSecureCommandExecutor(&security.SecurityConfig{AllowedCommands: []string{"ls"}}),
+	}, "1.0.0")
+
+	messages := make(chan *mcp.LoggingMessageParams, 16)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			messages <- req.Params
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	// Entries below the client's level are not sent
+	require.NoError(t, session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "warning"}))
+	callCtx := logging.ContextWithCall(ctx, logging.Call{CorrelationID: "c1", Tool: "ssh"})
+	logging.FromContext(callCtx, logger).Info("Executing SSH command", nil)
+	logging.FromContext(callCtx, logger).Warning("Command denied by policy", map[string]any{"command": "reboot"})
+	// Entries of no call or of another session's calls are not sent
+	logger.Error("Server error", nil, nil)
+	otherCtx := logging.ContextWithCall(ctx, logging.Call{CorrelationID: "c2", Tool: "ssh", SessionID: "other"})
+	logging.FromContext(otherCtx, logger).Error("Other session error", nil, nil)
+	logging.FromContext(callCtx, logger).Error("Command failed", nil, nil)
+
+	receive := func() (*mcp.LoggingMessageParams, logging.LogEntry) {
+		select {
+		case msg := <-messages:
+			data, err := json.Marshal(msg.Data)
+			require.NoError(t, err)
+			var entry logging.LogEntry
+			require.NoError(t, json.Unmarshal(data, &entry))
+			return msg, entry
+		case <-ctx.Done():
+			t.Fatal("no log notification received")
+			return nil, logging.LogEntry{}
+		}
+	}
+
+	msg, entry := receive()
+	assert.Equal(t, mcp.LoggingLevel("warning"), msg.Level)
+	assert.Equal(t, "ssh", msg.Logger)
+	assert.Equal(t, "c1", msg.Meta[logging.CorrelationMetaKey])
+	assert.Equal(t, "Command denied by policy", entry.Message)
+	assert.Equal(t, "reboot", entry.Fields["command"])
+	assert.Equal(t, "c1", entry.Fields["correlation_id"])
+
+	msg, entry = receive()
+	assert.Equal(t, mcp.LoggingLevel("error"), msg.Level)
+	assert.Equal(t, "Command failed", entry.Message)
+	assert.Equal(t, "c1", msg.Meta[logging.CorrelationMetaKey])
+}
+
+func TestSessionLogMessage_ServerLogger(t *testing.T) {
+	params := sessionLogMessage(logging.LogEntry{Level: logging.LogLevelError, Message: "Server error"}, []byte(`{}`))
+	assert.Equal(t, serverLoggerName, params.Logger)
+	assert.Empty(t, params.Meta)
+}
+
+func TestLevelToMCP(t *testing.T) {
+	for _, level := range []logging.LogLevel{logging.LogLevelDebug, logging.LogLevelInfo, logging.LogLevelWarning, logging.LogLevelError} {
+		assert.Equal(t, level, levelFromMCP(levelToMCP(level)))
+	}
+	assert.Equal(t, mcp.LoggingLevel("critical"), levelToMCP(logging.LogLevelFatal))
+}
+
+func TestSessionLogForwarder_DetachesAfterLastSession(t *testing.T) {
+	logger := logging.NewLogger(io.Discard, logging.LogLevelError)
+	forwarder := newSessionLogForwarder(logger)
+	require.NotNil(t, forwarder)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(logLevelMiddleware(logger, forwarder))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	attached := func() bool {
+		forwarder.mu.Lock()
+		defer forwarder.mu.Unlock()
+		return forwarder.sink != nil
+	}
+
+	first := connect(t, ctx, server)
+	second := connect(t, ctx, server)
+	assert.False(t, attached(), "no sink until a session sets a level")
+
+	require.NoError(t, first.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "info"}))
+	require.NoError(t, second.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "debug"}))
+	assert.True(t, attached())
+	assert.Equal(t, logging.LogLevelDebug, forwarder.sink.Level())
+
+	require.NoError(t, second.Close())
+	assert.Eventually(t, func() bool {
+		forwarder.mu.Lock()
+		defer forwarder.mu.Unlock()
+		return forwarder.sink != nil && forwarder.sink.Level() == logging.LogLevelInfo
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, first.Close())
+	assert.Eventually(t, func() bool { return !attached() }, 5*time.Second, 10*time.Millisecond)
+}
@@ -50,6 +50,15 @@ type SecurityConfig struct {
 
 	// Environment variables
 	AllowedEnvVars []string `json:"allowed_env_vars"`
+
+	// Process names (glob patterns) that process-control tools must never signal
+	ProtectedProcesses []string `json:"protected_processes"`
+
+	// Systemd units (glob patterns) that service_control may act on; none by default
+	ControllableUnits []string `json:"controllable_units"`
+
+	// Commands the server's own tools may run in addition to the allowlist
+	SystemCommands []string `json:"system_commands"`
 }
 
 // AuthConfig holds authentication configuration
@@ -153,12 +162,14 @@ func loadSecurityConfig() SecurityConfig {
 			"ps", "top", "df", "du", "free", "uptime", "who", "w",
 			"git", "docker", "nomad", "consul", "terraform",
 		},
-		WorkingDirectory: getEnv("SECURITY_WORKING_DIR", "/tmp"),
-		CommandTimeout:   getDurationEnv("SECURITY_COMMAND_TIMEOUT", 30*time.Second),
-		MaxOutputSize:    getInt64Env("SECURITY_MAX_OUTPUT_SIZE", 1024*1024), // 1MB
-		AllowedPaths:     []string{"/tmp", "/var/log", "/proc"},
-		BlockedPaths:     []string{"/etc/passwd", "/etc/shadow", "/root", "/home"},
-		AllowedEnvVars:   []string{"PATH", "HOME", "USER", "PWD"},
+		WorkingDirectory:   getEnv("SECURITY_WORKING_DIR", "/tmp"),
+		CommandTimeout:     getDurationEnv("SECURITY_COMMAND_TIMEOUT", 30*time.Second),
+		MaxOutputSize:      getInt64Env("SECURITY_MAX_OUTPUT_SIZE", 1024*1024), // 1MB
+		AllowedPaths:       []string{"/tmp", "/var/log", "/proc"},
+		BlockedPaths:       []string{"/etc/passwd", "/etc/shadow", "/root", "/home"},
+		AllowedEnvVars:     []string{"PATH", "HOME", "USER", "PWD"},
+		ProtectedProcesses: security.DefaultProtectedProcesses(),
+		SystemCommands:     security.DefaultSystemCommands(),
 	}
 
 	// Override with environment variables if provided
@@ -178,6 +189,18 @@ func loadSecurityConfig() SecurityConfig {
 		config.AllowedEnvVars = strings.Split(allowedEnvVars, ",")
 	}
 
+	if protected := getEnv("SECURITY_PROTECTED_PROCESSES", ""); protected != "" {
+		config.ProtectedProcesses = strings.Split(protected, ",")
+	}
+
+	if units := getEnv("SECURITY_CONTROLLABLE_UNITS", ""); units != "" {
+		config.ControllableUnits = strings.Split(units, ",")
+	}
+
+	if systemCommands := getEnv("SECURITY_SYSTEM_COMMANDS", ""); systemCommands != "" {
+		config.SystemCommands = strings.Split(systemCommands, ",")
+	}
+
 	return config
 }
 
@@ -237,13 +260,16 @@ func loadFeatureFlags() FeatureFlags {
 // ToSecurityConfig converts the security configuration to the security package format
 func (c *Config) ToSecurityConfig() *security.SecurityConfig {
 	return &security.SecurityConfig{
-		AllowedCommands:  c.Security.AllowedCommands,
-		WorkingDirectory: c.Security.WorkingDirectory,
-		CommandTimeout:   c.Security.CommandTimeout,
-		MaxOutputSize:    c.Security.MaxOutputSize,
-		AllowedEnvVars:   c.Security.AllowedEnvVars,
-		AllowedPaths:     c.Security.AllowedPaths,
-		BlockedPaths:     c.Security.BlockedPaths,
+		AllowedCommands:    c.Security.AllowedCommands,
+		WorkingDirectory:   c.Security.WorkingDirectory,
+		CommandTimeout:     c.Security.CommandTimeout,
+		MaxOutputSize:      c.Security.MaxOutputSize,
+		AllowedEnvVars:     c.Security.AllowedEnvVars,
+		AllowedPaths:       c.Security.AllowedPaths,
+		BlockedPaths:       c.Security.BlockedPaths,
+		ProtectedProcesses: c.Security.ProtectedProcesses,
+		ControllableUnits:  c.Security.ControllableUnits,
+		SystemCommands:     c.Security.SystemCommands,
 	}
 }
 
@@ -398,6 +424,12 @@ func loadConfigFromEnv(config *Config) {
 	if allowedCommands := getEnv("SECURITY_ALLOWED_COMMANDS", ""); allowedCommands != "" {
 		config.Security.AllowedCommands = strings.Split(allowedCommands, ",")
 	}
+	if units := getEnv("SECURITY_CONTROLLABLE_UNITS", ""); units != "" {
+		config.Security.ControllableUnits = strings.Split(units, ",")
+	}
+	if systemCommands := getEnv("SECURITY_SYSTEM_COMMANDS", ""); systemCommands != "" {
+		config.Security.SystemCommands = strings.Split(systemCommands, ",")
+	}
 	if workingDir := getEnv("SECURITY_WORKING_DIR", ""); workingDir != "" {
 		config.Security.WorkingDirectory = workingDir
 	}
@@ -3,6 +3,7 @@ package errors
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"runtime"
 	"strings"
 	"time"
@@ -244,6 +245,48 @@ func GetErrorCode(err error) ErrorCode {
 	return ErrorCodeInternalError
 }
 
+// errorCodePattern matches the bracketed code that ErrorResponse and security
+// errors put at the start of their text
+var errorCodePattern = regexp.MustCompile(`^\s*(?:security error )?\[([A-Z][A-Z_]+)\]`)
+
+// knownErrorCodes holds the codes ParseErrorCode accepts
+var knownErrorCodes = map[ErrorCode]bool{
+	ErrorCodeUnauthorized:       true,
+	ErrorCodeInvalidAPIKey:      true,
+	ErrorCodeRateLimitExceeded:  true,
+	ErrorCodeCommandNotFound:    true,
+	ErrorCodeCommandTimeout:     true,
+	ErrorCodeCommandFailed:      true,
+	ErrorCodeCommandBlocked:     true,
+	ErrorCodeFileNotFound:       true,
+	ErrorCodePermissionDenied:   true,
+	ErrorCodePathBlocked:        true,
+	ErrorCodeInvalidInput:       true,
+	ErrorCodeMissingRequired:    true,
+	ErrorCodeInvalidFormat:      true,
+	ErrorCodeInternalError:      true,
+	ErrorCodeServiceUnavailable: true,
+	ErrorCodeResourceExhausted:  true,
+}
+
+// IsKnownErrorCode reports whether code is one of the ErrorCode constants
+func IsKnownErrorCode(code ErrorCode) bool {
+	return knownErrorCodes[code]
+}
+
+// ParseErrorCode extracts the error code from error text such as
+// "[FILE_NOT_FOUND] File not found: /tmp/x", which is how tools usually
+// surface an ErrorResponse in their results. Only a code leading the text
+// and naming a known ErrorCode is accepted, so brackets quoted from command
+// output or user input are not mistaken for one.
+func ParseErrorCode(text string) (ErrorCode, bool) {
+	match := errorCodePattern.FindStringSubmatch(text)
+	if match == nil || !IsKnownErrorCode(ErrorCode(match[1])) {
+		return "", false
+	}
+	return ErrorCode(match[1]), true
+}
+
 // GetErrorMessage extracts error message from an error
 func GetErrorMessage(err error) string {
 	if errResp, ok := err.(*ErrorResponse); ok {
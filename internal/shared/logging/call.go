@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+)
+
+const (
+	// CorrelationMetaKey is the _meta key of a tool call that carries its
+	// correlation ID, either supplied by the client or derived by the server
+	CorrelationMetaKey = "mini-mcp/correlation_id"
+	// CorrelationEnvVar passes the correlation ID to subprocesses
+	CorrelationEnvVar = "MINI_MCP_CORRELATION_ID"
+)
+
+// Call identifies the tool call whose handling produced a log entry
+type Call struct {
+	// CorrelationID ties together the log lines, audit records, errors and
+	// subprocesses of the call
+	CorrelationID string
+	// Tool is the name of the called tool
+	Tool string
+	// SessionID is the MCP session of the caller; empty for stdio
+	SessionID string
+}
+
+// callKey is the context key of the current call
+type callKey struct{}
+
+// NewCorrelationID returns a random correlation ID
+func NewCorrelationID() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// ContextWithCall returns a context for handling call
+func ContextWithCall(ctx context.Context, call Call) context.Context {
+	return context.WithValue(ctx, callKey{}, call)
+}
+
+// CallFromContext returns the call ctx is handling
+func CallFromContext(ctx context.Context) (Call, bool) {
+	call, ok := ctx.Value(callKey{}).(Call)
+	return call, ok
+}
+
+// CorrelationID returns the correlation ID of the call ctx is handling, or ""
+func CorrelationID(ctx context.Context) string {
+	call, _ := CallFromContext(ctx)
+	return call.CorrelationID
+}
+
+// CommandEnv returns the environment of a subprocess started for the call
+// ctx is handling: env, or the server's environment when env is nil, plus
+// the correlation ID
+func CommandEnv(ctx context.Context, env []string) []string {
+	id := CorrelationID(ctx)
+	if id == "" {
+		return env
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	return append(env[:len(env):len(env)], CorrelationEnvVar+"="+id)
+}
+
+// fields adds the call to the fields of an entry
+func (c Call) fields(fields map[string]any) map[string]any {
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+	fields["correlation_id"] = c.CorrelationID
+	if _, ok := fields["tool"]; !ok && c.Tool != "" {
+		fields["tool"] = c.Tool
+	}
+	if c.SessionID != "" {
+		fields["session_id"] = c.SessionID
+	}
+	return fields
+}
+
+// FromContext returns a logger that tags entries with the call ctx is
+// handling, or logger itself outside of a call
+func FromContext(ctx context.Context, logger Logger) Logger {
+	if _, ok := CallFromContext(ctx); !ok {
+		return logger
+	}
+	return &ContextLogger{logger: logger, ctx: ctx}
+}
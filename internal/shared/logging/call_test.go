@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext_TagsEntriesWithCall(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LogLevelDebug)
+	assert.Same(t, logger, FromContext(context.Background(), logger))
+
+	ctx := ContextWithCall(context.Background(), Call{CorrelationID: "abc:1", Tool: "read", SessionID: "s1"})
+	FromContext(ctx, logger).Error("Read failed", errors.New("boom"), map[string]any{"tool": "file_read"})
+
+	var entry LogEntry
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, map[string]any{
+		"correlation_id": "abc:1",
+		"session_id":     "s1",
+		"tool":           "file_read",
+		"error":          "boom",
+	}, entry.Fields)
+	assert.Equal(t, "abc:1", CorrelationID(ctx))
+	assert.Empty(t, CorrelationID(context.Background()))
+}
+
+func TestCommandEnv(t *testing.T) {
+	assert.Nil(t, CommandEnv(context.Background(), nil), "inherits the environment")
+	assert.Equal(t, []string{"PATH=/bin"}, CommandEnv(context.Background(), []string{"PATH=/bin"}))
+
+	ctx := ContextWithCall(context.Background(), Call{CorrelationID: "abc:1"})
+	env := []string{"PATH=/bin"}
+	assert.Equal(t, []string{"PATH=/bin", CorrelationEnvVar + "=abc:1"}, CommandEnv(ctx, env))
+	assert.Equal(t, []string{"PATH=/bin"}, env, "the given environment is not modified")
+	assert.Contains(t, CommandEnv(ctx, nil), CorrelationEnvVar+"=abc:1")
+}
@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sink types
+const (
+	SinkStderr = "stderr"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+)
+
+// Config is the logging configuration file
+type Config struct {
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// SinkConfig is one destination of log entries
+type SinkConfig struct {
+	// Type is stderr, file or syslog
+	Type string `yaml:"type"`
+	// Level is the lowest level the sink receives; empty uses -log-level
+	Level string `yaml:"level"`
+
+	// Path is the log file of a file sink
+	Path string `yaml:"path"`
+	// MaxSizeMB rotates the file before it grows beyond this many megabytes
+	MaxSizeMB int `yaml:"max_size_mb"`
+	// MaxAge rotates the file once it has been written to for this long
+	MaxAge time.Duration `yaml:"max_age"`
+	// MaxBackups is how many rotated files are kept; 0 keeps them all
+	MaxBackups int `yaml:"max_backups"`
+	// Compress gzips rotated files
+	Compress bool `yaml:"compress"`
+
+	// Network is unix (default) or udp for a syslog sink
+	Network string `yaml:"network"`
+	// Address is the socket path (default /dev/log) or host:port of a syslog sink
+	Address string `yaml:"address"`
+	// Facility is the syslog facility, e.g. daemon (default) or local0
+	Facility string `yaml:"facility"`
+	// AppName is the syslog APP-NAME (default mini-mcp)
+	AppName string `yaml:"app_name"`
+}
+
+// LoadConfig reads and validates a logging configuration file
+func LoadConfig(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logging config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses and validates a logging configuration
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse logging config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validate checks every sink
+func (c *Config) validate() error {
+	if len(c.Sinks) == 0 {
+		return fmt.Errorf("logging config has no sinks")
+	}
+	for i, sink := range c.Sinks {
+		if err := sink.validate(); err != nil {
+			return fmt.Errorf("sink %d (%s): %w", i+1, sink.Type, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the fields of one sink
+func (s SinkConfig) validate() error {
+	if s.Level != "" {
+		if _, err := ParseLevel(s.Level); err != nil {
+			return err
+		}
+	}
+	switch s.Type {
+	case SinkStderr:
+	case SinkFile:
+		if s.Path == "" {
+			return fmt.Errorf("path is required")
+		}
+		if s.MaxSizeMB < 0 || s.MaxAge < 0 || s.MaxBackups < 0 {
+			return fmt.Errorf("max_size_mb, max_age and max_backups must not be negative")
+		}
+	case SinkSyslog:
+		if s.Network != "" && s.Network != SyslogUnix && s.Network != SyslogUDP {
+			return fmt.Errorf("invalid network %q: must be unix or udp", s.Network)
+		}
+		if s.Network == SyslogUDP && s.Address == "" {
+			return fmt.Errorf("address is required for udp")
+		}
+		if s.Facility != "" {
+			if _, ok := syslogFacilities[strings.ToLower(s.Facility)]; !ok {
+				return fmt.Errorf("invalid facility %q: must be one of %s", s.Facility, strings.Join(SyslogFacilities(), ", "))
+			}
+		}
+	default:
+		return fmt.Errorf("invalid type %q: must be stderr, file or syslog", s.Type)
+	}
+	return nil
+}
+
+// Open creates the sinks; a sink without a level logs at defaultLevel. On
+// failure the sinks already opened are closed.
+func (c *Config) Open(defaultLevel LogLevel) ([]Output, error) {
+	outputs := make([]Output, 0, len(c.Sinks))
+	for i, cfg := range c.Sinks {
+		level := defaultLevel
+		if cfg.Level != "" {
+			level, _ = ParseLevel(cfg.Level)
+		}
+		sink, err := cfg.open()
+		if err != nil {
+			var errs []error
+			for _, output := range outputs {
+				errs = append(errs, output.Sink.Close())
+			}
+			return nil, errors.Join(append([]error{fmt.Errorf("sink %d (%s): %w", i+1, cfg.Type, err)}, errs...)...)
+		}
+		outputs = append(outputs, Output{Sink: sink, Level: level})
+	}
+	return outputs, nil
+}
+
+// open creates the sink of the configuration
+func (s SinkConfig) open() (Sink, error) {
+	switch s.Type {
+	case SinkFile:
+		return NewFileSink(FileOptions{
+			Path:       s.Path,
+			MaxSize:    int64(s.MaxSizeMB) * 1024 * 1024,
+			MaxAge:     s.MaxAge,
+			MaxBackups: s.MaxBackups,
+			Compress:   s.Compress,
+		})
+	case SinkSyslog:
+		return NewSyslogSink(SyslogOptions{
+			Network:  s.Network,
+			Address:  s.Address,
+			Facility: s.Facility,
+			AppName:  s.AppName,
+		})
+	}
+	return NewWriterSink(os.Stderr), nil
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+sinks:
+  - type: stderr
+  - type: file
+    level: debug
+    path: /var/log/app.log
+    max_size_mb: 10
+    max_age: 12h
+  - type: syslog
+    network: udp
+    address: 127.0.0.1:514
+    facility: LOCAL0
+`))
+	require.NoError(t, err)
+	require.Len(t, cfg.Sinks, 3)
+	assert.Equal(t, 12*time.Hour, cfg.Sinks[1].MaxAge)
+
+	invalid := map[string]string{
+		"no sinks":       "sinks: []\n",
+		"unknown field":  "sinks:\n  - type: file\n    pth: /tmp/a.log\n",
+		"unknown type":   "sinks:\n  - type: journald\n",
+		"bad level":      "sinks:\n  - type: stderr\n    level: loud\n",
+		"missing path":   "sinks:\n  - type: file\n",
+		"negative size":  "sinks:\n  - type: file\n    path: /tmp/a.log\n    max_size_mb: -1\n",
+		"bad network":    "sinks:\n  - type: syslog\n    network: tcp\n",
+		"udp no address": "sinks:\n  - type: syslog\n    network: udp\n",
+		"bad facility":   "sinks:\n  - type: syslog\n    facility: local8\n",
+		"bad max_age":    "sinks:\n  - type: file\n    path: /tmp/a.log\n    max_age: daily\n",
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_Example(t *testing.T) {
+	cfg, err := LoadConfig("../../../logging.yaml.example")
+	require.NoError(t, err)
+	require.Len(t, cfg.Sinks, 3)
+	assert.Equal(t, SinkFile, cfg.Sinks[1].Type)
+	assert.Equal(t, 24*time.Hour, cfg.Sinks[1].MaxAge)
+}
+
+func TestConfig_Open(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	cfg, err := ParseConfig([]byte("sinks:\n  - type: file\n    path: " + path + "\n    level: error\n  - type: stderr\n"))
+	require.NoError(t, err)
+
+	outputs, err := cfg.Open(LogLevelInfo)
+	require.NoError(t, err)
+	require.Len(t, outputs, 2)
+	assert.Equal(t, LogLevelError, outputs[0].Level)
+	assert.Equal(t, LogLevelInfo, outputs[1].Level, "default level")
+
+	logger := NewMultiLogger(outputs[:1])
+	logger.Info("skipped", nil)
+	logger.Error("written", nil, nil)
+	require.NoError(t, logger.(*LoggerImpl).Close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"written"}, messages(t, bytes.NewBuffer(data)))
+
+	cfg.Sinks = append(cfg.Sinks, SinkConfig{Type: SinkSyslog, Address: filepath.Join(t.TempDir(), "missing")})
+	_, err = cfg.Open(LogLevelInfo)
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Latency sketch parameters. Durations are counted in buckets whose bounds
+// grow geometrically, so a sketch has at most sketchBuckets buckets however
+// many durations it holds, and a percentile read from it is within
+// SketchAccuracy of the true value.
+const (
+	// SketchAccuracy is the relative error of the percentiles a sketch reports
+	SketchAccuracy = 0.02
+	// sketchMin and sketchMax clamp the durations a sketch distinguishes
+	sketchMin = time.Microsecond
+	sketchMax = time.Hour
+)
+
+var (
+	sketchGamma    = (1 + SketchAccuracy) / (1 - SketchAccuracy)
+	sketchLogGamma = math.Log(sketchGamma)
+	sketchBuckets  = sketchIndex(sketchMax) + 1
+)
+
+// sketch is a streaming latency histogram in the manner of DDSketch: bucket
+// i counts the durations in (sketchMin·γ^(i-1), sketchMin·γ^i]
+type sketch struct {
+	counts map[int]uint64
+	count  uint64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// sketchIndex returns the bucket of a duration
+func sketchIndex(d time.Duration) int {
+	if d <= sketchMin {
+		return 0
+	}
+	if d >= sketchMax {
+		d = sketchMax
+	}
+	return int(math.Ceil(math.Log(float64(d)/float64(sketchMin)) / sketchLogGamma))
+}
+
+// sketchValue returns the duration a bucket stands for, chosen so that every
+// duration in the bucket is within SketchAccuracy of it
+func sketchValue(i int) time.Duration {
+	if i == 0 {
+		return sketchMin
+	}
+	return time.Duration(float64(sketchMin) * 2 * math.Pow(sketchGamma, float64(i)) / (sketchGamma + 1))
+}
+
+// add counts one duration
+func (s *sketch) add(d time.Duration) {
+	if s.counts == nil {
+		s.counts = make(map[int]uint64)
+	}
+	s.counts[sketchIndex(d)]++
+	if s.count == 0 || d < s.min {
+		s.min = d
+	}
+	if d > s.max {
+		s.max = d
+	}
+	s.count++
+	s.sum += d
+}
+
+// merge adds the durations of another sketch
+func (s *sketch) merge(o *sketch) {
+	if o.count == 0 {
+		return
+	}
+	if s.counts == nil {
+		s.counts = make(map[int]uint64, len(o.counts))
+	}
+	for i, n := range o.counts {
+		s.counts[i] += n
+	}
+	if s.count == 0 || o.min < s.min {
+		s.min = o.min
+	}
+	if o.max > s.max {
+		s.max = o.max
+	}
+	s.count += o.count
+	s.sum += o.sum
+}
+
+// mean returns the average duration
+func (s *sketch) mean() time.Duration {
+	if s.count == 0 {
+		return 0
+	}
+	return s.sum / time.Duration(s.count)
+}
+
+// quantile returns the duration below which a fraction q of the durations fall
+func (s *sketch) quantile(q float64) time.Duration {
+	switch {
+	case s.count == 0:
+		return 0
+	case q <= 0:
+		return s.min
+	case q >= 1:
+		return s.max
+	}
+	indexes := make([]int, 0, len(s.counts))
+	for i := range s.counts {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	rank := uint64(q * float64(s.count-1))
+	var seen uint64
+	value := s.max
+	for _, i := range indexes {
+		seen += s.counts[i]
+		if seen > rank {
+			value = sketchValue(i)
+			break
+		}
+	}
+	// The exact extremes are known; keep estimates within them
+	return min(max(value, s.min), s.max)
+}
+
+// slot holds the calls of one time slice of a window ring
+type slot struct {
+	// start is the Unix time in seconds the slice starts at; 0 marks an unused slot
+	start    int64
+	latency  sketch
+	requests int64
+	errors   int64
+}
+
+// ring keeps the calls of the last len(slots)·width in fixed time slices,
+// reusing the oldest slice as time moves on
+type ring struct {
+	width time.Duration
+	slots []slot
+}
+
+// newRing creates a ring covering span in slices of width
+func newRing(width, span time.Duration) *ring {
+	return &ring{width: width, slots: make([]slot, int(span/width))}
+}
+
+// add records a call at now
+func (r *ring) add(now time.Time, d time.Duration, failed bool) {
+	width := int64(r.width / time.Second)
+	start := now.Unix() / width * width
+	s := &r.slots[int(start/width)%len(r.slots)]
+	if s.start != start {
+		*s = slot{start: start}
+	}
+	s.latency.add(d)
+	s.requests++
+	if failed {
+		s.errors++
+	}
+}
+
+// window merges the slices that overlap the span before now
+func (r *ring) window(now time.Time, span time.Duration) (latency sketch, requests, errors int64) {
+	from := now.Add(-span).Unix()
+	width := int64(r.width / time.Second)
+	for i := range r.slots {
+		s := &r.slots[i]
+		if s.start == 0 || s.start+width <= from || s.start > now.Unix() {
+			continue
+		}
+		latency.merge(&s.latency)
+		requests += s.requests
+		errors += s.errors
+	}
+	return latency, requests, errors
+}
@@ -3,9 +3,11 @@ package logging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -47,12 +49,31 @@ type LogEntry struct {
 	Stack     string         `json:"stack,omitempty"`
 }
 
+// LevelController changes the level of a logger at runtime
+type LevelController interface {
+	// SetLevel makes every output but a LevelSink log at level, whatever its
+	// configured level
+	SetLevel(level LogLevel)
+	// ResetLevel returns every output to its configured level
+	ResetLevel()
+	// ToggleDebug sets DEBUG, or resets the level when DEBUG is already set,
+	// and reports whether DEBUG is now set
+	ToggleDebug() bool
+}
+
+// Output is a sink and the lowest level of the entries it receives
+type Output struct {
+	Sink  Sink
+	Level LogLevel
+}
+
 // LoggerImpl provides structured logging functionality
 type LoggerImpl struct {
-	output  io.Writer
-	level   LogLevel
-	mu      sync.Mutex
-	metrics *Metrics
+	outputs []Output
+	// override replaces the level of every output but a LevelSink while it is set
+	override LogLevel
+	mu       sync.Mutex
+	metrics  *Metrics
 }
 
 // NewLogger creates a new logger
@@ -61,9 +82,14 @@ func NewLogger(output io.Writer, level LogLevel) Logger {
 		output = os.Stderr
 	}
 
+	return NewMultiLogger([]Output{{Sink: NewWriterSink(output), Level: level}})
+}
+
+// NewMultiLogger creates a logger that writes every entry to the outputs
+// whose level it meets
+func NewMultiLogger(outputs []Output) Logger {
 	return &LoggerImpl{
-		output:  output,
-		level:   level,
+		outputs: outputs,
 		metrics: NewMetrics(),
 	}
 }
@@ -115,23 +141,61 @@ func (l *LoggerImpl) Error(message string, err error, fields map[string]any) {
 // Fatal logs a fatal message and exits
 func (l *LoggerImpl) Fatal(message string, fields map[string]any) {
 	l.Log(LogLevelFatal, message, fields)
+	_ = l.Close()
 	os.Exit(1)
 }
 
-// shouldLog determines if a message should be logged based on the current level
-func (l *LoggerImpl) shouldLog(level LogLevel) bool {
-	levels := map[LogLevel]int{
-		LogLevelDebug:   0,
-		LogLevelInfo:    1,
-		LogLevelWarning: 2,
-		LogLevelError:   3,
-		LogLevelFatal:   4,
+// levelRanks orders the levels from least to most severe
+var levelRanks = map[LogLevel]int{
+	LogLevelDebug:   0,
+	LogLevelInfo:    1,
+	LogLevelWarning: 2,
+	LogLevelError:   3,
+	LogLevelFatal:   4,
+}
+
+// ParseLevel parses a level name in any case; WARN is accepted for WARNING
+func ParseLevel(name string) (LogLevel, error) {
+	level := LogLevel(strings.ToUpper(strings.TrimSpace(name)))
+	if level == "WARN" {
+		level = LogLevelWarning
 	}
+	if _, ok := levelRanks[level]; !ok {
+		return "", fmt.Errorf("invalid log level %q: must be DEBUG, INFO, WARNING, ERROR or FATAL", name)
+	}
+	return level, nil
+}
 
-	return levels[level] >= levels[l.level]
+// accepts reports whether an output with threshold receives entries of level
+func accepts(threshold, level LogLevel) bool {
+	return levelRanks[level] >= levelRanks[threshold]
 }
 
-// writeEntry writes a log entry to the output
+// outputLevel returns the level an output currently logs at
+func (l *LoggerImpl) outputLevel(output Output) LogLevel {
+	if sink, ok := output.Sink.(LevelSink); ok {
+		return sink.Level()
+	}
+	if l.override != "" {
+		return l.override
+	}
+	return output.Level
+}
+
+// shouldLog determines if any output takes messages of the level
+func (l *LoggerImpl) shouldLog(level LogLevel) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, output := range l.outputs {
+		if accepts(l.outputLevel(output), level) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeEntry writes a log entry to the outputs whose level it meets
 func (l *LoggerImpl) writeEntry(entry LogEntry) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -146,13 +210,75 @@ func (l *LoggerImpl) writeEntry(entry LogEntry) {
 		return
 	}
 
-	// Write to output
-	if _, err := fmt.Fprintln(l.output, string(data)); err != nil {
-		// Log error but don't fail the operation
-		GetGlobalLogger().Error("Failed to write log output", err, map[string]any{
-			"output_type": "log_entry",
-		})
+	// Write to outputs; a failing output cannot log its own failure
+	for _, output := range l.outputs {
+		if !accepts(l.outputLevel(output), entry.Level) {
+			continue
+		}
+		if err := output.Sink.Write(entry, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write log entry: %v\n", err)
+		}
+	}
+}
+
+// SetLevel makes every output but a LevelSink log at level, whatever its configured level
+func (l *LoggerImpl) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.override = level
+}
+
+// ResetLevel returns every output to its configured level
+func (l *LoggerImpl) ResetLevel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.override = ""
+}
+
+// ToggleDebug sets DEBUG, or resets the level when DEBUG is already set
+func (l *LoggerImpl) ToggleDebug() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.override == LogLevelDebug {
+		l.override = ""
+		return false
+	}
+	l.override = LogLevelDebug
+	return true
+}
+
+// AddOutput adds an output, e.g. one that only exists once the server does
+func (l *LoggerImpl) AddOutput(output Output) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.outputs = append(l.outputs, output)
+}
+
+// RemoveOutput removes the outputs writing to sink, without closing it
+func (l *LoggerImpl) RemoveOutput(sink Sink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	outputs := make([]Output, 0, len(l.outputs))
+	for _, output := range l.outputs {
+		if output.Sink != sink {
+			outputs = append(outputs, output)
+		}
+	}
+	l.outputs = outputs
+}
+
+// Close closes every output
+func (l *LoggerImpl) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	for _, output := range l.outputs {
+		if err := output.Sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
 }
 
 // WithContext creates a logger with context information
@@ -197,6 +323,10 @@ func (l *ContextLogger) Log(level LogLevel, message string, fields map[string]an
 		fields["user_agent"] = userAgent
 	}
 
+	if call, ok := CallFromContext(l.ctx); ok {
+		fields = call.fields(fields)
+	}
+
 	l.logger.Log(level, message, fields)
 }
 
@@ -217,7 +347,28 @@ func (l *ContextLogger) Warning(message string, fields map[string]any) {
 
 // Error logs an error message with context
 func (l *ContextLogger) Error(message string, err error, fields map[string]any) {
-	l.logger.Error(message, err, fields)
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+
+	l.Log(LogLevelError, message, fields)
+}
+
+// Fatal logs a fatal message with context and exits
+func (l *ContextLogger) Fatal(message string, fields map[string]any) {
+	if call, ok := CallFromContext(l.ctx); ok {
+		fields = call.fields(fields)
+	}
+	l.logger.Fatal(message, fields)
+}
+
+// GetMetrics returns the metrics of the underlying logger
+func (l *ContextLogger) GetMetrics() *Metrics {
+	return l.logger.GetMetrics()
 }
 
 // NewToolLogger creates a new tool logger
@@ -269,298 +420,6 @@ func (l *ToolLogger) Error(message string, err error, fields map[string]any) {
 	l.logger.Error(message, err, fields)
 }
 
-// Metrics provides metrics collection for logging
-type Metrics struct {
-	mu sync.RWMutex
-
-	// Log counts by level and tool
-	LogCounts map[string]int64 `json:"log_counts"`
-
-	// Response times by tool
-	ResponseTimes map[string][]time.Duration `json:"response_times"`
-
-	// Error rates by tool
-	ErrorRates map[string]float64 `json:"error_rates"`
-
-	// Active connections
-	ActiveConnections int `json:"active_connections"`
-
-	// Request counts by tool
-	RequestCounts map[string]int64 `json:"request_counts"`
-
-	// Performance metrics
-	PerformanceMetrics map[string]*PerformanceMetrics `json:"performance_metrics"`
-}
-
-// PerformanceMetrics tracks performance data for a specific tool
-type PerformanceMetrics struct {
-	TotalRequests     int64         `json:"total_requests"`
-	TotalDuration     time.Duration `json:"total_duration"`
-	AverageDuration   time.Duration `json:"average_duration"`
-	MinDuration       time.Duration `json:"min_duration"`
-	MaxDuration       time.Duration `json:"max_duration"`
-	P95Duration       time.Duration `json:"p95_duration"`
-	P99Duration       time.Duration `json:"p99_duration"`
-	RequestsPerSecond float64       `json:"requests_per_second"`
-	ErrorCount        int64         `json:"error_count"`
-	ErrorRate         float64       `json:"error_rate"`
-	LastUpdated       time.Time     `json:"last_updated"`
-}
-
-// NewMetrics creates new metrics
-func NewMetrics() *Metrics {
-	return &Metrics{
-		LogCounts:          make(map[string]int64),
-		ResponseTimes:      make(map[string][]time.Duration),
-		ErrorRates:         make(map[string]float64),
-		RequestCounts:      make(map[string]int64),
-		PerformanceMetrics: make(map[string]*PerformanceMetrics),
-	}
-}
-
-// IncrementLogCount increments the log count for a level and tool
-func (m *Metrics) IncrementLogCount(level LogLevel, tool string) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	key := fmt.Sprintf("%s:%s", level, tool)
-	m.LogCounts[key]++
-}
-
-// RecordResponseTime records a response time for a tool
-func (m *Metrics) RecordResponseTime(tool string, duration time.Duration) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if m.ResponseTimes[tool] == nil {
-		m.ResponseTimes[tool] = make([]time.Duration, 0)
-	}
-
-	m.ResponseTimes[tool] = append(m.ResponseTimes[tool], duration)
-
-	// Keep only the last 1000 measurements
-	if len(m.ResponseTimes[tool]) > 1000 {
-		m.ResponseTimes[tool] = m.ResponseTimes[tool][len(m.ResponseTimes[tool])-1000:]
-	}
-}
-
-// UpdateErrorRate updates the error rate for a tool
-func (m *Metrics) UpdateErrorRate(tool string, rate float64) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	m.ErrorRates[tool] = rate
-}
-
-// SetActiveConnections sets the number of active connections
-func (m *Metrics) SetActiveConnections(count int) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	m.ActiveConnections = count
-}
-
-// GetAverageResponseTime gets the average response time for a tool
-func (m *Metrics) GetAverageResponseTime(tool string) time.Duration {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	times, exists := m.ResponseTimes[tool]
-	if !exists || len(times) == 0 {
-		return 0
-	}
-
-	var total time.Duration
-	for _, t := range times {
-		total += t
-	}
-
-	return total / time.Duration(len(times))
-}
-
-// RecordRequest records a request for a tool
-func (m *Metrics) RecordRequest(tool string, duration time.Duration, success bool) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// Increment request count
-	m.RequestCounts[tool]++
-
-	// Update response times
-	if m.ResponseTimes[tool] == nil {
-		m.ResponseTimes[tool] = make([]time.Duration, 0)
-	}
-	m.ResponseTimes[tool] = append(m.ResponseTimes[tool], duration)
-
-	// Keep only the last 1000 measurements
-	if len(m.ResponseTimes[tool]) > 1000 {
-		m.ResponseTimes[tool] = m.ResponseTimes[tool][len(m.ResponseTimes[tool])-1000:]
-	}
-
-	// Update performance metrics
-	m.updatePerformanceMetrics(tool, duration, success)
-}
-
-// RecordError records an error for a tool
-func (m *Metrics) RecordError(tool string) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// Update error rates
-	totalRequests := m.RequestCounts[tool]
-	errorCount := float64(0)
-	if perfMetrics, exists := m.PerformanceMetrics[tool]; exists {
-		perfMetrics.ErrorCount++
-		errorCount = float64(perfMetrics.ErrorCount)
-	}
-	if totalRequests > 0 {
-		m.ErrorRates[tool] = errorCount / float64(totalRequests)
-	}
-}
-
-// updatePerformanceMetrics updates performance metrics for a tool
-func (m *Metrics) updatePerformanceMetrics(tool string, duration time.Duration, success bool) {
-	if m.PerformanceMetrics[tool] == nil {
-		m.PerformanceMetrics[tool] = &PerformanceMetrics{
-			MinDuration: duration,
-			MaxDuration: duration,
-		}
-	}
-
-	perf := m.PerformanceMetrics[tool]
-	perf.TotalRequests++
-	perf.TotalDuration += duration
-	perf.AverageDuration = perf.TotalDuration / time.Duration(perf.TotalRequests)
-
-	// Update min/max
-	if duration < perf.MinDuration {
-		perf.MinDuration = duration
-	}
-	if duration > perf.MaxDuration {
-		perf.MaxDuration = duration
-	}
-
-	// Update percentiles (simplified calculation)
-	responseTimes := m.ResponseTimes[tool]
-	if len(responseTimes) > 0 {
-		sortedTimes := make([]time.Duration, len(responseTimes))
-		copy(sortedTimes, responseTimes)
-
-		// Simple percentile calculation
-		p95Index := int(float64(len(sortedTimes)) * 0.95)
-		p99Index := int(float64(len(sortedTimes)) * 0.99)
-
-		if p95Index >= len(sortedTimes) {
-			p95Index = len(sortedTimes) - 1
-		}
-		if p99Index >= len(sortedTimes) {
-			p99Index = len(sortedTimes) - 1
-		}
-
-		perf.P95Duration = sortedTimes[p95Index]
-		perf.P99Duration = sortedTimes[p99Index]
-	}
-
-	// Update error count and rate
-	if !success {
-		perf.ErrorCount++
-	}
-	totalReqs := float64(perf.TotalRequests)
-	perf.ErrorRate = float64(perf.ErrorCount) / totalReqs
-
-	// Calculate requests per second (over last minute)
-	elapsed := time.Since(perf.LastUpdated)
-	if elapsed > time.Minute {
-		perf.RequestsPerSecond = float64(perf.TotalRequests) / elapsed.Seconds()
-		perf.LastUpdated = time.Now()
-	}
-}
-
-// IncrementActiveConnections increments the active connections count
-func (m *Metrics) IncrementActiveConnections() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.ActiveConnections++
-}
-
-// DecrementActiveConnections decrements the active connections count
-func (m *Metrics) DecrementActiveConnections() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	if m.ActiveConnections > 0 {
-		m.ActiveConnections--
-	}
-}
-
-// GetMetricsSummary returns a summary of all metrics
-func (m *Metrics) GetMetricsSummary() map[string]any {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	summary := make(map[string]any)
-
-	// Log counts
-	summary["log_counts"] = m.LogCounts
-
-	// Request counts
-	summary["request_counts"] = m.RequestCounts
-
-	// Average response times
-	avgResponseTimes := make(map[string]time.Duration)
-	for tool := range m.ResponseTimes {
-		avgResponseTimes[tool] = m.GetAverageResponseTime(tool)
-	}
-	summary["average_response_times"] = avgResponseTimes
-
-	// Error rates
-	summary["error_rates"] = m.ErrorRates
-
-	// Active connections
-	summary["active_connections"] = m.ActiveConnections
-
-	// Performance metrics
-	summary["performance_metrics"] = m.PerformanceMetrics
-
-	return summary
-}
-
-// GetToolMetrics returns metrics for a specific tool
-func (m *Metrics) GetToolMetrics(tool string) map[string]any {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	metrics := make(map[string]any)
-
-	// Request count
-	metrics["requests"] = m.RequestCounts[tool]
-
-	// Average response time
-	metrics["avg_response_time"] = m.GetAverageResponseTime(tool)
-
-	// Error rate
-	metrics["error_rate"] = m.ErrorRates[tool]
-
-	// Performance metrics
-	if perf, exists := m.PerformanceMetrics[tool]; exists {
-		metrics["performance"] = perf
-	}
-
-	return metrics
-}
-
-// ResetMetrics resets all metrics (useful for testing)
-func (m *Metrics) ResetMetrics() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	m.LogCounts = make(map[string]int64)
-	m.ResponseTimes = make(map[string][]time.Duration)
-	m.ErrorRates = make(map[string]float64)
-	m.RequestCounts = make(map[string]int64)
-	m.PerformanceMetrics = make(map[string]*PerformanceMetrics)
-	m.ActiveConnections = 0
-}
-
 // Global logger instance
 var globalLogger Logger
 
@@ -574,6 +433,19 @@ func InitGlobalLogger(level LogLevel) {
 	})
 }
 
+// InitGlobalLoggerWithOutputs initializes the global logger with several outputs
+func InitGlobalLoggerWithOutputs(outputs []Output) {
+	globalLogger = NewMultiLogger(outputs)
+	levels := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		levels = append(levels, string(output.Level))
+	}
+	globalLogger.Info("Global logger initialized", map[string]any{
+		"levels":  levels,
+		"outputs": len(outputs),
+	})
+}
+
 // GetGlobalLogger returns the global logger
 func GetGlobalLogger() Logger {
 	if globalLogger == nil {
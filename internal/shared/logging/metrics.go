@@ -0,0 +1,428 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-mcp/internal/shared/errors"
+)
+
+// MaxTrackedTools bounds how many tools are tracked separately; calls of
+// further tools are counted together under OtherTool
+const MaxTrackedTools = 200
+
+// OtherTool collects the calls of tools beyond MaxTrackedTools
+const OtherTool = "_other"
+
+// OtherErrorCode collects the errors whose code is not a known errors.ErrorCode
+const OtherErrorCode = "_other"
+
+// LatencyBuckets are the upper bounds, in seconds, of the tool call latency histogram
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Windows are the sliding windows tool metrics are reported over. The 1m and
+// 5m windows are kept in 10s slices and the 1h window in 5m slices, so a
+// window covers its length give or take one slice.
+var Windows = []struct {
+	Name string
+	Span time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+}
+
+// Metrics provides metrics collection for logging. Memory stays bounded:
+// latencies are kept in fixed-accuracy sketches rather than as samples, and
+// at most MaxTrackedTools tools are tracked.
+type Metrics struct {
+	mu sync.RWMutex
+
+	// Log counts by level and tool
+	LogCounts map[string]int64 `json:"log_counts"`
+
+	// Active connections
+	ActiveConnections int `json:"active_connections"`
+
+	tools map[string]*toolStats
+	// now is the clock; replaced in tests
+	now func() time.Time
+}
+
+// toolStats tracks the calls of one tool
+type toolStats struct {
+	// latency covers every call since start or reset
+	latency     sketch
+	requests    int64
+	errors      int64
+	lastRequest time.Time
+
+	// fine holds the last 5 minutes in 10s slices, coarse the last hour in 5m slices
+	fine   *ring
+	coarse *ring
+
+	inFlight     int64
+	errorsByCode map[string]int64
+	// buckets counts calls per LatencyBuckets bucket, not cumulative; the last slot is +Inf
+	buckets []uint64
+}
+
+// newToolStats creates the stats of a tool that has not been called yet
+func newToolStats() *toolStats {
+	return &toolStats{
+		fine:         newRing(10*time.Second, 5*time.Minute),
+		coarse:       newRing(5*time.Minute, time.Hour),
+		errorsByCode: make(map[string]int64),
+		buckets:      make([]uint64, len(LatencyBuckets)+1),
+	}
+}
+
+// PerformanceMetrics tracks performance data for a specific tool since the
+// server started
+type PerformanceMetrics struct {
+	TotalRequests   int64         `json:"total_requests"`
+	TotalDuration   time.Duration `json:"total_duration"`
+	AverageDuration time.Duration `json:"average_duration"`
+	MinDuration     time.Duration `json:"min_duration"`
+	MaxDuration     time.Duration `json:"max_duration"`
+	P50Duration     time.Duration `json:"p50_duration"`
+	P90Duration     time.Duration `json:"p90_duration"`
+	P95Duration     time.Duration `json:"p95_duration"`
+	P99Duration     time.Duration `json:"p99_duration"`
+	// RequestsPerSecond is the throughput over the last minute
+	RequestsPerSecond float64   `json:"requests_per_second"`
+	ErrorCount        int64     `json:"error_count"`
+	ErrorRate         float64   `json:"error_rate"`
+	LastUpdated       time.Time `json:"last_updated"`
+}
+
+// WindowMetrics summarizes the calls of a tool within a sliding window
+type WindowMetrics struct {
+	Window            string        `json:"window"`
+	Requests          int64         `json:"requests"`
+	Errors            int64         `json:"errors"`
+	ErrorRate         float64       `json:"error_rate"`
+	RequestsPerSecond float64       `json:"requests_per_second"`
+	AverageDuration   time.Duration `json:"average_duration"`
+	P50Duration       time.Duration `json:"p50_duration"`
+	P90Duration       time.Duration `json:"p90_duration"`
+	P99Duration       time.Duration `json:"p99_duration"`
+	MaxDuration       time.Duration `json:"max_duration"`
+}
+
+// ToolCallStats is a point-in-time copy of the call counters of one tool
+type ToolCallStats struct {
+	Tool     string
+	Total    int64
+	InFlight int64
+	// Errors counts failed calls by error code
+	Errors map[string]int64
+	// Buckets holds the cumulative call count at or below each of LatencyBuckets
+	Buckets    []uint64
+	Count      uint64
+	SumSeconds float64
+}
+
+// NewMetrics creates new metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		LogCounts: make(map[string]int64),
+		tools:     make(map[string]*toolStats),
+		now:       time.Now,
+	}
+}
+
+// IncrementLogCount increments the log count for a level and tool
+func (m *Metrics) IncrementLogCount(level LogLevel, tool string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", level, tool)
+	m.LogCounts[key]++
+}
+
+// stats returns the stats of a tool, creating them; the caller holds the lock
+func (m *Metrics) stats(tool string) *toolStats {
+	if stats, ok := m.tools[tool]; ok {
+		return stats
+	}
+	if len(m.tools) >= MaxTrackedTools {
+		// m.tools holds MaxTrackedTools tools before OtherTool is added
+		tool = OtherTool
+		if stats, ok := m.tools[tool]; ok {
+			return stats
+		}
+	}
+	stats := newToolStats()
+	m.tools[tool] = stats
+	return stats
+}
+
+// RecordRequest records a request for a tool
+func (m *Metrics) RecordRequest(tool string, duration time.Duration, success bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.record(m.stats(tool), duration, !success)
+}
+
+// record adds a call to the stats of a tool; the caller holds the lock
+func (m *Metrics) record(stats *toolStats, duration time.Duration, failed bool) {
+	now := m.now()
+	stats.latency.add(duration)
+	stats.requests++
+	if failed {
+		stats.errors++
+	}
+	stats.lastRequest = now
+	stats.fine.add(now, duration, failed)
+	stats.coarse.add(now, duration, failed)
+
+	seconds := duration.Seconds()
+	i := 0
+	for i < len(LatencyBuckets) && seconds > LatencyBuckets[i] {
+		i++
+	}
+	stats.buckets[i]++
+}
+
+// ToolCallStarted marks a tool call as in flight until RecordToolCall records it
+func (m *Metrics) ToolCallStarted(tool string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats(tool).inFlight++
+}
+
+// RecordToolCall records a finished tool call started with ToolCallStarted;
+// errorCode is empty when the call succeeded
+func (m *Metrics) RecordToolCall(tool string, duration time.Duration, errorCode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats(tool)
+	if stats.inFlight > 0 {
+		stats.inFlight--
+	}
+	if errorCode != "" {
+		// Codes are parsed from tool output, so unknown ones share a bucket
+		// to keep the per-tool error map bounded
+		if !errors.IsKnownErrorCode(errors.ErrorCode(errorCode)) {
+			errorCode = OtherErrorCode
+		}
+		stats.errorsByCode[errorCode]++
+	}
+	m.record(stats, duration, errorCode != "")
+}
+
+// ToolCallStats returns the call counters of every tool, ordered by tool name
+func (m *Metrics) ToolCallStats() []ToolCallStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]ToolCallStats, 0, len(m.tools))
+	for tool, stats := range m.tools {
+		s := ToolCallStats{
+			Tool:       tool,
+			Total:      stats.requests,
+			InFlight:   stats.inFlight,
+			Errors:     make(map[string]int64, len(stats.errorsByCode)),
+			Buckets:    make([]uint64, len(LatencyBuckets)),
+			SumSeconds: stats.latency.sum.Seconds(),
+		}
+		for code, n := range stats.errorsByCode {
+			s.Errors[code] = n
+		}
+		for i, n := range stats.buckets {
+			s.Count += n
+			if i < len(LatencyBuckets) {
+				s.Buckets[i] = s.Count
+			}
+		}
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tool < result[j].Tool })
+	return result
+}
+
+// LogCountsByLevel returns the number of entries written at each level
+func (m *Metrics) LogCountsByLevel() map[LogLevel]int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[LogLevel]int64)
+	for key, n := range m.LogCounts {
+		level, _, _ := strings.Cut(key, ":")
+		counts[LogLevel(level)] += n
+	}
+	return counts
+}
+
+// IncrementActiveConnections increments the active connections count
+func (m *Metrics) IncrementActiveConnections() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ActiveConnections++
+}
+
+// DecrementActiveConnections decrements the active connections count
+func (m *Metrics) DecrementActiveConnections() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ActiveConnections > 0 {
+		m.ActiveConnections--
+	}
+}
+
+// SetActiveConnections sets the number of active connections
+func (m *Metrics) SetActiveConnections(count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ActiveConnections = count
+}
+
+// GetActiveConnections returns the number of active connections
+func (m *Metrics) GetActiveConnections() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ActiveConnections
+}
+
+// GetAverageResponseTime gets the average response time for a tool
+func (m *Metrics) GetAverageResponseTime(tool string) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats, ok := m.tools[tool]
+	if !ok {
+		return 0
+	}
+	return stats.latency.mean()
+}
+
+// errorRate is errors as a fraction of requests
+func errorRate(errors, requests int64) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(errors) / float64(requests)
+}
+
+// performance summarizes the calls of a tool since start; the caller holds the lock
+func (m *Metrics) performance(stats *toolStats, now time.Time) *PerformanceMetrics {
+	lastMinute := m.window(stats, now, time.Minute, "1m")
+	return &PerformanceMetrics{
+		TotalRequests:     stats.requests,
+		TotalDuration:     stats.latency.sum,
+		AverageDuration:   stats.latency.mean(),
+		MinDuration:       stats.latency.min,
+		MaxDuration:       stats.latency.max,
+		P50Duration:       stats.latency.quantile(0.50),
+		P90Duration:       stats.latency.quantile(0.90),
+		P95Duration:       stats.latency.quantile(0.95),
+		P99Duration:       stats.latency.quantile(0.99),
+		RequestsPerSecond: lastMinute.RequestsPerSecond,
+		ErrorCount:        stats.errors,
+		ErrorRate:         errorRate(stats.errors, stats.requests),
+		LastUpdated:       stats.lastRequest,
+	}
+}
+
+// window summarizes the calls of a tool within span before now; the caller holds the lock
+func (m *Metrics) window(stats *toolStats, now time.Time, span time.Duration, name string) WindowMetrics {
+	r := stats.fine
+	if span > time.Duration(len(r.slots))*r.width {
+		r = stats.coarse
+	}
+	latency, requests, errors := r.window(now, span)
+	return WindowMetrics{
+		Window:            name,
+		Requests:          requests,
+		Errors:            errors,
+		ErrorRate:         errorRate(errors, requests),
+		RequestsPerSecond: float64(requests) / span.Seconds(),
+		AverageDuration:   latency.mean(),
+		P50Duration:       latency.quantile(0.50),
+		P90Duration:       latency.quantile(0.90),
+		P99Duration:       latency.quantile(0.99),
+		MaxDuration:       latency.max,
+	}
+}
+
+// GetMetricsSummary returns a summary of all metrics
+func (m *Metrics) GetMetricsSummary() map[string]any {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.now()
+	summary := make(map[string]any)
+
+	// Log counts
+	summary["log_counts"] = m.LogCounts
+
+	requestCounts := make(map[string]int64, len(m.tools))
+	avgResponseTimes := make(map[string]time.Duration, len(m.tools))
+	errorRates := make(map[string]float64, len(m.tools))
+	performance := make(map[string]*PerformanceMetrics, len(m.tools))
+	for tool, stats := range m.tools {
+		requestCounts[tool] = stats.requests
+		avgResponseTimes[tool] = stats.latency.mean()
+		errorRates[tool] = errorRate(stats.errors, stats.requests)
+		performance[tool] = m.performance(stats, now)
+	}
+	summary["request_counts"] = requestCounts
+	summary["average_response_times"] = avgResponseTimes
+	summary["error_rates"] = errorRates
+
+	// Active connections
+	summary["active_connections"] = m.ActiveConnections
+
+	// Performance metrics
+	summary["performance_metrics"] = performance
+
+	return summary
+}
+
+// GetToolMetrics returns metrics for a specific tool, including its latency
+// percentiles, error rate and throughput over each of Windows
+func (m *Metrics) GetToolMetrics(tool string) map[string]any {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	metrics := make(map[string]any)
+	stats, ok := m.tools[tool]
+	if !ok {
+		metrics["requests"] = int64(0)
+		metrics["avg_response_time"] = time.Duration(0)
+		metrics["error_rate"] = float64(0)
+		return metrics
+	}
+
+	now := m.now()
+	metrics["requests"] = stats.requests
+	metrics["avg_response_time"] = stats.latency.mean()
+	metrics["error_rate"] = errorRate(stats.errors, stats.requests)
+	metrics["performance"] = m.performance(stats, now)
+
+	windows := make([]WindowMetrics, 0, len(Windows))
+	for _, w := range Windows {
+		windows = append(windows, m.window(stats, now, w.Span, w.Name))
+	}
+	metrics["windows"] = windows
+
+	return metrics
+}
+
+// ResetMetrics resets all metrics (useful for testing)
+func (m *Metrics) ResetMetrics() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.LogCounts = make(map[string]int64)
+	m.tools = make(map[string]*toolStats)
+	m.ActiveConnections = 0
+}
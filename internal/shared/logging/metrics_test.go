@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// testMetrics returns metrics on a clock the test moves
+func testMetrics() (*Metrics, *time.Time) {
+	m := NewMetrics()
+	now := epoch
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestSketch_Quantiles(t *testing.T) {
+	var s sketch
+	rng := rand.New(rand.NewSource(1))
+	for _, i := range rng.Perm(10000) {
+		s.add(time.Duration(i+1) * 100 * time.Microsecond)
+	}
+
+	for _, tt := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.5, 500 * time.Millisecond},
+		{0.9, 900 * time.Millisecond},
+		{0.99, 990 * time.Millisecond},
+	} {
+		got := s.quantile(tt.q)
+		assert.InEpsilon(t, float64(tt.want), float64(got), SketchAccuracy+0.001, "p%v", tt.q*100)
+	}
+	assert.Equal(t, 100*time.Microsecond, s.quantile(0))
+	assert.Equal(t, time.Second, s.quantile(1))
+	assert.LessOrEqual(t, len(s.counts), sketchBuckets)
+
+	var merged sketch
+	merged.merge(&s)
+	merged.merge(&sketch{})
+	assert.Equal(t, s.quantile(0.5), merged.quantile(0.5))
+	assert.Equal(t, s.count, merged.count)
+}
+
+func TestMetrics_ToolMetrics(t *testing.T) {
+	m, now := testMetrics()
+
+	// Ten calls an hour ago, then one call a second for two minutes of which one in four fails
+	for i := 0; i < 10; i++ {
+		m.RecordRequest("file_read", 2*time.Second, true)
+	}
+	*now = now.Add(50 * time.Minute)
+	for i := 0; i < 120; i++ {
+		*now = now.Add(time.Second)
+		m.RecordRequest("file_read", time.Duration(i+1)*time.Millisecond, i%4 != 0)
+	}
+
+	metrics := m.GetToolMetrics("file_read")
+	assert.Equal(t, int64(130), metrics["requests"])
+	assert.InDelta(t, 30.0/130, metrics["error_rate"], 1e-9)
+
+	perf := metrics["performance"].(*PerformanceMetrics)
+	assert.Equal(t, 2*time.Second, perf.MaxDuration)
+	assert.Equal(t, time.Millisecond, perf.MinDuration)
+	assert.InEpsilon(t, float64(2*time.Second), float64(perf.P99Duration), SketchAccuracy)
+	assert.Equal(t, int64(30), perf.ErrorCount)
+
+	windows := metrics["windows"].([]WindowMetrics)
+	require.Len(t, windows, 3)
+	lastMinute := windows[0]
+	assert.Equal(t, "1m", lastMinute.Window)
+	// The 1m window covers 60s to 70s, depending on where in its 10s slice now falls
+	assert.GreaterOrEqual(t, lastMinute.Requests, int64(60))
+	assert.LessOrEqual(t, lastMinute.Requests, int64(70))
+	assert.InDelta(t, 0.25, lastMinute.ErrorRate, 0.02)
+	assert.InDelta(t, float64(lastMinute.Requests)/60, lastMinute.RequestsPerSecond, 1e-9)
+	assert.Equal(t, 120*time.Millisecond, lastMinute.MaxDuration)
+	assert.InEpsilon(t, float64(lastMinute.P50Duration), float64(120*time.Millisecond-time.Duration(lastMinute.Requests/2)*time.Millisecond), 0.05)
+
+	assert.Equal(t, int64(120), windows[1].Requests, "the 5m window holds the last two minutes")
+	assert.Equal(t, int64(130), windows[2].Requests, "the 1h window still holds the calls of 50 minutes ago")
+
+	// Two hours on every window is empty and the totals remain
+	*now = now.Add(2 * time.Hour)
+	windows = m.GetToolMetrics("file_read")["windows"].([]WindowMetrics)
+	for _, w := range windows {
+		assert.Zero(t, w.Requests, w.Window)
+		assert.Zero(t, w.P99Duration, w.Window)
+	}
+	assert.Equal(t, int64(130), m.GetToolMetrics("file_read")["requests"])
+
+	assert.Equal(t, int64(0), m.GetToolMetrics("unknown")["requests"])
+}
+
+func TestMetrics_ToolCalls(t *testing.T) {
+	m, _ := testMetrics()
+
+	m.ToolCallStarted("run")
+	m.ToolCallStarted("run")
+	m.RecordToolCall("run", 30*time.Millisecond, "")
+	m.RecordToolCall("run", 3*time.Second, "COMMAND_BLOCKED")
+	m.RecordToolCall("run", time.Second, "NOT_A_CODE_1")
+	m.RecordToolCall("run", time.Second, "NOT_A_CODE_2")
+
+	stats := m.ToolCallStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(4), stats[0].Total)
+	assert.Equal(t, int64(0), stats[0].InFlight)
+	assert.Equal(t, map[string]int64{"COMMAND_BLOCKED": 1, OtherErrorCode: 2}, stats[0].Errors)
+	assert.Equal(t, uint64(4), stats[0].Count)
+	assert.Equal(t, uint64(0), stats[0].Buckets[2])
+	assert.Equal(t, uint64(1), stats[0].Buckets[3], "30ms is at or below the 0.05s bucket")
+	assert.InDelta(t, 5.03, stats[0].SumSeconds, 1e-9)
+
+	summary := m.GetMetricsSummary()
+	assert.Equal(t, 0.75, summary["error_rates"].(map[string]float64)["run"])
+	assert.Equal(t, int64(4), summary["request_counts"].(map[string]int64)["run"])
+}
+
+func TestMetrics_BoundedTools(t *testing.T) {
+	m, _ := testMetrics()
+	for i := 0; i < MaxTrackedTools+50; i++ {
+		m.RecordRequest(fmt.Sprintf("tool_%d", i), time.Millisecond, true)
+	}
+
+	assert.Len(t, m.tools, MaxTrackedTools+1)
+	assert.Equal(t, int64(50), m.GetToolMetrics(OtherTool)["requests"])
+
+	m.ResetMetrics()
+	assert.Empty(t, m.ToolCallStats())
+}
@@ -0,0 +1,240 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files so that they sort by age
+const backupTimeFormat = "20060102T150405.000"
+
+// FileOptions configures a rotating log file
+type FileOptions struct {
+	Path string
+	// MaxSize rotates the file before it grows beyond this many bytes; 0 never
+	MaxSize int64
+	// MaxAge rotates the file once it has been written to for this long; 0 never
+	MaxAge time.Duration
+	// MaxBackups is how many rotated files are kept; 0 keeps them all
+	MaxBackups int
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// FileSink writes entries as JSON lines to a file, rotating it by size and
+// age. A rotated file is renamed to <name>-<time><ext>, then compressed and
+// pruned in the background.
+type FileSink struct {
+	opts   FileOptions
+	now    func() time.Time
+	rename func(oldpath, newpath string) error
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	// maintenance serializes compressing and pruning; wg waits for it on Close
+	maintenance sync.Mutex
+	wg          sync.WaitGroup
+}
+
+// NewFileSink opens the log file for appending, creating it and its directory
+func NewFileSink(opts FileOptions) (*FileSink, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	s := &FileSink{opts: opts, now: time.Now, rename: os.Rename}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the current file and records its size
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.opts.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	s.file, s.size, s.opened = file, info.Size(), s.now()
+	return nil
+}
+
+// Write appends the entry, rotating the file first when it is due
+func (s *FileSink) Write(entry LogEntry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("log file %s is closed", s.opts.Path)
+	}
+	n := int64(len(line)) + 1
+	if s.size > 0 && s.rotationDue(n) {
+		if err := s.rotate(); err != nil {
+			if s.file == nil {
+				return err
+			}
+			// Keep appending to the current file; the next write retries
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+	written, err := s.file.Write(append(line, '\n'))
+	s.size += int64(written)
+	return err
+}
+
+// rotationDue reports whether writing n more bytes needs a new file
+func (s *FileSink) rotationDue(n int64) bool {
+	if s.opts.MaxSize > 0 && s.size+n > s.opts.MaxSize {
+		return true
+	}
+	return s.opts.MaxAge > 0 && s.now().Sub(s.opened) >= s.opts.MaxAge
+}
+
+// rotate renames the current file aside and opens a new one. When the file
+// cannot be renamed it is reopened, so that entries are not lost until the
+// rotation succeeds.
+func (s *FileSink) rotate() error {
+	closeErr := s.file.Close()
+	backup := s.backupName(s.now())
+	var renameErr error
+	if closeErr == nil {
+		renameErr = s.rename(s.opts.Path, backup)
+	}
+	if err := s.open(); err != nil {
+		s.file = nil
+		return err
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close log file: %w", closeErr)
+	}
+	if renameErr != nil {
+		return fmt.Errorf("failed to rotate log file: %w", renameErr)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.maintain(backup)
+	}()
+	return nil
+}
+
+// backupName returns an unused name for a file rotated at t
+func (s *FileSink) backupName(t time.Time) string {
+	prefix, ext := s.backupPrefix()
+	name := prefix + t.UTC().Format(backupTimeFormat) + ext
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s%s.%d%s", prefix, t.UTC().Format(backupTimeFormat), i, ext)
+	}
+	return name
+}
+
+// backupPrefix returns the parts of rotated file names around the time
+func (s *FileSink) backupPrefix() (prefix, ext string) {
+	ext = filepath.Ext(s.opts.Path)
+	return strings.TrimSuffix(s.opts.Path, ext) + "-", ext
+}
+
+// maintain compresses a rotated file and removes the oldest backups
+func (s *FileSink) maintain(backup string) {
+	s.maintenance.Lock()
+	defer s.maintenance.Unlock()
+
+	if s.opts.Compress {
+		if err := compressFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to compress rotated log file: %v\n", err)
+		}
+	}
+	if s.opts.MaxBackups > 0 {
+		backups := s.Backups()
+		for _, old := range backups[:max(0, len(backups)-s.opts.MaxBackups)] {
+			if err := os.Remove(old); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to remove old log file: %v\n", err)
+			}
+		}
+	}
+}
+
+// Backups returns the rotated files from oldest to newest
+func (s *FileSink) Backups() []string {
+	prefix, ext := s.backupPrefix()
+	matches, _ := filepath.Glob(prefix + "*" + ext + "*")
+	backups := matches[:0]
+	for _, name := range matches {
+		rest := strings.TrimPrefix(name, prefix)
+		if strings.HasSuffix(rest, ext) || strings.HasSuffix(rest, ext+".gz") {
+			if _, err := time.Parse(backupTimeFormat, rest[:min(len(rest), len(backupTimeFormat))]); err == nil {
+				backups = append(backups, name)
+			}
+		}
+	}
+	sort.Strings(backups)
+	return backups
+}
+
+// Close closes the file after any compression in progress finishes
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.file != nil {
+		err = s.file.Close()
+		s.file = nil
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// compressFile replaces a file with its gzip
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(dst.Name())
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(dst.Name())
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(dst.Name())
+		return err
+	}
+	return os.Remove(path)
+}
+
+// fileExists reports whether a file exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
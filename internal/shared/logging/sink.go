@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"io"
+	"sync"
+)
+
+// Sink writes encoded log entries to one destination
+type Sink interface {
+	// Write writes one entry; line is its JSON encoding without a newline
+	Write(entry LogEntry, line []byte) error
+	// Close releases the destination
+	Close() error
+}
+
+// LevelSink is a sink that decides its own level at runtime, e.g. from the
+// levels its readers asked for; neither the level of its Output nor the
+// logger's override apply to it
+type LevelSink interface {
+	Sink
+	// Level returns the lowest level of the entries the sink currently takes
+	Level() LogLevel
+}
+
+// WriterSink writes entries as JSON lines to a writer such as stderr
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink over w; closing the sink leaves w open
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write writes the entry as one line
+func (s *WriterSink) Write(entry LogEntry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close does nothing; the writer belongs to the caller
+func (s *WriterSink) Close() error {
+	return nil
+}
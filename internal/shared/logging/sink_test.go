@@ -0,0 +1,257 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// messages returns the messages of the JSON lines in buf
+func messages(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry LogEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		msgs = append(msgs, entry.Message)
+	}
+	buf.Reset()
+	return msgs
+}
+
+func TestLogger_OutputLevels(t *testing.T) {
+	var verbose, quiet bytes.Buffer
+	logger := NewMultiLogger([]Output{
+		{Sink: NewWriterSink(&verbose), Level: LogLevelInfo},
+		{Sink: NewWriterSink(&quiet), Level: LogLevelError},
+	})
+	controller := logger.(LevelController)
+
+	logger.Debug("debug", nil)
+	logger.Info("info", nil)
+	logger.Error("error", nil, nil)
+	assert.Equal(t, []string{"info", "error"}, messages(t, &verbose))
+	assert.Equal(t, []string{"error"}, messages(t, &quiet))
+
+	// An override applies to every output
+	controller.SetLevel(LogLevelWarning)
+	logger.Info("info", nil)
+	logger.Warning("warning", nil)
+	assert.Equal(t, []string{"warning"}, messages(t, &verbose))
+	assert.Equal(t, []string{"warning"}, messages(t, &quiet))
+
+	assert.True(t, controller.ToggleDebug())
+	logger.Debug("debug", nil)
+	assert.Equal(t, []string{"debug"}, messages(t, &verbose))
+	assert.Equal(t, []string{"debug"}, messages(t, &quiet))
+	assert.False(t, controller.ToggleDebug())
+	logger.Debug("debug", nil)
+	logger.Info("info", nil)
+	assert.Equal(t, []string{"info"}, messages(t, &verbose))
+	assert.Empty(t, messages(t, &quiet))
+
+	controller.SetLevel(LogLevelDebug)
+	controller.ResetLevel()
+	logger.Debug("debug", nil)
+	assert.Empty(t, messages(t, &verbose))
+}
+
+// levelSink is a writer sink that picks its own level
+type levelSink struct {
+	*WriterSink
+	level LogLevel
+}
+
+func (s *levelSink) Level() LogLevel { return s.level }
+
+func TestLogger_LevelSinkIgnoresOverride(t *testing.T) {
+	var plain, leveled bytes.Buffer
+	sink := &levelSink{WriterSink: NewWriterSink(&leveled), level: LogLevelDebug}
+	logger := NewMultiLogger([]Output{
+		{Sink: NewWriterSink(&plain), Level: LogLevelInfo},
+		{Sink: sink, Level: LogLevelError},
+	})
+
+	logger.Debug("debug", nil)
+	assert.Empty(t, messages(t, &plain))
+	assert.Equal(t, []string{"debug"}, messages(t, &leveled))
+
+	logger.(LevelController).SetLevel(LogLevelError)
+	sink.level = LogLevelWarning
+	logger.Warning("warning", nil)
+	assert.Empty(t, messages(t, &plain))
+	assert.Equal(t, []string{"warning"}, messages(t, &leveled))
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]LogLevel{
+		"debug": LogLevelDebug, "INFO": LogLevelInfo, "warn": LogLevelWarning,
+		" Warning ": LogLevelWarning, "error": LogLevelError, "FATAL": LogLevelFatal,
+	} {
+		got, err := ParseLevel(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	sink, err := NewFileSink(FileOptions{Path: path, MaxSize: 100, MaxAge: time.Hour, MaxBackups: 2, Compress: true})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+	sink.opened = now
+
+	line := []byte(strings.Repeat("x", 39)) // 40 bytes with the newline
+	write := func() {
+		require.NoError(t, sink.Write(LogEntry{}, line))
+	}
+
+	// Two lines fit, the third rotates by size
+	write()
+	write()
+	now = now.Add(time.Second)
+	write()
+	sink.wg.Wait()
+	require.Len(t, sink.Backups(), 1)
+	assert.Equal(t, filepath.Join(dir, "app-20240115T100001.000.log.gz"), sink.Backups()[0])
+
+	// The next write after an hour rotates by age
+	now = now.Add(time.Hour)
+	write()
+	sink.wg.Wait()
+	assert.Len(t, sink.Backups(), 2)
+
+	// Only max_backups rotated files are kept, the oldest go first
+	now = now.Add(time.Hour)
+	write()
+	require.NoError(t, sink.Close())
+	backups := sink.Backups()
+	require.Len(t, backups, 2)
+	assert.Equal(t, filepath.Join(dir, "app-20240115T110001.000.log.gz"), backups[0])
+
+	f, err := os.Open(backups[0])
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, string(line)+"\n", string(data))
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(line)+"\n", string(current))
+	assert.Error(t, sink.Write(LogEntry{}, line), "write after close")
+}
+
+func TestFileSink_FailedRenameKeepsWriting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink, err := NewFileSink(FileOptions{Path: path, MaxSize: 50})
+	require.NoError(t, err)
+	defer sink.Close()
+	failing := true
+	sink.rename = func(oldpath, newpath string) error {
+		if failing {
+			return os.ErrPermission
+		}
+		return os.Rename(oldpath, newpath)
+	}
+
+	line := []byte(strings.Repeat("x", 39))
+	require.NoError(t, sink.Write(LogEntry{}, line))
+	require.NoError(t, sink.Write(LogEntry{}, line), "the entry is written though the rotation failed")
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat(string(line)+"\n", 2), string(current))
+	assert.Empty(t, sink.Backups())
+
+	// The next write retries the rotation
+	failing = false
+	require.NoError(t, sink.Write(LogEntry{}, line))
+	sink.wg.Wait()
+	assert.Len(t, sink.Backups(), 1)
+	current, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(line)+"\n", string(current))
+}
+
+// rfc5424 matches the messages of the syslog sink
+var rfc5424 = regexp.MustCompile(`^<(\d+)>1 (\S+) (\S+) (\S+) (\d+) - - (\{.*\})\n?$`)
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(SyslogOptions{Network: SyslogUDP, Address: conn.LocalAddr().String(), Facility: "local3", AppName: "test-app"})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	logger := NewMultiLogger([]Output{{Sink: sink, Level: LogLevelInfo}})
+	logger.Warning("disk almost full", map[string]any{"used": 95})
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	match := rfc5424.FindStringSubmatch(string(buf[:n]))
+	require.NotNil(t, match, string(buf[:n]))
+	assert.Equal(t, "156", match[1], "local3 (19) * 8 + warning (4)")
+	_, err = time.Parse(time.RFC3339Nano, match[2])
+	assert.NoError(t, err)
+	assert.Equal(t, "test-app", match[4])
+	var entry LogEntry
+	require.NoError(t, json.Unmarshal([]byte(match[6]), &entry))
+	assert.Equal(t, "disk almost full", entry.Message)
+}
+
+func TestSyslogSink_UnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "syslog-")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	socket := filepath.Join(dir, "log")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(SyslogOptions{Address: socket})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(LogEntry{Level: LogLevelError, Message: "boom"}, []byte(`{"message":"boom"}`)))
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	match := rfc5424.FindStringSubmatch(string(buf[:n]))
+	require.NotNil(t, match, string(buf[:n]))
+	assert.Equal(t, "27", match[1], "daemon (3) * 8 + error (3)")
+	assert.Equal(t, DefaultSyslogAppName, match[4])
+
+	require.NoError(t, sink.Close())
+	assert.Error(t, sink.Write(LogEntry{}, []byte("{}")), "write after close")
+
+	_, err = NewSyslogSink(SyslogOptions{Address: filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+	_, err = NewSyslogSink(SyslogOptions{Address: socket, Facility: "local9"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Syslog transports
+const (
+	SyslogUnix = "unix"
+	SyslogUDP  = "udp"
+)
+
+// Syslog defaults
+const (
+	DefaultSyslogSocket   = "/dev/log"
+	DefaultSyslogFacility = "daemon"
+	DefaultSyslogAppName  = "mini-mcp"
+)
+
+// syslogFacilities are the RFC 5424 facility codes by name
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverities map levels to RFC 5424 severities
+var syslogSeverities = map[LogLevel]int{
+	LogLevelDebug:   7, // debug
+	LogLevelInfo:    6, // informational
+	LogLevelWarning: 4, // warning
+	LogLevelError:   3, // error
+	LogLevelFatal:   2, // critical
+}
+
+// SyslogFacilities lists the facility names
+func SyslogFacilities() []string {
+	names := make([]string, 0, len(syslogFacilities))
+	for name := range syslogFacilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SyslogOptions configures a syslog sink
+type SyslogOptions struct {
+	// Network is unix (default) for a local socket or udp for a remote server
+	Network string
+	// Address is the socket path (default /dev/log) or host:port
+	Address string
+	// Facility is a facility name such as daemon (default) or local0
+	Facility string
+	// AppName is the APP-NAME of every message (default mini-mcp)
+	AppName string
+}
+
+// SyslogSink sends entries as RFC 5424 messages whose MSG is the JSON entry
+type SyslogSink struct {
+	opts     SyslogOptions
+	facility int
+	hostname string
+	pid      int
+
+	mu     sync.Mutex
+	conn   net.Conn
+	stream bool
+	closed bool
+}
+
+// NewSyslogSink connects to the syslog server
+func NewSyslogSink(opts SyslogOptions) (*SyslogSink, error) {
+	if opts.Network == "" {
+		opts.Network = SyslogUnix
+	}
+	if opts.Address == "" && opts.Network == SyslogUnix {
+		opts.Address = DefaultSyslogSocket
+	}
+	if opts.Facility == "" {
+		opts.Facility = DefaultSyslogFacility
+	}
+	if opts.AppName == "" {
+		opts.AppName = DefaultSyslogAppName
+	}
+	facility, ok := syslogFacilities[strings.ToLower(opts.Facility)]
+	if !ok {
+		return nil, fmt.Errorf("invalid syslog facility %q", opts.Facility)
+	}
+	if opts.Network != SyslogUnix && opts.Network != SyslogUDP {
+		return nil, fmt.Errorf("invalid syslog network %q: must be unix or udp", opts.Network)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	s := &SyslogSink{
+		opts:     opts,
+		facility: facility,
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect dials the server; a unix socket is tried as datagram, then stream
+func (s *SyslogSink) connect() error {
+	if s.opts.Network == SyslogUDP {
+		conn, err := net.Dial("udp", s.opts.Address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		s.conn, s.stream = conn, false
+		return nil
+	}
+	conn, err := net.Dial("unixgram", s.opts.Address)
+	if err == nil {
+		s.conn, s.stream = conn, false
+		return nil
+	}
+	conn, err = net.Dial("unix", s.opts.Address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	s.conn, s.stream = conn, true
+	return nil
+}
+
+// format renders an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *SyslogSink) format(entry LogEntry, line []byte) []byte {
+	severity, ok := syslogSeverities[entry.Level]
+	if !ok {
+		severity = syslogSeverities[LogLevelInfo]
+	}
+	timestamp := entry.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %d - - ",
+		s.facility*8+severity,
+		timestamp.Format("2006-01-02T15:04:05.000000Z07:00"),
+		s.hostname, s.opts.AppName, s.pid)
+	msg := append([]byte(header), line...)
+	if s.stream {
+		// Stream sockets need a frame delimiter
+		msg = append(msg, '\n')
+	}
+	return msg
+}
+
+// Write sends the entry, reconnecting once if the server went away
+func (s *SyslogSink) Write(entry LogEntry, line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("syslog sink is closed")
+	}
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.conn.Write(s.format(entry, line)); err == nil {
+		return nil
+	}
+	_ = s.conn.Close()
+	s.conn = nil
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(s.format(entry, line))
+	return err
+}
+
+// Close closes the connection
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
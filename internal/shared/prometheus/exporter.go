@@ -0,0 +1,280 @@
+// Package prometheus exports the server's tool call metrics, security policy
+// denials and Go runtime statistics in the Prometheus text exposition format.
+package prometheus
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+)
+
+// ContentType is the content type of the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsPath is where the exporter is served
+const MetricsPath = "/metrics"
+
+// shutdownTimeout bounds how long ListenAndServe waits for scrapes in progress
+const shutdownTimeout = 5 * time.Second
+
+// DenialSource reports security policy denials
+type DenialSource interface {
+	PolicyDenials() []security.PolicyDenial
+}
+
+// Exporter renders metrics on every scrape; it keeps no state of its own
+type Exporter struct {
+	metrics   *logging.Metrics
+	denials   DenialSource
+	version   string
+	startTime time.Time
+}
+
+// NewExporter creates an exporter over the logger metrics and the policy
+// denials; denials may be nil
+func NewExporter(metrics *logging.Metrics, denials DenialSource, version string) *Exporter {
+	return &Exporter{
+		metrics:   metrics,
+		denials:   denials,
+		version:   version,
+		startTime: time.Now(),
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := e.Write(w); err != nil {
+		logging.GetGlobalLogger().Error("Failed to write metrics", err, nil)
+	}
+}
+
+// Write renders every metric family to w
+func (e *Exporter) Write(w io.Writer) error {
+	out := &writer{w: bufio.NewWriter(w)}
+
+	out.family("mini_mcp_build_info", "gauge", "Version of the mini-mcp server; always 1")
+	out.sample("mini_mcp_build_info", labels{"version", e.version, "go_version", runtime.Version()}, 1)
+	out.family("mini_mcp_start_time_seconds", "gauge", "Start time of the server since the Unix epoch in seconds")
+	out.sample("mini_mcp_start_time_seconds", nil, float64(e.startTime.UnixNano())/1e9)
+
+	if e.metrics != nil {
+		e.writeToolMetrics(out)
+	}
+	if e.denials != nil {
+		out.family("mini_mcp_policy_denials_total", "counter", "Commands and paths refused by the security policy, by policy and reason")
+		for _, d := range e.denials.PolicyDenials() {
+			out.sample("mini_mcp_policy_denials_total", labels{"policy", d.Policy, "code", d.Code}, float64(d.Count))
+		}
+	}
+	writeRuntimeMetrics(out)
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// writeToolMetrics renders the tool call counters and histograms
+func (e *Exporter) writeToolMetrics(out *writer) {
+	stats := e.metrics.ToolCallStats()
+
+	out.family("mini_mcp_tool_calls_total", "counter", "Tool calls completed, by tool")
+	for _, s := range stats {
+		out.sample("mini_mcp_tool_calls_total", labels{"tool", s.Tool}, float64(s.Total))
+	}
+
+	out.family("mini_mcp_tool_errors_total", "counter", "Tool calls that failed, by tool and error code")
+	for _, s := range stats {
+		codes := make([]string, 0, len(s.Errors))
+		for code := range s.Errors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			out.sample("mini_mcp_tool_errors_total", labels{"tool", s.Tool, "code", code}, float64(s.Errors[code]))
+		}
+	}
+
+	out.family("mini_mcp_tool_calls_in_flight", "gauge", "Tool calls currently being executed, by tool")
+	for _, s := range stats {
+		out.sample("mini_mcp_tool_calls_in_flight", labels{"tool", s.Tool}, float64(s.InFlight))
+	}
+
+	out.family("mini_mcp_tool_call_duration_seconds", "histogram", "Duration of tool calls, by tool")
+	for _, s := range stats {
+		for i, bound := range logging.LatencyBuckets {
+			out.sample("mini_mcp_tool_call_duration_seconds_bucket", labels{"tool", s.Tool, "le", formatFloat(bound)}, float64(s.Buckets[i]))
+		}
+		out.sample("mini_mcp_tool_call_duration_seconds_bucket", labels{"tool", s.Tool, "le", "+Inf"}, float64(s.Count))
+		out.sample("mini_mcp_tool_call_duration_seconds_sum", labels{"tool", s.Tool}, s.SumSeconds)
+		out.sample("mini_mcp_tool_call_duration_seconds_count", labels{"tool", s.Tool}, float64(s.Count))
+	}
+
+	out.family("mini_mcp_log_entries_total", "counter", "Log entries written, by level")
+	counts := e.metrics.LogCountsByLevel()
+	levels := make([]string, 0, len(counts))
+	for level := range counts {
+		levels = append(levels, string(level))
+	}
+	sort.Strings(levels)
+	for _, level := range levels {
+		out.sample("mini_mcp_log_entries_total", labels{"level", level}, float64(counts[logging.LogLevel(level)]))
+	}
+
+	out.family("mini_mcp_active_connections", "gauge", "Active client connections")
+	out.sample("mini_mcp_active_connections", nil, float64(e.metrics.GetActiveConnections()))
+}
+
+// writeRuntimeMetrics renders Go runtime statistics under the names the
+// Prometheus Go client uses, so existing dashboards work unchanged
+func writeRuntimeMetrics(out *writer) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	gauges := []struct {
+		name, help string
+		value      float64
+	}{
+		{"go_goroutines", "Number of goroutines that currently exist", float64(runtime.NumGoroutine())},
+		{"go_memstats_alloc_bytes", "Number of bytes allocated and still in use", float64(mem.Alloc)},
+		{"go_memstats_sys_bytes", "Number of bytes obtained from the system", float64(mem.Sys)},
+		{"go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use", float64(mem.HeapAlloc)},
+		{"go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use", float64(mem.HeapInuse)},
+		{"go_memstats_heap_idle_bytes", "Number of heap bytes waiting to be used", float64(mem.HeapIdle)},
+		{"go_memstats_heap_objects", "Number of allocated objects", float64(mem.HeapObjects)},
+		{"go_memstats_stack_inuse_bytes", "Number of bytes in use by the stack allocator", float64(mem.StackInuse)},
+		{"go_memstats_next_gc_bytes", "Number of heap bytes when the next garbage collection will take place", float64(mem.NextGC)},
+		{"go_memstats_last_gc_time_seconds", "Number of seconds since 1970 of last garbage collection", float64(mem.LastGC) / 1e9},
+	}
+	for _, g := range gauges {
+		out.family(g.name, "gauge", g.help)
+		out.sample(g.name, nil, g.value)
+	}
+
+	counters := []struct {
+		name, help string
+		value      float64
+	}{
+		{"go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed", float64(mem.TotalAlloc)},
+		{"go_memstats_mallocs_total", "Total number of mallocs", float64(mem.Mallocs)},
+		{"go_memstats_frees_total", "Total number of frees", float64(mem.Frees)},
+		{"go_gc_cycles_total", "Number of completed garbage collection cycles", float64(mem.NumGC)},
+		{"go_gc_pause_seconds_total", "Total time the world was stopped for garbage collection", float64(mem.PauseTotalNs) / 1e9},
+	}
+	for _, c := range counters {
+		out.family(c.name, "counter", c.help)
+		out.sample(c.name, nil, c.value)
+	}
+
+	out.family("go_info", "gauge", "Information about the Go environment")
+	out.sample("go_info", labels{"version", runtime.Version()}, 1)
+}
+
+// ListenAndServe serves the exporter on addr until ctx is cancelled
+func ListenAndServe(ctx context.Context, addr string, exporter *Exporter) error {
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, exporter)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics listener failed: %w", err)
+	}
+	return nil
+}
+
+// labels alternates label names and values
+type labels []string
+
+// writer renders samples, keeping the first write error
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// family writes the HELP and TYPE lines of a metric family
+func (w *writer) family(name, typ, help string) {
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+}
+
+// sample writes one sample line
+func (w *writer) sample(name string, l labels, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(l) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(l); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(l[i])
+			b.WriteString(`="`)
+			b.WriteString(escapeLabel(l[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	w.printf("%s %s\n", b.String(), formatFloat(value))
+}
+
+// printf writes unless an earlier write failed
+func (w *writer) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, format, args...)
+}
+
+// formatFloat formats a sample value as the text format expects
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabel escapes a label value
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// escapeHelp escapes a HELP text
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,117 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/security"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDenials []security.PolicyDenial
+
+func (f fakeDenials) PolicyDenials() []security.PolicyDenial { return f }
+
+func testExporter() *Exporter {
+	metrics := logging.NewMetrics()
+	for _, call := range []struct {
+		tool     string
+		duration time.Duration
+		code     string
+	}{
+		{"file_read", 3 * time.Millisecond, ""},
+		{"file_read", 200 * time.Millisecond, "FILE_NOT_FOUND"},
+		{"file_read", 2 * time.Minute, ""},
+		{"run", 40 * time.Millisecond, "COMMAND_BLOCKED"},
+	} {
+		metrics.ToolCallStarted(call.tool)
+		metrics.RecordToolCall(call.tool, call.duration, call.code)
+	}
+	metrics.ToolCallStarted("run")
+	metrics.IncrementLogCount(logging.LogLevelError, "run")
+
+	denials := fakeDenials{{Policy: security.PolicyPath, Code: security.ErrCodePathBlocked, Count: 3}}
+	return NewExporter(metrics, denials, `1.2.3"beta`)
+}
+
+func TestExporter_Write(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testExporter().Write(&buf))
+	out := buf.String()
+
+	for _, line := range []string{
+		"# TYPE mini_mcp_tool_calls_total counter",
+		`mini_mcp_tool_calls_total{tool="file_read"} 3`,
+		`mini_mcp_tool_calls_total{tool="run"} 1`,
+		`mini_mcp_tool_errors_total{tool="file_read",code="FILE_NOT_FOUND"} 1`,
+		`mini_mcp_tool_errors_total{tool="run",code="COMMAND_BLOCKED"} 1`,
+		`mini_mcp_tool_calls_in_flight{tool="run"} 1`,
+		"# TYPE mini_mcp_tool_call_duration_seconds histogram",
+		`mini_mcp_tool_call_duration_seconds_bucket{tool="file_read",le="0.005"} 1`,
+		`mini_mcp_tool_call_duration_seconds_bucket{tool="file_read",le="0.1"} 1`,
+		`mini_mcp_tool_call_duration_seconds_bucket{tool="file_read",le="0.25"} 2`,
+		`mini_mcp_tool_call_duration_seconds_bucket{tool="file_read",le="60"} 2`,
+		`mini_mcp_tool_call_duration_seconds_bucket{tool="file_read",le="+Inf"} 3`,
+		`mini_mcp_tool_call_duration_seconds_sum{tool="file_read"} 120.203`,
+		`mini_mcp_tool_call_duration_seconds_count{tool="file_read"} 3`,
+		`mini_mcp_policy_denials_total{policy="path",code="PATH_BLOCKED"} 3`,
+		`mini_mcp_log_entries_total{level="ERROR"} 1`,
+		`mini_mcp_build_info{version="1.2.3\"beta",go_version="`,
+		"# TYPE go_goroutines gauge",
+		"# TYPE go_gc_cycles_total counter",
+	} {
+		assert.Contains(t, out, line)
+	}
+}
+
+func TestExporter_ServeHTTP(t *testing.T) {
+	exporter := testExporter()
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "mini_mcp_tool_calls_total")
+
+	rec = httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, MetricsPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestListenAndServe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ListenAndServe(ctx, addr, testExporter()) }()
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Get("http://" + addr + MetricsPath)
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Contains(t, string(body), "go_goroutines")
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("ListenAndServe did not return after cancellation")
+	}
+}
@@ -0,0 +1,118 @@
+package security
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Policies whose denials are counted
+const (
+	PolicyCommand = "command"
+	PolicyPath    = "path"
+)
+
+// PolicyDenial is how many requests a policy refused for one reason
+type PolicyDenial struct {
+	Policy string
+	// Code is the SecurityError code of the denial
+	Code  string
+	Count int64
+}
+
+// denialKey identifies a denial counter
+type denialKey struct {
+	policy string
+	code   string
+}
+
+// denialCounter counts policy denials
+type denialCounter struct {
+	mu     sync.Mutex
+	counts map[denialKey]int64
+}
+
+// newDenialCounter creates an empty denial counter
+func newDenialCounter() *denialCounter {
+	return &denialCounter{counts: make(map[denialKey]int64)}
+}
+
+// record counts one denial
+func (d *denialCounter) record(policy, code string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.counts[denialKey{policy: policy, code: code}]++
+}
+
+// recordError counts err as a denial, using its SecurityError code when it has one
+func (d *denialCounter) recordError(policy string, err error) {
+	code := "DENIED"
+	var secErr SecurityError
+	if errors.As(err, &secErr) {
+		code = secErr.Code
+	}
+	d.record(policy, code)
+}
+
+// list returns the counters ordered by policy and code
+func (d *denialCounter) list() []PolicyDenial {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	denials := make([]PolicyDenial, 0, len(d.counts))
+	for key, count := range d.counts {
+		denials = append(denials, PolicyDenial{Policy: key.policy, Code: key.code, Count: count})
+	}
+	sort.Slice(denials, func(i, j int) bool {
+		if denials[i].Policy != denials[j].Policy {
+			return denials[i].Policy < denials[j].Policy
+		}
+		return denials[i].Code < denials[j].Code
+	})
+	return denials
+}
+
+// countingCommandValidator counts the commands a validator refuses
+type countingCommandValidator struct {
+	CommandValidator
+	denials *denialCounter
+}
+
+// ValidateCommand validates a command, counting a refusal
+func (v countingCommandValidator) ValidateCommand(command string) error {
+	err := v.CommandValidator.ValidateCommand(command)
+	if err != nil {
+		v.denials.recordError(PolicyCommand, err)
+	}
+	return err
+}
+
+// IsCommandAllowed checks the allowlist, counting a refusal
+func (v countingCommandValidator) IsCommandAllowed(command string) bool {
+	allowed := v.CommandValidator.IsCommandAllowed(command)
+	if !allowed {
+		v.denials.record(PolicyCommand, ErrCodeCommandNotAllowed)
+	}
+	return allowed
+}
+
+// countingPathValidator counts the paths a validator refuses
+type countingPathValidator struct {
+	PathValidator
+	denials *denialCounter
+}
+
+// ValidatePath validates a path, counting a refusal
+func (v countingPathValidator) ValidatePath(path string) error {
+	err := v.PathValidator.ValidatePath(path)
+	if err != nil {
+		v.denials.recordError(PolicyPath, err)
+	}
+	return err
+}
+
+// IsPathAllowed checks a path without counting a refusal, since callers
+// use it to probe paths such as completion candidates rather than to deny a request
+func (v countingPathValidator) IsPathAllowed(path string) bool {
+	return v.PathValidator.IsPathAllowed(path)
+}
@@ -0,0 +1,36 @@
+package security
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureCommandExecutor_PolicyDenials(t *testing.T) {
+	executor := NewSecureCommandExecutor(&SecurityConfig{
+		AllowedCommands: []string{"ls"},
+		AllowedPaths:    []string{"/tmp"},
+		BlockedPaths:    []string{"/etc/shadow"},
+	})
+	assert.Empty(t, executor.PolicyDenials())
+
+	assert.True(t, executor.IsCommandAllowed("ls"))
+	assert.False(t, executor.IsCommandAllowed("rm"))
+	_, err := executor.ExecuteCommand(context.Background(), "whoami")
+	require.Error(t, err)
+
+	assert.NoError(t, executor.ValidatePath("/tmp/file"))
+	assert.Error(t, executor.ValidatePath("/etc/shadow"))
+	assert.False(t, executor.GetPathValidator().IsPathAllowed("/var/lib/x"), "probes are not counted")
+	assert.Error(t, executor.ValidatePath("/var/lib/x"))
+	assert.Error(t, executor.ValidatePath("/tmp/../etc"))
+
+	assert.Equal(t, []PolicyDenial{
+		{Policy: PolicyCommand, Code: ErrCodeCommandNotAllowed, Count: 2},
+		{Policy: PolicyPath, Code: ErrCodePathBlocked, Count: 1},
+		{Policy: PolicyPath, Code: ErrCodePathNotAllowed, Count: 1},
+		{Policy: PolicyPath, Code: ErrCodePathTraversal, Count: 1},
+	}, executor.PolicyDenials())
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/shared/tracing"
 )
 
 // CommandSecurity provides secure command execution with allowlisting and sandboxing
@@ -44,17 +45,29 @@ type SecurityConfig struct {
 	// Path restrictions
 	AllowedPaths []string `json:"allowed_paths"`
 	BlockedPaths []string `json:"blocked_paths"`
+
+	// Process names (glob patterns) that process-control tools must never signal
+	ProtectedProcesses []string `json:"protected_processes"`
+
+	// Systemd units (glob patterns) that service_control may act on; none by default
+	ControllableUnits []string `json:"controllable_units"`
+
+	// Commands the server's own tools may run with arguments they build
+	// themselves, in addition to the allowlist; never reachable from execute_command
+	SystemCommands []string `json:"system_commands"`
 }
 
 // SecureCommandExecutor handles secure command execution
 type SecureCommandExecutor struct {
 	config          *SecurityConfig
 	allowedCommands map[string]bool
+	systemCommands  map[string]bool
 	activeCommands  map[string]*exec.Cmd
 	mutex           sync.RWMutex
 	validator       CommandValidator
 	pathValidator   PathValidator
 	sanitizer       InputSanitizer
+	denials         *denialCounter
 }
 
 // NewSecureCommandExecutor creates a new secure command executor
@@ -66,19 +79,27 @@ func NewSecureCommandExecutor(config *SecurityConfig) *SecureCommandExecutor {
 	for _, cmd := range config.AllowedCommands {
 		allowedCommands[cmd] = true
 	}
+	systemCommands := make(map[string]bool)
+	for _, cmd := range config.SystemCommands {
+		systemCommands[cmd] = true
+	}
 
-	validator := NewCommandValidator(config)
-	pathValidator := NewPathValidator(config)
+	// Validators count what they refuse so denials can be exported as metrics
+	denials := newDenialCounter()
+	validator := countingCommandValidator{CommandValidator: NewCommandValidator(config), denials: denials}
+	pathValidator := countingPathValidator{PathValidator: NewPathValidator(config), denials: denials}
 	sanitizer := NewInputSanitizer()
 
 	return &SecureCommandExecutor{
 		config:          config,
 		allowedCommands: allowedCommands,
+		systemCommands:  systemCommands,
 		activeCommands:  make(map[string]*exec.Cmd),
 		mutex:           sync.RWMutex{},
 		validator:       validator,
 		pathValidator:   pathValidator,
 		sanitizer:       sanitizer,
+		denials:         denials,
 	}
 }
 
@@ -105,12 +126,27 @@ func DefaultSecurityConfig() *SecurityConfig {
 			"ps", "top", "df", "du", "free", "uptime", "who", "w",
 			"git", "docker", "consul", "terraform",
 		},
-		WorkingDirectory: "/tmp",
-		CommandTimeout:   30 * time.Second,
-		MaxOutputSize:    1024 * 1024, // 1MB
-		AllowedEnvVars:   []string{"PATH", "HOME", "USER", "PWD"},
-		AllowedPaths:     []string{"/tmp", "/var/log", "/proc"},
-		BlockedPaths:     []string{"/etc/passwd", "/etc/shadow", "/root", "/home"},
+		WorkingDirectory:   "/tmp",
+		CommandTimeout:     30 * time.Second,
+		MaxOutputSize:      1024 * 1024, // 1MB
+		AllowedEnvVars:     []string{"PATH", "HOME", "USER", "PWD"},
+		AllowedPaths:       []string{"/tmp", "/var/log", "/proc"},
+		BlockedPaths:       []string{"/etc/passwd", "/etc/shadow", "/root", "/home"},
+		ProtectedProcesses: DefaultProtectedProcesses(),
+		SystemCommands:     DefaultSystemCommands(),
+	}
+}
+
+// DefaultSystemCommands returns the commands the built-in tools run by default
+func DefaultSystemCommands() []string {
+	return []string{"systemctl", "journalctl", "nft", "iptables-save", "kubectl", "pvesh", "ceph", "nomad"}
+}
+
+// DefaultProtectedProcesses returns the process names that are never signalled by default
+func DefaultProtectedProcesses() []string {
+	return []string{
+		"init", "systemd", "systemd-*", "sshd", "dockerd", "containerd", "containerd-shim*",
+		"kubelet", "dbus-daemon", "mini-mcp",
 	}
 }
 
@@ -120,20 +156,28 @@ func (s *SecureCommandExecutor) ExecuteCommand(ctx context.Context, command stri
 	command = s.sanitizer.Sanitize(command)
 
 	// Validate and sanitize command
+	span := tracing.StartPolicyCheck(ctx, PolicyCommand, command)
 	if err := s.validator.ValidateCommand(command); err != nil {
+		tracing.EndPolicyCheck(span, err)
 		return "", fmt.Errorf("command validation failed: %w", err)
 	}
 
 	// Split command into parts
 	parts := strings.Fields(command)
 	if len(parts) == 0 {
-		return "", fmt.Errorf("empty command")
+		err := fmt.Errorf("empty command")
+		tracing.EndPolicyCheck(span, err)
+		return "", err
 	}
 
 	// Check if command is allowed
 	if !s.allowedCommands[parts[0]] {
-		return "", fmt.Errorf("command '%s' is not allowed", parts[0])
+		s.denials.record(PolicyCommand, ErrCodeCommandNotAllowed)
+		err := fmt.Errorf("command '%s' is not allowed", parts[0])
+		tracing.EndPolicyCheck(span, err)
+		return "", err
 	}
+	tracing.EndPolicyCheck(span, nil)
 
 	// Create command with context and timeout
 	ctx, cancel := context.WithTimeout(ctx, s.config.CommandTimeout)
@@ -147,10 +191,12 @@ func (s *SecureCommandExecutor) ExecuteCommand(ctx context.Context, command stri
 	}
 
 	// Set allowed environment variables
-	cmd.Env = s.filterEnvironment(os.Environ())
+	cmd.Env = logging.CommandEnv(ctx, s.filterEnvironment(os.Environ()))
 
 	// Execute command
+	_, execSpan := tracing.StartExec(ctx, parts[0], parts[1:])
 	output, err := cmd.CombinedOutput()
+	tracing.EndExec(execSpan, err)
 
 	// Check output size
 	if int64(len(output)) > s.config.MaxOutputSize {
@@ -183,6 +229,44 @@ func (s *SecureCommandExecutor) ValidatePath(path string) error {
 	return s.pathValidator.ValidatePath(path)
 }
 
+// CheckPath validates a path like ValidatePath, recording the check as a span of ctx
+func (s *SecureCommandExecutor) CheckPath(ctx context.Context, path string) error {
+	span := tracing.StartPolicyCheck(ctx, PolicyPath, path)
+	err := s.pathValidator.ValidatePath(path)
+	tracing.EndPolicyCheck(span, err)
+	return err
+}
+
+// CheckCommand checks a command like IsCommandAllowed, recording the check as a span of ctx
+func (s *SecureCommandExecutor) CheckCommand(ctx context.Context, command string) bool {
+	span := tracing.StartPolicyCheck(ctx, PolicyCommand, command)
+	allowed := s.validator.IsCommandAllowed(command)
+	if allowed {
+		tracing.EndPolicyCheck(span, nil)
+	} else {
+		tracing.EndPolicyCheck(span, fmt.Errorf("command not allowed: %s", command))
+	}
+	return allowed
+}
+
+// CheckSystemCommand checks a command a tool runs with arguments it builds
+// itself, which may be a system command as well as an allowed one, recording
+// the check as a span of ctx
+func (s *SecureCommandExecutor) CheckSystemCommand(ctx context.Context, command string) bool {
+	if !s.systemCommands[command] {
+		return s.CheckCommand(ctx, command)
+	}
+	span := tracing.StartPolicyCheck(ctx, PolicyCommand, command)
+	tracing.EndPolicyCheck(span, nil)
+	return true
+}
+
+// IsSystemCommandAllowed reports whether CheckSystemCommand allows a command,
+// without recording a refusal
+func (s *SecureCommandExecutor) IsSystemCommandAllowed(command string) bool {
+	return s.systemCommands[command] || s.allowedCommands[command]
+}
+
 // SanitizeInput sanitizes input using the input sanitizer
 func (s *SecureCommandExecutor) SanitizeInput(input string) string {
 	return s.sanitizer.Sanitize(input)
@@ -193,6 +277,11 @@ func (s *SecureCommandExecutor) IsCommandAllowed(command string) bool {
 	return s.validator.IsCommandAllowed(command)
 }
 
+// PolicyDenials returns how many commands and paths the security policy has refused, by reason
+func (s *SecureCommandExecutor) PolicyDenials() []PolicyDenial {
+	return s.denials.list()
+}
+
 // GetPathValidator returns the path validator for external use
 func (s *SecureCommandExecutor) GetPathValidator() PathValidator {
 	return s.pathValidator
@@ -203,6 +292,19 @@ func (s *SecureCommandExecutor) GetCommandValidator() CommandValidator {
 	return s.validator
 }
 
+// GetProtectedProcesses returns the process name patterns that must never be signalled
+func (s *SecureCommandExecutor) GetProtectedProcesses() []string {
+	if s.config.ProtectedProcesses == nil {
+		return DefaultProtectedProcesses()
+	}
+	return s.config.ProtectedProcesses
+}
+
+// GetControllableUnits returns the systemd unit patterns that service_control may act on
+func (s *SecureCommandExecutor) GetControllableUnits() []string {
+	return s.config.ControllableUnits
+}
+
 // GetInputSanitizer returns the input sanitizer for external use
 func (s *SecureCommandExecutor) GetInputSanitizer() InputSanitizer {
 	return s.sanitizer
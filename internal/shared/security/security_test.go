@@ -0,0 +1,33 @@
+package security
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureCommandExecutor_CheckSystemCommand(t *testing.T) {
+	executor := NewSecureCommandExecutor(nil)
+	ctx := context.Background()
+
+	// Every command the built-in tools run is allowed by default
+	for _, command := range []string{
+		"systemctl", "journalctl", "nft", "iptables-save",
+		"docker", "kubectl", "pvesh", "ceph", "nomad",
+	} {
+		assert.True(t, executor.CheckSystemCommand(ctx, command), command)
+	}
+	assert.Empty(t, executor.PolicyDenials())
+
+	// System commands stay out of reach of execute_command
+	assert.False(t, executor.CheckCommand(ctx, "systemctl"))
+	assert.False(t, executor.CheckSystemCommand(ctx, "rm"))
+	assert.Equal(t, []PolicyDenial{{Policy: PolicyCommand, Code: ErrCodeCommandNotAllowed, Count: 2}}, executor.PolicyDenials())
+
+	// Probes at load time are not counted
+	assert.True(t, executor.IsSystemCommandAllowed("journalctl"))
+	assert.True(t, executor.IsSystemCommandAllowed("df"))
+	assert.False(t, executor.IsSystemCommandAllowed("rm"))
+	assert.Equal(t, int64(2), executor.PolicyDenials()[0].Count)
+}
@@ -0,0 +1,378 @@
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// exportTimeout bounds one export of one batch
+const exportTimeout = 10 * time.Second
+
+// TracesPath is the OTLP/HTTP path of the traces service
+const TracesPath = "/v1/traces"
+
+// scopeName is the instrumentation scope of every span
+const scopeName = "mini-mcp"
+
+// Batch is a set of finished spans of one process
+type Batch struct {
+	// Resource describes the process, e.g. service.name
+	Resource map[string]any
+	Spans    []SpanData
+}
+
+// Exporter delivers batches of finished spans
+type Exporter interface {
+	Export(ctx context.Context, batch Batch) error
+	Shutdown(ctx context.Context) error
+}
+
+// ===== OTLP JSON encoding =====
+
+// The types below are the OTLP ExportTraceServiceRequest in its JSON
+// encoding: IDs are hex, 64-bit integers are decimal strings and enums are
+// numbers
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+// EncodeJSON encodes a batch as an OTLP ExportTraceServiceRequest in JSON
+func EncodeJSON(batch Batch) ([]byte, error) {
+	spans := make([]otlpSpan, 0, len(batch.Spans))
+	for _, s := range batch.Spans {
+		span := otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        encodeAttributes(s.Attributes),
+			Status:            otlpStatus{Code: s.Status, Message: s.StatusMessage},
+		}
+		if s.ParentSpanID.IsValid() {
+			span.ParentSpanID = s.ParentSpanID.String()
+		}
+		spans = append(spans, span)
+	}
+
+	return json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttributes(batch.Resource)},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: scopeName}, Spans: spans}},
+	}}})
+}
+
+// encodeAttributes encodes attributes ordered by key
+func encodeAttributes(attrs map[string]any) []otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, otlpKeyValue{Key: key, Value: encodeValue(attrs[key])})
+	}
+	return kvs
+}
+
+// encodeValue encodes an attribute value; values are normalized by
+// redactAttribute, so only these types occur
+func encodeValue(value any) otlpAnyValue {
+	switch v := value.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case []string:
+		values := make([]otlpAnyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, encodeValue(item))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	}
+	s := fmt.Sprint(value)
+	return otlpAnyValue{StringValue: &s}
+}
+
+// ReadSpans decodes the spans of OTLP JSON requests, one per line, as the
+// file exporter writes them; it lets traces be checked without a collector
+func ReadSpans(r io.Reader) ([]SpanData, error) {
+	var spans []SpanData
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req otlpRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			return nil, fmt.Errorf("invalid OTLP JSON: %w", err)
+		}
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					span, err := decodeSpan(s)
+					if err != nil {
+						return nil, err
+					}
+					spans = append(spans, span)
+				}
+			}
+		}
+	}
+	return spans, scanner.Err()
+}
+
+// decodeSpan converts an OTLP JSON span back to span data
+func decodeSpan(s otlpSpan) (SpanData, error) {
+	span := SpanData{
+		Name:          s.Name,
+		Kind:          s.Kind,
+		Status:        s.Status.Code,
+		StatusMessage: s.Status.Message,
+		Attributes:    make(map[string]any, len(s.Attributes)),
+	}
+	if err := decodeHex(span.TraceID[:], s.TraceID); err != nil {
+		return SpanData{}, fmt.Errorf("span %q: invalid trace ID: %w", s.Name, err)
+	}
+	if err := decodeHex(span.SpanID[:], s.SpanID); err != nil {
+		return SpanData{}, fmt.Errorf("span %q: invalid span ID: %w", s.Name, err)
+	}
+	if s.ParentSpanID != "" {
+		if err := decodeHex(span.ParentSpanID[:], s.ParentSpanID); err != nil {
+			return SpanData{}, fmt.Errorf("span %q: invalid parent span ID: %w", s.Name, err)
+		}
+	}
+	start, err := strconv.ParseInt(s.StartTimeUnixNano, 10, 64)
+	if err != nil {
+		return SpanData{}, fmt.Errorf("span %q: invalid start time: %w", s.Name, err)
+	}
+	end, err := strconv.ParseInt(s.EndTimeUnixNano, 10, 64)
+	if err != nil {
+		return SpanData{}, fmt.Errorf("span %q: invalid end time: %w", s.Name, err)
+	}
+	span.Start, span.End = time.Unix(0, start), time.Unix(0, end)
+	for _, kv := range s.Attributes {
+		span.Attributes[kv.Key] = decodeValue(kv.Value)
+	}
+	return span, nil
+}
+
+// decodeHex decodes an ID of exactly len(dst) bytes
+func decodeHex(dst []byte, s string) error {
+	if len(s) != 2*len(dst) {
+		return fmt.Errorf("want %d hex digits, got %q", 2*len(dst), s)
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// decodeValue converts an OTLP JSON value back to the attribute types
+func decodeValue(v otlpAnyValue) any {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.BoolValue != nil:
+		return *v.BoolValue
+	case v.IntValue != nil:
+		n, _ := strconv.ParseInt(*v.IntValue, 10, 64)
+		return n
+	case v.DoubleValue != nil:
+		return *v.DoubleValue
+	case v.ArrayValue != nil:
+		values := make([]string, 0, len(v.ArrayValue.Values))
+		for _, item := range v.ArrayValue.Values {
+			values = append(values, fmt.Sprint(decodeValue(item)))
+		}
+		return values
+	}
+	return nil
+}
+
+// ===== OTLP/HTTP exporter =====
+
+// HTTPExporter posts batches to an OTLP/HTTP collector in the JSON encoding
+type HTTPExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPExporter creates an exporter for a collector endpoint such as
+// http://localhost:4318; an endpoint without a path gets /v1/traces.
+// headers are added to every request, e.g. for authentication.
+func NewHTTPExporter(endpoint string, headers map[string]string) (*HTTPExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", endpoint)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: no host", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = TracesPath
+	}
+	return &HTTPExporter{
+		url:     u.String(),
+		headers: headers,
+		client:  &http.Client{Timeout: exportTimeout},
+	}, nil
+}
+
+// Export posts one batch
+func (e *HTTPExporter) Export(ctx context.Context, batch Batch) error {
+	body, err := EncodeJSON(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OTLP export failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("OTLP export failed: HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Shutdown does nothing; the exporter holds no resources
+func (e *HTTPExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// ===== File exporter =====
+
+// FileExporter appends each batch to a file as one line of OTLP JSON, the
+// format of the OpenTelemetry collector's file exporter
+type FileExporter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileExporter opens path for appending, creating it if needed
+func NewFileExporter(path string) (*FileExporter, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	return &FileExporter{file: file}, nil
+}
+
+// Export appends one batch
+func (e *FileExporter) Export(ctx context.Context, batch Batch) error {
+	data, err := EncodeJSON(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return fmt.Errorf("trace file is closed")
+	}
+	if _, err := e.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write trace file: %w", err)
+	}
+	return nil
+}
+
+// Shutdown closes the file
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Close()
+	e.file = nil
+	return err
+}
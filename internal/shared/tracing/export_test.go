@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBatch() Batch {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	root := SpanData{
+		TraceID:    TraceID{1, 2, 3},
+		SpanID:     SpanID{4, 5, 6},
+		Name:       "tools/call docker_compose",
+		Kind:       SpanKindServer,
+		Start:      start,
+		End:        start.Add(3 * time.Second),
+		Attributes: map[string]any{"mcp.tool.name": "docker_compose", "retry": int64(2), "ok": true, "ratio": 0.5},
+	}
+	child := SpanData{
+		TraceID:       root.TraceID,
+		SpanID:        SpanID{7, 8, 9},
+		ParentSpanID:  root.SpanID,
+		Name:          "exec docker-compose",
+		Kind:          SpanKindInternal,
+		Start:         start.Add(time.Millisecond),
+		End:           start.Add(2 * time.Second),
+		Attributes:    map[string]any{"process.command_args": []string{"docker-compose", "up", "-d"}},
+		Status:        StatusError,
+		StatusMessage: "exit status 1",
+	}
+	return Batch{Resource: map[string]any{"service.name": "mini-mcp"}, Spans: []SpanData{root, child}}
+}
+
+func TestHTTPExporter(t *testing.T) {
+	// A collector stand-in that decodes what it receives
+	var (
+		mu       sync.Mutex
+		received []SpanData
+		headers  http.Header
+		path     string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spans, err := ReadSpans(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, spans...)
+		headers, path = r.Header.Clone(), r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	exporter, err := NewHTTPExporter(collector.URL, map[string]string{"X-Api-Key": "k"})
+	require.NoError(t, err)
+	batch := testBatch()
+	require.NoError(t, exporter.Export(context.Background(), batch))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, TracesPath, path)
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "k", headers.Get("X-Api-Key"))
+	require.Len(t, received, 2)
+	for i, want := range batch.Spans {
+		got := received[i]
+		assert.Equal(t, want.TraceID, got.TraceID)
+		assert.Equal(t, want.SpanID, got.SpanID)
+		assert.Equal(t, want.ParentSpanID, got.ParentSpanID)
+		assert.Equal(t, want.Name, got.Name)
+		assert.Equal(t, want.Kind, got.Kind)
+		assert.True(t, want.Start.Equal(got.Start))
+		assert.True(t, want.End.Equal(got.End))
+		assert.Equal(t, want.Attributes, got.Attributes)
+		assert.Equal(t, want.Status, got.Status)
+		assert.Equal(t, want.StatusMessage, got.StatusMessage)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	exporter, err = NewHTTPExporter(failing.URL+"/custom/traces", nil)
+	require.NoError(t, err)
+	err = exporter.Export(context.Background(), batch)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 503: overloaded")
+}
+
+func TestNewHTTPExporter_InvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"localhost:4318", "ftp://collector", "http://", "://bad"} {
+		_, err := NewHTTPExporter(endpoint, nil)
+		assert.Error(t, err, endpoint)
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	exporter, err := NewFileExporter(path)
+	require.NoError(t, err)
+
+	tracer := NewTracer(Options{ServiceVersion: "1.2.3", Exporters: []Exporter{exporter}, BatchSize: 1})
+	ctx, root := tracer.start(context.Background(), SpanContext{}, "tools/call run", SpanKindServer, map[string]any{"api_token": "abc"})
+	_, child := Start(ctx, "exec ls", SpanKindInternal, nil)
+	child.End()
+	root.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+	assert.Error(t, exporter.Export(context.Background(), testBatch()), "export after shutdown")
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	spans, err := ReadSpans(f)
+	require.NoError(t, err)
+	require.Len(t, spans, 2)
+	assert.Equal(t, "exec ls", spans[0].Name)
+	assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	assert.Equal(t, Redacted, spans[1].Attributes["api_token"])
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"key":"service.version","value":{"stringValue":"1.2.3"}`)
+}
@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces secrets in span attributes and status messages
+const Redacted = "[REDACTED]"
+
+// MaxAttributeLength bounds string attribute values; longer values are cut
+const MaxAttributeLength = 1024
+
+// sensitiveKeyParts mark an attribute or argument name whose value is a secret
+var sensitiveKeyParts = []string{
+	"password", "passwd", "secret", "token", "apikey", "api_key", "api-key",
+	"authorization", "cookie", "credential", "private_key", "ticket", "csrf",
+}
+
+// secretPatterns match secrets embedded in free text such as command lines,
+// URLs and error messages
+var secretPatterns = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	// key=value and key: value pairs with a sensitive key, including an
+	// authorization scheme in the value
+	{regexp.MustCompile(`(?i)((?:password|passwd|secret|token|api[_-]?key|authorization|ticket|csrfpreventiontoken)["']?\s*[=:]\s*["']?)(?:(?:bearer|basic)\s+)?[^\s"'&,;]+`), "${1}" + Redacted},
+	// HTTP authorization schemes
+	{regexp.MustCompile(`(?i)(\b(?:bearer|basic|pveapitoken)[\s=]+)[^\s"',;]+`), "${1}" + Redacted},
+	// passwords in URL userinfo
+	{regexp.MustCompile(`(://[^:/@\s]+:)[^@/\s]+@`), "${1}" + Redacted + "@"},
+	// command line password flags
+	{regexp.MustCompile(`(?i)(--?(?:password|passwd|token|secret)[=\s]+)\S+`), "${1}" + Redacted},
+}
+
+// IsSensitiveKey reports whether an attribute or argument name holds a secret
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactString replaces the secrets found in s and cuts it to MaxAttributeLength
+func RedactString(s string) string {
+	for _, p := range secretPatterns {
+		s = p.pattern.ReplaceAllString(s, p.replace)
+	}
+	if len(s) > MaxAttributeLength {
+		cut := MaxAttributeLength
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "…"
+	}
+	return s
+}
+
+// secretShortFlags are single-letter flags whose next argument is a password,
+// as in mysql -p or sshpass -p
+var secretShortFlags = map[string]bool{"-p": true}
+
+// RedactArgv redacts an argument vector. Besides the secrets within each
+// argument, the argument after a secret-named flag ("--password s3cr3t") is
+// replaced whole.
+func RedactArgv(argv []string) []string {
+	redacted := make([]string, len(argv))
+	for i, arg := range argv {
+		if i > 0 && isSecretFlag(argv[i-1]) {
+			redacted[i] = Redacted
+			continue
+		}
+		redacted[i] = RedactString(arg)
+	}
+	return redacted
+}
+
+// isSecretFlag reports whether arg is a flag without an inline value whose
+// value is a secret
+func isSecretFlag(arg string) bool {
+	if !strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+		return false
+	}
+	return secretShortFlags[arg] || IsSensitiveKey(strings.TrimLeft(arg, "-"))
+}
+
+// redactAttribute normalizes an attribute value to the types OTLP encodes,
+// redacting the whole value of a sensitive key and secrets within strings
+func redactAttribute(key string, value any) any {
+	if IsSensitiveKey(key) {
+		return Redacted
+	}
+	switch v := value.(type) {
+	case string:
+		return RedactString(v)
+	case bool, int64, float64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		return RedactArgv(v)
+	case fmt.Stringer:
+		return RedactString(v.String())
+	}
+	return RedactString(fmt.Sprint(value))
+}
+
+// RedactArguments renders tool arguments as JSON with the values of
+// sensitive keys and the secrets in strings redacted
+func RedactArguments(args any) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return ""
+	}
+	data, err = json.Marshal(redactTree(tree))
+	if err != nil {
+		return ""
+	}
+	return RedactString(string(data))
+}
+
+// redactTree redacts a decoded JSON value in place
+func redactTree(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if IsSensitiveKey(key) {
+				v[key] = Redacted
+			} else {
+				v[key] = redactTree(item)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactTree(item)
+		}
+	case string:
+		return RedactString(v)
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// StartExec starts a span for running a subprocess
+func StartExec(ctx context.Context, command string, args []string) (context.Context, *Span) {
+	return Start(ctx, "exec "+command, SpanKindInternal, map[string]any{
+		"process.executable.name": command,
+		"process.command_args":    append([]string{command}, args...),
+	})
+}
+
+// EndExec ends a subprocess span with the exit code and error of the run
+func EndExec(span *Span, err error) {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		span.SetAttribute("process.exit.code", 0)
+	case errors.As(err, &exitErr):
+		span.SetAttribute("process.exit.code", exitErr.ExitCode())
+	}
+	span.RecordError(err)
+	span.End()
+}
+
+// StartPolicyCheck starts a span for a security policy check of a command
+// or path; EndPolicyCheck ends it with the verdict
+func StartPolicyCheck(ctx context.Context, policy, subject string) *Span {
+	_, span := Start(ctx, "policy "+policy, SpanKindInternal, map[string]any{
+		"security.policy":  policy,
+		"security.subject": subject,
+	})
+	return span
+}
+
+// EndPolicyCheck ends a policy check span; a nil err allows the request
+func EndPolicyCheck(span *Span, err error) {
+	span.SetAttribute("security.allowed", err == nil)
+	span.RecordError(err)
+	span.End()
+}
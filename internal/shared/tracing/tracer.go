@@ -0,0 +1,220 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"mini-mcp/internal/shared/logging"
+)
+
+// Batching defaults
+const (
+	DefaultQueueSize     = 2048
+	DefaultBatchSize     = 512
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// Options configures a tracer
+type Options struct {
+	ServiceName    string
+	ServiceVersion string
+	// Exporters receive every batch of finished spans
+	Exporters []Exporter
+	// QueueSize bounds the spans waiting for export; spans beyond it are dropped
+	QueueSize int
+	// BatchSize is how many spans are exported at once
+	BatchSize int
+	// FlushInterval is how long a finished span waits for its batch to fill
+	FlushInterval time.Duration
+}
+
+// Tracer batches finished spans and hands them to its exporters in the
+// background, so ending a span never waits on the network
+type Tracer struct {
+	resource  map[string]any
+	exporters []Exporter
+	batchSize int
+	interval  time.Duration
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan SpanData
+	flush   chan chan struct{}
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+// NewTracer creates a tracer and starts its export loop
+func NewTracer(opts Options) *Tracer {
+	if opts.ServiceName == "" {
+		opts.ServiceName = "mini-mcp"
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+
+	resource := map[string]any{"service.name": opts.ServiceName}
+	if opts.ServiceVersion != "" {
+		resource["service.version"] = opts.ServiceVersion
+	}
+
+	t := &Tracer{
+		resource:  resource,
+		exporters: opts.Exporters,
+		batchSize: opts.BatchSize,
+		interval:  opts.FlushInterval,
+		queue:     make(chan SpanData, opts.QueueSize),
+		flush:     make(chan chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Dropped returns how many spans were dropped because the queue was full
+func (t *Tracer) Dropped() int64 {
+	return t.dropped.Load()
+}
+
+// enqueue queues a finished span without blocking
+func (t *Tracer) enqueue(span SpanData) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return
+	}
+	select {
+	case t.queue <- span:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// run exports batches until the tracer is shut down
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batchSize)
+	export := func() {
+		if len(batch) > 0 {
+			t.export(batch)
+			batch = make([]SpanData, 0, t.batchSize)
+		}
+	}
+	// drain moves every queued span into batches
+	drain := func() {
+		for {
+			select {
+			case span, ok := <-t.queue:
+				if !ok {
+					return
+				}
+				batch = append(batch, span)
+				if len(batch) >= t.batchSize {
+					export()
+				}
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case span, ok := <-t.queue:
+			if !ok {
+				export()
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= t.batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case reply := <-t.flush:
+			drain()
+			export()
+			close(reply)
+		}
+	}
+}
+
+// export sends a batch to every exporter, logging failures
+func (t *Tracer) export(spans []SpanData) {
+	batch := Batch{Resource: t.resource, Spans: spans}
+	for _, exporter := range t.exporters {
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		if err := exporter.Export(ctx, batch); err != nil {
+			logging.GetGlobalLogger().Warning("Failed to export spans", map[string]any{
+				"error": err.Error(),
+				"spans": len(spans),
+			})
+		}
+		cancel()
+	}
+}
+
+// Flush exports the spans finished so far and waits for the export
+func (t *Tracer) Flush(ctx context.Context) error {
+	t.mu.RLock()
+	closed := t.closed
+	t.mu.RUnlock()
+	if closed {
+		return nil
+	}
+
+	reply := make(chan struct{})
+	select {
+	case t.flush <- reply:
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown exports the remaining spans and closes the exporters; spans ended
+// afterwards are discarded
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.queue)
+	t.mu.Unlock()
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var errs []error
+	for _, exporter := range t.exporters {
+		if err := exporter.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,350 @@
+// Package tracing records spans for tool calls and the operations they run,
+// and exports them in the OTLP JSON encoding to an OTLP/HTTP collector or a
+// file. Spans follow the W3C trace context, so a client that puts a
+// traceparent in the request _meta sees the server's spans in its own trace.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// IsValid reports whether the ID is not all zeros
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// String returns the ID in lowercase hex
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// IsValid reports whether the ID is not all zeros
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// String returns the ID in lowercase hex
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// SpanContext is the part of a span that is propagated to other processes
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid reports whether both IDs are set
+func (sc SpanContext) IsValid() bool { return sc.TraceID.IsValid() && sc.SpanID.IsValid() }
+
+// TraceparentKey is the name of the W3C trace context header and _meta key
+const TraceparentKey = "traceparent"
+
+// Traceparent formats the span context as a W3C traceparent value
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses a W3C traceparent value such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func ParseTraceparent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+	var sc SpanContext
+	var flags [1]byte
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid traceparent trace ID: %w", err)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid traceparent span ID: %w", err)
+	}
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid traceparent flags: %w", err)
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q: zero ID", value)
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc, nil
+}
+
+// SpanKind is the OTLP span kind
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode is the OTLP span status code
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// SpanData is a finished span as it is exported
+type SpanData struct {
+	TraceID       TraceID
+	SpanID        SpanID
+	ParentSpanID  SpanID
+	Name          string
+	Kind          SpanKind
+	Start         time.Time
+	End           time.Time
+	Attributes    map[string]any
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Span is an operation in progress. All methods are safe on a nil span, which
+// is what Start returns when tracing is disabled.
+type Span struct {
+	tracer *Tracer
+	mu     sync.Mutex
+	data   SpanData
+	sc     SpanContext
+	ended  bool
+}
+
+// SpanContext returns the propagated part of the span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// IsRecording reports whether the span will be exported, so callers can skip
+// computing costly attributes
+func (s *Span) IsRecording() bool {
+	return s != nil && s.sc.Sampled
+}
+
+// SetAttribute sets an attribute, redacting secrets in it
+func (s *Span) SetAttribute(key string, value any) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Attributes[key] = redactAttribute(key, value)
+	}
+}
+
+// SetAttributes sets several attributes
+func (s *Span) SetAttributes(attrs map[string]any) {
+	for key, value := range attrs {
+		s.SetAttribute(key, value)
+	}
+}
+
+// SetStatus sets the span status; an error status takes precedence over OK
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended || s.data.Status == StatusError {
+		return
+	}
+	s.data.Status = code
+	if code == StatusError {
+		s.data.StatusMessage = RedactString(message)
+	}
+}
+
+// RecordError marks the span failed with err; a nil err is ignored
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End finishes the span and queues it for export; later calls do nothing
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.sc.Sampled {
+		s.tracer.enqueue(data)
+	}
+}
+
+// spanKey and remoteKey are the context keys of the current span and of a
+// span context received from another process
+type (
+	spanKey   struct{}
+	remoteKey struct{}
+)
+
+// ContextWithSpan returns a context whose current span is span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the current span of ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteParent returns a context whose next span continues the
+// trace of a span in another process
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// ContextWithMeta continues the trace named by the traceparent key of an MCP
+// request _meta, if it has a valid one
+func ContextWithMeta(ctx context.Context, meta map[string]any) context.Context {
+	value, ok := meta[TraceparentKey].(string)
+	if !ok {
+		return ctx
+	}
+	sc, err := ParseTraceparent(value)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteParent(ctx, sc)
+}
+
+// parentFromContext returns the span context new spans of ctx are children of
+func parentFromContext(ctx context.Context) (SpanContext, *Tracer) {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.sc, span.tracer
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc, nil
+}
+
+// Traceparent returns the traceparent of the current span of ctx, or "" when
+// there is none; it is what outgoing requests propagate
+func Traceparent(ctx context.Context) string {
+	if sc, _ := parentFromContext(ctx); sc.IsValid() {
+		return sc.Traceparent()
+	}
+	return ""
+}
+
+var (
+	globalMu     sync.RWMutex
+	globalTracer *Tracer
+)
+
+// SetGlobalTracer sets the tracer Start uses; nil disables tracing
+func SetGlobalTracer(t *Tracer) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalTracer = t
+}
+
+// GetGlobalTracer returns the tracer Start uses, or nil
+func GetGlobalTracer() *Tracer {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return globalTracer
+}
+
+// Start starts a span as a child of the current span of ctx, using the
+// tracer of that span or else the global tracer. With no tracer it returns
+// ctx and a nil span.
+func Start(ctx context.Context, name string, kind SpanKind, attrs map[string]any) (context.Context, *Span) {
+	parent, tracer := parentFromContext(ctx)
+	if tracer == nil {
+		tracer = GetGlobalTracer()
+	}
+	if tracer == nil {
+		return ctx, nil
+	}
+	return tracer.start(ctx, parent, name, kind, attrs)
+}
+
+// start creates a span of the tracer
+func (t *Tracer) start(ctx context.Context, parent SpanContext, name string, kind SpanKind, attrs map[string]any) (context.Context, *Span) {
+	sc := SpanContext{Sampled: true}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+	} else {
+		sc.TraceID = newTraceID()
+	}
+	sc.SpanID = newSpanID()
+
+	span := &Span{
+		tracer: t,
+		sc:     sc,
+		data: SpanData{
+			TraceID:      sc.TraceID,
+			SpanID:       sc.SpanID,
+			ParentSpanID: parent.SpanID,
+			Name:         name,
+			Kind:         kind,
+			Start:        time.Now(),
+			Attributes:   make(map[string]any, len(attrs)),
+		},
+	}
+	span.SetAttributes(attrs)
+	return ContextWithSpan(ctx, span), span
+}
+
+// newTraceID returns a random non-zero trace ID
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		for i := 0; i < len(id); i += 8 {
+			putUint64(id[i:], rand.Uint64())
+		}
+	}
+	return id
+}
+
+// newSpanID returns a random non-zero span ID
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		putUint64(id[:], rand.Uint64())
+	}
+	return id
+}
+
+// putUint64 writes v big-endian into the first eight bytes of b
+func putUint64(b []byte, v uint64) {
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
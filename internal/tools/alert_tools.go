@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"mini-mcp/internal/domain/alerting"
+	"mini-mcp/internal/registry"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// alertStates are the values accepted by the state argument of alerts
+var alertStates = []string{"active", alerting.StatePending, alerting.StateFiring, alerting.StateResolved, "all"}
+
+// RegisterAlertTools registers tools over the alert rules engine
+func RegisterAlertTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, engine *alerting.Engine) {
+	// alerts - What is currently wrong?
+	builder := registry.NewToolBuilder[AlertsArgs](toolRegistry, "alerts", "List alerts raised by the configured alert rules (-alert-rules). state is active (pending and firing, default), pending, firing, resolved (recently resolved) or all; min_severity keeps alerts at info, warning, error or critical and above. Each alert has its rule name, labels, the value compared with the threshold and when it became pending, fired and resolved. include_rules adds every rule with its expression and last evaluation.")
+
+	builder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args AlertsArgs) (*mcp.CallToolResult, any, error) {
+			successResult, _, _ := toolRegistry.CreateSuccessResult(engine.Report(args.filter(), args.IncludeRules))
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args AlertsArgs) error {
+			return args.Validate()
+		})
+
+	if err := builder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// AlertsArgs represents arguments for alerts
+type AlertsArgs struct {
+	State        string `json:"state,omitempty" jsonschema:"active (default), pending, firing, resolved or all"`
+	MinSeverity  string `json:"min_severity,omitempty" jsonschema:"Keep alerts at this severity or above: info, warning, error or critical"`
+	IncludeRules bool   `json:"include_rules,omitempty" jsonschema:"Include the configured rules with their last evaluation"`
+}
+
+// Validate validates AlertsArgs
+func (args AlertsArgs) Validate() error {
+	if args.State != "" && !slices.Contains(alertStates, args.State) {
+		return registry.NewValidationError("invalid_state", "state must be one of: "+strings.Join(alertStates, ", "))
+	}
+	if args.MinSeverity != "" && !slices.Contains(alerting.Severities, args.MinSeverity) {
+		return registry.NewValidationError("invalid_severity", "min_severity must be one of: "+strings.Join(alerting.Severities, ", "))
+	}
+	return nil
+}
+
+// filter converts the arguments to an alert filter
+func (args AlertsArgs) filter() alerting.Filter {
+	filter := alerting.Filter{MinSeverity: args.MinSeverity}
+	switch args.State {
+	case "", "active":
+	case "all":
+		filter.States = []string{alerting.StatePending, alerting.StateFiring, alerting.StateResolved}
+	default:
+		filter.States = []string{args.State}
+	}
+	return filter
+}
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"testing"
+
+	"mini-mcp/internal/domain/alerting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertsArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      AlertsArgs
+		wantError bool
+	}{
+		{name: "defaults", args: AlertsArgs{}},
+		{name: "firing critical", args: AlertsArgs{State: "firing", MinSeverity: "critical", IncludeRules: true}},
+		{name: "all", args: AlertsArgs{State: "all"}},
+		{name: "bad state", args: AlertsArgs{State: "silenced"}, wantError: true},
+		{name: "bad severity", args: AlertsArgs{MinSeverity: "page"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAlertsArgs_Filter(t *testing.T) {
+	assert.Empty(t, AlertsArgs{State: "active"}.filter().States)
+	assert.Equal(t, []string{alerting.StateResolved}, AlertsArgs{State: "resolved"}.filter().States)
+	assert.Len(t, AlertsArgs{State: "all"}.filter().States, 3)
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"path"
+
+	"mini-mcp/internal/handlers/core"
+	"mini-mcp/internal/registry"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ===== TYPE-SAFE ARGUMENT STRUCTURES =====
+
+// ArchiveCreateArgs represents arguments for the archive_create command
+type ArchiveCreateArgs struct {
+	Source    string   `json:"source" jsonschema:"File or directory to archive"`
+	Archive   string   `json:"archive" jsonschema:"Archive file to create"`
+	Format    string   `json:"format,omitempty" jsonschema:"Archive format: tar, tar.gz, tar.zst or zip (detected from the archive name when omitted)"`
+	Include   []string `json:"include,omitempty" jsonschema:"Only archive files matching these globs (matched against relative path and base name)"`
+	Exclude   []string `json:"exclude,omitempty" jsonschema:"Skip files and directories matching these globs"`
+	Overwrite bool     `json:"overwrite,omitempty" jsonschema:"Replace an existing archive"`
+}
+
+// ArchiveExtractArgs represents arguments for the archive_extract command
+type ArchiveExtractArgs struct {
+	Archive     string `json:"archive" jsonschema:"Archive file to extract"`
+	Destination string `json:"destination" jsonschema:"Directory to extract into (created if missing)"`
+	Format      string `json:"format,omitempty" jsonschema:"Archive format: tar, tar.gz, tar.zst or zip (detected from the archive name when omitted)"`
+	MaxBytes    int64  `json:"max_bytes,omitempty" jsonschema:"Maximum total uncompressed size in bytes (default 1 GiB)"`
+	MaxEntries  int    `json:"max_entries,omitempty" jsonschema:"Maximum number of entries (default 10000)"`
+	Overwrite   bool   `json:"overwrite,omitempty" jsonschema:"Replace existing files in the destination"`
+}
+
+// ===== VALIDATION METHODS (STRATEGY PATTERN) =====
+
+// Validate validates ArchiveCreateArgs
+func (args ArchiveCreateArgs) Validate() error {
+	if args.Source == "" {
+		return registry.NewValidationError("missing_source", "source is required")
+	}
+	if args.Archive == "" {
+		return registry.NewValidationError("missing_archive", "archive is required")
+	}
+	if err := validateArchiveFormat(args.Format); err != nil {
+		return err
+	}
+	for _, pattern := range append(append([]string{}, args.Include...), args.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return registry.NewValidationError("invalid_glob", "invalid glob pattern: "+pattern)
+		}
+	}
+	return nil
+}
+
+// Validate validates ArchiveExtractArgs
+func (args ArchiveExtractArgs) Validate() error {
+	if args.Archive == "" {
+		return registry.NewValidationError("missing_archive", "archive is required")
+	}
+	if args.Destination == "" {
+		return registry.NewValidationError("missing_destination", "destination is required")
+	}
+	if err := validateArchiveFormat(args.Format); err != nil {
+		return err
+	}
+	if args.MaxBytes < 0 {
+		return registry.NewValidationError("invalid_max_bytes", "max_bytes must not be negative")
+	}
+	if args.MaxEntries < 0 {
+		return registry.NewValidationError("invalid_max_entries", "max_entries must not be negative")
+	}
+	return nil
+}
+
+// validateArchiveFormat checks an optional archive format
+func validateArchiveFormat(format string) error {
+	switch format {
+	case "", "tar", "tar.gz", "tar.zst", "zip":
+		return nil
+	default:
+		return registry.NewValidationError("invalid_format", "format must be one of: tar, tar.gz, tar.zst, zip")
+	}
+}
+
+// ===== TOOL REGISTRATION USING DESIGN PATTERNS =====
+
+// RegisterArchiveTools registers archive creation and extraction tools
+func RegisterArchiveTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, fileHandler *core.FileHandlerImpl) {
+	// archive_create - Pack files into an archive (Builder Pattern)
+	createBuilder := registry.NewToolBuilder[ArchiveCreateArgs](toolRegistry, "archive_create", "Create a tar, tar.gz, tar.zst or zip archive from a file or directory with include/exclude globs")
+
+	createBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args ArchiveCreateArgs) (*mcp.CallToolResult, any, error) {
+			result, err := fileHandler.CreateArchive(ctx, map[string]any{
+				"source":      args.Source,
+				"destination": args.Archive,
+				"format":      args.Format,
+				"include":     args.Include,
+				"exclude":     args.Exclude,
+				"overwrite":   args.Overwrite,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"source":  args.Source,
+					"archive": args.Archive,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args ArchiveCreateArgs) error {
+			return args.Validate()
+		})
+
+	if err := createBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// archive_extract - Unpack an archive safely (Builder Pattern)
+	extractBuilder := registry.NewToolBuilder[ArchiveExtractArgs](toolRegistry, "archive_extract", "Extract a tar, tar.gz, tar.zst or zip archive, rejecting absolute paths, '..' escapes, escaping symlinks and device files, with size and entry limits")
+
+	extractBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args ArchiveExtractArgs) (*mcp.CallToolResult, any, error) {
+			result, err := fileHandler.ExtractArchive(ctx, map[string]any{
+				"source":      args.Archive,
+				"destination": args.Destination,
+				"format":      args.Format,
+				"max_bytes":   args.MaxBytes,
+				"max_entries": args.MaxEntries,
+				"overwrite":   args.Overwrite,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"archive":     args.Archive,
+					"destination": args.Destination,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args ArchiveExtractArgs) error {
+			return args.Validate()
+		})
+
+	if err := extractBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
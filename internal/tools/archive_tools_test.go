@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveTools_Validation(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      interface{}
+		wantError bool
+	}{
+		{
+			name: "valid archive create args",
+			args: ArchiveCreateArgs{
+				Source:  "/var/log/app",
+				Archive: "/tmp/app-logs.tar.gz",
+				Exclude: []string{"*.tmp"},
+			},
+			wantError: false,
+		},
+		{
+			name: "missing archive in create",
+			args: ArchiveCreateArgs{
+				Source: "/var/log/app",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid glob in create",
+			args: ArchiveCreateArgs{
+				Source:  "/var/log/app",
+				Archive: "/tmp/app-logs.zip",
+				Include: []string{"["},
+			},
+			wantError: true,
+		},
+		{
+			name: "unsupported format in create",
+			args: ArchiveCreateArgs{
+				Source:  "/var/log/app",
+				Archive: "/tmp/app-logs.rar",
+				Format:  "rar",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid archive extract args",
+			args: ArchiveExtractArgs{
+				Archive:     "/tmp/release.tar.zst",
+				Destination: "/opt/release",
+				MaxEntries:  500,
+			},
+			wantError: false,
+		},
+		{
+			name: "missing destination in extract",
+			args: ArchiveExtractArgs{
+				Archive: "/tmp/release.tar.zst",
+			},
+			wantError: true,
+		},
+		{
+			name: "negative max bytes in extract",
+			args: ArchiveExtractArgs{
+				Archive:     "/tmp/release.tar.zst",
+				Destination: "/opt/release",
+				MaxBytes:    -1,
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			switch args := tt.args.(type) {
+			case ArchiveCreateArgs:
+				err = args.Validate()
+			case ArchiveExtractArgs:
+				err = args.Validate()
+			}
+
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -23,7 +23,7 @@ func RegisterCommandTools(server *mcp.Server, toolRegistry *registry.TypeSafeToo
 				"timeout": float64(args.Timeout),
 			})
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"command": args.Command,
 					"timeout": args.Timeout,
 				})
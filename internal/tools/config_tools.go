@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+
+	"mini-mcp/internal/handlers/core"
+	"mini-mcp/internal/registry"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ===== TYPE-SAFE ARGUMENT STRUCTURES =====
+
+// ConfigGetArgs represents arguments for the config_get command
+type ConfigGetArgs struct {
+	Path   string `json:"path" jsonschema:"Configuration file to read"`
+	Key    string `json:"key" jsonschema:"Key path such as services.web.image or [0].name"`
+	Format string `json:"format,omitempty" jsonschema:"File format: yaml, json, toml or ini (detected from the extension when omitted)"`
+}
+
+// ConfigSetArgs represents arguments for the config_set command
+type ConfigSetArgs struct {
+	Path   string `json:"path" jsonschema:"Configuration file to edit"`
+	Key    string `json:"key" jsonschema:"Key path such as services.web.image or [0].name; missing intermediate keys are created"`
+	Value  any    `json:"value" jsonschema:"Value to set (string, number, boolean, list or object)"`
+	Format string `json:"format,omitempty" jsonschema:"File format: yaml, json, toml or ini (detected from the extension when omitted)"`
+	Create bool   `json:"create,omitempty" jsonschema:"Create the file if it does not exist"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema:"Return the diff without writing the file"`
+}
+
+// ConfigDeleteArgs represents arguments for the config_delete command
+type ConfigDeleteArgs struct {
+	Path   string `json:"path" jsonschema:"Configuration file to edit"`
+	Key    string `json:"key" jsonschema:"Key path of the value, list element or section to remove"`
+	Format string `json:"format,omitempty" jsonschema:"File format: yaml, json, toml or ini (detected from the extension when omitted)"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema:"Return the diff without writing the file"`
+}
+
+// ===== VALIDATION METHODS (STRATEGY PATTERN) =====
+
+// Validate validates ConfigGetArgs
+func (args ConfigGetArgs) Validate() error {
+	return validateConfigTarget(args.Path, args.Key, args.Format)
+}
+
+// Validate validates ConfigSetArgs
+func (args ConfigSetArgs) Validate() error {
+	if err := validateConfigTarget(args.Path, args.Key, args.Format); err != nil {
+		return err
+	}
+	if args.Value == nil {
+		return registry.NewValidationError("missing_value", "value is required")
+	}
+	return nil
+}
+
+// Validate validates ConfigDeleteArgs
+func (args ConfigDeleteArgs) Validate() error {
+	return validateConfigTarget(args.Path, args.Key, args.Format)
+}
+
+// validateConfigTarget checks the arguments shared by all config tools
+func validateConfigTarget(path, key, format string) error {
+	if path == "" {
+		return registry.NewValidationError("missing_path", "path is required")
+	}
+	if key == "" {
+		return registry.NewValidationError("missing_key", "key is required")
+	}
+	switch format {
+	case "", "yaml", "json", "toml", "ini":
+		return nil
+	default:
+		return registry.NewValidationError("invalid_format", "format must be one of: yaml, json, toml, ini")
+	}
+}
+
+// ===== TOOL REGISTRATION USING DESIGN PATTERNS =====
+
+// RegisterConfigTools registers structured configuration file tools
+func RegisterConfigTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, configHandler *core.ConfigHandlerImpl) {
+	// config_get - Read a value by key path (Builder Pattern)
+	getBuilder := registry.NewToolBuilder[ConfigGetArgs](toolRegistry, "config_get", "Read a value from a YAML, JSON, TOML or INI file by key path (e.g. services.web.image, [0].name)")
+
+	getBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args ConfigGetArgs) (*mcp.CallToolResult, any, error) {
+			result, err := configHandler.GetValue(ctx, map[string]any{
+				"path":   args.Path,
+				"key":    args.Key,
+				"format": args.Format,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+					"key":  args.Key,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args ConfigGetArgs) error {
+			return args.Validate()
+		})
+
+	if err := getBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// config_set - Set a value while preserving comments and formatting (Builder Pattern)
+	setBuilder := registry.NewToolBuilder[ConfigSetArgs](toolRegistry, "config_set", "Set a value in a YAML, JSON, TOML or INI file by key path, preserving comments, key order and formatting; validates the result, writes atomically and returns a diff")
+
+	setBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args ConfigSetArgs) (*mcp.CallToolResult, any, error) {
+			result, err := configHandler.SetValue(ctx, map[string]any{
+				"path":    args.Path,
+				"key":     args.Key,
+				"value":   args.Value,
+				"format":  args.Format,
+				"create":  args.Create,
+				"dry_run": args.DryRun,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+					"key":  args.Key,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args ConfigSetArgs) error {
+			return args.Validate()
+		})
+
+	if err := setBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// config_delete - Remove a value while preserving comments and formatting (Builder Pattern)
+	deleteBuilder := registry.NewToolBuilder[ConfigDeleteArgs](toolRegistry, "config_delete", "Remove a key, list element or section from a YAML, JSON, TOML or INI file, preserving the rest of the file; writes atomically and returns a diff")
+
+	deleteBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args ConfigDeleteArgs) (*mcp.CallToolResult, any, error) {
+			result, err := configHandler.DeleteValue(ctx, map[string]any{
+				"path":    args.Path,
+				"key":     args.Key,
+				"format":  args.Format,
+				"dry_run": args.DryRun,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+					"key":  args.Key,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args ConfigDeleteArgs) error {
+			return args.Validate()
+		})
+
+	if err := deleteBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
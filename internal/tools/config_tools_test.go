@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigTools_Validation(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      interface{}
+		wantError bool
+	}{
+		{
+			name: "valid config get args",
+			args: ConfigGetArgs{
+				Path: "/tmp/compose.yaml",
+				Key:  "services.web.image",
+			},
+			wantError: false,
+		},
+		{
+			name: "missing key in get",
+			args: ConfigGetArgs{
+				Path: "/tmp/compose.yaml",
+			},
+			wantError: true,
+		},
+		{
+			name: "unsupported format in get",
+			args: ConfigGetArgs{
+				Path:   "/tmp/app.conf",
+				Key:    "a",
+				Format: "xml",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid config set args",
+			args: ConfigSetArgs{
+				Path:   "/tmp/app.json",
+				Key:    "[0].name",
+				Value:  "web",
+				DryRun: true,
+			},
+			wantError: false,
+		},
+		{
+			name: "missing value in set",
+			args: ConfigSetArgs{
+				Path: "/tmp/app.json",
+				Key:  "name",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid config delete args",
+			args: ConfigDeleteArgs{
+				Path:   "/tmp/app.ini",
+				Key:    "server.port",
+				Format: "ini",
+			},
+			wantError: false,
+		},
+		{
+			name: "missing path in delete",
+			args: ConfigDeleteArgs{
+				Key: "server.port",
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			switch args := tt.args.(type) {
+			case ConfigGetArgs:
+				err = args.Validate()
+			case ConfigSetArgs:
+				err = args.Validate()
+			case ConfigDeleteArgs:
+				err = args.Validate()
+			}
+
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"strings"
 
 	"mini-mcp/internal/handlers/core"
 	"mini-mcp/internal/registry"
@@ -13,7 +14,15 @@ import (
 
 // FileListArgs represents arguments for the ls command
 type FileListArgs struct {
-	Path string `json:"path" jsonschema:"Directory path to list"`
+	Path          string `json:"path" jsonschema:"Directory path to list"`
+	Recursive     bool   `json:"recursive,omitempty" jsonschema:"Descend into subdirectories"`
+	MaxDepth      int    `json:"max_depth,omitempty" jsonschema:"Maximum recursion depth (1 = direct children only, 0 = unlimited)"`
+	IncludeHidden bool   `json:"include_hidden,omitempty" jsonschema:"Include dot-files and dot-directories"`
+	SortBy        string `json:"sort_by,omitempty" jsonschema:"Sort siblings by name, size, mtime or type (default name)"`
+	Reverse       bool   `json:"reverse,omitempty" jsonschema:"Reverse the sort order"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"Index of the first entry to return"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum number of entries to return (default 500, max 5000)"`
+	DirSizes      bool   `json:"dir_sizes,omitempty" jsonschema:"Compute aggregate sizes for directories"`
 }
 
 // FileReadArgs represents arguments for the cat command
@@ -32,11 +41,80 @@ type FileDeleteArgs struct {
 	Path string `json:"path" jsonschema:"File or directory path to remove"`
 }
 
+// FileMkdirArgs represents arguments for the mkdir command
+type FileMkdirArgs struct {
+	Path string `json:"path" jsonschema:"Directory path to create (parents are created as needed)"`
+	Mode string `json:"mode,omitempty" jsonschema:"Octal permission bits (default 0755)"`
+}
+
+// FileMoveArgs represents arguments for the mv command
+type FileMoveArgs struct {
+	Source      string `json:"source" jsonschema:"Path to move"`
+	Destination string `json:"destination" jsonschema:"Target path, or an existing directory to move into"`
+	Overwrite   bool   `json:"overwrite,omitempty" jsonschema:"Replace an existing destination"`
+}
+
+// FileCopyArgs represents arguments for the cp command
+type FileCopyArgs struct {
+	Source        string `json:"source" jsonschema:"Path to copy"`
+	Destination   string `json:"destination" jsonschema:"Target path, or an existing directory to copy into"`
+	Recursive     bool   `json:"recursive,omitempty" jsonschema:"Copy directories recursively"`
+	Overwrite     bool   `json:"overwrite,omitempty" jsonschema:"Replace existing destination files"`
+	PreserveOwner bool   `json:"preserve_owner,omitempty" jsonschema:"Preserve owner and group (requires privileges)"`
+}
+
+// FileChmodArgs represents arguments for the chmod command
+type FileChmodArgs struct {
+	Path      string `json:"path" jsonschema:"File or directory path"`
+	Mode      string `json:"mode" jsonschema:"Octal permission bits (e.g. 0644)"`
+	Recursive bool   `json:"recursive,omitempty" jsonschema:"Apply to the whole directory tree"`
+}
+
+// FileChownArgs represents arguments for the chown command
+type FileChownArgs struct {
+	Path      string `json:"path" jsonschema:"File or directory path"`
+	Owner     string `json:"owner,omitempty" jsonschema:"New owner user name or UID"`
+	Group     string `json:"group,omitempty" jsonschema:"New group name or GID"`
+	Recursive bool   `json:"recursive,omitempty" jsonschema:"Apply to the whole directory tree"`
+}
+
+// FileStatArgs represents arguments for the stat command
+type FileStatArgs struct {
+	Path string `json:"path" jsonschema:"Path to inspect"`
+}
+
+// FileChecksumArgs represents arguments for the checksum command
+type FileChecksumArgs struct {
+	Path      string `json:"path" jsonschema:"File to hash"`
+	Algorithm string `json:"algorithm,omitempty" jsonschema:"Digest algorithm: sha256 (default) or md5"`
+}
+
+// FileTailArgs represents arguments for the tail command
+type FileTailArgs struct {
+	Path   string `json:"path" jsonschema:"File to read"`
+	Lines  int    `json:"lines,omitempty" jsonschema:"Number of trailing lines, or the maximum number of new lines when a cursor is given (default 100, max 5000)"`
+	Cursor string `json:"cursor,omitempty" jsonschema:"Cursor from a previous tail call; only lines appended since are returned"`
+}
+
 // ===== VALIDATION METHODS (STRATEGY PATTERN) =====
 
 // Validate validates FileListArgs
 func (args FileListArgs) Validate() error {
-	// Basic validation - path will be validated by security layer
+	// Path will be validated by security layer
+	switch args.SortBy {
+	case "", "name", "size", "mtime", "type":
+	default:
+		return registry.NewValidationError("invalid_sort_by", "sort_by must be one of: name, size, mtime, type")
+	}
+	if args.MaxDepth < 0 {
+		return registry.NewValidationError("invalid_max_depth", "max_depth must not be negative")
+	}
+	if args.Offset < 0 {
+		return registry.NewValidationError("invalid_offset", "offset must not be negative")
+	}
+	if args.Limit < 0 {
+		return registry.NewValidationError("invalid_limit", "limit must not be negative")
+	}
 	return nil
 }
 
@@ -64,12 +142,112 @@ func (args FileDeleteArgs) Validate() error {
 	return nil
 }
 
+// Validate validates FileMkdirArgs
+func (args FileMkdirArgs) Validate() error {
+	if args.Path == "" {
+		return registry.NewValidationError("missing_path", "path is required")
+	}
+	if args.Mode != "" && !isOctalMode(args.Mode) {
+		return registry.NewValidationError("invalid_mode", "mode must be an octal permission string such as 0755")
+	}
+	return nil
+}
+
+// Validate validates FileMoveArgs
+func (args FileMoveArgs) Validate() error {
+	if args.Source == "" {
+		return registry.NewValidationError("missing_source", "source is required")
+	}
+	if args.Destination == "" {
+		return registry.NewValidationError("missing_destination", "destination is required")
+	}
+	return nil
+}
+
+// Validate validates FileCopyArgs
+func (args FileCopyArgs) Validate() error {
+	if args.Source == "" {
+		return registry.NewValidationError("missing_source", "source is required")
+	}
+	if args.Destination == "" {
+		return registry.NewValidationError("missing_destination", "destination is required")
+	}
+	return nil
+}
+
+// Validate validates FileChmodArgs
+func (args FileChmodArgs) Validate() error {
+	if args.Path == "" {
+		return registry.NewValidationError("missing_path", "path is required")
+	}
+	if !isOctalMode(args.Mode) {
+		return registry.NewValidationError("invalid_mode", "mode must be an octal permission string such as 0644")
+	}
+	return nil
+}
+
+// Validate validates FileChownArgs
+func (args FileChownArgs) Validate() error {
+	if args.Path == "" {
+		return registry.NewValidationError("missing_path", "path is required")
+	}
+	if args.Owner == "" && args.Group == "" {
+		return registry.NewValidationError("missing_owner", "owner or group is required")
+	}
+	return nil
+}
+
+// Validate validates FileStatArgs
+func (args FileStatArgs) Validate() error {
+	if args.Path == "" {
+		return registry.NewValidationError("missing_path", "path is required")
+	}
+	return nil
+}
+
+// Validate validates FileChecksumArgs
+func (args FileChecksumArgs) Validate() error {
+	if args.Path == "" {
+		return registry.NewValidationError("missing_path", "path is required")
+	}
+	switch strings.ToLower(args.Algorithm) {
+	case "", "sha256", "md5":
+	default:
+		return registry.NewValidationError("invalid_algorithm", "algorithm must be one of: sha256, md5")
+	}
+	return nil
+}
+
+// Validate validates FileTailArgs
+func (args FileTailArgs) Validate() error {
+	if args.Path == "" {
+		return registry.NewValidationError("missing_path", "path is required")
+	}
+	if args.Lines < 0 {
+		return registry.NewValidationError("invalid_lines", "lines must not be negative")
+	}
+	return nil
+}
+
+// isOctalMode reports whether s is a 3 or 4 digit octal permission string
+func isOctalMode(s string) bool {
+	if len(s) < 3 || len(s) > 4 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '7' {
+			return false
+		}
+	}
+	return true
+}
+
 // ===== TOOL REGISTRATION USING DESIGN PATTERNS =====
 
 // RegisterFileTools registers file-related tools using proper design patterns
 func RegisterFileTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, fileHandler *core.FileHandlerImpl) {
 	// ls - List directory contents (Builder Pattern)
-	lsBuilder := registry.NewToolBuilder[FileListArgs](toolRegistry, "ls", "List directory contents with security validation")
+	lsBuilder := registry.NewToolBuilder[FileListArgs](toolRegistry, "ls", "List directory contents as structured entries (type, size, mode, owner, mtime, symlink target) with optional recursion, sorting, pagination and directory sizes")
 
 	lsBuilder.
 		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileListArgs) (*mcp.CallToolResult, any, error) {
@@ -77,17 +255,25 @@ func RegisterFileTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRe
 				args.Path = "."
 			}
 
-			output, err := fileHandler.ListDirectory(ctx, map[string]any{
-				"path": args.Path,
+			listing, err := fileHandler.ListDirectory(ctx, map[string]any{
+				"path":           args.Path,
+				"recursive":      args.Recursive,
+				"max_depth":      args.MaxDepth,
+				"include_hidden": args.IncludeHidden,
+				"sort_by":        args.SortBy,
+				"reverse":        args.Reverse,
+				"offset":         args.Offset,
+				"limit":          args.Limit,
+				"dir_sizes":      args.DirSizes,
 			})
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"path": args.Path,
 				})
 				return errorResult, nil, nil
 			}
 
-			successResult, _, _ := toolRegistry.CreateTextResult(output)
+			successResult, _, _ := toolRegistry.CreateSuccessResult(listing)
 			return successResult, nil, nil
 		}).
 		WithValidator(func(args FileListArgs) error {
@@ -108,7 +294,7 @@ func RegisterFileTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRe
 				"path": args.Path,
 			})
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"path": args.Path,
 				})
 				return errorResult, nil, nil
@@ -136,7 +322,7 @@ func RegisterFileTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRe
 				"content": args.Content,
 			})
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"path": args.Path,
 				})
 				return errorResult, nil, nil
@@ -163,7 +349,7 @@ func RegisterFileTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRe
 				"path": args.Path,
 			})
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"path": args.Path,
 				})
 				return errorResult, nil, nil
@@ -180,4 +366,238 @@ func RegisterFileTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRe
 		// Log error but continue - tool registration failure should not crash the server
 		return
 	}
+
+	// mkdir - Create directory with parents (Builder Pattern)
+	mkdirBuilder := registry.NewToolBuilder[FileMkdirArgs](toolRegistry, "mkdir", "Create a directory and any missing parents (mkdir -p) with security validation")
+
+	mkdirBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileMkdirArgs) (*mcp.CallToolResult, any, error) {
+			output, err := fileHandler.CreateDirectory(ctx, map[string]any{
+				"path": args.Path,
+				"mode": args.Mode,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateTextResult(output)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileMkdirArgs) error {
+			return args.Validate()
+		})
+
+	if err := mkdirBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// mv - Move or rename (Builder Pattern)
+	mvBuilder := registry.NewToolBuilder[FileMoveArgs](toolRegistry, "mv", "Move or rename a file or directory with security validation on both paths")
+
+	mvBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileMoveArgs) (*mcp.CallToolResult, any, error) {
+			output, err := fileHandler.MovePath(ctx, map[string]any{
+				"source":      args.Source,
+				"destination": args.Destination,
+				"overwrite":   args.Overwrite,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"source":      args.Source,
+					"destination": args.Destination,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateTextResult(output)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileMoveArgs) error {
+			return args.Validate()
+		})
+
+	if err := mvBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// cp - Copy files and directories (Builder Pattern)
+	cpBuilder := registry.NewToolBuilder[FileCopyArgs](toolRegistry, "cp", "Copy a file or directory tree, preserving permissions, timestamps and symlinks")
+
+	cpBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileCopyArgs) (*mcp.CallToolResult, any, error) {
+			result, err := fileHandler.CopyPath(ctx, map[string]any{
+				"source":         args.Source,
+				"destination":    args.Destination,
+				"recursive":      args.Recursive,
+				"overwrite":      args.Overwrite,
+				"preserve_owner": args.PreserveOwner,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"source":      args.Source,
+					"destination": args.Destination,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileCopyArgs) error {
+			return args.Validate()
+		})
+
+	if err := cpBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// chmod - Change permissions (Builder Pattern)
+	chmodBuilder := registry.NewToolBuilder[FileChmodArgs](toolRegistry, "chmod", "Change permission bits; world-writable, setuid and setgid modes are refused by policy")
+
+	chmodBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileChmodArgs) (*mcp.CallToolResult, any, error) {
+			output, err := fileHandler.ChangeMode(ctx, map[string]any{
+				"path":      args.Path,
+				"mode":      args.Mode,
+				"recursive": args.Recursive,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+					"mode": args.Mode,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateTextResult(output)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileChmodArgs) error {
+			return args.Validate()
+		})
+
+	if err := chmodBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// chown - Change ownership (Builder Pattern)
+	chownBuilder := registry.NewToolBuilder[FileChownArgs](toolRegistry, "chown", "Change owner and/or group; transferring ownership to root is refused by policy")
+
+	chownBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileChownArgs) (*mcp.CallToolResult, any, error) {
+			output, err := fileHandler.ChangeOwner(ctx, map[string]any{
+				"path":      args.Path,
+				"owner":     args.Owner,
+				"group":     args.Group,
+				"recursive": args.Recursive,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateTextResult(output)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileChownArgs) error {
+			return args.Validate()
+		})
+
+	if err := chownBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// stat - Detailed file metadata (Builder Pattern)
+	statBuilder := registry.NewToolBuilder[FileStatArgs](toolRegistry, "stat", "Show detailed metadata for a path (type, mode, owner, inode, links, timestamps)")
+
+	statBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileStatArgs) (*mcp.CallToolResult, any, error) {
+			result, err := fileHandler.StatPath(ctx, map[string]any{
+				"path": args.Path,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileStatArgs) error {
+			return args.Validate()
+		})
+
+	if err := statBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// checksum - File digest (Builder Pattern)
+	checksumBuilder := registry.NewToolBuilder[FileChecksumArgs](toolRegistry, "checksum", "Compute the sha256 or md5 checksum of a file")
+
+	checksumBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileChecksumArgs) (*mcp.CallToolResult, any, error) {
+			result, err := fileHandler.Checksum(ctx, map[string]any{
+				"path":      args.Path,
+				"algorithm": args.Algorithm,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileChecksumArgs) error {
+			return args.Validate()
+		})
+
+	if err := checksumBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// tail - Read new lines from a file (Builder Pattern)
+	tailBuilder := registry.NewToolBuilder[FileTailArgs](toolRegistry, "tail", "Return the last lines of a file, or only the lines appended since a cursor; detects rotation and truncation")
+
+	tailBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args FileTailArgs) (*mcp.CallToolResult, any, error) {
+			result, err := fileHandler.TailFile(ctx, map[string]any{
+				"path":   args.Path,
+				"lines":  args.Lines,
+				"cursor": args.Cursor,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"path": args.Path,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args FileTailArgs) error {
+			return args.Validate()
+		})
+
+	if err := tailBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
 }
@@ -19,6 +19,33 @@ func TestFileTools_Validation(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "valid recursive file list args",
+			args: FileListArgs{
+				Path:      "/tmp",
+				Recursive: true,
+				MaxDepth:  3,
+				SortBy:    "mtime",
+				Limit:     100,
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid sort key in file list",
+			args: FileListArgs{
+				Path:   "/tmp",
+				SortBy: "owner",
+			},
+			wantError: true,
+		},
+		{
+			name: "negative offset in file list",
+			args: FileListArgs{
+				Path:   "/tmp",
+				Offset: -1,
+			},
+			wantError: true,
+		},
 		{
 			name: "valid file read args",
 			args: FileReadArgs{
@@ -63,6 +90,84 @@ func TestFileTools_Validation(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "valid mkdir args",
+			args: FileMkdirArgs{
+				Path: "/tmp/a/b",
+				Mode: "0750",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid mkdir mode",
+			args: FileMkdirArgs{
+				Path: "/tmp/a/b",
+				Mode: "rwx",
+			},
+			wantError: true,
+		},
+		{
+			name: "missing destination in move",
+			args: FileMoveArgs{
+				Source: "/tmp/a",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid copy args",
+			args: FileCopyArgs{
+				Source:      "/tmp/a",
+				Destination: "/tmp/b",
+				Recursive:   true,
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid chmod mode",
+			args: FileChmodArgs{
+				Path: "/tmp/a",
+				Mode: "999",
+			},
+			wantError: true,
+		},
+		{
+			name: "chown without owner or group",
+			args: FileChownArgs{
+				Path: "/tmp/a",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid stat args",
+			args: FileStatArgs{
+				Path: "/tmp/a",
+			},
+			wantError: false,
+		},
+		{
+			name: "unsupported checksum algorithm",
+			args: FileChecksumArgs{
+				Path:      "/tmp/a",
+				Algorithm: "sha1",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid tail args with cursor",
+			args: FileTailArgs{
+				Path:   "/var/log/syslog",
+				Cursor: "1234:5678",
+			},
+			wantError: false,
+		},
+		{
+			name: "negative lines in tail",
+			args: FileTailArgs{
+				Path:  "/var/log/syslog",
+				Lines: -5,
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -77,6 +182,22 @@ func TestFileTools_Validation(t *testing.T) {
 				err = args.Validate()
 			case FileDeleteArgs:
 				err = args.Validate()
+			case FileMkdirArgs:
+				err = args.Validate()
+			case FileMoveArgs:
+				err = args.Validate()
+			case FileCopyArgs:
+				err = args.Validate()
+			case FileChmodArgs:
+				err = args.Validate()
+			case FileChownArgs:
+				err = args.Validate()
+			case FileStatArgs:
+				err = args.Validate()
+			case FileChecksumArgs:
+				err = args.Validate()
+			case FileTailArgs:
+				err = args.Validate()
 			}
 
 			if tt.wantError {
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"mini-mcp/internal/health"
+	"mini-mcp/internal/registry"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxHealthCheckNames bounds how many checks one health_check call names
+const maxHealthCheckNames = 100
+
+// RegisterHealthTools registers tools over the health checker
+func RegisterHealthTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, healthChecker *health.HealthChecker) {
+	// health_check - Is everything this host depends on working right now?
+	builder := registry.NewToolBuilder[HealthCheckArgs](toolRegistry, "health_check", "Run health checks now and report their results: the built-in checks plus the http, tcp, dns, process, file, disk and command checks declared in the -health-checks file. checks limits the run to the named checks and dependencies; by default all run. Each result has its status (healthy, degraded or unhealthy), message, details, duration, since when it has had that status and whether it is flapping; include_history adds the recent results of each check.")
+
+	builder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args HealthCheckArgs) (*mcp.CallToolResult, any, error) {
+			info, err := healthChecker.RunChecks(ctx, args.Checks)
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"checks": args.Checks,
+				})
+				return errorResult, nil, nil
+			}
+
+			report := HealthCheckReport{HealthInfo: info}
+			if args.IncludeHistory {
+				report.History = make(map[string][]health.HistoryEntry)
+				for name := range info.Checks {
+					report.History[name], _ = healthChecker.History(name)
+				}
+				for _, dep := range info.Dependencies {
+					report.History[dep.Name], _ = healthChecker.History(dep.Name)
+				}
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(report)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args HealthCheckArgs) error {
+			return args.Validate()
+		})
+
+	if err := builder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// HealthCheckArgs represents arguments for health_check
+type HealthCheckArgs struct {
+	Checks         []string `json:"checks,omitempty" jsonschema:"Names of the checks and dependencies to run; empty runs all"`
+	IncludeHistory bool     `json:"include_history,omitempty" jsonschema:"Include the recent results of each check"`
+}
+
+// Validate validates HealthCheckArgs
+func (args HealthCheckArgs) Validate() error {
+	if len(args.Checks) > maxHealthCheckNames {
+		return registry.NewValidationError("too_many_checks", "at most 100 checks can be named")
+	}
+	for _, name := range args.Checks {
+		if strings.TrimSpace(name) == "" {
+			return registry.NewValidationError("invalid_check", "check names must not be empty")
+		}
+	}
+	return nil
+}
+
+// HealthCheckReport is the result of health_check
+type HealthCheckReport struct {
+	*health.HealthInfo
+	// History holds the recent results of each check, oldest first
+	History map[string][]health.HistoryEntry `json:"history,omitempty"`
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckArgs_Validate(t *testing.T) {
+	tooMany := make([]string, maxHealthCheckNames+1)
+	for i := range tooMany {
+		tooMany[i] = "check" + strings.Repeat("x", i)
+	}
+
+	tests := []struct {
+		name      string
+		args      HealthCheckArgs
+		wantError bool
+	}{
+		{name: "all", args: HealthCheckArgs{}},
+		{name: "named with history", args: HealthCheckArgs{Checks: []string{"ping", "api"}, IncludeHistory: true}},
+		{name: "empty name", args: HealthCheckArgs{Checks: []string{"ping", " "}}, wantError: true},
+		{name: "too many", args: HealthCheckArgs{Checks: tooMany}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package tools
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"mini-mcp/internal/domain/infra"
+	"mini-mcp/internal/proxmox/types"
 	"mini-mcp/internal/registry"
 	"mini-mcp/internal/types/tools"
 
@@ -10,7 +14,7 @@ import (
 )
 
 // RegisterInfrastructureTools registers infrastructure-related tools
-func RegisterInfrastructureTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, executor *registry.CommandExecutor) {
+func RegisterInfrastructureTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, executor *registry.CommandExecutor, infraService infra.Service) {
 	// ssh - Execute remote commands over SSH
 	sshBuilder := registry.NewToolBuilder[tools.SSHCommandArgs](toolRegistry, "ssh", "Execute a remote command over SSH with security validation")
 
@@ -18,7 +22,7 @@ func RegisterInfrastructureTools(server *mcp.Server, toolRegistry *registry.Type
 		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args tools.SSHCommandArgs) (*mcp.CallToolResult, any, error) {
 			output, err := executor.ExecuteSSHCommand(ctx, args.Host, args.Command, args.User, args.Port, args.KeyPath, args.Timeout)
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"host":    args.Host,
 					"command": args.Command,
 					"user":    args.User,
@@ -45,7 +49,7 @@ func RegisterInfrastructureTools(server *mcp.Server, toolRegistry *registry.Type
 		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args DockerComposeArgs) (*mcp.CallToolResult, any, error) {
 			output, err := executor.ExecuteDockerCompose(ctx, args.Path, args.Command, args.Detached, args.RemoveVolumes)
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"path":    args.Path,
 					"command": args.Command,
 				})
@@ -77,7 +81,7 @@ func RegisterInfrastructureTools(server *mcp.Server, toolRegistry *registry.Type
 		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args DockerSwarmArgs) (*mcp.CallToolResult, any, error) {
 			output, err := executor.ExecuteSystemCommand(ctx, "docker", "swarm", "info")
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{})
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{})
 				return errorResult, nil, nil
 			}
 
@@ -92,6 +96,89 @@ func RegisterInfrastructureTools(server *mcp.Server, toolRegistry *registry.Type
 		// Log error but continue - tool registration failure should not crash the server
 		return
 	}
+
+	// infrastructure_info - Everything about this host and the platforms it runs, in one call
+	infoBuilder := registry.NewToolBuilder[tools.InfrastructureInfoArgs](toolRegistry, "infrastructure_info", "Collect system, process, network, service, Docker, Kubernetes, cloud, Proxmox, Ceph and Nomad information in one call. Enabled sections run concurrently, each under its own timeout; a section that fails or times out is reported in collectors without failing the call. collection_level (basic, standard, detailed, comprehensive) sets the depth; time_interval is the process CPU sampling window in seconds at detailed and above. Docker, Kubernetes, Proxmox, Ceph and Nomad run docker, kubectl, pvesh, ceph and nomad, which the allowed commands and system commands (SECURITY_ALLOWED_COMMANDS, SECURITY_SYSTEM_COMMANDS) permit by default. Proxmox is read through the local pvesh unless proxmox_auth names an API host; set verify_ssl to check its certificate.")
+
+	infoBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args tools.InfrastructureInfoArgs) (*mcp.CallToolResult, any, error) {
+			// The validator ran on a copy; validate again so the level, section and interval defaults apply here
+			if err := args.Validate(); err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"collection_level": args.CollectionLevel,
+				})
+				return errorResult, nil, nil
+			}
+
+			info, err := infraService.Collect(ctx, infrastructureOptions(args))
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"collection_level": args.CollectionLevel,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(info)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args tools.InfrastructureInfoArgs) error {
+			return args.Validate()
+		})
+
+	if err := infoBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// infrastructureOptions converts validated infrastructure_info arguments to collector options
+func infrastructureOptions(args tools.InfrastructureInfoArgs) infra.Options {
+	level, _ := infra.ParseLevel(args.CollectionLevel)
+	opts := infra.Options{
+		Level:          level,
+		SampleInterval: time.Duration(args.TimeInterval) * time.Second,
+	}
+
+	for _, section := range []struct {
+		name    string
+		include bool
+	}{
+		{infra.SectionSystem, args.IncludeSystem},
+		{infra.SectionProcesses, args.IncludeProcesses},
+		{infra.SectionNetwork, args.IncludeNetwork},
+		{infra.SectionServices, args.IncludeServices},
+		{infra.SectionDocker, args.IncludeDocker},
+		{infra.SectionKubernetes, args.IncludeKubernetes},
+		{infra.SectionCloud, args.IncludeCloud},
+		{infra.SectionProxmox, args.IncludeProxmox},
+		{infra.SectionCeph, args.IncludeCeph},
+		{infra.SectionNomad, args.IncludeNomad},
+	} {
+		if section.include {
+			opts.Sections = append(opts.Sections, section.name)
+		}
+	}
+
+	if args.KubernetesAuth != nil {
+		opts.Kubeconfig = args.KubernetesAuth.KubeconfigPath
+		opts.Namespace = args.KubernetesAuth.Namespace
+	}
+	if args.ProxmoxAuth != nil && args.ProxmoxAuth.Host != "" {
+		// Users without a realm are taken to be PAM users
+		user := args.ProxmoxAuth.User
+		if !strings.Contains(user, "@") {
+			user += "@pam"
+		}
+		opts.Proxmox = &types.AuthConfig{}
+		opts.Proxmox.Proxmox.Host = args.ProxmoxAuth.Host
+		opts.Proxmox.Proxmox.User = user
+		opts.Proxmox.Proxmox.Password = args.ProxmoxAuth.Password
+		opts.Proxmox.Proxmox.VerifySSL = args.ProxmoxAuth.VerifySSL
+	}
+	if args.CloudAuth != nil {
+		opts.CloudProvider = args.CloudAuth.Provider
+	}
+	return opts
 }
 
 // DockerComposeArgs represents arguments for Docker Compose operations
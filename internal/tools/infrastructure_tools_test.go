@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"mini-mcp/internal/domain/infra"
+	"mini-mcp/internal/types/tools"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfrastructureInfoArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      tools.InfrastructureInfoArgs
+		wantError bool
+	}{
+		{name: "defaults", args: tools.InfrastructureInfoArgs{}},
+		{name: "comprehensive", args: tools.InfrastructureInfoArgs{CollectionLevel: "comprehensive", IncludeAll: true}},
+		{name: "unknown level", args: tools.InfrastructureInfoArgs{CollectionLevel: "verbose"}, wantError: true},
+		{name: "interval too long", args: tools.InfrastructureInfoArgs{TimeInterval: 600}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInfrastructureOptions(t *testing.T) {
+	args := tools.InfrastructureInfoArgs{
+		CollectionLevel: "detailed",
+		IncludeDocker:   true,
+		IncludeCeph:     true,
+		TimeInterval:    2,
+		KubernetesAuth:  &tools.KubernetesInfoArgs{Namespace: "shop"},
+	}
+	require.NoError(t, args.Validate())
+
+	opts := infrastructureOptions(args)
+	assert.Equal(t, infra.LevelDetailed, opts.Level)
+	assert.Equal(t, []string{infra.SectionDocker, infra.SectionCeph}, opts.Sections)
+	assert.Equal(t, 2*time.Second, opts.SampleInterval)
+	assert.Equal(t, "shop", opts.Namespace)
+	assert.Nil(t, opts.Proxmox)
+
+	// With no section flags, only the system section is collected
+	args = tools.InfrastructureInfoArgs{}
+	require.NoError(t, args.Validate())
+	opts = infrastructureOptions(args)
+	assert.Equal(t, infra.LevelStandard, opts.Level)
+	assert.Equal(t, []string{infra.SectionSystem}, opts.Sections)
+
+	// Proxmox API credentials default to the PAM realm
+	args = tools.InfrastructureInfoArgs{
+		IncludeProxmox: true,
+		ProxmoxAuth:    &tools.ProxmoxArgs{Host: "pve.example.com", User: "root", Password: "secret", VerifySSL: true},
+	}
+	require.NoError(t, args.Validate())
+	opts = infrastructureOptions(args)
+	require.NotNil(t, opts.Proxmox)
+	assert.Equal(t, "pve.example.com", opts.Proxmox.Proxmox.Host)
+	assert.Equal(t, "root@pam", opts.Proxmox.Proxmox.User)
+	assert.True(t, opts.Proxmox.Proxmox.VerifySSL)
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/domain/journal"
+	"mini-mcp/internal/domain/logquery"
+	"mini-mcp/internal/registry"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterLogTools registers log search tools
+func RegisterLogTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, logService logquery.Service) {
+	// log_query - What went wrong, where and when?
+	builder := registry.NewToolBuilder[LogQueryArgs](toolRegistry, "log_query", "Search the systemd journal or a log file under /var/log (syslog, nginx access/error and JSON-lines formats are detected automatically; .gz files are supported). Filter by since/until (RFC 3339 or an age such as 15m, 2h, 7d; default the last hour), priority, unit, pid, boot_id (journal only) and a message regex. Entries are returned oldest first; pass next_cursor back as cursor for the next page. group_by (unit, level, source, host) and/or interval (e.g. 5m) return counts instead of entries, e.g. errors per unit per 5 minutes. The journal is read with journalctl, which the system commands (SECURITY_SYSTEM_COMMANDS) allow by default.")
+
+	builder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args LogQueryArgs) (*mcp.CallToolResult, any, error) {
+			q, err := args.query(time.Now())
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{"source": args.Source})
+				return errorResult, nil, nil
+			}
+
+			result, err := logService.Query(ctx, q)
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"source": args.Source,
+					"unit":   args.Unit,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args LogQueryArgs) error {
+			return args.Validate()
+		})
+
+	if err := builder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// LogQueryArgs represents arguments for log_query
+type LogQueryArgs struct {
+	Source   string   `json:"source,omitempty" jsonschema:"'journal' (default) or a log file path under /var/log, absolute or relative"`
+	Since    string   `json:"since,omitempty" jsonschema:"Start of the range: RFC 3339, '2006-01-02 15:04:05' or an age such as 15m, 2h, 7d (default 1h)"`
+	Until    string   `json:"until,omitempty" jsonschema:"End of the range, in the same formats as since (default now)"`
+	Priority string   `json:"priority,omitempty" jsonschema:"Keep entries at this priority or more severe: emerg, alert, crit, err, warning, notice, info, debug or 0-7"`
+	Unit     string   `json:"unit,omitempty" jsonschema:"Systemd unit or program name"`
+	PID      int      `json:"pid,omitempty" jsonschema:"Process ID"`
+	BootID   string   `json:"boot_id,omitempty" jsonschema:"Journal boot ID, or an offset such as 0 (current) or -1 (previous)"`
+	Pattern  string   `json:"pattern,omitempty" jsonschema:"Regular expression matched against the message"`
+	Limit    int      `json:"limit,omitempty" jsonschema:"Entries per page (default 100, max 1000)"`
+	Cursor   string   `json:"cursor,omitempty" jsonschema:"next_cursor of a previous call with the same source"`
+	GroupBy  []string `json:"group_by,omitempty" jsonschema:"Count matches per value of these fields: unit, level, source, host"`
+	Interval string   `json:"interval,omitempty" jsonschema:"Count matches per time bucket of this size, e.g. 5m or 1h"`
+}
+
+// bootIDPattern matches a journal boot ID or boot offset
+var bootIDPattern = regexp.MustCompile(`^([0-9a-fA-F]{32}|[+-]?\d+)$`)
+
+// Validate validates LogQueryArgs
+func (args LogQueryArgs) Validate() error {
+	_, err := args.query(time.Now())
+	return err
+}
+
+// query converts the arguments to a log query relative to now
+func (args LogQueryArgs) query(now time.Time) (logquery.Query, error) {
+	q := logquery.Query{
+		Source:   strings.TrimSpace(args.Source),
+		Unit:     strings.TrimSpace(args.Unit),
+		PID:      args.PID,
+		BootID:   strings.TrimSpace(args.BootID),
+		Limit:    args.Limit,
+		Cursor:   args.Cursor,
+		GroupBy:  args.GroupBy,
+		Priority: -1,
+	}
+
+	var err error
+	if q.Since, err = logquery.ParseTime(args.Since, now); err != nil {
+		return q, registry.NewValidationError("invalid_since", err.Error())
+	}
+	if q.Until, err = logquery.ParseTime(args.Until, now); err != nil {
+		return q, registry.NewValidationError("invalid_until", err.Error())
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && q.Until.Before(q.Since) {
+		return q, registry.NewValidationError("invalid_range", "until must not be before since")
+	}
+	if args.Priority != "" {
+		if q.Priority, err = journal.ParsePriority(args.Priority); err != nil {
+			return q, registry.NewValidationError("invalid_priority", err.Error())
+		}
+	}
+	if strings.HasPrefix(q.Unit, "-") {
+		return q, registry.NewValidationError("invalid_unit", "unit must not start with '-'")
+	}
+	if args.PID < 0 {
+		return q, registry.NewValidationError("invalid_pid", "pid must be positive")
+	}
+	if q.BootID != "" && !bootIDPattern.MatchString(q.BootID) {
+		return q, registry.NewValidationError("invalid_boot_id", "boot_id must be a 32 character hex ID or an offset such as -1")
+	}
+	if args.Pattern != "" {
+		if q.Pattern, err = regexp.Compile(args.Pattern); err != nil {
+			return q, registry.NewValidationError("invalid_pattern", err.Error())
+		}
+	}
+	if args.Limit < 0 || args.Limit > logquery.MaxLimit {
+		return q, registry.NewValidationError("invalid_limit", "limit must be between 1 and 1000")
+	}
+	for _, field := range args.GroupBy {
+		if !slices.Contains(logquery.GroupFields, field) {
+			return q, registry.NewValidationError("invalid_group_by", "group_by fields must be among: "+strings.Join(logquery.GroupFields, ", "))
+		}
+	}
+	if args.Interval != "" {
+		if q.Interval, err = time.ParseDuration(args.Interval); err != nil || q.Interval < time.Second {
+			return q, registry.NewValidationError("invalid_interval", "interval must be a duration of at least 1s, e.g. 5m")
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogQueryArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      LogQueryArgs
+		wantError bool
+	}{
+		{name: "defaults", args: LogQueryArgs{}},
+		{name: "file with grouping", args: LogQueryArgs{Source: "nginx/error.log", Since: "2h", GroupBy: []string{"unit", "level"}, Interval: "5m"}},
+		{name: "journal filters", args: LogQueryArgs{Priority: "err", Unit: "nginx", PID: 10, BootID: "-1", Pattern: "timed? out"}},
+		{name: "bad since", args: LogQueryArgs{Since: "yesterday"}, wantError: true},
+		{name: "until before since", args: LogQueryArgs{Since: "1h", Until: "2h"}, wantError: true},
+		{name: "bad priority", args: LogQueryArgs{Priority: "loud"}, wantError: true},
+		{name: "option as unit", args: LogQueryArgs{Unit: "--all"}, wantError: true},
+		{name: "bad boot id", args: LogQueryArgs{BootID: "--merge"}, wantError: true},
+		{name: "bad pattern", args: LogQueryArgs{Pattern: "("}, wantError: true},
+		{name: "limit too large", args: LogQueryArgs{Limit: 5000}, wantError: true},
+		{name: "bad group field", args: LogQueryArgs{GroupBy: []string{"message"}}, wantError: true},
+		{name: "tiny interval", args: LogQueryArgs{Interval: "10ms"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogQueryArgs_Query(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	q, err := LogQueryArgs{Since: "30m", Priority: "warning", Interval: "5m"}.query(now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-30*time.Minute), q.Since)
+	assert.Equal(t, 4, q.Priority)
+	assert.Equal(t, 5*time.Minute, q.Interval)
+
+	q, err = LogQueryArgs{}.query(now)
+	require.NoError(t, err)
+	assert.Equal(t, -1, q.Priority)
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/domain/logquery"
+	"mini-mcp/internal/domain/metrics"
+	"mini-mcp/internal/registry"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterMetricsHistoryTools registers tools over the metrics recorded by the background sampler
+func RegisterMetricsHistoryTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, store *metrics.Store) {
+	// metrics_query - When did memory start climbing?
+	builder := registry.NewToolBuilder[MetricsQueryArgs](toolRegistry, "metrics_query", "Query the host metrics history recorded in the background: cpu_percent, load1/load5/load15, memory_used_percent, memory_used_bytes, swap_used_percent, disk_used_percent and disk_used_bytes per mount, network_rx/tx_bytes_per_sec per interface, and process_cpu_percent and process_memory_bytes for the busiest processes (labels pid and name). metric takes a name or glob such as disk_*; labels filter series by label value (globs allowed). Each series returns min, max, avg and p95 over the window plus a history downsampled to max_points (default 120) or step, combined with aggregation (avg, min, max, last). Call without metric to list the recorded series.")
+
+	builder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args MetricsQueryArgs) (*mcp.CallToolResult, any, error) {
+			if strings.TrimSpace(args.Metric) == "" {
+				successResult, _, _ := toolRegistry.CreateSuccessResult(store.List())
+				return successResult, nil, nil
+			}
+
+			now := time.Now()
+			q, err := args.query(now)
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{"metric": args.Metric})
+				return errorResult, nil, nil
+			}
+
+			result, err := store.Query(q, now)
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{"metric": args.Metric})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args MetricsQueryArgs) error {
+			return args.Validate()
+		})
+
+	if err := builder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// MetricsQueryArgs represents arguments for metrics_query
+type MetricsQueryArgs struct {
+	Metric      string            `json:"metric,omitempty" jsonschema:"Metric name or glob, e.g. memory_used_percent or disk_*; omit to list the recorded series"`
+	Labels      map[string]string `json:"labels,omitempty" jsonschema:"Keep series whose labels match, e.g. {\"mount\": \"/var\"} or {\"name\": \"postgres*\"}"`
+	Since       string            `json:"since,omitempty" jsonschema:"Start of the window: RFC 3339, '2006-01-02 15:04:05' or an age such as 15m, 6h, 1d (default 1h)"`
+	Until       string            `json:"until,omitempty" jsonschema:"End of the window, in the same formats as since (default now)"`
+	Step        string            `json:"step,omitempty" jsonschema:"Width of each returned point, e.g. 1m or 1h; overrides max_points but may give at most 2000 points"`
+	MaxPoints   int               `json:"max_points,omitempty" jsonschema:"Maximum points per series when step is not set (default 120, max 2000)"`
+	Aggregation string            `json:"aggregation,omitempty" jsonschema:"How samples within a step are combined: avg (default), min, max or last"`
+}
+
+// Validate validates MetricsQueryArgs
+func (args MetricsQueryArgs) Validate() error {
+	_, err := args.query(time.Now())
+	return err
+}
+
+// query converts the arguments to a metrics query relative to now
+func (args MetricsQueryArgs) query(now time.Time) (metrics.Query, error) {
+	q := metrics.Query{
+		Metric:      strings.TrimSpace(args.Metric),
+		Labels:      args.Labels,
+		MaxPoints:   args.MaxPoints,
+		Aggregation: strings.ToLower(strings.TrimSpace(args.Aggregation)),
+	}
+
+	if _, err := path.Match(q.Metric, ""); err != nil {
+		return q, registry.NewValidationError("invalid_metric", "metric must be a name or a valid glob")
+	}
+	for name, value := range q.Labels {
+		if _, err := path.Match(value, ""); err != nil {
+			return q, registry.NewValidationError("invalid_labels", "label "+name+" must be a value or a valid glob")
+		}
+	}
+
+	var err error
+	if q.Since, err = logquery.ParseTime(args.Since, now); err != nil {
+		return q, registry.NewValidationError("invalid_since", err.Error())
+	}
+	if q.Until, err = logquery.ParseTime(args.Until, now); err != nil {
+		return q, registry.NewValidationError("invalid_until", err.Error())
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Until.After(q.Since) {
+		return q, registry.NewValidationError("invalid_range", "until must be after since")
+	}
+	if args.Step != "" {
+		if q.Step, err = time.ParseDuration(args.Step); err != nil || q.Step < time.Second {
+			return q, registry.NewValidationError("invalid_step", "step must be a duration of at least 1s, e.g. 5m")
+		}
+	}
+	if args.MaxPoints < 0 || args.MaxPoints > metrics.MaxPoints {
+		return q, registry.NewValidationError("invalid_max_points", "max_points must be between 1 and 2000")
+	}
+	if q.Aggregation != "" && !slices.Contains(metrics.Aggregations, q.Aggregation) {
+		return q, registry.NewValidationError("invalid_aggregation", "aggregation must be one of: "+strings.Join(metrics.Aggregations, ", "))
+	}
+	return q, nil
+}
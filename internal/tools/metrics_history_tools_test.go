@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsQueryArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      MetricsQueryArgs
+		wantError bool
+	}{
+		{name: "list series", args: MetricsQueryArgs{}},
+		{name: "glob with labels", args: MetricsQueryArgs{Metric: "disk_*", Labels: map[string]string{"mount": "/var*"}, Since: "6h", Step: "10m", Aggregation: "max"}},
+		{name: "max points", args: MetricsQueryArgs{Metric: "cpu_percent", Since: "1d", MaxPoints: 500}},
+		{name: "bad metric glob", args: MetricsQueryArgs{Metric: "disk_["}, wantError: true},
+		{name: "bad label glob", args: MetricsQueryArgs{Metric: "disk_used_percent", Labels: map[string]string{"mount": "["}}, wantError: true},
+		{name: "bad since", args: MetricsQueryArgs{Metric: "cpu_percent", Since: "yesterday"}, wantError: true},
+		{name: "until before since", args: MetricsQueryArgs{Metric: "cpu_percent", Since: "1h", Until: "2h"}, wantError: true},
+		{name: "tiny step", args: MetricsQueryArgs{Metric: "cpu_percent", Step: "10ms"}, wantError: true},
+		{name: "too many points", args: MetricsQueryArgs{Metric: "cpu_percent", MaxPoints: 5000}, wantError: true},
+		{name: "bad aggregation", args: MetricsQueryArgs{Metric: "cpu_percent", Aggregation: "median"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMetricsQueryArgs_Query(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	q, err := MetricsQueryArgs{Metric: " memory_used_percent ", Since: "2h", Step: "5m", Aggregation: "MAX"}.query(now)
+	require.NoError(t, err)
+	assert.Equal(t, "memory_used_percent", q.Metric)
+	assert.Equal(t, now.Add(-2*time.Hour), q.Since)
+	assert.True(t, q.Until.IsZero())
+	assert.Equal(t, 5*time.Minute, q.Step)
+	assert.Equal(t, "max", q.Aggregation)
+}
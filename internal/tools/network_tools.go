@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+
+	"mini-mcp/internal/domain/network"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/types/tools"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterNetworkTools registers network inspection tools
+func RegisterNetworkTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, networkService network.Service) {
+	// network_info - Interfaces, routes, DNS, sockets and firewall rules
+	networkBuilder := registry.NewToolBuilder[tools.NetworkInfoArgs](toolRegistry, "network_info", "Inspect host networking: interfaces with addresses, MTU, flags and traffic counters; IPv4 and IPv6 routes; DNS servers and search domains from resolv.conf; a count of sockets per protocol and state plus the listening sockets; and firewall rules from nft or iptables-save (system commands allowed by default; see SECURITY_SYSTEM_COMMANDS). Select sections with the include flags (all when none is set) and restrict interfaces, routes, sockets and firewall rules to one interface with filter_interface. Sections that cannot be read are listed under warnings.")
+
+	networkBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args tools.NetworkInfoArgs) (*mcp.CallToolResult, any, error) {
+			info, err := networkService.Info(ctx, networkOptions(args))
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"filter_interface": args.FilterInterface,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(info)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args tools.NetworkInfoArgs) error {
+			return args.Validate()
+		})
+
+	if err := networkBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// networkOptions maps the include flags to report sections; no flags means every section
+func networkOptions(args tools.NetworkInfoArgs) network.Options {
+	if !args.IncludeInterfaces && !args.IncludeConnections && !args.IncludeRouting && !args.IncludeDNS && !args.IncludeFirewall {
+		return network.Options{Interfaces: true, Connections: true, Routing: true, DNS: true, Firewall: true, Interface: args.FilterInterface}
+	}
+	return network.Options{
+		Interfaces:  args.IncludeInterfaces,
+		Connections: args.IncludeConnections,
+		Routing:     args.IncludeRouting,
+		DNS:         args.IncludeDNS,
+		Firewall:    args.IncludeFirewall,
+		Interface:   args.FilterInterface,
+	}
+}
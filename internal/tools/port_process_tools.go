@@ -3,24 +3,28 @@ package tools
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
+	"mini-mcp/internal/domain/process"
+	"mini-mcp/internal/domain/procfs"
 	"mini-mcp/internal/registry"
+	"mini-mcp/internal/types/resources"
 
 	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // RegisterPortProcessTools registers port/process-related tools
-func RegisterPortProcessTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, executor *registry.CommandExecutor) {
+func RegisterPortProcessTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, collector *procfs.Collector, processService process.Service) {
 	// port_process_tools - Investigate and manage ports and processes
-	portProcessBuilder := registry.NewToolBuilder[PortProcessArgs](toolRegistry, "port_process_tools", "Investigate and manage network ports and processes. List ports, find processes using ports, kill processes, clean up occupied ports, and get detailed port/process information. Essential for debugging and system maintenance.")
+	portProcessBuilder := registry.NewToolBuilder[PortProcessArgs](toolRegistry, "port_process_tools", "Investigate and manage network ports and processes. List ports, find processes using ports, kill processes, clean up occupied ports, and get detailed port/process information. clean_ports needs a port, port_range or process_name filter, never touches PID 1, mini-mcp itself or protected services, and only returns a plan unless execute is true.")
 
 	portProcessBuilder.
 		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args PortProcessArgs) (*mcp.CallToolResult, any, error) {
-			output, err := executePortProcessCommand(executor, args.Command, args.Port, args.ProcessID, args.ProcessName, args.User, args.State)
+			output, err := executePortProcessCommand(ctx, collector, processService, args)
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"command": args.Command,
 					"port":    args.Port,
 					"pid":     args.ProcessID,
@@ -28,14 +32,15 @@ func RegisterPortProcessTools(server *mcp.Server, toolRegistry *registry.TypeSaf
 				return errorResult, nil, nil
 			}
 
-			successResult, _, _ := toolRegistry.CreateTextResult(output)
+			if text, ok := output.(string); ok {
+				successResult, _, _ := toolRegistry.CreateTextResult(text)
+				return successResult, nil, nil
+			}
+			successResult, _, _ := toolRegistry.CreateSuccessResult(output)
 			return successResult, nil, nil
 		}).
 		WithValidator(func(args PortProcessArgs) error {
-			if args.Command == "" {
-				return registry.NewValidationError("missing_command", "command is required")
-			}
-			return nil
+			return args.Validate()
 		})
 
 	if err := portProcessBuilder.Register(); err != nil {
@@ -49,221 +54,189 @@ type PortProcessArgs struct {
 	Command     string `json:"command" jsonschema:"Operation (list_ports, list_processes, kill_process, find_port, clean_ports, port_info, process_info, network_stats)"`
 	Port        int    `json:"port,omitempty" jsonschema:"Port number to investigate"`
 	ProcessID   int    `json:"process_id,omitempty" jsonschema:"Process ID to investigate"`
-	ProcessName string `json:"process_name,omitempty" jsonschema:"Process name to search for"`
+	ProcessName string `json:"process_name,omitempty" jsonschema:"Process name to search for (case-insensitive substring; comma-separated glob patterns for clean_ports)"`
 	User        string `json:"user,omitempty" jsonschema:"User to filter by"`
 	State       string `json:"state,omitempty" jsonschema:"Port state to filter by (LISTEN, ESTABLISHED, etc.)"`
+	PortRange   string `json:"port_range,omitempty" jsonschema:"Ports for clean_ports, e.g. 8000-8100,9090"`
+	Execute     bool   `json:"execute,omitempty" jsonschema:"clean_ports only lists its plan unless this is true"`
+	GracePeriod int    `json:"grace_period,omitempty" jsonschema:"Seconds clean_ports waits after SIGTERM before sending SIGKILL (default 5)"`
+}
+
+// Validate validates PortProcessArgs
+func (args PortProcessArgs) Validate() error {
+	if args.Command == "" {
+		return registry.NewValidationError("missing_command", "command is required")
+	}
+	if args.GracePeriod < 0 {
+		return registry.NewValidationError("invalid_grace_period", "grace_period must not be negative")
+	}
+	if args.PortRange != "" {
+		if _, err := process.ParsePortRanges(args.PortRange); err != nil {
+			return registry.NewValidationError("invalid_port_range", err.Error())
+		}
+	}
+	if args.Command == "clean_ports" && args.Port <= 0 && args.PortRange == "" && args.ProcessName == "" {
+		return registry.NewValidationError("missing_filter", "clean_ports requires port, port_range or process_name")
+	}
+	return nil
+}
+
+// PortDetails describes the sockets bound to a port and the processes holding them
+type PortDetails struct {
+	// Port is the port that was looked up
+	Port int `json:"port"`
+	// Connections are sockets using the port locally or remotely
+	Connections []resources.NetworkConnection `json:"connections"`
+	// Processes are the owners of those sockets, where visible
+	Processes []resources.Process `json:"processes,omitempty"`
 }
 
 // executePortProcessCommand executes port/process related commands
-func executePortProcessCommand(executor *registry.CommandExecutor, command string, port int, processID int, processName string, user string, state string) (string, error) {
-	switch command {
+func executePortProcessCommand(ctx context.Context, collector *procfs.Collector, processService process.Service, args PortProcessArgs) (any, error) {
+	switch args.Command {
 	case "list_ports":
-		return listPorts(executor, state)
+		return listPorts(collector, args.State)
 	case "list_processes":
-		return listProcesses(executor, user)
+		return listProcesses(collector, args.User, args.ProcessName)
 	case "kill_process":
-		return killProcess(executor, processID)
+		return killProcess(ctx, processService, args.ProcessID)
 	case "find_port":
-		return findPort(executor, port)
+		return findPort(collector, args.Port)
 	case "clean_ports":
-		return cleanPorts(executor)
+		return cleanPorts(ctx, processService, args)
 	case "port_info":
-		return getPortInfo(executor, port)
+		return getPortInfo(collector, args.Port)
 	case "process_info":
-		return getProcessInfo(executor, processID)
+		return getProcessInfo(collector, args.ProcessID)
 	case "network_stats":
-		return getNetworkStats(executor)
+		return collector.Interfaces()
 	default:
-		return "", fmt.Errorf("unsupported command: %s", command)
+		return nil, fmt.Errorf("unsupported command: %s", args.Command)
 	}
 }
 
-// listPorts lists network ports
-func listPorts(executor *registry.CommandExecutor, state string) (string, error) {
-	output, err := executor.ExecuteSystemCommand(context.Background(), "netstat", "-tuln")
+// inetProtocols are the socket tables that carry ports
+var inetProtocols = []string{procfs.ProtocolTCP, procfs.ProtocolTCP6, procfs.ProtocolUDP, procfs.ProtocolUDP6}
+
+// listPorts lists listening sockets, or all sockets in the given state
+func listPorts(collector *procfs.Collector, state string) ([]resources.NetworkConnection, error) {
+	conns, err := collector.Connections(inetProtocols...)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to read sockets: %w", err)
 	}
 
-	lines := strings.Split(output, "\n")
-	var result strings.Builder
-
-	for _, line := range lines {
-		if state == "" || strings.Contains(line, state) {
-			result.WriteString(line + "\n")
+	result := make([]resources.NetworkConnection, 0)
+	for _, conn := range conns {
+		if (state == "" && procfs.IsListening(conn)) || (state != "" && strings.EqualFold(conn.State, state)) {
+			result = append(result, conn)
 		}
 	}
-
-	return result.String(), nil
+	return result, nil
 }
 
-// listProcesses lists running processes
-func listProcesses(executor *registry.CommandExecutor, user string) (string, error) {
-	output, err := executor.ExecuteSystemCommand(context.Background(), "ps", "aux")
+// listProcesses lists running processes, optionally filtered by user and name
+func listProcesses(collector *procfs.Collector, user, name string) (*resources.ProcessInfo, error) {
+	procs, err := collector.Processes()
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to read processes: %w", err)
 	}
 
-	lines := strings.Split(output, "\n")
-	var result strings.Builder
-
-	for _, line := range lines {
-		if user == "" || strings.Contains(line, user) {
-			result.WriteString(line + "\n")
+	info := &resources.ProcessInfo{TotalProcesses: len(procs), Processes: make([]resources.Process, 0)}
+	for _, proc := range procs {
+		if user != "" && proc.User != user {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(proc.Name), strings.ToLower(name)) {
+			continue
 		}
+		info.Processes = append(info.Processes, proc)
 	}
-
-	return result.String(), nil
+	return info, nil
 }
 
-// killProcess kills a process by ID
-func killProcess(executor *registry.CommandExecutor, pid int) (string, error) {
+// killProcess terminates a process with SIGTERM, escalating to SIGKILL, and verifies it exited
+func killProcess(ctx context.Context, processService process.Service, pid int) (*resources.SignalResult, error) {
 	if pid <= 0 {
-		return "", fmt.Errorf("invalid process ID: %d", pid)
-	}
-
-	success := executor.KillProcessGracefully(context.Background(), pid)
-	if !success {
-		return "", fmt.Errorf("failed to kill process %d", pid)
+		return nil, fmt.Errorf("invalid process ID: %d", pid)
 	}
-
-	return fmt.Sprintf("Process %d killed successfully", pid), nil
+	return processService.SignalProcess(ctx, process.SignalOptions{PID: pid, Signal: syscall.SIGTERM})
 }
 
-// findPort finds processes using a specific port
-func findPort(executor *registry.CommandExecutor, port int) (string, error) {
+// findPort finds sockets using a specific port, locally or remotely
+func findPort(collector *procfs.Collector, port int) ([]resources.NetworkConnection, error) {
 	if port <= 0 {
-		return "", fmt.Errorf("invalid port: %d", port)
+		return nil, fmt.Errorf("invalid port: %d", port)
 	}
 
-	output, err := executor.ExecuteSystemCommand(context.Background(), "lsof", "-i", ":"+strconv.Itoa(port))
+	conns, err := collector.Connections(inetProtocols...)
 	if err != nil {
-		return "", fmt.Errorf("failed to find processes on port %d: %w", port, err)
+		return nil, fmt.Errorf("failed to find sockets on port %d: %w", port, err)
 	}
 
-	return output, nil
-}
-
-// cleanPorts attempts to clean up occupied ports
-func cleanPorts(executor *registry.CommandExecutor) (string, error) {
-	result := "Port cleanup completed:\n"
-
-	// Get list of used ports
-	ports, err := getUsedPorts(executor)
-	if err != nil {
-		return "", fmt.Errorf("failed to get used ports: %w", err)
-	}
-
-	if len(ports) == 0 {
-		return "No ports to clean up", nil
-	}
-
-	// Attempt to clean up each port
-	cleaned := 0
-	for _, port := range ports {
-		if cleanupPort(executor, port) {
-			result += fmt.Sprintf("✓ Cleaned up port %d\n", port)
-			cleaned++
-		} else {
-			result += fmt.Sprintf("✗ Failed to clean up port %d\n", port)
+	result := make([]resources.NetworkConnection, 0)
+	for _, conn := range conns {
+		if conn.LocalPort == port || conn.RemotePort == port {
+			result = append(result, conn)
 		}
 	}
-
-	result += fmt.Sprintf("\nSummary: %d/%d ports cleaned up", cleaned, len(ports))
 	return result, nil
 }
 
-// getUsedPorts gets a list of currently used ports
-func getUsedPorts(executor *registry.CommandExecutor) ([]int, error) {
-	// Try ss first, fallback to netstat
-	output, err := executor.ExecuteSystemCommand(context.Background(), "ss", "-tuln")
+// cleanPorts terminates processes on the selected ports; it is a dry run unless execute is set
+func cleanPorts(ctx context.Context, processService process.Service, args PortProcessArgs) (*resources.PortCleanupReport, error) {
+	ranges, err := process.ParsePortRanges(args.PortRange)
 	if err != nil {
-		// Fallback to netstat if ss is not available
-		output, err = executor.ExecuteSystemCommand(context.Background(), "netstat", "-tuln")
-		if err != nil {
-			return nil, fmt.Errorf("failed to get used ports: %w", err)
-		}
+		return nil, err
 	}
-
-	// Parse output to extract port numbers
-	ports := executor.ParsePortNumbers(output)
-	return ports, nil
-}
-
-// cleanupPort attempts to clean up a specific port
-func cleanupPort(executor *registry.CommandExecutor, port int) bool {
-	// Get processes using the port
-	processes, err := getProcessesUsingPort(executor, port)
-	if err != nil || len(processes) == 0 {
-		return false
+	if args.Port > 0 {
+		ranges = append(ranges, process.PortRange{From: args.Port, To: args.Port})
 	}
 
-	// Kill processes (be careful - this is a dangerous operation)
-	for _, pid := range processes {
-		if executor.KillProcessGracefully(context.Background(), pid) {
-			// Process killed - logged via structured logging
-			_ = pid // Process successfully killed
+	var names []string
+	for _, name := range strings.Split(args.ProcessName, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
 		}
 	}
 
-	return true
+	return processService.CleanPorts(ctx, process.CleanupOptions{
+		Ports:        ranges,
+		ProcessNames: names,
+		Execute:      args.Execute,
+		GracePeriod:  time.Duration(args.GracePeriod) * time.Second,
+	})
 }
 
-// getProcessesUsingPort gets PIDs of processes using a specific port
-func getProcessesUsingPort(executor *registry.CommandExecutor, port int) ([]int, error) {
-	// Use lsof to find processes using the port
-	output, err := executor.ExecuteSystemCommand(context.Background(), "lsof", "-ti", ":"+strconv.Itoa(port))
+// getPortInfo gets the sockets on a port together with their owning processes
+func getPortInfo(collector *procfs.Collector, port int) (*PortDetails, error) {
+	conns, err := findPort(collector, port)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find processes using port %d: %w", port, err)
+		return nil, err
 	}
 
-	// Parse PIDs from output
-	pids := make([]int, 0)
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-
-	for _, line := range lines {
-		if pid, err := strconv.Atoi(line); err == nil {
-			pids = append(pids, pid)
+	details := &PortDetails{Port: port, Connections: conns}
+	seen := make(map[int]bool)
+	for _, conn := range conns {
+		if conn.PID <= 0 || seen[conn.PID] {
+			continue
+		}
+		seen[conn.PID] = true
+		if proc, err := collector.Process(conn.PID); err == nil {
+			details.Processes = append(details.Processes, *proc)
 		}
 	}
-
-	return pids, nil
-}
-
-// getPortInfo gets detailed information about a port
-func getPortInfo(executor *registry.CommandExecutor, port int) (string, error) {
-	if port <= 0 {
-		return "", fmt.Errorf("invalid port: %d", port)
-	}
-
-	output, err := executor.ExecuteSystemCommand(context.Background(), "lsof", "-i", ":"+strconv.Itoa(port))
-	if err != nil {
-		return "", fmt.Errorf("failed to get info for port %d: %w", port, err)
-	}
-
-	return output, nil
+	return details, nil
 }
 
 // getProcessInfo gets detailed information about a process
-func getProcessInfo(executor *registry.CommandExecutor, pid int) (string, error) {
+func getProcessInfo(collector *procfs.Collector, pid int) (*resources.Process, error) {
 	if pid <= 0 {
-		return "", fmt.Errorf("invalid process ID: %d", pid)
+		return nil, fmt.Errorf("invalid process ID: %d", pid)
 	}
 
-	output, err := executor.ExecuteSystemCommand(context.Background(), "ps", "-p", strconv.Itoa(pid), "-o", "pid,ppid,cmd,user,time")
+	proc, err := collector.Process(pid)
 	if err != nil {
-		return "", fmt.Errorf("failed to get info for process %d: %w", pid, err)
+		return nil, fmt.Errorf("failed to get info for process %d: %w", pid, err)
 	}
-
-	return output, nil
-}
-
-// getNetworkStats gets network statistics
-func getNetworkStats(executor *registry.CommandExecutor) (string, error) {
-	output, err := executor.ExecuteSystemCommand(context.Background(), "netstat", "-i")
-	if err != nil {
-		return "", err
-	}
-
-	return output, nil
+	return proc, nil
 }
@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortProcessArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      PortProcessArgs
+		wantError bool
+	}{
+		{name: "list ports", args: PortProcessArgs{Command: "list_ports"}},
+		{name: "missing command", args: PortProcessArgs{}, wantError: true},
+		{name: "clean ports without filter", args: PortProcessArgs{Command: "clean_ports", Execute: true}, wantError: true},
+		{name: "clean ports with range", args: PortProcessArgs{Command: "clean_ports", PortRange: "8000-8100,9090"}},
+		{name: "clean ports with single port", args: PortProcessArgs{Command: "clean_ports", Port: 3000}},
+		{name: "clean ports with process name", args: PortProcessArgs{Command: "clean_ports", ProcessName: "node,python*"}},
+		{name: "invalid range", args: PortProcessArgs{Command: "clean_ports", PortRange: "9000-8000"}, wantError: true},
+		{name: "negative grace period", args: PortProcessArgs{Command: "clean_ports", Port: 3000, GracePeriod: -1}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
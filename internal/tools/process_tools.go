@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"mini-mcp/internal/domain/process"
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/types/resources"
+	"mini-mcp/internal/types/tools"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// processSampleInterval is the gap between the two reads process_info compares
+const processSampleInterval = 500 * time.Millisecond
+
+// RegisterProcessTools registers process inspection and control tools
+func RegisterProcessTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, collector *procfs.Collector, processService process.Service) {
+	// process_info - Non-interactive top/iotop
+	processInfoBuilder := registry.NewToolBuilder[tools.ProcessInfoArgs](toolRegistry, "process_info", "List the top processes like a non-interactive top/iotop. Samples /proc twice over half a second to report current CPU usage (per core, as in top) and storage read/write rates, then filters by user or name, sorts by cpu, memory, io or name and returns the first limit entries (default 10). include_cpu, include_memory and include_io select the figures returned; all are included when none is set.")
+
+	processInfoBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args tools.ProcessInfoArgs) (*mcp.CallToolResult, any, error) {
+			// The validator ran on a copy; validate again so the sort and limit defaults apply here
+			if err := args.Validate(); err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{"sort": args.Sort})
+				return errorResult, nil, nil
+			}
+			info, err := sampleTopProcesses(ctx, collector, args)
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"sort":  args.Sort,
+					"limit": args.Limit,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(info)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args tools.ProcessInfoArgs) error {
+			return args.Validate()
+		})
+
+	if err := processInfoBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// signal_process - Send a signal to a process or process tree
+	signalBuilder := registry.NewToolBuilder[SignalProcessArgs](toolRegistry, "signal_process", "Send a signal (TERM, KILL, HUP, INT, QUIT, USR1, USR2, STOP, CONT, TSTP) to a process, optionally including all of its descendants. Terminating signals wait for the grace period, escalate to SIGKILL and verify that each PID exited or was reused. PID 1, mini-mcp itself and protected services are never signalled.")
+
+	signalBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args SignalProcessArgs) (*mcp.CallToolResult, any, error) {
+			sig, _ := process.ParseSignal(args.Signal)
+			result, err := processService.SignalProcess(ctx, process.SignalOptions{
+				PID:         args.PID,
+				Signal:      sig,
+				Tree:        args.Tree,
+				GracePeriod: time.Duration(args.GracePeriod) * time.Second,
+				NoEscalate:  args.NoEscalate,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"pid":    args.PID,
+					"signal": args.Signal,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args SignalProcessArgs) error {
+			return args.Validate()
+		})
+
+	if err := signalBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// SignalProcessArgs represents arguments for signal_process
+type SignalProcessArgs struct {
+	PID         int    `json:"pid" jsonschema:"Process ID to signal"`
+	Signal      string `json:"signal,omitempty" jsonschema:"Signal name or number, e.g. TERM, SIGKILL, HUP or 9 (default TERM)"`
+	Tree        bool   `json:"tree,omitempty" jsonschema:"Also signal every descendant, children before parents"`
+	GracePeriod int    `json:"grace_period,omitempty" jsonschema:"Seconds to wait for terminating signals before escalating to SIGKILL (default 5)"`
+	NoEscalate  bool   `json:"no_escalate,omitempty" jsonschema:"Do not send SIGKILL to processes that outlive the grace period"`
+}
+
+// Validate validates SignalProcessArgs
+func (args SignalProcessArgs) Validate() error {
+	if args.PID <= 0 {
+		return registry.NewValidationError("invalid_pid", "pid must be positive")
+	}
+	if _, err := process.ParseSignal(args.Signal); err != nil {
+		return registry.NewValidationError("invalid_signal", err.Error())
+	}
+	if args.GracePeriod < 0 {
+		return registry.NewValidationError("invalid_grace_period", "grace_period must not be negative")
+	}
+	return nil
+}
+
+// sampleTopProcesses samples, filters, sorts and limits processes for process_info
+func sampleTopProcesses(ctx context.Context, collector *procfs.Collector, args tools.ProcessInfoArgs) (*resources.ProcessInfo, error) {
+	includeAll := !args.IncludeCPU && !args.IncludeMemory && !args.IncludeIO
+	includeIO := includeAll || args.IncludeIO || args.Sort == "io"
+
+	procs, err := collector.SampleProcesses(ctx, processSampleInterval, includeIO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample processes: %w", err)
+	}
+
+	info := &resources.ProcessInfo{TotalProcesses: len(procs), Processes: make([]resources.Process, 0, args.Limit)}
+	filtered := procs[:0]
+	for _, proc := range procs {
+		if args.FilterUser != "" && proc.User != args.FilterUser {
+			continue
+		}
+		if args.FilterName != "" && !strings.Contains(strings.ToLower(proc.Name), strings.ToLower(args.FilterName)) {
+			continue
+		}
+		filtered = append(filtered, proc)
+	}
+	sortProcesses(filtered, args.Sort)
+
+	for _, proc := range filtered[:min(args.Limit, len(filtered))] {
+		if !includeAll {
+			if !args.IncludeCPU {
+				proc.CPUPercent = 0
+			}
+			if !args.IncludeMemory {
+				proc.MemoryUsage, proc.MemoryPercent = 0, 0
+			}
+			if !args.IncludeIO {
+				proc.IOStats = nil
+			}
+		}
+		info.Processes = append(info.Processes, proc)
+	}
+	return info, nil
+}
+
+// sortProcesses orders processes by cpu, memory or io (highest first) or by name
+func sortProcesses(procs []resources.Process, by string) {
+	ioRate := func(p resources.Process) float64 {
+		if p.IOStats == nil {
+			return 0
+		}
+		return p.IOStats.ReadBytesPerSec + p.IOStats.WriteBytesPerSec
+	}
+
+	sort.SliceStable(procs, func(i, j int) bool {
+		a, b := procs[i], procs[j]
+		switch by {
+		case "memory":
+			if a.MemoryUsage != b.MemoryUsage {
+				return a.MemoryUsage > b.MemoryUsage
+			}
+		case "io":
+			if ioRate(a) != ioRate(b) {
+				return ioRate(a) > ioRate(b)
+			}
+		case "name":
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+			return a.PID < b.PID
+		}
+		if a.CPUPercent != b.CPUPercent {
+			return a.CPUPercent > b.CPUPercent
+		}
+		return a.MemoryUsage > b.MemoryUsage
+	})
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"testing"
+
+	"mini-mcp/internal/types/resources"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignalProcessArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      SignalProcessArgs
+		wantError bool
+	}{
+		{name: "default signal", args: SignalProcessArgs{PID: 1234}},
+		{name: "named signal with tree", args: SignalProcessArgs{PID: 1234, Signal: "SIGHUP", Tree: true}},
+		{name: "numeric signal", args: SignalProcessArgs{PID: 1234, Signal: "9"}},
+		{name: "missing pid", args: SignalProcessArgs{Signal: "TERM"}, wantError: true},
+		{name: "unknown signal", args: SignalProcessArgs{PID: 1234, Signal: "SIGFOO"}, wantError: true},
+		{name: "negative grace period", args: SignalProcessArgs{PID: 1234, GracePeriod: -1}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSortProcesses(t *testing.T) {
+	procs := []resources.Process{
+		{PID: 1, Name: "init", CPUPercent: 0.1, MemoryUsage: 300},
+		{PID: 2, Name: "db", CPUPercent: 5, MemoryUsage: 100, IOStats: &resources.ProcessIOStats{WriteBytesPerSec: 10}},
+		{PID: 3, Name: "app", CPUPercent: 50, MemoryUsage: 200, IOStats: &resources.ProcessIOStats{ReadBytesPerSec: 1}},
+	}
+	order := func() []int {
+		pids := make([]int, len(procs))
+		for i, p := range procs {
+			pids[i] = p.PID
+		}
+		return pids
+	}
+
+	sortProcesses(procs, "cpu")
+	assert.Equal(t, []int{3, 2, 1}, order())
+	sortProcesses(procs, "memory")
+	assert.Equal(t, []int{1, 3, 2}, order())
+	sortProcesses(procs, "io")
+	assert.Equal(t, []int{2, 3, 1}, order())
+	sortProcesses(procs, "name")
+	assert.Equal(t, []int{3, 2, 1}, order())
+}
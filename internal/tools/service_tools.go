@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"mini-mcp/internal/domain/systemd"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/types/tools"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterServiceTools registers systemd service tools
+func RegisterServiceTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, systemdService systemd.Service) {
+	// service_info - Why is service X down?
+	infoBuilder := registry.NewToolBuilder[tools.ServiceInfoArgs](toolRegistry, "service_info", "List systemd services and their state. filter_service takes a unit glob or substring. include_status adds enablement, main PID, last result, exit status and restart count; include_logs adds the last log_lines journal entries (default 20) per unit when at most 5 units match. Every section is included when no include flag is set. Runs systemctl and journalctl, which the system commands (SECURITY_SYSTEM_COMMANDS) allow by default.")
+
+	infoBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args tools.ServiceInfoArgs) (*mcp.CallToolResult, any, error) {
+			// The validator ran on a copy; validate again so the log_lines default applies here
+			if err := args.Validate(); err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"filter_service": args.FilterService,
+				})
+				return errorResult, nil, nil
+			}
+			includeAll := !args.IncludeSystemd && !args.IncludeStatus && !args.IncludeLogs
+
+			info, err := systemdService.List(ctx, systemd.ListOptions{
+				Filter:   args.FilterService,
+				Status:   includeAll || args.IncludeStatus,
+				Logs:     includeAll || args.IncludeLogs,
+				LogLines: args.LogLines,
+			})
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"filter_service": args.FilterService,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(info)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args tools.ServiceInfoArgs) error {
+			return args.Validate()
+		})
+
+	if err := infoBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+
+	// service_control - Start, stop, restart, reload, enable or disable an allowlisted unit
+	controlBuilder := registry.NewToolBuilder[ServiceControlArgs](toolRegistry, "service_control", "Start, stop, restart, reload, enable or disable a systemd unit and return its state afterwards, with recent journal entries when the action fails. Only units matching the controllable units allowlist (SECURITY_CONTROLLABLE_UNITS) can be controlled; the allowlist is empty by default.")
+
+	controlBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args ServiceControlArgs) (*mcp.CallToolResult, any, error) {
+			result, err := systemdService.Control(ctx, args.Unit, args.Action)
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"unit":   args.Unit,
+					"action": args.Action,
+				})
+				return errorResult, nil, nil
+			}
+			if !result.Success {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, result.Error, map[string]any{
+					"unit":    result.Unit,
+					"action":  result.Action,
+					"service": result.Service,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(result)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args ServiceControlArgs) error {
+			return args.Validate()
+		})
+
+	if err := controlBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// ServiceControlArgs represents arguments for service_control
+type ServiceControlArgs struct {
+	Unit   string `json:"unit" jsonschema:"Unit name, e.g. nginx or nginx.service"`
+	Action string `json:"action" jsonschema:"Action to perform (start, stop, restart, reload, enable, disable)"`
+}
+
+// Validate validates ServiceControlArgs
+func (args ServiceControlArgs) Validate() error {
+	if _, err := systemd.NormalizeUnit(args.Unit); err != nil {
+		return registry.NewValidationError("invalid_unit", err.Error())
+	}
+	if !systemd.IsValidAction(args.Action) {
+		return registry.NewValidationError("invalid_action", "action must be one of: "+strings.Join(systemd.Actions, ", "))
+	}
+	return nil
+}
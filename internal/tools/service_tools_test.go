@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceControlArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      ServiceControlArgs
+		wantError bool
+	}{
+		{name: "restart", args: ServiceControlArgs{Unit: "nginx", Action: "restart"}},
+		{name: "enable timer", args: ServiceControlArgs{Unit: "backup.timer", Action: "enable"}},
+		{name: "missing unit", args: ServiceControlArgs{Action: "start"}, wantError: true},
+		{name: "option as unit", args: ServiceControlArgs{Unit: "--force", Action: "stop"}, wantError: true},
+		{name: "unsupported action", args: ServiceControlArgs{Unit: "nginx", Action: "mask"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
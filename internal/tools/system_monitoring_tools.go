@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/types/resources"
+	"mini-mcp/internal/types/tools"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// topProcessLimit caps the processes metric
+const topProcessLimit = 20
+
+// RegisterSystemMonitoringTools registers the system_monitoring tool
+func RegisterSystemMonitoringTools(server *mcp.Server, toolRegistry *registry.TypeSafeToolRegistry, collector *procfs.Collector) {
+	// system_monitoring - Read system metrics natively from /proc and statfs
+	monitoringBuilder := registry.NewToolBuilder[tools.SystemMonitoringArgs](toolRegistry, "system_monitoring", "Monitor the host: processes (top 20 by CPU), disk_usage (mounted filesystems), memory_usage (RAM and swap), network (interfaces with addresses and traffic counters) or uptime (uptime, boot time and load averages). Values are read directly from /proc and statfs and returned as structured JSON with sizes in bytes.")
+
+	monitoringBuilder.
+		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args tools.SystemMonitoringArgs) (*mcp.CallToolResult, any, error) {
+			output, err := collectSystemMetric(collector, args.Metric)
+			if err != nil {
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
+					"metric": args.Metric,
+				})
+				return errorResult, nil, nil
+			}
+
+			successResult, _, _ := toolRegistry.CreateSuccessResult(output)
+			return successResult, nil, nil
+		}).
+		WithValidator(func(args tools.SystemMonitoringArgs) error {
+			return args.Validate()
+		})
+
+	if err := monitoringBuilder.Register(); err != nil {
+		// Log error but continue - tool registration failure should not crash the server
+		return
+	}
+}
+
+// collectSystemMetric reads one system metric
+func collectSystemMetric(collector *procfs.Collector, metric tools.SystemMetric) (any, error) {
+	switch metric {
+	case tools.MetricProcesses:
+		return topProcesses(collector, topProcessLimit)
+	case tools.MetricDiskUsage:
+		disks, err := collector.Disks()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mounts: %w", err)
+		}
+		return disks, nil
+	case tools.MetricMemoryUsage:
+		mem, err := collector.Memory()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read memory usage: %w", err)
+		}
+		return mem, nil
+	case tools.MetricNetwork:
+		return networkInterfaces(collector)
+	case tools.MetricUptime:
+		uptime, err := collector.Uptime()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read uptime: %w", err)
+		}
+		return uptime, nil
+	default:
+		return nil, fmt.Errorf("unsupported metric: %s", metric)
+	}
+}
+
+// topProcesses returns the processes using the most CPU, then memory
+func topProcesses(collector *procfs.Collector, limit int) (*resources.ProcessInfo, error) {
+	procs, err := collector.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read processes: %w", err)
+	}
+
+	sortProcesses(procs, "cpu")
+	info := &resources.ProcessInfo{TotalProcesses: len(procs)}
+	info.Processes = procs[:min(limit, len(procs))]
+	return info, nil
+}
+
+// networkInterfaces combines the /proc/net/dev counters with addresses, MTU and flags
+func networkInterfaces(collector *procfs.Collector) ([]resources.NetworkInterface, error) {
+	counters, err := collector.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interface counters: %w", err)
+	}
+
+	for i := range counters {
+		iface, err := net.InterfaceByName(counters[i].Name)
+		if err != nil {
+			continue // the interface went away or lives in another namespace
+		}
+		counters[i].HardwareAddr = iface.HardwareAddr.String()
+		counters[i].MTU = iface.MTU
+		for _, flag := range []net.Flags{net.FlagUp, net.FlagBroadcast, net.FlagLoopback, net.FlagPointToPoint, net.FlagMulticast, net.FlagRunning} {
+			if iface.Flags&flag != 0 {
+				counters[i].Flags = append(counters[i].Flags, flag.String())
+			}
+		}
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				counters[i].Addresses = append(counters[i].Addresses, addr.String())
+			}
+		}
+	}
+	return counters, nil
+}
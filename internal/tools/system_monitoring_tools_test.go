@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mini-mcp/internal/domain/procfs"
+	"mini-mcp/internal/registry"
+	"mini-mcp/internal/shared/logging"
+	"mini-mcp/internal/types/resources"
+
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMonitoringFixture builds a proc tree with memory, uptime and 25
+// processes whose CPU time grows with their PID
+func writeMonitoringFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("stat", "cpu  1 2 3 4\nbtime 1700000000\n")
+	write("uptime", "1000.00 4000.00\n")
+	write("loadavg", "0.50 0.25 0.10 2/120 4321\n")
+	write("meminfo", "MemTotal:       1000000 kB\nMemFree:         500000 kB\nMemAvailable:    750000 kB\n")
+	for pid := 1; pid <= 25; pid++ {
+		write(fmt.Sprintf("%d/stat", pid), fmt.Sprintf("%d (proc%d) S 1 %d %d 0 -1 0 0 0 0 0 %d 0 0 0 20 0 1 0 1000 1000 250 0\n", pid, pid, pid, pid, pid*100))
+		write(fmt.Sprintf("%d/status", pid), fmt.Sprintf("Name:\tproc%d\nUid:\t0\t0\t0\t0\nVmRSS:\t    1024 kB\n", pid))
+		write(fmt.Sprintf("%d/cmdline", pid), fmt.Sprintf("/usr/bin/proc%d\x00", pid))
+	}
+	return root
+}
+
+func TestSystemMonitoringTool(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	toolRegistry := registry.NewTypeSafeToolRegistry(server, logging.NewLogger(io.Discard, logging.LogLevelError))
+	RegisterSystemMonitoringTools(server, toolRegistry, procfs.NewCollector(writeMonitoringFixture(t)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "system_monitoring", Arguments: args})
+		require.NoError(t, err)
+		return result
+	}
+	decode := func(result *mcp.CallToolResult, into any) {
+		t.Helper()
+		require.False(t, result.IsError, "%v", result.Content)
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), into))
+	}
+
+	// Processes are capped at the top 20 by CPU
+	var procs resources.ProcessInfo
+	decode(call(map[string]any{"metric": "processes"}), &procs)
+	assert.Equal(t, 25, procs.TotalProcesses)
+	require.Len(t, procs.Processes, topProcessLimit)
+	assert.Equal(t, 25, procs.Processes[0].PID)
+	assert.Equal(t, "proc25", procs.Processes[0].Name)
+	assert.Equal(t, 6, procs.Processes[topProcessLimit-1].PID)
+
+	var mem resources.MemoryDetails
+	decode(call(map[string]any{"metric": "memory_usage"}), &mem)
+	assert.Equal(t, uint64(1000000*1024), mem.Total)
+	assert.Equal(t, uint64(250000*1024), mem.Used)
+	assert.Equal(t, 25.0, mem.UsedPercent)
+
+	var uptime resources.UptimeInfo
+	decode(call(map[string]any{"metric": "uptime"}), &uptime)
+	assert.Equal(t, 1000.0, uptime.UptimeSeconds)
+	assert.Equal(t, []float64{0.5, 0.25, 0.1}, uptime.LoadAverage)
+	assert.Equal(t, 120, uptime.TotalProcesses)
+
+	// Invalid arguments are rejected before anything is read
+	for _, args := range []map[string]any{{}, {"metric": "temperature"}, {"metric": 3}} {
+		result := call(args)
+		assert.True(t, result.IsError, "%v", args)
+	}
+
+	// A metric whose source is missing reports the failure
+	result := call(map[string]any{"metric": "network"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "interface counters")
+}
@@ -24,7 +24,7 @@ func RegisterSystemTools(server *mcp.Server, toolRegistry *registry.TypeSafeTool
 				"include_health":  args.IncludeHealth,
 			})
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult(err.Error(), map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, err.Error(), map[string]any{
 					"include_metrics": args.IncludeMetrics,
 					"include_health":  args.IncludeHealth,
 				})
@@ -44,13 +44,17 @@ func RegisterSystemTools(server *mcp.Server, toolRegistry *registry.TypeSafeTool
 	}
 
 	// metrics - Get application metrics and performance data
-	metricsBuilder := registry.NewToolBuilder[MetricsArgs](toolRegistry, "metrics", "Get application metrics, performance data, and observability information")
+	metricsBuilder := registry.NewToolBuilder[MetricsArgs](toolRegistry, "metrics", "Get application metrics, performance data, and observability information: request counts, error rates and p50/p90/p95/p99 latency per tool. With tool set, returns that tool's metrics including requests, error rate, throughput and latency percentiles over sliding 1m, 5m and 1h windows.")
 
 	metricsBuilder.
 		WithHandler(func(ctx context.Context, req *mcp.CallToolRequest, args MetricsArgs) (*mcp.CallToolResult, any, error) {
 			// Get metrics from logger
 			metrics := logger.GetMetrics()
 			metricsData := metrics.GetMetricsSummary()
+			if args.Tool != "" {
+				metricsData = metrics.GetToolMetrics(args.Tool)
+				metricsData["tool"] = args.Tool
+			}
 
 			// Add health check status if requested
 			if args.IncludeHealth && healthChecker != nil {
@@ -62,7 +66,7 @@ func RegisterSystemTools(server *mcp.Server, toolRegistry *registry.TypeSafeTool
 			// Convert to JSON for better readability
 			jsonData, err := json.MarshalIndent(metricsData, "", "  ")
 			if err != nil {
-				errorResult, _, _ := toolRegistry.CreateErrorResult("Failed to format metrics", map[string]any{
+				errorResult, _, _ := toolRegistry.CreateErrorResult(ctx, "Failed to format metrics", map[string]any{
 					"error": err.Error(),
 				})
 				return errorResult, nil, nil
@@ -89,5 +93,6 @@ type SystemInfoArgs struct {
 
 // MetricsArgs represents arguments for metrics
 type MetricsArgs struct {
-	IncludeHealth bool `json:"include_health,omitempty" jsonschema:"Include health check information"`
+	Tool          string `json:"tool,omitempty" jsonschema:"Report a single tool, with its sliding window percentiles"`
+	IncludeHealth bool   `json:"include_health,omitempty" jsonschema:"Include health check information"`
 }
@@ -36,6 +36,14 @@ type DockerInfo struct {
 	LoggingDriver string `json:"logging_driver,omitempty"`
 	// VolumePlugins is a list of volume plugins installed
 	VolumePlugins []string `json:"volume_plugins,omitempty"`
+	// ContainerList contains the containers, with statistics when requested
+	ContainerList []DockerContainer `json:"container_list,omitempty"`
+	// ImageList contains the images
+	ImageList []DockerImage `json:"image_list,omitempty"`
+	// VolumeList contains the volumes
+	VolumeList []DockerVolume `json:"volume_list,omitempty"`
+	// NetworkList contains the networks
+	NetworkList []DockerNetwork `json:"network_list,omitempty"`
 }
 
 // NewDockerInfo creates a new DockerInfo with the given parameters.
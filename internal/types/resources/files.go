@@ -0,0 +1,191 @@
+package resources
+
+import "time"
+
+// FileEntry represents a single entry in a directory listing.
+// Example:
+//
+//	{
+//	  "name": "app.log",
+//	  "path": "logs/app.log",
+//	  "type": "file",
+//	  "size": 10240,
+//	  "mode": "-rw-r--r--",
+//	  "permissions": "0644",
+//	  "owner": "www-data",
+//	  "group": "www-data",
+//	  "mod_time": "2023-01-01T00:00:00Z",
+//	  "depth": 1
+//	}
+type FileEntry struct {
+	// Name is the base name of the entry
+	Name string `json:"name"`
+	// Path is the entry path relative to the listed directory
+	Path string `json:"path"`
+	// Type is the entry type (file, dir, symlink, socket, pipe, device, char_device, other)
+	Type string `json:"type"`
+	// Size is the size in bytes as reported by lstat
+	Size int64 `json:"size"`
+	// TotalSize is the aggregate size of all files below a directory (when requested)
+	TotalSize int64 `json:"total_size,omitempty"`
+	// Mode is the symbolic file mode (e.g., "-rw-r--r--")
+	Mode string `json:"mode"`
+	// Permissions is the octal permission bits (e.g., "0644")
+	Permissions string `json:"permissions"`
+	// Owner is the owning user name, or the numeric UID if it cannot be resolved
+	Owner string `json:"owner,omitempty"`
+	// Group is the owning group name, or the numeric GID if it cannot be resolved
+	Group string `json:"group,omitempty"`
+	// UID is the numeric owner ID
+	UID uint32 `json:"uid"`
+	// GID is the numeric group ID
+	GID uint32 `json:"gid"`
+	// ModTime is the last modification time
+	ModTime time.Time `json:"mod_time"`
+	// LinkTarget is the target of a symbolic link
+	LinkTarget string `json:"link_target,omitempty"`
+	// Hidden is whether the entry name starts with a dot
+	Hidden bool `json:"hidden,omitempty"`
+	// Depth is the nesting level below the listed directory (0 for direct children)
+	Depth int `json:"depth"`
+}
+
+// DirectoryListing represents the result of listing a directory.
+type DirectoryListing struct {
+	// Path is the directory that was listed
+	Path string `json:"path"`
+	// Entries is the current page of entries in tree order
+	Entries []FileEntry `json:"entries"`
+	// TotalEntries is the number of entries matched before pagination
+	TotalEntries int `json:"total_entries"`
+	// Offset is the index of the first returned entry
+	Offset int `json:"offset"`
+	// Limit is the maximum number of entries returned
+	Limit int `json:"limit"`
+	// HasMore is whether further pages are available
+	HasMore bool `json:"has_more"`
+	// NextOffset is the offset to request the next page with
+	NextOffset int `json:"next_offset,omitempty"`
+	// TotalSize is the aggregate size of all files below the directory (when requested)
+	TotalSize int64 `json:"total_size,omitempty"`
+	// Truncated is whether the walk stopped early because of the entry cap
+	Truncated bool `json:"truncated,omitempty"`
+	// SizesPartial is whether the directory sizes stopped counting at the entry cap
+	SizesPartial bool `json:"sizes_partial,omitempty"`
+}
+
+// FileStat represents detailed metadata for a single path.
+type FileStat struct {
+	FileEntry
+	// Inode is the inode number
+	Inode uint64 `json:"inode,omitempty"`
+	// Links is the number of hard links
+	Links uint64 `json:"links,omitempty"`
+	// Device is the ID of the device containing the file
+	Device uint64 `json:"device,omitempty"`
+	// Blocks is the number of 512-byte blocks allocated
+	Blocks int64 `json:"blocks,omitempty"`
+	// BlockSize is the preferred I/O block size
+	BlockSize int64 `json:"block_size,omitempty"`
+	// AccessTime is the last access time
+	AccessTime time.Time `json:"access_time,omitempty"`
+	// ChangeTime is the last status change time
+	ChangeTime time.Time `json:"change_time,omitempty"`
+}
+
+// FileChecksum represents the digest of a file.
+type FileChecksum struct {
+	// Path is the file that was hashed
+	Path string `json:"path"`
+	// Algorithm is the digest algorithm (sha256, md5)
+	Algorithm string `json:"algorithm"`
+	// Checksum is the hex-encoded digest
+	Checksum string `json:"checksum"`
+	// Size is the number of bytes hashed
+	Size int64 `json:"size"`
+}
+
+// CopyResult summarizes a copy operation.
+type CopyResult struct {
+	// Source is the copied path
+	Source string `json:"source"`
+	// Destination is the path that was written
+	Destination string `json:"destination"`
+	// Files is the number of regular files copied
+	Files int `json:"files"`
+	// Directories is the number of directories created
+	Directories int `json:"directories"`
+	// Symlinks is the number of symbolic links recreated
+	Symlinks int `json:"symlinks"`
+	// Bytes is the number of bytes copied
+	Bytes int64 `json:"bytes"`
+	// Skipped lists special files (devices, sockets, pipes) and paths the
+	// file access policy denies, which were not copied
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ArchiveResult summarizes an archive create or extract operation.
+type ArchiveResult struct {
+	// Archive is the archive file path
+	Archive string `json:"archive"`
+	// Format is the archive format (tar, tar.gz, tar.zst, zip)
+	Format string `json:"format"`
+	// Directory is the source directory (create) or destination directory (extract)
+	Directory string `json:"directory"`
+	// Entries is the number of entries written
+	Entries int `json:"entries"`
+	// Bytes is the total uncompressed size of regular files
+	Bytes int64 `json:"bytes"`
+	// ArchiveSize is the size of the archive file
+	ArchiveSize int64 `json:"archive_size,omitempty"`
+	// Skipped lists entries that were left out (special files or filtered paths)
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// FileTail represents the lines read from the end of a file or since a cursor.
+type FileTail struct {
+	// Path is the file that was read
+	Path string `json:"path"`
+	// Lines are the complete lines read, without trailing newlines
+	Lines []string `json:"lines"`
+	// Cursor is an opaque position to pass back to read only newer lines
+	Cursor string `json:"cursor"`
+	// Size is the file size at the time of the read
+	Size int64 `json:"size"`
+	// Inode identifies the file so rotation can be detected
+	Inode uint64 `json:"inode,omitempty"`
+	// Rotated is whether the file was replaced since the cursor was issued
+	Rotated bool `json:"rotated,omitempty"`
+	// Truncated is whether the file shrank since the cursor was issued
+	Truncated bool `json:"truncated,omitempty"`
+	// HasMore is whether further complete lines are available beyond the read limit
+	HasMore bool `json:"has_more,omitempty"`
+}
+
+// ConfigValue represents a value read from a structured configuration file.
+type ConfigValue struct {
+	// Path is the configuration file
+	Path string `json:"path"`
+	// Format is the file format (yaml, json, toml or ini)
+	Format string `json:"format"`
+	// Key is the key path that was read
+	Key string `json:"key"`
+	// Value is the decoded value
+	Value any `json:"value"`
+}
+
+// ConfigChange represents the outcome of editing a configuration file.
+type ConfigChange struct {
+	// Path is the configuration file
+	Path string `json:"path"`
+	// Format is the file format (yaml, json, toml or ini)
+	Format string `json:"format"`
+	// Key is the key path that was edited
+	Key string `json:"key"`
+	// Changed is whether the file content differs from before
+	Changed bool `json:"changed"`
+	// DryRun is whether the change was computed without writing
+	DryRun bool `json:"dry_run,omitempty"`
+	// Diff is a unified diff of the change
+	Diff string `json:"diff,omitempty"`
+}
@@ -28,10 +28,26 @@ type InfrastructureInfo struct {
 	ProxmoxInfo *ProxmoxInfo `json:"proxmox_info,omitempty"`
 	// CephInfo contains Ceph information
 	CephInfo *CephInfo `json:"ceph_info,omitempty"`
+	// NomadInfo contains Nomad information
+	NomadInfo *NomadInfo `json:"nomad_info,omitempty"`
 	// MetricSummary contains aggregated metrics from various sources
 	MetricSummary *MetricSummary `json:"metric_summary,omitempty"`
 	// Alerts contains any alerts or warnings detected during collection
 	Alerts []Alert `json:"alerts,omitempty"`
+	// Collectors reports the outcome and duration of each section's collector
+	Collectors []CollectorStatus `json:"collectors,omitempty"`
+}
+
+// CollectorStatus reports how one infrastructure_info section was collected
+type CollectorStatus struct {
+	// Name is the section name (system, processes, docker, ...)
+	Name string `json:"name"`
+	// Status is ok, error or timeout
+	Status string `json:"status"`
+	// Error describes why the section is missing or incomplete
+	Error string `json:"error,omitempty"`
+	// DurationMs is how long the collector ran in milliseconds
+	DurationMs int64 `json:"duration_ms"`
 }
 
 // NewInfrastructureInfo creates a new InfrastructureInfo with the given parameters
@@ -55,6 +71,8 @@ type ProcessInfo struct {
 type Process struct {
 	// PID is the process ID
 	PID int `json:"pid"`
+	// PPID is the parent process ID
+	PPID int `json:"ppid,omitempty"`
 	// Name is the process name
 	Name string `json:"name"`
 	// User is the user running the process
@@ -87,6 +105,10 @@ type ProcessIOStats struct {
 	ReadOperations uint64 `json:"read_operations,omitempty"`
 	// WriteOperations is the number of write operations
 	WriteOperations uint64 `json:"write_operations,omitempty"`
+	// ReadBytesPerSec is the storage read rate over the sampling interval
+	ReadBytesPerSec float64 `json:"read_bytes_per_sec"`
+	// WriteBytesPerSec is the storage write rate over the sampling interval
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
 }
 
 // NetworkInfo represents information about the network
@@ -101,6 +123,20 @@ type NetworkInfo struct {
 	DNSConfig *DNSConfig `json:"dns_config,omitempty"`
 	// FirewallRules contains firewall rules
 	FirewallRules []FirewallRule `json:"firewall_rules,omitempty"`
+	// ConnectionSummary counts sockets by protocol and state
+	ConnectionSummary *ConnectionSummary `json:"connection_summary,omitempty"`
+	// Warnings lists sections that could not be read
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ConnectionSummary counts sockets by protocol and state
+type ConnectionSummary struct {
+	// Total is the number of sockets counted
+	Total int `json:"total"`
+	// ByProtocol counts sockets per protocol (tcp, tcp6, udp, udp6)
+	ByProtocol map[string]int `json:"by_protocol"`
+	// ByState counts sockets per state (LISTEN, ESTABLISHED, TIME_WAIT, etc.)
+	ByState map[string]int `json:"by_state"`
 }
 
 // NetworkConnection represents a network connection
@@ -121,6 +157,8 @@ type NetworkConnection struct {
 	PID int `json:"pid,omitempty"`
 	// ProcessName is the name of the process that owns this connection
 	ProcessName string `json:"process_name,omitempty"`
+	// Inode is the socket inode, used to map the connection to its owning process
+	Inode uint64 `json:"inode,omitempty"`
 }
 
 // Route represents a routing table entry
@@ -159,6 +197,12 @@ type FirewallRule struct {
 	Source string `json:"source,omitempty"`
 	// Destination is the destination address/network
 	Destination string `json:"destination,omitempty"`
+	// Table is the table the chain belongs to (filter, nat, or an nftables table name)
+	Table string `json:"table,omitempty"`
+	// InInterface is the input interface the rule matches
+	InInterface string `json:"in_interface,omitempty"`
+	// OutInterface is the output interface the rule matches
+	OutInterface string `json:"out_interface,omitempty"`
 }
 
 // ServiceInfo contains information about system services
@@ -171,6 +215,8 @@ type ServiceInfo struct {
 	SystemdActive bool `json:"systemd_active,omitempty"`
 	// ServiceCount is the total number of services
 	ServiceCount int `json:"service_count"`
+	// Warnings lists details that could not be collected
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // Service represents a system service
@@ -185,10 +231,38 @@ type Service struct {
 	StartTime time.Time `json:"start_time,omitempty"`
 	// Description is the service description
 	Description string `json:"description,omitempty"`
+	// LoadState is the systemd load state (loaded, not-found, masked, etc.)
+	LoadState string `json:"load_state,omitempty"`
+	// SubState is the unit-type specific state (running, exited, dead, auto-restart, etc.)
+	SubState string `json:"sub_state,omitempty"`
+	// UnitFileState is the enablement state (enabled, disabled, static, masked, etc.)
+	UnitFileState string `json:"unit_file_state,omitempty"`
+	// MainPID is the main process ID while the service runs
+	MainPID int `json:"main_pid,omitempty"`
+	// Result is how the service last stopped (success, exit-code, signal, timeout, etc.)
+	Result string `json:"result,omitempty"`
+	// ExitStatus is the exit status of the main process when it last exited
+	ExitStatus int `json:"exit_status,omitempty"`
+	// Restarts is how often systemd has restarted the service
+	Restarts int `json:"restarts,omitempty"`
 	// RecentLogs contains recent log entries
 	RecentLogs []LogEntry `json:"recent_logs,omitempty"`
 }
 
+// ServiceControlResult reports a service_control action
+type ServiceControlResult struct {
+	// Unit is the unit acted on
+	Unit string `json:"unit"`
+	// Action is the action performed (start, stop, restart, reload, enable, disable)
+	Action string `json:"action"`
+	// Success is whether systemctl completed the action
+	Success bool `json:"success"`
+	// Error is the systemctl error when the action failed
+	Error string `json:"error,omitempty"`
+	// Service is the unit state after the action
+	Service *Service `json:"service,omitempty"`
+}
+
 // LogEntry represents a log entry
 type LogEntry struct {
 	// Timestamp is the log entry timestamp
@@ -199,6 +273,44 @@ type LogEntry struct {
 	Level string `json:"level,omitempty"`
 	// Source is the log source
 	Source string `json:"source,omitempty"`
+	// Unit is the systemd unit or service that logged the entry
+	Unit string `json:"unit,omitempty"`
+	// PID is the process that logged the entry
+	PID int `json:"pid,omitempty"`
+	// Host is the host that logged the entry
+	Host string `json:"host,omitempty"`
+	// BootID identifies the boot the entry was logged in (journal only)
+	BootID string `json:"boot_id,omitempty"`
+}
+
+// LogQueryResult is a page of log entries or, when grouping, entry counts
+type LogQueryResult struct {
+	// Source is the journal or the file that was read
+	Source string `json:"source"`
+	// Format is the detected file format (journal, json, syslog, nginx_access, nginx_error, plain)
+	Format string `json:"format"`
+	// Entries are the matching entries in chronological order
+	Entries []LogEntry `json:"entries,omitempty"`
+	// NextCursor continues the query after the last entry; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Groups are match counts per group and time bucket
+	Groups []LogGroup `json:"groups,omitempty"`
+	// Matched is the number of matching entries seen
+	Matched int `json:"matched"`
+	// Scanned is the number of entries read
+	Scanned int `json:"scanned"`
+	// Truncated is set when the scan limit stopped the query early
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// LogGroup counts the log entries sharing the grouped fields within a time bucket
+type LogGroup struct {
+	// Bucket is the start of the time bucket when an interval is set
+	Bucket *time.Time `json:"bucket,omitempty"`
+	// Key holds the grouped field values, e.g. {"unit": "nginx.service", "level": "error"}
+	Key map[string]string `json:"key,omitempty"`
+	// Count is the number of matching entries
+	Count int `json:"count"`
 }
 
 // DockerContainer represents a Docker container
@@ -575,6 +687,46 @@ type CephPool struct {
 	UsedPercent float64 `json:"used_percent,omitempty"`
 }
 
+// NomadInfo contains information about a Nomad cluster
+type NomadInfo struct {
+	// Address is the Nomad agent address that was queried
+	Address string `json:"address,omitempty"`
+	// Nodes is a list of client nodes
+	Nodes []NomadNode `json:"nodes,omitempty"`
+	// Jobs is a list of jobs
+	Jobs []NomadJob `json:"jobs,omitempty"`
+}
+
+// NomadNode represents a Nomad client node
+type NomadNode struct {
+	// ID is the node ID
+	ID string `json:"id"`
+	// Name is the node name
+	Name string `json:"name"`
+	// Datacenter is the node datacenter
+	Datacenter string `json:"datacenter,omitempty"`
+	// Status is the node status (ready, down, initializing)
+	Status string `json:"status"`
+	// Eligibility is the scheduling eligibility
+	Eligibility string `json:"eligibility,omitempty"`
+	// Drain is whether the node is draining
+	Drain bool `json:"drain,omitempty"`
+}
+
+// NomadJob represents a Nomad job
+type NomadJob struct {
+	// ID is the job ID
+	ID string `json:"id"`
+	// Name is the job name
+	Name string `json:"name,omitempty"`
+	// Type is the job type (service, batch, system, sysbatch)
+	Type string `json:"type,omitempty"`
+	// Status is the job status (pending, running, dead)
+	Status string `json:"status"`
+	// Namespace is the job namespace
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // MetricSummary represents aggregated metrics from various sources
 type MetricSummary struct {
 	// CPUSummary contains CPU metrics
@@ -601,6 +753,8 @@ type MetricData struct {
 	Max float64 `json:"max,omitempty"`
 	// Avg is the average value
 	Avg float64 `json:"avg,omitempty"`
+	// P95 is the 95th percentile value
+	P95 float64 `json:"p95,omitempty"`
 	// Unit is the unit of measurement
 	Unit string `json:"unit,omitempty"`
 }
@@ -613,6 +767,61 @@ type MetricPoint struct {
 	Value float64 `json:"value"`
 }
 
+// MetricQueryResult holds recorded metric series over a time window
+type MetricQueryResult struct {
+	// Since is the start of the window
+	Since time.Time `json:"since"`
+	// Until is the end of the window
+	Until time.Time `json:"until"`
+	// StepSeconds is the width of each downsampled point
+	StepSeconds float64 `json:"step_seconds"`
+	// Aggregation is how samples within a step are combined (avg, min, max, last)
+	Aggregation string `json:"aggregation"`
+	// Series are the matching series, sorted by name and labels
+	Series []MetricSeries `json:"series"`
+	// Truncated is set when more series matched than were returned
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// MetricSeries is one recorded metric with its statistics over the query window.
+// Min, Max, Avg and P95 are computed from the raw samples; History is downsampled.
+// Values holds first, last and change (last minus first).
+type MetricSeries struct {
+	// Name is the metric name, e.g. memory_used_percent
+	Name string `json:"name"`
+	// Labels identify the series within the metric, e.g. {"mount": "/"}
+	Labels map[string]string `json:"labels,omitempty"`
+	// Samples is the number of raw samples in the window
+	Samples int `json:"samples"`
+	MetricData
+}
+
+// MetricCatalog lists the metric series recorded by the background sampler
+type MetricCatalog struct {
+	// IntervalSeconds is the sampling interval
+	IntervalSeconds float64 `json:"interval_seconds"`
+	// RetentionSeconds is how long samples are kept
+	RetentionSeconds float64 `json:"retention_seconds"`
+	// Series are the recorded series, sorted by name and labels
+	Series []MetricSeriesInfo `json:"series"`
+}
+
+// MetricSeriesInfo describes a recorded series without its samples
+type MetricSeriesInfo struct {
+	// Name is the metric name
+	Name string `json:"name"`
+	// Labels identify the series within the metric
+	Labels map[string]string `json:"labels,omitempty"`
+	// Unit is the unit of measurement
+	Unit string `json:"unit,omitempty"`
+	// Samples is the number of samples held
+	Samples int `json:"samples"`
+	// Oldest is the time of the oldest sample held
+	Oldest time.Time `json:"oldest"`
+	// Newest is the time of the newest sample held
+	Newest time.Time `json:"newest"`
+}
+
 // Alert represents an alert or warning detected during collection
 type Alert struct {
 	// Severity is the alert severity (info, warning, error, critical)
@@ -627,4 +836,120 @@ type Alert struct {
 	ResourceID string `json:"resource_id,omitempty"`
 	// Metadata contains additional metadata
 	Metadata map[string]string `json:"metadata,omitempty"`
+	// Name is the name of the rule that raised the alert
+	Name string `json:"name,omitempty"`
+	// State is the alert state (pending, firing, resolved)
+	State string `json:"state,omitempty"`
+	// Fingerprint identifies the alert across evaluations
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Labels identify the alert, including alertname and severity
+	Labels map[string]string `json:"labels,omitempty"`
+	// Value is the value that was compared with the threshold
+	Value float64 `json:"value"`
+	// Threshold is the rule threshold
+	Threshold float64 `json:"threshold"`
+	// FiredAt is when the alert started firing
+	FiredAt *time.Time `json:"fired_at,omitempty"`
+	// ResolvedAt is when the alert resolved
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// AlertsReport lists tracked alerts and, on request, the alert rules
+type AlertsReport struct {
+	// Alerts are the matching alerts, most severe first
+	Alerts []Alert `json:"alerts"`
+	// Firing is the number of firing alerts
+	Firing int `json:"firing"`
+	// Pending is the number of pending alerts
+	Pending int `json:"pending"`
+	// Rules are the configured rules with their last evaluation
+	Rules []AlertRuleStatus `json:"rules,omitempty"`
+}
+
+// AlertRuleStatus reports an alert rule and the outcome of its last evaluation
+type AlertRuleStatus struct {
+	// Name is the rule name
+	Name string `json:"name"`
+	// Source is what the rule evaluates (metrics, health)
+	Source string `json:"source"`
+	// Severity is the severity of the rule's alerts
+	Severity string `json:"severity"`
+	// Expression describes the condition, e.g. avg(memory_used_percent[5m]) > 90
+	Expression string `json:"expression"`
+	// For is how long the condition must hold before an alert fires
+	For string `json:"for,omitempty"`
+	// Active is the number of pending and firing alerts of the rule
+	Active int `json:"active"`
+	// LastEvaluation is when the rule was last evaluated
+	LastEvaluation *time.Time `json:"last_evaluation,omitempty"`
+	// LastError is why the last evaluation failed
+	LastError string `json:"last_error,omitempty"`
+}
+
+// PortCleanupReport represents the plan and outcome of a clean_ports request
+type PortCleanupReport struct {
+	// DryRun is whether processes were only listed, not signalled
+	DryRun bool `json:"dry_run"`
+	// Targets are the listening sockets whose owners are (or would be) terminated
+	Targets []PortCleanupTarget `json:"targets"`
+	// Skipped are matching sockets left alone, with the reason
+	Skipped []PortCleanupTarget `json:"skipped,omitempty"`
+	// Summary is a one-line description of the result
+	Summary string `json:"summary"`
+}
+
+// PortCleanupTarget represents one listening socket and the process holding it
+type PortCleanupTarget struct {
+	// Port is the local port
+	Port int `json:"port"`
+	// Protocol is the socket protocol (tcp, tcp6, udp, udp6)
+	Protocol string `json:"protocol"`
+	// Address is the local address the socket is bound to
+	Address string `json:"address"`
+	// PID is the owning process, 0 when it could not be determined
+	PID int `json:"pid,omitempty"`
+	// Command is the command line of the owning process
+	Command string `json:"command,omitempty"`
+	// User is the owner of the process
+	User string `json:"user,omitempty"`
+	// Reason explains why a socket was skipped
+	Reason string `json:"reason,omitempty"`
+	// Signal is the last signal sent to the process
+	Signal string `json:"signal,omitempty"`
+	// Outcome is released, still_bound or failed after execution
+	Outcome string `json:"outcome,omitempty"`
+	// Error describes a failure to signal the process
+	Error string `json:"error,omitempty"`
+}
+
+// SignalResult represents the outcome of signalling a process or process tree
+type SignalResult struct {
+	// Signal is the signal that was requested
+	Signal string `json:"signal"`
+	// Tree is whether descendants were signalled too
+	Tree bool `json:"tree,omitempty"`
+	// Processes lists each process that was considered, children first
+	Processes []SignalOutcome `json:"processes"`
+	// Summary is a one-line description of the result
+	Summary string `json:"summary"`
+}
+
+// SignalOutcome represents what happened to a single process
+type SignalOutcome struct {
+	// PID is the process ID
+	PID int `json:"pid"`
+	// Name is the process name
+	Name string `json:"name"`
+	// Command is the command line of the process
+	Command string `json:"command,omitempty"`
+	// User is the owner of the process
+	User string `json:"user,omitempty"`
+	// Signal is the last signal sent
+	Signal string `json:"signal,omitempty"`
+	// Escalated is whether SIGKILL was sent after the grace period
+	Escalated bool `json:"escalated,omitempty"`
+	// Outcome is exited, reused, running, delivered, skipped or failed
+	Outcome string `json:"outcome"`
+	// Reason explains a skipped or failed process
+	Reason string `json:"reason,omitempty"`
 }
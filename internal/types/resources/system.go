@@ -212,3 +212,19 @@ func NewDetailedSystemInfo(
 		CloudInfo:         cloudInfo,
 	}
 }
+
+// UptimeInfo represents system uptime and load
+type UptimeInfo struct {
+	// Uptime is the system uptime as a human-readable string
+	Uptime string `json:"uptime"`
+	// UptimeSeconds is the system uptime in seconds
+	UptimeSeconds float64 `json:"uptime_seconds"`
+	// BootTime is when the system was last booted
+	BootTime time.Time `json:"boot_time,omitempty"`
+	// LoadAverage is the 1, 5, and 15-minute load averages
+	LoadAverage []float64 `json:"load_average"`
+	// RunningProcesses is the number of currently runnable scheduling entities
+	RunningProcesses int `json:"running_processes"`
+	// TotalProcesses is the number of scheduling entities on the system
+	TotalProcesses int `json:"total_processes"`
+}
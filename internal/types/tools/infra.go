@@ -15,6 +15,8 @@ type ProxmoxArgs struct {
 	User string `json:"user"`
 	// Password is the password for Proxmox authentication
 	Password string `json:"password"`
+	// VerifySSL checks the server certificate; off by default for self-signed clusters
+	VerifySSL bool `json:"verify_ssl,omitempty"`
 }
 
 // Validate checks if the Proxmox arguments are valid.
@@ -242,6 +244,11 @@ func (args *ProcessInfoArgs) Validate() error {
 	if args.Sort == "" {
 		args.Sort = "cpu"
 	}
+	switch args.Sort {
+	case "cpu", "memory", "io", "name":
+	default:
+		return validation.NewInvalidFormatError("sort", "must be one of: cpu, memory, io, name")
+	}
 	return nil
 }
 
@@ -370,6 +377,11 @@ func (args *InfrastructureInfoArgs) Validate() error {
 	if args.CollectionLevel == "" {
 		args.CollectionLevel = "standard"
 	}
+	switch args.CollectionLevel {
+	case "basic", "standard", "detailed", "comprehensive":
+	default:
+		return validation.NewInvalidFormatError("collection_level", "must be one of: basic, standard, detailed, comprehensive")
+	}
 
 	// If IncludeAll is set, enable all include flags
 	if args.IncludeAll {
@@ -396,6 +408,15 @@ func (args *InfrastructureInfoArgs) Validate() error {
 	if args.TimeInterval <= 0 {
 		args.TimeInterval = 5
 	}
+	if args.TimeInterval > 60 {
+		return validation.NewInvalidFormatError("time_interval", "must be at most 60 seconds")
+	}
+
+	if args.ProxmoxAuth != nil && args.ProxmoxAuth.Host != "" {
+		if err := args.ProxmoxAuth.Validate(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }